@@ -1,7 +1,10 @@
 package block
 
 import (
-	//"github.com/joeqian10/neo-gogogo/rpc/models"
+	"encoding/json"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
 	"github.com/joeqian10/neo3-gogogo/tx"
 )
 
@@ -10,10 +13,73 @@ type Block struct {
 	Transactions []tx.Transaction
 }
 
+// NewBlockFromRPC converts the block of getblock, the hashes of the block and the transactions are checked
+func NewBlockFromRPC(block *models.RpcBlock) (*Block, error) {
+	data, err := json.Marshal(block)
+	if err != nil {
+		return nil, err
+	}
+	b := &Block{}
+	if err = json.Unmarshal(data, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ToRpcBlock converts the block to the model of getblock, without confirmations and nextblockhash
+func (b *Block) ToRpcBlock() (*models.RpcBlock, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.RpcBlock{}
+	if err = json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (b *Block) GetSize() int {
 	sz := 0
 	for _, tx := range b.Transactions {
 		sz += tx.GetSize()
 	}
-	return b.Header.GetSize() + sz
+	return b.Header.GetSize() + helper.GetVarSize(len(b.Transactions)) + sz
+}
+
+// MarshalJSON implements the json marshaller interface, in the format of the verbose getblock of the node
+func (b *Block) MarshalJSON() ([]byte, error) {
+	h, err := b.Header.toJson()
+	if err != nil {
+		return nil, err
+	}
+	h.Size = b.GetSize()
+	txs := make([]*tx.Transaction, len(b.Transactions))
+	for i := range b.Transactions {
+		txs[i] = &b.Transactions[i]
+	}
+	return json.Marshal(struct {
+		*headerJson
+		Tx []*tx.Transaction `json:"tx"`
+	}{h, txs})
+}
+
+// UnmarshalJSON implements the json unmarshaller interface, the hashes of the block and the transactions are
+// checked if they are not empty
+func (b *Block) UnmarshalJSON(data []byte) error {
+	var h Header
+	if err := h.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	v := struct {
+		Tx []tx.Transaction `json:"tx"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Tx == nil {
+		v.Tx = []tx.Transaction{}
+	}
+	b.Header, b.Transactions = h, v.Tx
+	return nil
 }
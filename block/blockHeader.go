@@ -2,7 +2,11 @@ package block
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
@@ -246,3 +250,99 @@ func (h *Header) SerializeUnsigned(bw *io.BinaryWriter) {
 func (h *Header) GetHashString() string {
 	return hex.EncodeToString(helper.ReverseBytes(h.GetHash().ToByteArray())) // reverse to big endian
 }
+
+// headerJson is the json of a header in the verbose getblockheader of the node, without confirmations and
+// nextblockhash
+type headerJson struct {
+	Hash              string        `json:"hash"`
+	Size              int           `json:"size"`
+	Version           uint32        `json:"version"`
+	PreviousBlockHash string        `json:"previousblockhash"`
+	MerkleRoot        string        `json:"merkleroot"`
+	Time              uint64        `json:"time"`
+	Nonce             string        `json:"nonce"`
+	Index             uint32        `json:"index"`
+	PrimaryIndex      byte          `json:"primary"`
+	NextConsensus     string        `json:"nextconsensus"`
+	Witnesses         []*tx.Witness `json:"witnesses"`
+}
+
+func (h *Header) toJson() (*headerJson, error) {
+	if h.Witness == nil {
+		return nil, fmt.Errorf("format error: witness of header is nil")
+	}
+	return &headerJson{
+		Hash:              "0x" + h.GetHash().String(),
+		Size:              h.GetSize(),
+		Version:           h.version,
+		PreviousBlockHash: "0x" + h.prevHash.String(),
+		MerkleRoot:        "0x" + h.merkleRoot.String(),
+		Time:              h.timestamp,
+		Nonce:             fmt.Sprintf("%016X", h.nonce),
+		Index:             h.index,
+		PrimaryIndex:      h.primaryIndex,
+		NextConsensus:     crypto.ScriptHashToAddress(h.nextConsensus, helper.DefaultAddressVersion),
+		Witnesses:         []*tx.Witness{h.Witness},
+	}, nil
+}
+
+// MarshalJSON implements the json marshaller interface, the next consensus is an address with
+// helper.DefaultAddressVersion
+func (h *Header) MarshalJSON() ([]byte, error) {
+	v, err := h.toJson()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json unmarshaller interface, the hash is checked if it is not empty
+func (h *Header) UnmarshalJSON(data []byte) error {
+	var v headerJson
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	prevHash, err := parseUInt256(v.PreviousBlockHash)
+	if err != nil {
+		return err
+	}
+	merkleRoot, err := parseUInt256(v.MerkleRoot)
+	if err != nil {
+		return err
+	}
+	nonce, err := strconv.ParseUint(v.Nonce, 16, 64)
+	if err != nil {
+		return fmt.Errorf("format error: invalid nonce: %s", v.Nonce)
+	}
+	nextConsensus, err := crypto.AddressToScriptHash(v.NextConsensus, helper.DefaultAddressVersion)
+	if err != nil {
+		return fmt.Errorf("format error: invalid nextconsensus: %s", v.NextConsensus)
+	}
+	if len(v.Witnesses) != 1 || v.Witnesses[0] == nil {
+		return fmt.Errorf("format error: header must have 1 witness, got %d", len(v.Witnesses))
+	}
+	bh := Header{
+		version:       v.Version,
+		prevHash:      prevHash,
+		merkleRoot:    merkleRoot,
+		timestamp:     v.Time,
+		nonce:         nonce,
+		index:         v.Index,
+		primaryIndex:  v.PrimaryIndex,
+		nextConsensus: nextConsensus,
+		Witness:       v.Witnesses[0],
+	}
+	if v.Hash != "" && strings.ToLower(strings.TrimPrefix(v.Hash, "0x")) != bh.GetHash().String() {
+		return fmt.Errorf("wrong block hash, expected: %s, got: %s", v.Hash, bh.GetHashString())
+	}
+	*h = bh
+	return nil
+}
+
+// parseUInt256 parses a hash in big-endian hex, with or without 0x
+func parseUInt256(s string) (*helper.UInt256, error) {
+	if len(strings.TrimPrefix(s, "0x")) != helper.UINT256SIZE*2 {
+		return nil, fmt.Errorf("format error: invalid hash: %s", s)
+	}
+	return helper.UInt256FromString(s)
+}
@@ -1,6 +1,8 @@
 package block
 
 import (
+	"encoding/json"
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
@@ -105,10 +107,9 @@ func TestBlockHeader_SerializeUnsigned(t *testing.T) {
 	assert.Equal(t, helper.BytesToHex(requiredData), helper.BytesToHex(buf.Bytes()))
 }
 
-func TestNewBlockHeaderFromRPC(t *testing.T) {
-	//consensusData := binary.BigEndian.Uint64(helper.HexToBytes("000000007c2bac1d"))
-	//assert.Equal(t, uint64(2083236893), consensusData)
-	rpcHeader := models.RpcBlockHeader{
+// newTestRpcHeader returns the header of a genesis block
+func newTestRpcHeader() models.RpcBlockHeader {
+	return models.RpcBlockHeader{
 		Hash:              "0x159359b9a57c1d94f946dfb510409ac4a32008c31f6742b21d3d8e165cdd660d",
 		Size:              401,
 		Version:           0,
@@ -126,12 +127,46 @@ func TestNewBlockHeaderFromRPC(t *testing.T) {
 		Confirmations: 5276880,
 		NextBlockHash: "0xd782db8a38b0eea0d7394e0f007c61c71798867578c77c387c08113903946cc9",
 	}
+}
+
+func TestNewBlockHeaderFromRPC(t *testing.T) {
+	//consensusData := binary.BigEndian.Uint64(helper.HexToBytes("000000007c2bac1d"))
+	//assert.Equal(t, uint64(2083236893), consensusData)
+	rpcHeader := newTestRpcHeader()
 
 	header, err := NewBlockHeaderFromRPC(&rpcHeader)
 	assert.Nil(t, err)
 	assert.Equal(t, 252, len(header.Witness.VerificationScript))
 }
 
+func TestHeader_MarshalJSON(t *testing.T) {
+	rpcHeader := newTestRpcHeader()
+	data, _ := json.Marshal(rpcHeader)
+	header := &Header{}
+	assert.Nil(t, json.Unmarshal(data, header))
+	assert.Equal(t, rpcHeader.Hash, "0x"+header.GetHash().String())
+	assert.Equal(t, rpcHeader.Witnesses[0].Verification, crypto.Base64Encode(header.Witness.VerificationScript))
+
+	data, err := json.Marshal(header)
+	assert.Nil(t, err)
+	v := models.RpcBlockHeader{}
+	assert.Nil(t, json.Unmarshal(data, &v))
+	rpcHeader.Nonce = "0000000000000000"
+	rpcHeader.Size = header.GetSize()
+	rpcHeader.Confirmations, rpcHeader.NextBlockHash = 0, ""
+	assert.Equal(t, rpcHeader, v)
+
+	rpcHeader.Time++
+	data, _ = json.Marshal(rpcHeader)
+	assert.NotNil(t, json.Unmarshal(data, header))
+	rpcHeader.Hash = ""
+	data, _ = json.Marshal(rpcHeader)
+	assert.Nil(t, json.Unmarshal(data, header))
+	rpcHeader.Witnesses = nil
+	data, _ = json.Marshal(rpcHeader)
+	assert.NotNil(t, json.Unmarshal(data, header))
+}
+
 //func TestNewBlockHeaderFromRPC2(t *testing.T) {
 //	cli := rpc.NewClient("http://seed1t.neo.org:21332")
 //	res := cli.GetBlockHeader("1")
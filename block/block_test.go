@@ -0,0 +1,67 @@
+package block
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+func TestNewBlockFromRPC(t *testing.T) {
+	rpcBlock := &models.RpcBlock{RpcBlockHeader: newTestRpcHeader(), Tx: []models.RpcTransaction{}}
+	b, err := NewBlockFromRPC(rpcBlock)
+	assert.Nil(t, err)
+	assert.Equal(t, rpcBlock.Hash, "0x"+b.GetHash().String())
+	assert.Equal(t, 0, len(b.Transactions))
+	assert.Equal(t, b.Header.GetSize()+1, b.GetSize())
+
+	r, err := b.ToRpcBlock()
+	assert.Nil(t, err)
+	assert.Equal(t, rpcBlock.Hash, r.Hash)
+	assert.Equal(t, b.GetSize(), r.Size)
+	assert.Equal(t, rpcBlock.NextConsensus, r.NextConsensus)
+	assert.Equal(t, []models.RpcTransaction{}, r.Tx)
+}
+
+func TestBlock_MarshalJSON(t *testing.T) {
+	trx := tx.NewTransaction()
+	trx.SetNonce(1)
+	trx.SetValidUntilBlock(100)
+	trx.SetSigners([]*tx.Signer{tx.NewSigner(helper.UInt160FromBytes(crypto.Hash160([]byte{0x01})), tx.CalledByEntry)})
+	trx.SetAttributes([]tx.ITransactionAttribute{tx.NewNotValidBeforeAttribute(10)})
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetWitnesses([]*tx.Witness{{InvocationScript: []byte{}, VerificationScript: []byte{byte(sc.PUSH1)}}})
+	b := &Block{Header: *SetupBlockHeaderWithValues(), Transactions: []tx.Transaction{*trx}}
+
+	data, err := json.Marshal(b)
+	assert.Nil(t, err)
+	v := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(data, &v))
+	assert.Equal(t, "0x"+b.GetHash().String(), v["hash"])
+	assert.Equal(t, float64(b.GetSize()), v["size"])
+	assert.Equal(t, "0000000000000000", v["nonce"])
+	assert.Equal(t, "0x"+trx.GetHash().String(), v["tx"].([]interface{})[0].(map[string]interface{})["hash"])
+
+	b2 := &Block{}
+	assert.Nil(t, json.Unmarshal(data, b2))
+	assert.Equal(t, b.GetHash(), b2.GetHash())
+	assert.Equal(t, trx.ToByteArray(), b2.Transactions[0].ToByteArray())
+
+	r, err := b.ToRpcBlock()
+	assert.Nil(t, err)
+	assert.Equal(t, "NotValidBefore", r.Tx[0].Attributes[0].Type)
+	b3, err := NewBlockFromRPC(r)
+	assert.Nil(t, err)
+	assert.Equal(t, trx.ToByteArray(), b3.Transactions[0].ToByteArray())
+
+	// the hashes of the transactions are checked as well
+	r.Tx[0].Nonce++
+	_, err = NewBlockFromRPC(r)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+)
+
+// nefMagic is the magic of a nef file, "NEF3"
+const nefMagic uint32 = 0x3346454E
+
+// parseParameter parses an argument of a contract in the format <type>:<value>. The value is in the json format of
+// the node, e.g. base64 for ByteArray and a json list of parameters for Array, besides a Hash160 can be an address
+// and a ByteArray can be hex with 0x.
+func (c *cli) parseParameter(s string) (*sc.ContractParameter, error) {
+	i := strings.Index(s, ":")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid argument %s, expected <type>:<value>", s)
+	}
+	t, err := sc.NewContractParameterTypeFromString(s[:i])
+	if err != nil {
+		return nil, fmt.Errorf("invalid type of argument %s", s)
+	}
+	value := s[i+1:]
+	switch t {
+	case sc.Hash160:
+		if hash, err := c.parseScriptHash(value); err == nil {
+			return &sc.ContractParameter{Type: t, Value: hash}, nil
+		}
+	case sc.ByteArray:
+		if strings.HasPrefix(value, "0x") {
+			b, err := hex.DecodeString(value[2:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid hex of argument %s", s)
+			}
+			return &sc.ContractParameter{Type: t, Value: b}, nil
+		}
+	case sc.Any:
+		return &sc.ContractParameter{Type: t}, nil
+	}
+	raw := json.RawMessage(value)
+	if t != sc.Array && t != sc.Map {
+		raw, _ = json.Marshal(value)
+	}
+	data, _ := json.Marshal(map[string]interface{}{"type": t.String(), "value": raw})
+	p := &sc.ContractParameter{}
+	if err = json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("invalid argument %s: %v", s, err)
+	}
+	return p, nil
+}
+
+func (c *cli) parseParameters(args []string) ([]interface{}, error) {
+	params := make([]interface{}, len(args))
+	for i, arg := range args {
+		p, err := c.parseParameter(arg)
+		if err != nil {
+			return nil, err
+		}
+		params[i] = *p
+	}
+	return params, nil
+}
+
+func (c *cli) invoke(args []string) error {
+	fs := newFlagSet("invoke")
+	f := newTxFlags(fs)
+	contract := fs.String("contract", "", "hash of the contract")
+	method := fs.String("method", "", "method of the contract")
+	send := fs.Bool("send", false, "send the invocation as a transaction")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"contract": *contract, "method": *method}); err != nil {
+		return err
+	}
+	hash, err := c.parseScriptHash(*contract)
+	if err != nil {
+		return err
+	}
+	params, err := c.parseParameters(fs.Args())
+	if err != nil {
+		return err
+	}
+	script, err := sc.MakeScript(hash, *method, params)
+	if err != nil {
+		return err
+	}
+	if *f.wallet == "" {
+		if *send || *f.context != "" {
+			return fmt.Errorf("-wallet is required to send a transaction")
+		}
+		client, err := c.getClient()
+		if err != nil {
+			return err
+		}
+		return c.writeInvokeResult(client.InvokeScript(crypto.Base64Encode(script), nil, false))
+	}
+	wh, w, err := c.walletHelper(f)
+	if err != nil {
+		return err
+	}
+	sender, err := c.sender(w, *f.from)
+	if err != nil {
+		return err
+	}
+	if !*send && *f.context == "" {
+		signers := []models.RpcSigner{{Account: "0x" + sender.String(), Scopes: "CalledByEntry"}}
+		return c.writeInvokeResult(wh.Client.InvokeScript(crypto.Base64Encode(script), signers, false))
+	}
+	trx, err := makeTransaction(wh, script, sender)
+	if err != nil {
+		return err
+	}
+	result, err := c.signAndSend(wh, trx, *f.context)
+	if err != nil {
+		return err
+	}
+	return c.write(result)
+}
+
+func (c *cli) writeInvokeResult(response rpc.InvokeResultResponse) error {
+	if response.HasError() {
+		return fmt.Errorf(response.GetErrorInfo())
+	}
+	return c.write(response.Result)
+}
+
+// readNef reads a nef file and returns its checksum, which is the last 4 bytes
+func readNef(path string) ([]byte, uint32, error) {
+	nef, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(nef) < 4+64+4 || binary.LittleEndian.Uint32(nef) != nefMagic {
+		return nil, 0, fmt.Errorf("invalid nef file %s", path)
+	}
+	return nef, binary.LittleEndian.Uint32(nef[len(nef)-4:]), nil
+}
+
+// readManifest reads a manifest file and returns its name
+func readManifest(path string) (string, string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	var manifest struct {
+		Name string `json:"name"`
+	}
+	if err = json.Unmarshal(data, &manifest); err != nil || manifest.Name == "" {
+		return "", "", fmt.Errorf("invalid manifest file %s", path)
+	}
+	return string(data), manifest.Name, nil
+}
+
+func (c *cli) deploy(args []string) error {
+	fs := newFlagSet("deploy")
+	f := newTxFlags(fs)
+	nefPath := fs.String("nef", "", "path of the nef file")
+	manifestPath := fs.String("manifest", "", "path of the manifest file")
+	data := fs.String("data", "", "argument of _deploy, <type>:<value>")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"nef": *nefPath, "manifest": *manifestPath}); err != nil {
+		return err
+	}
+	nef, checksum, err := readNef(*nefPath)
+	if err != nil {
+		return err
+	}
+	manifest, name, err := readManifest(*manifestPath)
+	if err != nil {
+		return err
+	}
+	params := []interface{}{
+		sc.ContractParameter{Type: sc.ByteArray, Value: nef},
+		sc.ContractParameter{Type: sc.String, Value: manifest},
+	}
+	if *data != "" {
+		p, err := c.parseParameter(*data)
+		if err != nil {
+			return err
+		}
+		params = append(params, *p)
+	}
+	script, err := sc.MakeScript(sc.GetNativeContractHash("ContractManagement"), "deploy", params)
+	if err != nil {
+		return err
+	}
+	wh, w, err := c.walletHelper(f)
+	if err != nil {
+		return err
+	}
+	sender, err := c.sender(w, *f.from)
+	if err != nil {
+		return err
+	}
+	trx, err := makeTransaction(wh, script, sender)
+	if err != nil {
+		return err
+	}
+	result, err := c.signAndSend(wh, trx, *f.context)
+	if err != nil {
+		return err
+	}
+	return c.write(struct {
+		*txResult
+		Contract string `json:"contract"`
+	}{result, "0x" + sc.GetContractHash(sender, checksum, name).String()})
+}
@@ -0,0 +1,168 @@
+// Command neo3 is a command-line tool built on the SDK. It manages NEP-6 wallets, transfers assets, invokes and
+// deploys contracts, decodes transactions and signs multi-signature transactions offline. The results are written
+// to stdout as json and the errors to stderr as json, so it can be driven by scripts.
+//
+// Usage:
+//
+//	neo3 [-rpc url] [-network mainnet|testnet|<magic>] <command> [flags] [args]
+//
+// The password of a wallet is read from -password or the NEO3_PASSWORD environment variable.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+)
+
+// passwordEnv is the environment variable of the wallet password, it keeps the password out of the process list
+const passwordEnv = "NEO3_PASSWORD"
+
+// cli is the state shared by the commands
+type cli struct {
+	out      io.Writer
+	settings *helper.ProtocolSettings
+	rpcUrl   string
+	client   rpc.IRpcClient // created from rpcUrl when it is first used
+}
+
+type command struct {
+	usage string
+	run   func(c *cli, args []string) error
+}
+
+var commands = map[string]command{
+	"wallet create":  {"-wallet <path> [-name <name>] [-password <pw>]", (*cli).walletCreate},
+	"wallet list":    {"-wallet <path>", (*cli).walletList},
+	"wallet import":  {"-wallet <path> [-password <pw>] (-wif <wif> | -nep2 <key> [-passphrase <pw>] | -multisig <m> -pubkeys <key,...>)", (*cli).walletImport},
+	"wallet balance": {"-wallet <path> [-asset <hash>,...]", (*cli).walletBalance},
+	"transfer":       {"-wallet <path> [-password <pw>] -asset <hash> -to <address> -amount <decimal> [-from <address>] [-context <file>]", (*cli).transfer},
+	"invoke":         {"-contract <hash> -method <name> [-wallet <path> [-password <pw>] [-from <address>] [-send | -context <file>]] [<type>:<value> ...]", (*cli).invoke},
+	"deploy":         {"-wallet <path> [-password <pw>] -nef <file> -manifest <file> [-from <address>] [-data <type>:<value>] [-context <file>]", (*cli).deploy},
+	"tx decode":      {"(-tx <base64 or hex> | -file <file>)", (*cli).txDecode},
+	"multisig sign":  {"-wallet <path> [-password <pw>] -context <file> [-out <file>]", (*cli).multisigSign},
+	"multisig merge": {"-out <file> <context file> ...", (*cli).multisigMerge},
+	"multisig relay": {"-context <file>", (*cli).multisigRelay},
+	"storage dump":   {"-contract <hash> [-prefix <hex>] [-verify]", (*cli).storageDump},
+}
+
+func main() {
+	c := &cli{out: os.Stdout}
+	if err := c.run(os.Args[1:]); err != nil {
+		data, _ := json.Marshal(map[string]string{"error": err.Error()})
+		fmt.Fprintln(os.Stderr, string(data))
+		os.Exit(1)
+	}
+}
+
+func (c *cli) run(args []string) error {
+	fs := flag.NewFlagSet("neo3", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	rpcUrl := fs.String("rpc", "http://localhost:10332", "url of the rpc server")
+	network := fs.String("network", "mainnet", "mainnet, testnet or the magic of the network")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	settings, err := settingsOf(*network)
+	if err != nil {
+		return err
+	}
+	c.settings, c.rpcUrl = settings, *rpcUrl
+
+	args = fs.Args()
+	if len(args) == 0 || args[0] == "help" {
+		return c.write(usage())
+	}
+	name := args[0]
+	if _, ok := commands[name]; !ok && len(args) > 1 {
+		name = args[0] + " " + args[1]
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command %s", strings.Join(args, " "))
+	}
+	return cmd.run(c, args[len(strings.Fields(name)):])
+}
+
+func usage() map[string]string {
+	result := map[string]string{}
+	for name, cmd := range commands {
+		result[name] = cmd.usage
+	}
+	return result
+}
+
+// settingsOf returns the protocol settings of the network, a magic other than MainNet and TestNet uses the
+// settings of MainNet with the magic
+func settingsOf(network string) (*helper.ProtocolSettings, error) {
+	switch strings.ToLower(network) {
+	case "mainnet":
+		return helper.MainNetProtocolSettings.Copy(), nil
+	case "testnet":
+		return helper.TestNetProtocolSettings.Copy(), nil
+	}
+	magic, err := strconv.ParseUint(network, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network %s", network)
+	}
+	settings := helper.MainNetProtocolSettings.Copy()
+	settings.Magic = uint32(magic)
+	return settings, nil
+}
+
+func (c *cli) getClient() (rpc.IRpcClient, error) {
+	if c.client == nil {
+		client := rpc.NewClient(c.rpcUrl)
+		if client == nil {
+			return nil, fmt.Errorf("invalid rpc url %s", c.rpcUrl)
+		}
+		c.client = client
+	}
+	return c.client, nil
+}
+
+// write writes the result to stdout as indented json
+func (c *cli) write(v interface{}) error {
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(v)
+}
+
+// newFlagSet returns a flag set which returns the errors instead of printing them
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// password returns the flag or the environment variable
+func password(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return os.Getenv(passwordEnv)
+}
+
+// required returns an error for the first empty flag
+func required(flags map[string]string) error {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if flags[name] == "" {
+			return fmt.Errorf("-%s is required", name)
+		}
+	}
+	return nil
+}
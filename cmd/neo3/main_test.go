@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+)
+
+func newTestCli() *cli {
+	return &cli{out: &bytes.Buffer{}, settings: helper.TestNetProtocolSettings.Copy()}
+}
+
+// exec runs the command and decodes its output into v
+func exec(t *testing.T, c *cli, v interface{}, args ...string) error {
+	out := c.out.(*bytes.Buffer)
+	out.Reset()
+	cmd, ok := commands[args[0]]
+	if !ok {
+		cmd = commands[args[0]+" "+args[1]]
+		args = args[1:]
+	}
+	if err := cmd.run(c, args[1:]); err != nil {
+		return err
+	}
+	if v != nil {
+		assert.Nil(t, json.Unmarshal(out.Bytes(), v))
+	}
+	return nil
+}
+
+func TestCli_Run(t *testing.T) {
+	c := newTestCli()
+	err := c.run([]string{"-network", "testnet", "help"})
+	assert.Nil(t, err)
+	assert.Equal(t, helper.TestNetProtocolSettings.Magic, c.settings.Magic)
+	var u map[string]string
+	assert.Nil(t, json.Unmarshal(c.out.(*bytes.Buffer).Bytes(), &u))
+	assert.Equal(t, len(commands), len(u))
+	assert.Contains(t, u, "multisig sign")
+
+	err = c.run([]string{"wallet", "remove"})
+	assert.EqualError(t, err, "unknown command wallet remove")
+
+	err = c.run([]string{"-network", "abc", "help"})
+	assert.EqualError(t, err, "invalid network abc")
+
+	err = c.run([]string{"wallet", "create", "-password", "p"})
+	assert.EqualError(t, err, "-wallet is required")
+}
+
+func TestSettingsOf(t *testing.T) {
+	settings, err := settingsOf("MainNet")
+	assert.Nil(t, err)
+	assert.Equal(t, helper.MainNetProtocolSettings.Magic, settings.Magic)
+
+	settings, err = settingsOf("1234")
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1234), settings.Magic)
+	assert.Equal(t, helper.MainNetProtocolSettings.AddressVersion, settings.AddressVersion)
+}
+
+func TestCli_ParseParameter(t *testing.T) {
+	c := newTestCli()
+	p, err := c.parseParameter("Hash160:NdtB8RXRmJ7Nhw1FPTm7E6HoDZGnDw37nf")
+	assert.Nil(t, err)
+	assert.Equal(t, sc.Hash160, p.Type)
+	assert.Equal(t, "820944cfdc70976602d71b0091445eedbc661bc5", p.Value.(*helper.UInt160).String())
+
+	p, err = c.parseParameter("Hash160:0x820944cfdc70976602d71b0091445eedbc661bc5")
+	assert.Nil(t, err)
+	assert.Equal(t, "820944cfdc70976602d71b0091445eedbc661bc5", p.Value.(*helper.UInt160).String())
+
+	p, err = c.parseParameter("ByteArray:0x0102")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1, 2}, p.Value)
+
+	p, err = c.parseParameter("ByteArray:AQI=")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1, 2}, p.Value)
+
+	p, err = c.parseParameter("Integer:-100")
+	assert.Nil(t, err)
+	assert.Equal(t, "-100", p.Value.(interface{ String() string }).String())
+
+	p, err = c.parseParameter("String:a:b")
+	assert.Nil(t, err)
+	assert.Equal(t, "a:b", p.Value)
+
+	p, err = c.parseParameter(`Array:[{"type":"Boolean","value":true},{"type":"String","value":"x"}]`)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(p.Value.([]sc.ContractParameter)))
+
+	p, err = c.parseParameter("Any:")
+	assert.Nil(t, err)
+	assert.Nil(t, p.Value)
+
+	_, err = c.parseParameter("100")
+	assert.NotNil(t, err)
+	_, err = c.parseParameter("Number:100")
+	assert.NotNil(t, err)
+	_, err = c.parseParameter("Integer:abc")
+	assert.NotNil(t, err)
+	_, err = c.parseParameter("ByteArray:0xzz")
+	assert.NotNil(t, err)
+}
+
+func TestParseAmount(t *testing.T) {
+	amount, err := parseAmount("1.5", 8)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(150000000), amount.Int64())
+
+	amount, err = parseAmount("3", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), amount.Int64())
+
+	_, err = parseAmount("1.5", 0)
+	assert.EqualError(t, err, "amount 1.5 has more than 0 decimals")
+	_, err = parseAmount("0", 8)
+	assert.NotNil(t, err)
+	_, err = parseAmount("-1", 8)
+	assert.NotNil(t, err)
+}
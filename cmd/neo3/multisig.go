@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/wallet"
+)
+
+func (c *cli) multisigSign(args []string) error {
+	fs := newFlagSet("multisig sign")
+	path := fs.String("wallet", "", "path of the wallet file")
+	pw := fs.String("password", "", "password of the wallet")
+	contextPath := fs.String("context", "", "context file")
+	out := fs.String("out", "", "file of the signed context, the context file by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"context": *contextPath}); err != nil {
+		return err
+	}
+	if *out == "" {
+		*out = *contextPath
+	}
+	ctx, err := c.readContext(*contextPath)
+	if err != nil {
+		return err
+	}
+	w, err := c.openWallet(*path, password(*pw))
+	if err != nil {
+		return err
+	}
+	// the accounts are signed by the keys in the wallet, the client is only used for the contracts without keys
+	client, err := c.getClient()
+	if err != nil {
+		return err
+	}
+	signed, err := wallet.NewWalletHelperFromWallet(client, w).Sign(ctx, c.settings.Magic)
+	if err != nil {
+		return err
+	}
+	if !signed {
+		return fmt.Errorf("no account of the wallet can sign the context")
+	}
+	if err = writeContext(*out, ctx, c.settings.Magic); err != nil {
+		return err
+	}
+	return c.write(&txResult{Hash: "0x" + ctx.Verifiable.GetHash().String(), Completed: ctx.GetCompleted(), Context: *out})
+}
+
+func (c *cli) multisigMerge(args []string) error {
+	fs := newFlagSet("multisig merge")
+	out := fs.String("out", "", "file of the merged context")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"out": *out}); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("no context file to merge")
+	}
+	ctx, err := c.readContext(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	for _, path := range fs.Args()[1:] {
+		other, err := c.readContext(path)
+		if err != nil {
+			return err
+		}
+		if err = ctx.Merge(other); err != nil {
+			return fmt.Errorf("failed to merge %s: %v", path, err)
+		}
+	}
+	if err = writeContext(*out, ctx, c.settings.Magic); err != nil {
+		return err
+	}
+	return c.write(&txResult{Hash: "0x" + ctx.Verifiable.GetHash().String(), Completed: ctx.GetCompleted(), Context: *out})
+}
+
+func (c *cli) multisigRelay(args []string) error {
+	fs := newFlagSet("multisig relay")
+	contextPath := fs.String("context", "", "context file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"context": *contextPath}); err != nil {
+		return err
+	}
+	ctx, err := c.readContext(*contextPath)
+	if err != nil {
+		return err
+	}
+	result := &txResult{Hash: "0x" + ctx.Verifiable.GetHash().String(), Completed: ctx.GetCompleted()}
+	if !result.Completed {
+		return fmt.Errorf("transaction %s needs more signatures", result.Hash)
+	}
+	if err = c.relay(ctx, result); err != nil {
+		return err
+	}
+	return c.write(result)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
+
+func newTestClient() *rpc.RpcClientMock {
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 100})
+	clientMock.On("InvokeFunction", mock.Anything, "decimals", mock.Anything, mock.Anything, false).Return(rpc.InvokeResultResponse{
+		Result: models.InvokeResult{State: "HALT", Stack: []models.InvokeStack{{Type: "Integer", Value: "8"}}},
+	})
+	clientMock.On("InvokeFunction", mock.Anything, "balanceOf", mock.Anything, mock.Anything, false).Return(rpc.InvokeResultResponse{
+		Result: models.InvokeResult{State: "HALT", Stack: []models.InvokeStack{{Type: "Integer", Value: "100000000000"}}},
+	})
+	clientMock.On("InvokeScript", mock.Anything, mock.Anything, false).Return(rpc.InvokeResultResponse{
+		Result: models.InvokeResult{State: "HALT", GasConsumed: "9977780", Stack: []models.InvokeStack{{Type: "Boolean", Value: true}}},
+	})
+	return clientMock
+}
+
+func TestCli_MultiSig(t *testing.T) {
+	c := newTestCli()
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	// two wallets with a key each and a third one with only the multi-signature account
+	var a, b accountInfo
+	assert.Nil(t, exec(t, c, &a, "wallet", "create", "-wallet", path("a.json"), "-password", "p"))
+	assert.Nil(t, exec(t, c, &b, "wallet", "create", "-wallet", path("b.json"), "-password", "p"))
+	var multi accountInfo
+	for _, w := range []string{"a.json", "b.json"} {
+		assert.Nil(t, exec(t, c, &multi, "wallet", "import", "-wallet", path(w), "-password", "p",
+			"-multisig", "2", "-pubkeys", a.PublicKey+","+b.PublicKey))
+	}
+	assert.Nil(t, exec(t, c, nil, "wallet", "create", "-wallet", path("c.json"), "-password", "p"))
+	assert.Nil(t, exec(t, c, nil, "wallet", "import", "-wallet", path("c.json"), "-password", "p",
+		"-multisig", "2", "-pubkeys", a.PublicKey+","+b.PublicKey))
+
+	c.client = newTestClient()
+	var unsigned txResult
+	err := exec(t, c, &unsigned, "transfer", "-wallet", path("c.json"), "-password", "p", "-from", multi.Address,
+		"-asset", "GAS", "-to", a.Address, "-amount", "1.5", "-context", path("unsigned.json"))
+	assert.EqualError(t, err, "invalid script hash or address GAS")
+	err = exec(t, c, &unsigned, "transfer", "-wallet", path("c.json"), "-password", "p", "-from", multi.Address,
+		"-asset", "0xd2a4cff31913016155e38e474a2c06d08be276cf", "-to", a.Address, "-amount", "1.5",
+		"-context", path("unsigned.json"))
+	assert.Nil(t, err)
+	assert.False(t, unsigned.Completed)
+	assert.False(t, unsigned.Sent)
+
+	// the context can't be sent before it is signed, and wallet c has no key of the account
+	err = exec(t, c, nil, "multisig", "relay", "-context", path("unsigned.json"))
+	assert.EqualError(t, err, "transaction "+unsigned.Hash+" needs more signatures")
+	err = exec(t, c, nil, "multisig", "sign", "-wallet", path("c.json"), "-password", "p", "-context", path("unsigned.json"))
+	assert.EqualError(t, err, "no account of the wallet can sign the context")
+
+	// a and b sign in parallel, then the contexts are merged
+	var signed txResult
+	assert.Nil(t, exec(t, c, &signed, "multisig", "sign", "-wallet", path("a.json"), "-password", "p",
+		"-context", path("unsigned.json"), "-out", path("a-signed.json")))
+	assert.False(t, signed.Completed)
+	assert.Nil(t, exec(t, c, &signed, "multisig", "sign", "-wallet", path("b.json"), "-password", "p",
+		"-context", path("unsigned.json"), "-out", path("b-signed.json")))
+	assert.False(t, signed.Completed)
+	var merged txResult
+	assert.Nil(t, exec(t, c, &merged, "multisig", "merge", "-out", path("merged.json"),
+		path("a-signed.json"), path("b-signed.json")))
+	assert.Equal(t, unsigned.Hash, merged.Hash)
+	assert.True(t, merged.Completed)
+
+	// a context of another network is rejected
+	other := newTestCli()
+	other.settings.Magic++
+	err = exec(t, other, nil, "multisig", "relay", "-context", path("merged.json"))
+	assert.NotNil(t, err)
+
+	clientMock := c.client.(*rpc.RpcClientMock)
+	sent := rpc.SendRawTransactionResponse{}
+	sent.Result.Hash = unsigned.Hash
+	clientMock.On("SendRawTransaction", mock.Anything).Return(sent)
+	var relayed txResult
+	assert.Nil(t, exec(t, c, &relayed, "multisig", "relay", "-context", path("merged.json")))
+	assert.Equal(t, unsigned.Hash, relayed.Hash)
+	assert.True(t, relayed.Sent)
+	clientMock.AssertNumberOfCalls(t, "SendRawTransaction", 1)
+
+	// the sent transaction decodes with both signatures in the witness
+	raw := clientMock.Calls[len(clientMock.Calls)-1].Arguments.String(0)
+	var info txInfo
+	assert.Nil(t, exec(t, c, &info, "tx", "decode", "-tx", raw))
+	assert.Equal(t, unsigned.Hash, info.Hash)
+	assert.Equal(t, multi.Address, info.Sender)
+	assert.Equal(t, multi.ScriptHash, info.Signers[0].Account)
+	assert.Equal(t, 1, len(info.Witnesses))
+	assert.Contains(t, info.WitnessDisassembly[0][0], "PUSHDATA1")
+	assert.Contains(t, info.WitnessDisassembly[0][1], "PUSHDATA1")
+	assert.Contains(t, info.WitnessDisassembly[0][2], "2-of-2 multi-signature contract")
+	assert.Contains(t, info.Disassembly[1], "150000000")
+	assert.Contains(t, info.Disassembly[len(info.Disassembly)-2], "GasToken.transfer")
+}
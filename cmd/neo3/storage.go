@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/storage"
+)
+
+func (c *cli) storageDump(args []string) error {
+	fs := newFlagSet("storage dump")
+	contract := fs.String("contract", "", "hash of the contract")
+	prefix := fs.String("prefix", "", "prefix of the keys in hex")
+	verify := fs.Bool("verify", false, "verify the entries with the latest state root, it needs the StateService plugin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"contract": *contract}); err != nil {
+		return err
+	}
+	hash, err := c.parseScriptHash(*contract)
+	if err != nil {
+		return err
+	}
+	p, err := hex.DecodeString(strings.TrimPrefix(*prefix, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid prefix %s", *prefix)
+	}
+	client, err := c.getClient()
+	if err != nil {
+		return err
+	}
+	browser := storage.NewBrowser(client, hash)
+	if *verify {
+		if err = browser.UseLatestStateRoot(); err != nil {
+			return err
+		}
+	}
+	return browser.Dump(c.out, p)
+}
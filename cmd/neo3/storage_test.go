@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+func TestCli_StorageDump(t *testing.T) {
+	c := newTestCli()
+	clientMock := new(rpc.RpcClientMock)
+	prefix := crypto.Base64Encode([]byte{0x14})
+	clientMock.On("FindStorage", tx.GasTokenId, prefix, 0).Return(rpc.FindStorageResponse{
+		Result: models.RpcFoundStorage{
+			Truncated: true,
+			Next:      1,
+			Results:   []models.RpcStorageEntry{{Key: crypto.Base64Encode([]byte{0x14, 0x01}), Value: crypto.Base64Encode([]byte{0x01})}},
+		},
+	})
+	clientMock.On("FindStorage", tx.GasTokenId, prefix, 1).Return(rpc.FindStorageResponse{
+		Result: models.RpcFoundStorage{
+			Results: []models.RpcStorageEntry{{Key: crypto.Base64Encode([]byte{0x14, 0x02}), Value: crypto.Base64Encode([]byte{0x02})}},
+		},
+	})
+	c.client = clientMock
+
+	var result struct {
+		Contract string `json:"contract"`
+		Entries  []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"entries"`
+	}
+	err := exec(t, c, &result, "storage", "dump", "-contract", tx.GasTokenId, "-prefix", "0x14")
+	assert.Nil(t, err)
+	assert.Equal(t, tx.GasTokenId, result.Contract)
+	assert.Equal(t, 2, len(result.Entries))
+	assert.Equal(t, "1402", result.Entries[1].Key)
+	assert.Equal(t, "02", result.Entries[1].Value)
+
+	assert.NotNil(t, exec(t, c, nil, "storage", "dump", "-prefix", "14"))
+	assert.NotNil(t, exec(t, c, nil, "storage", "dump", "-contract", tx.GasTokenId, "-prefix", "zz"))
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/nep17"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/joeqian10/neo3-gogogo/wallet"
+)
+
+// txFlags are the flags of the commands which make a transaction
+type txFlags struct {
+	wallet   *string
+	password *string
+	from     *string
+	context  *string
+}
+
+func newTxFlags(fs *flag.FlagSet) *txFlags {
+	return &txFlags{
+		wallet:   fs.String("wallet", "", "path of the wallet file"),
+		password: fs.String("password", "", "password of the wallet"),
+		from:     fs.String("from", "", "address of the sender, the first account with a key by default"),
+		context:  fs.String("context", "", "write the context of the transaction to the file instead of sending it"),
+	}
+}
+
+// txResult is the json of a sent transaction or a written context
+type txResult struct {
+	Hash      string `json:"hash"`
+	Completed bool   `json:"completed"`
+	Context   string `json:"context,omitempty"`
+	Sent      bool   `json:"sent"`
+}
+
+// walletHelper opens the wallet of the flags with the password
+func (c *cli) walletHelper(f *txFlags) (*wallet.WalletHelper, *wallet.NEP6Wallet, error) {
+	w, err := c.openWallet(*f.wallet, password(*f.password))
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := c.getClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return wallet.NewWalletHelperFromWallet(client, w), w, nil
+}
+
+// sender returns the account of -from, or the first account with a key
+func (c *cli) sender(w *wallet.NEP6Wallet, from string) (*helper.UInt160, error) {
+	if from != "" {
+		hash, err := c.parseScriptHash(from)
+		if err != nil {
+			return nil, err
+		}
+		if !w.Contains(hash) {
+			return nil, fmt.Errorf("%s is not in the wallet", from)
+		}
+		return hash, nil
+	}
+	for _, account := range sortedAccounts(w) {
+		if account.HasKey() {
+			return account.GetScriptHash(), nil
+		}
+	}
+	return nil, fmt.Errorf("no account with a key in the wallet")
+}
+
+// makeTransaction makes a transaction of the script sent by the sender, which pays the fees
+func makeTransaction(wh *wallet.WalletHelper, script []byte, sender *helper.UInt160) (*tx.Transaction, error) {
+	gas, err := wh.GetBalanceFromAccount(tx.GasToken, sender)
+	if err != nil {
+		return nil, err
+	}
+	cosigners := []*tx.Signer{{Account: sender, Scopes: tx.CalledByEntry}}
+	balances := []*wallet.AccountAndBalance{{Account: sender, Value: gas}}
+	return wh.MakeTransaction(script, cosigners, []tx.ITransactionAttribute{}, balances)
+}
+
+// signAndSend signs the transaction with the wallet, then sends it, or writes its context to contextPath if the
+// path is set. A transaction which needs the signatures of other wallets must be written to a context.
+func (c *cli) signAndSend(wh *wallet.WalletHelper, trx *tx.Transaction, contextPath string) (*txResult, error) {
+	ctx := wallet.NewContractParametersContract(trx)
+	if _, err := wh.Sign(ctx, c.settings.Magic); err != nil {
+		return nil, err
+	}
+	result := &txResult{Hash: "0x" + trx.GetHash().String(), Completed: ctx.GetCompleted()}
+	if contextPath != "" {
+		if err := writeContext(contextPath, ctx, c.settings.Magic); err != nil {
+			return nil, err
+		}
+		result.Context = contextPath
+		return result, nil
+	}
+	if !result.Completed {
+		return nil, fmt.Errorf("transaction %s needs more signatures, write it to a context with -context", result.Hash)
+	}
+	return result, c.relay(ctx, result)
+}
+
+// relay sends the transaction of a completed context
+func (c *cli) relay(ctx *wallet.ContractParametersContext, result *txResult) error {
+	witnesses, err := ctx.GetWitnesses()
+	if err != nil {
+		return err
+	}
+	trx := ctx.Verifiable.(*tx.Transaction)
+	trx.SetWitnesses(witnesses)
+	client, err := c.getClient()
+	if err != nil {
+		return err
+	}
+	response := client.SendRawTransaction(crypto.Base64Encode(trx.ToByteArray()))
+	if response.HasError() {
+		return fmt.Errorf(response.GetErrorInfo())
+	}
+	result.Hash, result.Sent = response.Result.Hash, true
+	return nil
+}
+
+func writeContext(path string, ctx *wallet.ContractParametersContext, magic uint32) error {
+	r, err := ctx.ToRpcContractParameterContext(magic)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// readContext reads a context file, the network of the context must be the network of the command
+func (c *cli) readContext(path string) (*wallet.ContractParametersContext, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r models.RpcContractParameterContext
+	if err = json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid context %s: %v", path, err)
+	}
+	if r.Network != c.settings.Magic {
+		return nil, fmt.Errorf("context %s is of network %d, not %d", path, r.Network, c.settings.Magic)
+	}
+	ctx, err := wallet.NewContractParametersContextFromRpc(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid context %s: %v", path, err)
+	}
+	return ctx, nil
+}
+
+// parseAmount parses a decimal amount of an asset with the decimals
+func parseAmount(s string, decimals int) (*big.Int, error) {
+	if i := strings.Index(s, "."); i >= 0 && len(s)-i-1 > decimals {
+		return nil, fmt.Errorf("amount %s has more than %d decimals", s, decimals)
+	}
+	amount := helper.ConvertFloat64StringToBigInt(s, decimals)
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount %s", s)
+	}
+	return amount, nil
+}
+
+func (c *cli) transfer(args []string) error {
+	fs := newFlagSet("transfer")
+	f := newTxFlags(fs)
+	asset := fs.String("asset", "", "hash of the asset")
+	to := fs.String("to", "", "address of the receiver")
+	amount := fs.String("amount", "", "decimal amount")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"asset": *asset, "to": *to, "amount": *amount}); err != nil {
+		return err
+	}
+	assetHash, err := c.parseScriptHash(*asset)
+	if err != nil {
+		return err
+	}
+	toHash, err := c.parseScriptHash(*to)
+	if err != nil {
+		return err
+	}
+	wh, w, err := c.walletHelper(f)
+	if err != nil {
+		return err
+	}
+	decimals, err := nep17.NewNep17Helper(assetHash, wh.Client).Decimals()
+	if err != nil {
+		return err
+	}
+	value, err := parseAmount(*amount, decimals)
+	if err != nil {
+		return err
+	}
+	toAddress := crypto.ScriptHashToAddress(toHash, c.settings.AddressVersion)
+
+	// the accounts of the wallet pay in turn
+	if *f.from == "" && *f.context == "" {
+		hash, err := wh.Transfer(assetHash, toAddress, value, c.settings.Magic)
+		if err != nil {
+			return err
+		}
+		return c.write(&txResult{Hash: hash, Completed: true, Sent: true})
+	}
+
+	from, err := c.sender(w, *f.from)
+	if err != nil {
+		return err
+	}
+	sb := sc.NewScriptBuilder()
+	sb.EmitDynamicCall(assetHash, "transfer", []interface{}{
+		sc.ContractParameter{Type: sc.Hash160, Value: from},
+		sc.ContractParameter{Type: sc.Hash160, Value: toHash},
+		sc.ContractParameter{Type: sc.Integer, Value: value},
+		sc.ContractParameter{Type: sc.Any},
+	})
+	sb.Emit(sc.ASSERT)
+	script, err := sb.ToArray()
+	if err != nil {
+		return err
+	}
+	trx, err := makeTransaction(wh, script, from)
+	if err != nil {
+		return err
+	}
+	result, err := c.signAndSend(wh, trx, *f.context)
+	if err != nil {
+		return err
+	}
+	return c.write(result)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+// txInfo is the json of a decoded transaction, in the format of the verbose getrawtransaction with the disassembled
+// scripts
+type txInfo struct {
+	Hash            string              `json:"hash"`
+	Size            int                 `json:"size"`
+	Version         uint8               `json:"version"`
+	Nonce           uint32              `json:"nonce"`
+	Sender          string              `json:"sender"`
+	SysFee          string              `json:"sysfee"`
+	NetFee          string              `json:"netfee"`
+	ValidUntilBlock uint32              `json:"validuntilblock"`
+	Signers         []models.RpcSigner  `json:"signers"`
+	Attributes      []json.RawMessage   `json:"attributes"`
+	Script          string              `json:"script"`
+	Witnesses       []models.RpcWitness `json:"witnesses"`
+	// Disassembly is the disassembled script, the witnesses have the disassembled scripts in WitnessDisassembly
+	Disassembly        []string   `json:"disassembly"`
+	WitnessDisassembly [][]string `json:"witnessdisassembly"`
+}
+
+// decodeTransaction decodes a transaction in hex or base64, hex is tried first since it can be valid base64
+func decodeTransaction(s string) (*tx.Transaction, error) {
+	s = strings.TrimSpace(s)
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		if b, err = crypto.Base64Decode(s); err != nil {
+			return nil, fmt.Errorf("transaction is neither hex nor base64")
+		}
+	}
+	trx := tx.NewTransaction()
+	br := io.NewBinaryReaderFromBuf(b)
+	trx.Deserialize(br)
+	if br.Err != nil {
+		return nil, fmt.Errorf("invalid transaction: %v", br.Err)
+	}
+	return trx, nil
+}
+
+func disassemble(script []byte) []string {
+	s, err := sc.FormatScript(script)
+	if err != nil {
+		return []string{"; " + err.Error()}
+	}
+	return strings.Split(strings.TrimRight(s, "\n"), "\n")
+}
+
+func newTxInfo(trx *tx.Transaction, addressVersion byte) (*txInfo, error) {
+	data, err := json.Marshal(trx)
+	if err != nil {
+		return nil, err
+	}
+	info := &txInfo{}
+	if err = json.Unmarshal(data, info); err != nil {
+		return nil, err
+	}
+	info.Sender = crypto.ScriptHashToAddress(trx.GetSender(), addressVersion)
+	info.Disassembly = disassemble(trx.GetScript())
+	for _, witness := range trx.GetWitnesses() {
+		info.WitnessDisassembly = append(info.WitnessDisassembly,
+			append(disassemble(witness.InvocationScript), disassemble(witness.VerificationScript)...))
+	}
+	return info, nil
+}
+
+func (c *cli) txDecode(args []string) error {
+	fs := newFlagSet("tx decode")
+	raw := fs.String("tx", "", "transaction in base64 or hex")
+	file := fs.String("file", "", "file of the transaction in base64 or hex")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file != "" {
+		data, err := ioutil.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		*raw = string(data)
+	}
+	if *raw == "" {
+		return fmt.Errorf("-tx or -file is required")
+	}
+	trx, err := decodeTransaction(*raw)
+	if err != nil {
+		return err
+	}
+	info, err := newTxInfo(trx, c.settings.AddressVersion)
+	if err != nil {
+		return err
+	}
+	return c.write(info)
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/nep17"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/joeqian10/neo3-gogogo/wallet"
+)
+
+// accountInfo is the json of an account
+type accountInfo struct {
+	Address    string        `json:"address"`
+	ScriptHash string        `json:"scripthash"`
+	Label      string        `json:"label,omitempty"`
+	IsDefault  bool          `json:"isdefault,omitempty"`
+	WatchOnly  bool          `json:"watchonly"`
+	PublicKey  string        `json:"publickey,omitempty"` // of a signature account
+	MultiSig   *multiSigInfo `json:"multisig,omitempty"`
+}
+
+type multiSigInfo struct {
+	M          int      `json:"m"`
+	PublicKeys []string `json:"publickeys"`
+}
+
+func newAccountInfo(account wallet.IAccount) accountInfo {
+	info := accountInfo{
+		Address:    account.GetAddress(),
+		ScriptHash: "0x" + account.GetScriptHash().String(),
+		Label:      account.GetLabel(),
+		IsDefault:  account.GetIsDefault(),
+		WatchOnly:  account.WatchOnly(),
+	}
+	if contract := account.GetContract(); contract != nil {
+		script := contract.GetScript()
+		if sc.IsSignatureContract(script) {
+			if point, err := crypto.NewECPointFromBytes(script[2:35]); err == nil {
+				info.PublicKey = point.String()
+			}
+		}
+		if ok, m, points := sc.ByteSlice(script).IsMultiSigContractWithPoints(); ok {
+			info.MultiSig = &multiSigInfo{M: m, PublicKeys: make([]string, len(points))}
+			for i := range points {
+				info.MultiSig.PublicKeys[i] = points[i].String()
+			}
+		}
+	}
+	return info
+}
+
+// sortedAccounts returns the accounts of the wallet ordered by address
+func sortedAccounts(w *wallet.NEP6Wallet) []wallet.IAccount {
+	accounts := w.GetAccounts()
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].GetAddress() < accounts[j].GetAddress() })
+	return accounts
+}
+
+// openWallet opens an existing wallet, it is unlocked if the password is not empty
+func (c *cli) openWallet(path, password string) (*wallet.NEP6Wallet, error) {
+	if path == "" {
+		return nil, fmt.Errorf("-wallet is required")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	w, err := wallet.NewNEP6Wallet(path, c.settings, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if password != "" {
+		if err = w.Unlock(password); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// lockWallet locks the wallet file for a command which changes it, until the command returns
+func lockWallet(path string) (*wallet.WalletFileLock, error) {
+	if path == "" {
+		return nil, fmt.Errorf("-wallet is required")
+	}
+	return wallet.LockWalletFile(path)
+}
+
+func (c *cli) walletCreate(args []string) error {
+	fs := newFlagSet("wallet create")
+	path := fs.String("wallet", "", "path of the wallet file")
+	name := fs.String("name", "", "name of the wallet, the file name by default")
+	pw := fs.String("password", "", "password of the wallet")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	*pw = password(*pw)
+	if err := required(map[string]string{"wallet": *path, "password": *pw}); err != nil {
+		return err
+	}
+	lock, err := lockWallet(*path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	if _, err := os.Stat(*path); err == nil {
+		return fmt.Errorf("wallet %s already exists", *path)
+	}
+	if *name == "" {
+		*name = strings.TrimSuffix(filepath.Base(*path), filepath.Ext(*path))
+	}
+	w, err := wallet.NewNEP6Wallet(*path, c.settings, name, nil)
+	if err != nil {
+		return err
+	}
+	if err = w.Unlock(*pw); err != nil {
+		return err
+	}
+	account, err := w.CreateAccount()
+	if err != nil {
+		return err
+	}
+	if err = w.Save(*path); err != nil {
+		return err
+	}
+	return c.write(newAccountInfo(account))
+}
+
+func (c *cli) walletList(args []string) error {
+	fs := newFlagSet("wallet list")
+	path := fs.String("wallet", "", "path of the wallet file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	w, err := c.openWallet(*path, "")
+	if err != nil {
+		return err
+	}
+	accounts := []accountInfo{}
+	for _, account := range sortedAccounts(w) {
+		accounts = append(accounts, newAccountInfo(account))
+	}
+	return c.write(map[string]interface{}{
+		"name":     w.GetName(),
+		"version":  w.GetVersion(),
+		"accounts": accounts,
+	})
+}
+
+func (c *cli) walletImport(args []string) error {
+	fs := newFlagSet("wallet import")
+	path := fs.String("wallet", "", "path of the wallet file")
+	pw := fs.String("password", "", "password of the wallet")
+	wif := fs.String("wif", "", "private key in WIF")
+	nep2 := fs.String("nep2", "", "private key encrypted with NEP-2")
+	passphrase := fs.String("passphrase", "", "passphrase of the NEP-2 key, the password of the wallet by default")
+	scryptN := fs.Int("n", 0, "scrypt parameter N of the NEP-2 key, the one of the wallet by default")
+	scryptR := fs.Int("r", 0, "scrypt parameter r of the NEP-2 key, the one of the wallet by default")
+	scryptP := fs.Int("p", 0, "scrypt parameter p of the NEP-2 key, the one of the wallet by default")
+	m := fs.Int("multisig", 0, "number of the signatures of a multi-signature account")
+	pubKeys := fs.String("pubkeys", "", "public keys of the multi-signature account, separated by commas")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	*pw = password(*pw)
+	// held from reading the wallet to saving it, so that a concurrent import is not lost
+	lock, err := lockWallet(*path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	w, err := c.openWallet(*path, *pw)
+	if err != nil {
+		return err
+	}
+	var account wallet.IAccount
+	switch {
+	case *wif != "":
+		account, err = w.ImportFromWIF(*wif)
+	case *nep2 != "":
+		if *passphrase == "" {
+			*passphrase = *pw
+		}
+		if *scryptN == 0 {
+			*scryptN = w.Scrypt.N
+		}
+		if *scryptR == 0 {
+			*scryptR = w.Scrypt.R
+		}
+		if *scryptP == 0 {
+			*scryptP = w.Scrypt.P
+		}
+		account, err = w.ImportFromNEP2(*nep2, *passphrase, *scryptN, *scryptR, *scryptP)
+	case *m > 0:
+		account, err = importMultiSig(w, *m, *pubKeys)
+	default:
+		return fmt.Errorf("-wif, -nep2 or -multisig is required")
+	}
+	if err != nil {
+		return err
+	}
+	if err = w.Save(*path); err != nil {
+		return err
+	}
+	return c.write(newAccountInfo(account))
+}
+
+// importMultiSig adds an account without a key of the multi-signature contract, the transactions of the account are
+// signed by the accounts of its public keys in the wallet
+func importMultiSig(w *wallet.NEP6Wallet, m int, pubKeys string) (wallet.IAccount, error) {
+	if pubKeys == "" {
+		return nil, fmt.Errorf("-pubkeys is required")
+	}
+	points := []*crypto.ECPoint{}
+	for _, s := range strings.Split(pubKeys, ",") {
+		point, err := crypto.NewECPointFromString(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %v", s, err)
+		}
+		points = append(points, point)
+	}
+	contract, err := sc.CreateMultiSigContract(m, points)
+	if err != nil {
+		return nil, err
+	}
+	return w.CreateAccountWithContract(contract, nil)
+}
+
+// balanceInfo is the json of the balance of an asset
+type balanceInfo struct {
+	Asset    string `json:"asset"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+	Amount   string `json:"amount"` // decimal
+}
+
+func (c *cli) walletBalance(args []string) error {
+	fs := newFlagSet("wallet balance")
+	path := fs.String("wallet", "", "path of the wallet file")
+	assets := fs.String("asset", tx.NeoTokenId+","+tx.GasTokenId, "hashes of the assets, separated by commas")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	w, err := c.openWallet(*path, "")
+	if err != nil {
+		return err
+	}
+	client, err := c.getClient()
+	if err != nil {
+		return err
+	}
+	tokens := []*nep17.Nep17Helper{}
+	symbols, decimals := []string{}, []int{}
+	for _, s := range strings.Split(*assets, ",") {
+		hash, err := c.parseScriptHash(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		token := nep17.NewNep17Helper(hash, client)
+		symbol, err := token.Symbol()
+		if err != nil {
+			return fmt.Errorf("symbol of %s: %v", s, err)
+		}
+		d, err := token.Decimals()
+		if err != nil {
+			return fmt.Errorf("decimals of %s: %v", s, err)
+		}
+		tokens, symbols, decimals = append(tokens, token), append(symbols, symbol), append(decimals, d)
+	}
+	type accountBalances struct {
+		Address  string        `json:"address"`
+		Balances []balanceInfo `json:"balances"`
+	}
+	result := []accountBalances{}
+	for _, account := range sortedAccounts(w) {
+		r := accountBalances{Address: account.GetAddress(), Balances: []balanceInfo{}}
+		for i, token := range tokens {
+			b, err := token.BalanceOf(account.GetScriptHash())
+			if err != nil {
+				return fmt.Errorf("balance of %s: %v", account.GetAddress(), err)
+			}
+			r.Balances = append(r.Balances, balanceInfo{
+				Asset:    "0x" + token.ScriptHash.String(),
+				Symbol:   symbols[i],
+				Decimals: decimals[i],
+				Amount:   helper.ConvertBigIntToDecimalString(b, decimals[i]),
+			})
+		}
+		result = append(result, r)
+	}
+	return c.write(result)
+}
+
+// parseScriptHash parses a script hash in big-endian hex or an address
+func (c *cli) parseScriptHash(s string) (*helper.UInt160, error) {
+	if len(strings.TrimPrefix(s, "0x")) == 2*helper.UINT160SIZE {
+		return helper.UInt160FromString(s)
+	}
+	hash, err := crypto.AddressToScriptHash(s, c.settings.AddressVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script hash or address %s", s)
+	}
+	return hash, nil
+}
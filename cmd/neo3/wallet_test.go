@@ -0,0 +1,75 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/keys"
+)
+
+func TestCli_Wallet(t *testing.T) {
+	c := newTestCli()
+	path := filepath.Join(t.TempDir(), "test.json")
+
+	err := exec(t, c, nil, "wallet", "create", "-wallet", path)
+	assert.EqualError(t, err, "-password is required")
+
+	var created accountInfo
+	err = exec(t, c, &created, "wallet", "create", "-wallet", path, "-password", "p")
+	assert.Nil(t, err)
+	assert.False(t, created.WatchOnly)
+	assert.Equal(t, 66, len(created.PublicKey))
+
+	err = exec(t, c, nil, "wallet", "create", "-wallet", path, "-password", "p")
+	assert.EqualError(t, err, "wallet "+path+" already exists")
+
+	pair, _ := keys.GenerateKeyPair()
+	var imported accountInfo
+	t.Setenv(passwordEnv, "p")
+	err = exec(t, c, &imported, "wallet", "import", "-wallet", path, "-wif", pair.Export())
+	assert.Nil(t, err)
+	assert.Equal(t, pair.GetPublicKey().String(), imported.PublicKey)
+
+	var multi accountInfo
+	err = exec(t, c, &multi, "wallet", "import", "-wallet", path, "-multisig", "2",
+		"-pubkeys", created.PublicKey+","+imported.PublicKey)
+	assert.Nil(t, err)
+	assert.False(t, multi.WatchOnly)
+	assert.Equal(t, 2, multi.MultiSig.M)
+	assert.ElementsMatch(t, []string{created.PublicKey, imported.PublicKey}, multi.MultiSig.PublicKeys)
+
+	err = exec(t, c, nil, "wallet", "import", "-wallet", path, "-multisig", "3",
+		"-pubkeys", created.PublicKey+","+imported.PublicKey)
+	assert.NotNil(t, err)
+
+	var list struct {
+		Name     string        `json:"name"`
+		Accounts []accountInfo `json:"accounts"`
+	}
+	err = exec(t, c, &list, "wallet", "list", "-wallet", path)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", list.Name)
+	assert.Equal(t, 3, len(list.Accounts))
+	for i := 1; i < len(list.Accounts); i++ {
+		assert.True(t, list.Accounts[i-1].Address < list.Accounts[i].Address)
+	}
+
+	err = exec(t, c, nil, "wallet", "list", "-wallet", filepath.Join(t.TempDir(), "none.json"))
+	assert.NotNil(t, err)
+}
+
+func TestCli_ParseScriptHash(t *testing.T) {
+	c := newTestCli()
+	hash, err := c.parseScriptHash("NdtB8RXRmJ7Nhw1FPTm7E6HoDZGnDw37nf")
+	assert.Nil(t, err)
+	assert.Equal(t, "820944cfdc70976602d71b0091445eedbc661bc5", hash.String())
+
+	hash, err = c.parseScriptHash("0x820944cfdc70976602d71b0091445eedbc661bc5")
+	assert.Nil(t, err)
+	assert.Equal(t, "820944cfdc70976602d71b0091445eedbc661bc5", hash.String())
+
+	_, err = c.parseScriptHash("abc")
+	assert.EqualError(t, err, "invalid script hash or address abc")
+}
@@ -0,0 +1,311 @@
+package crosschain
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/mpt"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+const (
+	// CrossChainLockEvent is the event emitted by the cross chain manager contract (CCMC) when a cross chain tx is made
+	CrossChainLockEvent = "CrossChainLockEvent"
+
+	// the CrossChainLockEvent state is [caller, fromContract, toChainId, requestKey, rawParam]
+	lockEventStateCount = 5
+	lockEventToChainId  = 2
+	lockEventRequestKey = 3
+	lockEventRawParam   = 4
+
+	// StateValidatorRole is the role of state validators in the RoleManagement native contract
+	StateValidatorRole = 4
+)
+
+const RoleManagementId = "0x49cf4e5378ffcd4dec034fd98a174c5491e395e2"
+
+var RoleManagement, _ = helper.UInt160FromString(RoleManagementId)
+
+// CrossChainHelper wraps the Neo N3 side of the Poly Network cross chain flow
+type CrossChainHelper struct {
+	CCMC   *helper.UInt160 // script hash of the cross chain manager contract
+	Client rpc.IRpcClient
+}
+
+// CrossChainRequest is the cross chain request found in the application log of a Neo N3 tx
+type CrossChainRequest struct {
+	TxId      string
+	ToChainId uint64
+	Key       []byte // storage key of the request in CCMC
+	RawParam  []byte // serialized CrossChainTxParameter
+}
+
+// CrossChainProof is a cross chain request verified against a state root
+type CrossChainProof struct {
+	Request   *CrossChainRequest
+	Height    uint32 // block height of the tx
+	StateRoot *mpt.StateRoot
+	Proof     []byte // raw proof returned by getproof
+	Value     []byte // storage value proved by Proof
+	TxParam   *mpt.CrossChainTxParameter
+}
+
+// TxArgs is the typed content of CrossChainTxParameter.Args used by lock proxies
+type TxArgs struct {
+	AssetHash []byte
+	ToAddress []byte
+	Amount    *big.Int
+}
+
+func NewCrossChainHelper(ccmc *helper.UInt160, client rpc.IRpcClient) *CrossChainHelper {
+	if client == nil {
+		return nil
+	}
+	return &CrossChainHelper{
+		CCMC:   ccmc,
+		Client: client,
+	}
+}
+
+// GetCrossChainRequest finds the CrossChainLockEvent emitted by CCMC in a tx
+func (c *CrossChainHelper) GetCrossChainRequest(txId string) (*CrossChainRequest, error) {
+	response := c.Client.GetApplicationLog(txId)
+	if response.HasError() {
+		return nil, fmt.Errorf(response.GetErrorInfo())
+	}
+	for _, execution := range response.Result.Executions {
+		if execution.VMState == "FAULT" {
+			return nil, fmt.Errorf("tx %s faulted: %s", txId, execution.Exception)
+		}
+		for _, notification := range execution.Notifications {
+			u, err := helper.UInt160FromString(notification.Contract)
+			if err != nil || !u.Equals(c.CCMC) || notification.EventName != CrossChainLockEvent {
+				continue
+			}
+			return parseLockEvent(txId, notification.State)
+		}
+	}
+	return nil, fmt.Errorf("no %s found in tx %s", CrossChainLockEvent, txId)
+}
+
+func parseLockEvent(txId string, state models.InvokeStack) (*CrossChainRequest, error) {
+	if state.Type != "Array" {
+		return nil, fmt.Errorf("invalid %s state type: %s", CrossChainLockEvent, state.Type)
+	}
+	states := models.ConvertInvokeStackArray(state)
+	if len(states) != lockEventStateCount {
+		return nil, fmt.Errorf("invalid %s state count: %d", CrossChainLockEvent, len(states))
+	}
+	s, ok := states[lockEventToChainId].Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid toChainId in %s", CrossChainLockEvent)
+	}
+	toChainId, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	key, err := decodeByteString(states[lockEventRequestKey])
+	if err != nil {
+		return nil, err
+	}
+	rawParam, err := decodeByteString(states[lockEventRawParam])
+	if err != nil {
+		return nil, err
+	}
+	return &CrossChainRequest{
+		TxId:      txId,
+		ToChainId: toChainId,
+		Key:       key,
+		RawParam:  rawParam,
+	}, nil
+}
+
+func decodeByteString(s models.InvokeStack) ([]byte, error) {
+	v, ok := s.Value.(string)
+	if !ok || (s.Type != "ByteString" && s.Type != "Buffer") {
+		return nil, fmt.Errorf("invalid stack item, expected ByteString, got %s", s.Type)
+	}
+	return crypto.Base64Decode(v)
+}
+
+// GetVerifiedStateRoot gets the latest validated state root, which must not be lower than the given height,
+// and verifies its witness against the state validators designated at its index and the network magic
+func (c *CrossChainHelper) GetVerifiedStateRoot(height uint32, magic uint32) (*mpt.StateRoot, error) {
+	response := c.Client.GetStateHeight()
+	if response.HasError() {
+		return nil, fmt.Errorf(response.GetErrorInfo())
+	}
+	validated := response.Result.ValidateRootIndex
+	if validated < height {
+		return nil, fmt.Errorf("state root of height %d is not validated yet, validated height: %d", height, validated)
+	}
+	rootResponse := c.Client.GetStateRoot(validated)
+	if rootResponse.HasError() {
+		return nil, fmt.Errorf(rootResponse.GetErrorInfo())
+	}
+	sr := rootResponse.Result
+	if sr.Index != validated {
+		return nil, fmt.Errorf("state root %d is returned for height %d", sr.Index, validated)
+	}
+	validators, err := c.GetStateValidators(sr.Index)
+	if err != nil {
+		return nil, err
+	}
+	err = VerifyStateRoot(&sr, validators, magic)
+	if err != nil {
+		return nil, err
+	}
+	return &sr, nil
+}
+
+// GetStateValidators gets the state validators designated at the given block index
+func (c *CrossChainHelper) GetStateValidators(index uint32) ([]*crypto.ECPoint, error) {
+	args := []models.RpcContractParameter{
+		{Type: "Integer", Value: StateValidatorRole},
+		{Type: "Integer", Value: index},
+	}
+	response := c.Client.InvokeFunction(RoleManagement.String(), "getDesignatedByRole", args, nil, false)
+	stacks, err := rpc.PopInvokeStacks(response)
+	if err != nil {
+		return nil, err
+	}
+	if len(stacks) == 0 || stacks[0].Type != "Array" {
+		return nil, fmt.Errorf("invalid result of getDesignatedByRole")
+	}
+	items := models.ConvertInvokeStackArray(stacks[0])
+	result := make([]*crypto.ECPoint, len(items))
+	for i, item := range items {
+		b, err := decodeByteString(item)
+		if err != nil {
+			return nil, err
+		}
+		result[i], err = crypto.NewECPointFromBytes(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// VerifyStateRoot checks the state root is signed by the BFT address of the state validators
+func VerifyStateRoot(sr *mpt.StateRoot, validators []*crypto.ECPoint, magic uint32) error {
+	if len(validators) == 0 {
+		return fmt.Errorf("no state validators")
+	}
+	witnesses := sr.GetWitnesses()
+	if len(witnesses) != 1 || witnesses[0] == nil {
+		return fmt.Errorf("state root %d should have exactly one witness", sr.Index)
+	}
+	n := len(validators)
+	script, err := sc.CreateMultiSigRedeemScript(n-(n-1)/3, validators)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(witnesses[0].VerificationScript, script) {
+		return fmt.Errorf("state root %d is not signed by the state validators", sr.Index)
+	}
+	if !tx.VerifyMultiSignatureWitness(tx.GetSignData(sr, magic), witnesses[0]) {
+		return fmt.Errorf("invalid signatures of state root %d", sr.Index)
+	}
+	return nil
+}
+
+// GetCrossChainProof fetches the proof of the cross chain request made in a tx,
+// and verifies it against a state root signed by the state validators of the network with the magic
+func (c *CrossChainHelper) GetCrossChainProof(txId string, magic uint32) (*CrossChainProof, error) {
+	request, err := c.GetCrossChainRequest(txId)
+	if err != nil {
+		return nil, err
+	}
+	heightResponse := c.Client.GetTransactionHeight(txId)
+	if heightResponse.HasError() {
+		return nil, fmt.Errorf(heightResponse.GetErrorInfo())
+	}
+	height := uint32(heightResponse.Result)
+	sr, err := c.GetVerifiedStateRoot(height, magic)
+	if err != nil {
+		return nil, err
+	}
+	proofResponse := c.Client.GetProof(sr.RootHash, "0x"+c.CCMC.String(), crypto.Base64Encode(request.Key))
+	if proofResponse.HasError() {
+		return nil, fmt.Errorf(proofResponse.GetErrorInfo())
+	}
+	proof, err := crypto.Base64Decode(proofResponse.Result)
+	if err != nil {
+		return nil, err
+	}
+	value, err := VerifyCrossChainProof(sr, request.Key, proof)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(value, request.RawParam) {
+		return nil, fmt.Errorf("proved value does not match the request in tx %s", txId)
+	}
+	param, err := mpt.DeserializeCrossChainTxParameter(value, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &CrossChainProof{
+		Request:   request,
+		Height:    height,
+		StateRoot: sr,
+		Proof:     proof,
+		Value:     value,
+		TxParam:   param,
+	}, nil
+}
+
+// VerifyCrossChainProof verifies the proof of a storage key against the state root and returns the proved value
+func VerifyCrossChainProof(sr *mpt.StateRoot, key []byte, proof []byte) ([]byte, error) {
+	root, err := helper.UInt256FromString(sr.RootHash)
+	if err != nil {
+		return nil, err
+	}
+	id, k, proofs, err := mpt.ResolveProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(k, key) {
+		return nil, fmt.Errorf("proof key %s does not match the request key %s", helper.BytesToHex(k), helper.BytesToHex(key))
+	}
+	return mpt.VerifyProof(root, id, k, proofs)
+}
+
+// DecodeTxArgs decodes the args of a CrossChainTxParameter made by lock proxies
+func DecodeTxArgs(param *mpt.CrossChainTxParameter) (*TxArgs, error) {
+	if param == nil {
+		return nil, fmt.Errorf("CrossChainTxParameter is nil")
+	}
+	assetHash, toAddress, amount, err := mpt.DeserializeArgs(param.Args)
+	if err != nil {
+		return nil, err
+	}
+	return &TxArgs{
+		AssetHash: assetHash,
+		ToAddress: toAddress,
+		Amount:    amount,
+	}, nil
+}
+
+// GetAssetHash returns the asset hash as a Neo N3 script hash, only valid when the target chain is Neo N3
+func (a *TxArgs) GetAssetHash() (*helper.UInt160, error) {
+	if len(a.AssetHash) != helper.UINT160SIZE {
+		return nil, fmt.Errorf("invalid asset hash length: %d", len(a.AssetHash))
+	}
+	return helper.UInt160FromBytes(a.AssetHash), nil
+}
+
+// GetToAddress returns the receiver as a Neo N3 script hash, only valid when the target chain is Neo N3
+func (a *TxArgs) GetToAddress() (*helper.UInt160, error) {
+	if len(a.ToAddress) != helper.UINT160SIZE {
+		return nil, fmt.Errorf("invalid to address length: %d", len(a.ToAddress))
+	}
+	return helper.UInt160FromBytes(a.ToAddress), nil
+}
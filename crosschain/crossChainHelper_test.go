@@ -0,0 +1,197 @@
+package crosschain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/mpt"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+const testProof = "081f000000010202000ab20003faa49e3eb9f5706afcb09eca63e97fb70a7c8fc0fe54582eff57e9a9eda7811c0316ad99d39930e5ed8a9d9102197db08f4a7c5c86ca11e261b6f3a0b9b6550ff0037709d8f7f2d7cd8191d7f1eb01b4a59fcc72319752c8df6df295656030fe68f8034db94ceca7ecf2cda445773f3299ca387d21794f5a10f206309a9858845b09a50404040404040404040404034da9746bf082924d353e235c78bb8eb7cec65263bb36755b4cc7e2532d7de51304fdd20100035b87382f8326fbb948f6dadc72b75d68153ae04fb21b0ab381e048e351f5371603170c6a577b08d8cfb48ef8ccacef8a31f85854db2e7c0cba5eea0129f8c50806030f31825b4a4c2d5883b62f117e9946b98fc83e02c6002008e44783ae20e87510031ed4712740edf5b550b9d033664b771ae6919799c1f49156b10e6235f4adb85b038d0d66af3c496709924139c1eb7959b88c91320a8f12331a189933b26f525ab003cad0febb3c01839e171ff6ff664ad2f62dd5afc050612e0f79d8d2d60f2da17b032b522c797e7d349d43f9e254243d69e4ce59fe895ed5c0bd7306ed3fffe6f1590330de70f98131ea2cdcef3860230740126d2fc0bd5f092db02fc1e027cd35404403defb9abe9a03c9c428272a74e6ab6e397c3ad2e85f5a6bf9d62cf6bb0c9d737f037ac8d026f68d2daa2db64c65e4239fb2eb12ab3a035ddbc7b169813fe855d1d8040403426028540c777db462e6f582b41d5cde09afa1aaf4d701214778dc645bfbbeee0361fee55f5a7eb508ef63b49bbae38dcd845febb676d7c7907ec9ed3e85925325035b2c8482177062b6f1fe9c5aee1e76df8d5470641d04543a9dc9604d457475470349c95180b557f4605e885264a04852cb0687dc1de0433cca1316056248d8a58d042a01070000000000000003b1d7e70568351292c8beaf65faf12872ab3ca393d340509b7054e5fa15fab7199200040365a13c6d713fa39905bfdc8089c0751abead637d12b38721b5f04233f203ebcf03a91fdcc46b688f274c9a40ca0954451a44c4525db9036342ce18f959d759a7b303e9eda894371038774436fdb358e870dbd047161138fca05b783550e1b3fc8dab04037fdf37ec7a2b533746412242f763d665c20593baaabb2f6a528a51fa4d11172b04040404040404040404042401010003e047415c56f144c0d4d1c4515f4bbadee579a270cca5107d9a8618287bbe7ae352000403f55cf6e130de55c6d313926d7f90fb91cb4c425e46c16d046a16ba8720b287720338be68f1a99d2967a086dad7d96700e3e5fc3bc6bd274cf41c7011d86033a14804040404040404040404040404042401010003c66eec6a6b47b9fe65899a841e3a5e6bd5e98947928bb197a3c5751f3070f7be52000404030a244012611f97eb581a086ca300daeb9af36cb03c640cc5a1fd3d6c00368e4f0404033496d8c7133d7e9411d1844528ef33282ae117396f865f0b4a4313ad0d7565dc0404040404040404040404250102000003bcc8bd5123cfdea367b37fa420b3cc2bbdfbc9a5d96cf66cc9092c184b4519d5c802c6207015585f5c47874bfc080e1bb8e1331b35791ab4667133390e74fba3658a621b20c493054fd7ecd17bd346c020bce920f5e720c3fba3810ba3e879b9993c352cff14de3a7dff895992cd5a3e452394e7119c7e8f759702000000000000001499ac1a7a27f9abbca8630b3470e25265e073aed806756e6c6f636b4a14fb524c1033b3e4b74518f34703d66a96ae963fad1401efa51217a3e6eb14a8a5c6d6f79ce82d4d466f0100000000000000000000000000000000000000000000000000000000000000"
+const testRootHash = "0x61362d5f95a67ae6aaa0d2e98aaa466fd5089be516672603450673f1647d4c10"
+const testRequestKey = "01020200"
+const testRawParam = "207015585f5c47874bfc080e1bb8e1331b35791ab4667133390e74fba3658a621b20c493054fd7ecd17bd346c020bce920f5e720c3fba3810ba3e879b9993c352cff14de3a7dff895992cd5a3e452394e7119c7e8f759702000000000000001499ac1a7a27f9abbca8630b3470e25265e073aed806756e6c6f636b4a14fb524c1033b3e4b74518f34703d66a96ae963fad1401efa51217a3e6eb14a8a5c6d6f79ce82d4d466f0100000000000000000000000000000000000000000000000000000000000000"
+
+var ccmc, _ = helper.UInt160FromString("0x5ba6c543c5a86a85e9ab3f028a4ad849b924fab9")
+
+func lockEventLog() rpc.GetApplicationLogResponse {
+	item := func(t string, v interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": t, "value": v}
+	}
+	return rpc.GetApplicationLogResponse{
+		Result: models.RpcApplicationLog{
+			TxId: "0x1234",
+			Executions: []models.RpcExecution{
+				{
+					Trigger: "Application",
+					VMState: "HALT",
+					Notifications: []models.RpcNotification{
+						{
+							Contract:  "0x" + ccmc.String(),
+							EventName: CrossChainLockEvent,
+							State: models.InvokeStack{
+								Type: "Array",
+								Value: []interface{}{
+									item("ByteString", "3hp9/4lZks1aPkUjlOcRnH6PdZc="),
+									item("ByteString", "3hp9/4lZks1aPkUjlOcRnH6PdZc="),
+									item("Integer", "2"),
+									item("ByteString", crypto.Base64Encode(helper.HexToBytes(testRequestKey))),
+									item("ByteString", crypto.Base64Encode(helper.HexToBytes(testRawParam))),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCrossChainHelper_GetCrossChainRequest(t *testing.T) {
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetApplicationLog", "0x1234").Return(lockEventLog())
+	c := NewCrossChainHelper(ccmc, clientMock)
+
+	r, err := c.GetCrossChainRequest("0x1234")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), r.ToChainId)
+	assert.Equal(t, testRequestKey, helper.BytesToHex(r.Key))
+	assert.Equal(t, testRawParam, helper.BytesToHex(r.RawParam))
+
+	other, _ := helper.UInt160FromString("0x0000000000000000000000000000000000000001")
+	c = NewCrossChainHelper(other, clientMock)
+	_, err = c.GetCrossChainRequest("0x1234")
+	assert.NotNil(t, err)
+}
+
+const testMagic uint32 = 5195086
+
+// stateValidators creates 4 state validators and a state root at index 200 signed by 3 of them
+func stateValidators(t *testing.T) ([]*keys.KeyPair, []*crypto.ECPoint, mpt.StateRoot) {
+	pairs := make([]*keys.KeyPair, 4)
+	points := make([]*crypto.ECPoint, 4)
+	for i := 0; i < 4; i++ {
+		pairs[i], _ = keys.GenerateKeyPair()
+		points[i] = pairs[i].PublicKey
+	}
+	sr := mpt.StateRoot{Index: 200, RootHash: testRootHash}
+	witness, err := tx.CreateMultiSignatureWitness(tx.GetSignData(&sr, testMagic), pairs[:3], 3, points)
+	assert.Nil(t, err)
+	sr.SetWitnesses([]*tx.Witness{witness})
+	return pairs, points, sr
+}
+
+func designatedByRoleResult(points []*crypto.ECPoint) rpc.InvokeResultResponse {
+	items := make([]interface{}, len(points))
+	for i, p := range points {
+		items[i] = map[string]interface{}{"type": "ByteString", "value": crypto.Base64Encode(p.EncodePoint(true))}
+	}
+	return rpc.InvokeResultResponse{
+		Result: models.InvokeResult{
+			State: "HALT",
+			Stack: []models.InvokeStack{{Type: "Array", Value: items}},
+		},
+	}
+}
+
+func proofClientMock(sr mpt.StateRoot, points []*crypto.ECPoint) *rpc.RpcClientMock {
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetApplicationLog", "0x1234").Return(lockEventLog())
+	clientMock.On("GetTransactionHeight", "0x1234").Return(rpc.GetTransactionHeightResponse{Result: 100})
+	clientMock.On("GetStateHeight").Return(rpc.GetStateHeightResponse{
+		Result: models.RpcStateHeight{LocalRootIndex: 210, ValidateRootIndex: 200},
+	})
+	clientMock.On("GetStateRoot", uint32(200)).Return(rpc.GetStateRootResponse{Result: sr})
+	clientMock.On("InvokeFunction", RoleManagement.String(), "getDesignatedByRole", mock.Anything, nil, false).
+		Return(designatedByRoleResult(points))
+	clientMock.On("GetProof", testRootHash, "0x"+ccmc.String(), mock.Anything).Return(rpc.GetProofResponse{
+		Result: crypto.Base64Encode(helper.HexToBytes(testProof)),
+	})
+	return clientMock
+}
+
+func TestCrossChainHelper_GetCrossChainProof(t *testing.T) {
+	_, points, sr := stateValidators(t)
+	c := NewCrossChainHelper(ccmc, proofClientMock(sr, points))
+
+	p, err := c.GetCrossChainProof("0x1234", testMagic)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(100), p.Height)
+	assert.Equal(t, uint32(200), p.StateRoot.Index)
+	assert.Equal(t, testRawParam, helper.BytesToHex(p.Value))
+	assert.Equal(t, "unlock", string(p.TxParam.Method))
+	assert.Equal(t, uint64(2), p.TxParam.ToChainID)
+
+	args, err := DecodeTxArgs(p.TxParam)
+	assert.Nil(t, err)
+	assetHash, err := args.GetAssetHash()
+	assert.Nil(t, err)
+	assert.Equal(t, "ad3f96ae966ad60347f31845b7e4b333104c52fb", assetHash.String())
+	assert.Equal(t, int64(1), args.Amount.Int64())
+
+	// the state root must be signed for the network
+	_, err = c.GetCrossChainProof("0x1234", testMagic+1)
+	assert.NotNil(t, err)
+
+	// a fake witness is rejected
+	fake := sr
+	fake.Witnesses = []models.RpcWitness{{Invocation: "", Verification: "EQ=="}}
+	c = NewCrossChainHelper(ccmc, proofClientMock(fake, points))
+	_, err = c.GetCrossChainProof("0x1234", testMagic)
+	assert.NotNil(t, err)
+
+	// a root signed by other validators is rejected
+	_, others, _ := stateValidators(t)
+	c = NewCrossChainHelper(ccmc, proofClientMock(sr, others))
+	_, err = c.GetCrossChainProof("0x1234", testMagic)
+	assert.NotNil(t, err)
+}
+
+func TestCrossChainHelper_GetVerifiedStateRoot(t *testing.T) {
+	_, points, sr := stateValidators(t)
+	c := NewCrossChainHelper(ccmc, proofClientMock(sr, points))
+
+	_, err := c.GetVerifiedStateRoot(201, testMagic)
+	assert.NotNil(t, err)
+	root, err := c.GetVerifiedStateRoot(200, testMagic)
+	assert.Nil(t, err)
+	assert.Equal(t, testRootHash, root.RootHash)
+}
+
+func TestCrossChainHelper_GetStateValidators(t *testing.T) {
+	_, points, sr := stateValidators(t)
+	c := NewCrossChainHelper(ccmc, proofClientMock(sr, points))
+
+	validators, err := c.GetStateValidators(200)
+	assert.Nil(t, err)
+	assert.Equal(t, len(points), len(validators))
+	for i := range points {
+		assert.True(t, points[i].Equals(validators[i]))
+	}
+}
+
+func TestVerifyCrossChainProof(t *testing.T) {
+	sr := &mpt.StateRoot{RootHash: testRootHash}
+	v, err := VerifyCrossChainProof(sr, helper.HexToBytes(testRequestKey), helper.HexToBytes(testProof))
+	assert.Nil(t, err)
+	assert.Equal(t, testRawParam, helper.BytesToHex(v))
+
+	_, err = VerifyCrossChainProof(sr, helper.HexToBytes("01020300"), helper.HexToBytes(testProof))
+	assert.NotNil(t, err)
+}
+
+func TestVerifyStateRoot(t *testing.T) {
+	_, points, sr := stateValidators(t)
+
+	assert.Nil(t, VerifyStateRoot(&sr, points, testMagic))
+	assert.NotNil(t, VerifyStateRoot(&sr, points, testMagic+1))
+	assert.NotNil(t, VerifyStateRoot(&sr, points[:3], testMagic))
+}
@@ -0,0 +1,98 @@
+package crosschain
+
+import (
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/mpt"
+)
+
+// PolyHeader is the unsigned part of a Poly chain block header, which is what CCMC on Neo N3 takes as rawHeader
+type PolyHeader struct {
+	Version          uint32
+	ChainID          uint64
+	PrevBlockHash    []byte // 32 bytes
+	TransactionsRoot []byte // 32 bytes
+	CrossStateRoot   []byte // 32 bytes
+	BlockRoot        []byte // 32 bytes
+	Timestamp        uint32
+	Height           uint32
+	ConsensusData    uint64
+	ConsensusPayload []byte
+	NextBookkeeper   []byte // 20 bytes
+}
+
+// DeserializePolyHeader decodes a raw Poly header
+func DeserializePolyHeader(rawHeader []byte) (*PolyHeader, error) {
+	h := &PolyHeader{}
+	br := io.NewBinaryReaderFromBuf(rawHeader)
+	h.Deserialize(br)
+	if br.Err != nil {
+		return nil, br.Err
+	}
+	return h, nil
+}
+
+func (h *PolyHeader) Deserialize(br *io.BinaryReader) {
+	br.ReadLE(&h.Version)
+	br.ReadLE(&h.ChainID)
+	h.PrevBlockHash = readFixedBytes(br, 32)
+	h.TransactionsRoot = readFixedBytes(br, 32)
+	h.CrossStateRoot = readFixedBytes(br, 32)
+	h.BlockRoot = readFixedBytes(br, 32)
+	br.ReadLE(&h.Timestamp)
+	br.ReadLE(&h.Height)
+	br.ReadLE(&h.ConsensusData)
+	h.ConsensusPayload = br.ReadVarBytes()
+	h.NextBookkeeper = readFixedBytes(br, 20)
+}
+
+func (h *PolyHeader) Serialize(bw *io.BinaryWriter) {
+	bw.WriteLE(h.Version)
+	bw.WriteLE(h.ChainID)
+	writeFixedBytes(bw, h.PrevBlockHash, 32)
+	writeFixedBytes(bw, h.TransactionsRoot, 32)
+	writeFixedBytes(bw, h.CrossStateRoot, 32)
+	writeFixedBytes(bw, h.BlockRoot, 32)
+	bw.WriteLE(h.Timestamp)
+	bw.WriteLE(h.Height)
+	bw.WriteLE(h.ConsensusData)
+	bw.WriteVarBytes(h.ConsensusPayload)
+	writeFixedBytes(bw, h.NextBookkeeper, 20)
+}
+
+// GetHash returns the Poly block hash, the same as the one CCMC calculates
+func (h *PolyHeader) GetHash() []byte {
+	b, err := io.ToArray(h)
+	if err != nil {
+		return nil
+	}
+	return crypto.Hash256(b)
+}
+
+// VerifyToMerkleValue proves the Poly cross chain tx against the CrossStateRoot of the header
+func (h *PolyHeader) VerifyToMerkleValue(proof []byte) (*mpt.ToMerkleValue, error) {
+	value, err := mpt.MerkleProve(proof, h.CrossStateRoot)
+	if err != nil {
+		return nil, err
+	}
+	return mpt.DeserializeMerkleValue(value)
+}
+
+func readFixedBytes(br *io.BinaryReader, length int) []byte {
+	b := make([]byte, length)
+	br.ReadLE(b)
+	return b
+}
+
+func writeFixedBytes(bw *io.BinaryWriter, b []byte, length int) {
+	if len(b) != length {
+		if bw.Err == nil {
+			bw.Err = fmt.Errorf("invalid length, expected %d, got %d: %s", length, len(b), helper.BytesToHex(b))
+		}
+		return
+	}
+	bw.WriteLE(b)
+}
@@ -0,0 +1,69 @@
+package crosschain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/sc"
+)
+
+const testPolyProof = "ef204ce9a62083b29dd888394c124fc57f0b8533171ed3f869dd350b0375d8027c00020000000000000020000000000000000000000000000000000000000000000000000000000000e269204df8f0fc252d2bd3f21c474510fed914cf5fb5ba98510ddfe83b3d6d5a3715ff14250e76987d838a75310c34bf422ea9f1ac4cc9060e0000000000000014cb569453781497dcb067b73d95b28802cb01553806756e6c6f636b4a149328aec1e84c93855e2fb4a01f5eb7ec15e1abd614e9cdc1efd22c74b5706f0068f79b69b46fa85a0d2035f50500000000000000000000000000000000000000000000000000000000"
+const testCrossStateRoot = "d0acc6ea0e2cd2560ee298d4846bec230f590879090c83b235728488d1ab0fe0"
+
+func testPolyHeader() *PolyHeader {
+	return &PolyHeader{
+		Version:          0,
+		ChainID:          0,
+		PrevBlockHash:    make([]byte, 32),
+		TransactionsRoot: make([]byte, 32),
+		CrossStateRoot:   helper.HexToBytes(testCrossStateRoot),
+		BlockRoot:        make([]byte, 32),
+		Timestamp:        1617000000,
+		Height:           1000,
+		ConsensusData:    42,
+		ConsensusPayload: []byte("{}"),
+		NextBookkeeper:   make([]byte, 20),
+	}
+}
+
+func TestPolyHeader_Serialize(t *testing.T) {
+	h := testPolyHeader()
+	b, err := io.ToArray(h)
+	assert.Nil(t, err)
+	assert.Equal(t, 4+8+32*4+4+4+8+1+2+20, len(b))
+
+	h2, err := DeserializePolyHeader(b)
+	assert.Nil(t, err)
+	assert.Equal(t, h, h2)
+	assert.Equal(t, h.GetHash(), h2.GetHash())
+
+	h.NextBookkeeper = []byte{0x01}
+	_, err = io.ToArray(h)
+	assert.NotNil(t, err)
+}
+
+func TestPolyHeader_VerifyToMerkleValue(t *testing.T) {
+	h := testPolyHeader()
+	v, err := h.VerifyToMerkleValue(helper.HexToBytes(testPolyProof))
+	assert.Nil(t, err)
+	assert.Equal(t, "unlock", string(v.TxParam.Method))
+
+	h.CrossStateRoot = make([]byte, 32)
+	_, err = h.VerifyToMerkleValue(helper.HexToBytes(testPolyProof))
+	assert.NotNil(t, err)
+}
+
+func TestMakeVerifyAndExecuteTxScript(t *testing.T) {
+	script, err := MakeVerifyAndExecuteTxScript(ccmc, []byte{0x01}, []byte{0x02}, nil, nil, nil)
+	assert.Nil(t, err)
+	expected, _ := sc.MakeScript(ccmc, "verifyAndExecuteTx", []interface{}{
+		[]byte{0x01}, []byte{0x02}, []byte{}, []byte{}, []byte{},
+	})
+	assert.Equal(t, expected, script)
+
+	_, err = MakeVerifyAndExecuteTxScript(ccmc, nil, []byte{0x02}, nil, nil, nil)
+	assert.NotNil(t, err)
+}
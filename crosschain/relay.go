@@ -0,0 +1,78 @@
+package crosschain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/joeqian10/neo3-gogogo/wallet"
+)
+
+// MakeVerifyAndExecuteTxScript builds the script calling CCMC.verifyAndExecuteTx, which relays a Poly cross chain tx to Neo N3.
+// proof is the merkle proof of the tx in the Poly header rawHeader, headerProof proves rawHeader against currentRawHeader
+// when rawHeader is not in the current epoch, signList is the signatures of the Poly bookkeepers on the header.
+func MakeVerifyAndExecuteTxScript(ccmc *helper.UInt160, proof, rawHeader, headerProof, currentRawHeader, signList []byte) ([]byte, error) {
+	if len(proof) == 0 || len(rawHeader) == 0 {
+		return nil, fmt.Errorf("proof and rawHeader should not be empty")
+	}
+	return sc.MakeScript(ccmc, "verifyAndExecuteTx", []interface{}{
+		sc.ContractParameter{Type: sc.ByteArray, Value: proof},
+		sc.ContractParameter{Type: sc.ByteArray, Value: rawHeader},
+		sc.ContractParameter{Type: sc.ByteArray, Value: nonNilBytes(headerProof)},
+		sc.ContractParameter{Type: sc.ByteArray, Value: nonNilBytes(currentRawHeader)},
+		sc.ContractParameter{Type: sc.ByteArray, Value: nonNilBytes(signList)},
+	})
+}
+
+// RelayPolyTx checks the Poly proof offline, then sends a tx invoking CCMC.verifyAndExecuteTx with the fee paid by wh
+func (c *CrossChainHelper) RelayPolyTx(wh *wallet.WalletHelper, magic uint32, proof, rawHeader, headerProof, currentRawHeader, signList []byte) (string, error) {
+	header, err := DeserializePolyHeader(rawHeader)
+	if err != nil {
+		return "", err
+	}
+	toMerkleValue, err := header.VerifyToMerkleValue(proof)
+	if err != nil {
+		return "", err
+	}
+	if len(toMerkleValue.TxParam.ToContract) != helper.UINT160SIZE {
+		return "", fmt.Errorf("invalid ToContract: %s", helper.BytesToHex(toMerkleValue.TxParam.ToContract))
+	}
+	script, err := MakeVerifyAndExecuteTxScript(c.CCMC, proof, rawHeader, headerProof, currentRawHeader, signList)
+	if err != nil {
+		return "", err
+	}
+	balancesGas, err := wh.GetAccountAndBalance(tx.GasToken)
+	if err != nil {
+		return "", err
+	}
+	if len(balancesGas) == 0 {
+		return "", fmt.Errorf("insufficient GAS")
+	}
+	// the relayer only pays the fee, no witness is checked in the call
+	sort.Sort(sort.Reverse(wallet.AccountAndBalanceSlice(balancesGas)))
+	payer := balancesGas[0]
+	cosigners := []*tx.Signer{tx.NewSigner(payer.Account, tx.None)}
+	trx, err := wh.MakeTransaction(script, cosigners, []tx.ITransactionAttribute{}, []*wallet.AccountAndBalance{payer})
+	if err != nil {
+		return "", err
+	}
+	trx, err = wh.SignTransaction(trx, magic)
+	if err != nil {
+		return "", err
+	}
+	response := c.Client.SendRawTransaction(crypto.Base64Encode(trx.ToByteArray()))
+	if response.HasError() {
+		return "", fmt.Errorf(response.GetErrorInfo())
+	}
+	return response.Result.Hash, nil
+}
+
+func nonNilBytes(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
@@ -0,0 +1,149 @@
+// Package bls12381 implements BLS12-381 with the point encodings of the bls12381 methods of CryptoLib, and BLS
+// signatures with public keys in G1 and signatures in G2. It is written for preparing and checking contract
+// inputs, it is not constant time.
+package bls12381
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// the domain separation tags of the proof of possession scheme with public keys in G1 and signatures in G2,
+// the aggregated signatures of a committee signing the same message are safe against rogue keys only if every
+// public key comes with a valid proof of possession
+const (
+	SignatureDST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	PopDST       = "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+// ScalarSize is the length of the scalar of CryptoLib.bls12381Mul
+const ScalarSize = 32
+
+// PrivateKey is a BLS private key, 0 < d < r
+type PrivateKey struct {
+	d *big.Int
+}
+
+// GeneratePrivateKey generates a random private key
+func GeneratePrivateKey() (*PrivateKey, error) {
+	d, err := rand.Int(rand.Reader, new(big.Int).Sub(r, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{d: d.Add(d, big.NewInt(1))}, nil
+}
+
+// NewPrivateKey creates a private key from 32 big-endian bytes
+func NewPrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != ScalarSize {
+		return nil, fmt.Errorf("invalid private key length %d, expected %d", len(b), ScalarSize)
+	}
+	d := new(big.Int).SetBytes(b)
+	if d.Sign() == 0 || d.Cmp(r) >= 0 {
+		return nil, fmt.Errorf("private key is out of range")
+	}
+	return &PrivateKey{d: d}, nil
+}
+
+// Bytes returns the 32 big-endian bytes of the private key
+func (k *PrivateKey) Bytes() []byte {
+	return k.d.FillBytes(make([]byte, ScalarSize))
+}
+
+// PublicKey returns d * G1
+func (k *PrivateKey) PublicKey() *G1Point {
+	return G1Generator().Mul(k.d)
+}
+
+// Sign returns d * H(msg), H is HashToG2 with SignatureDST
+func (k *PrivateKey) Sign(msg []byte) *G2Point {
+	return hashMessage(msg, SignatureDST).Mul(k.d)
+}
+
+// ProvePossession signs the compressed public key with PopDST
+func (k *PrivateKey) ProvePossession() *G2Point {
+	return hashMessage(k.PublicKey().Bytes(), PopDST).Mul(k.d)
+}
+
+// Verify checks e(publicKey, H(msg)) == e(G1, signature)
+func Verify(publicKey *G1Point, msg []byte, signature *G2Point) bool {
+	if publicKey.IsInfinity() {
+		return false
+	}
+	return verify([]*G1Point{publicKey}, [][]byte{msg}, signature, SignatureDST)
+}
+
+// VerifyPossession checks the proof of possession of the public key
+func VerifyPossession(publicKey *G1Point, proof *G2Point) bool {
+	if publicKey.IsInfinity() {
+		return false
+	}
+	return verify([]*G1Point{publicKey}, [][]byte{publicKey.Bytes()}, proof, PopDST)
+}
+
+// AggregateSignatures returns the sum of the signatures
+func AggregateSignatures(signatures ...*G2Point) *G2Point {
+	sum := G2Infinity()
+	for _, s := range signatures {
+		sum = sum.Add(s)
+	}
+	return sum
+}
+
+// AggregatePublicKeys returns the sum of the public keys
+func AggregatePublicKeys(publicKeys ...*G1Point) *G1Point {
+	sum := G1Infinity()
+	for _, pk := range publicKeys {
+		sum = sum.Add(pk)
+	}
+	return sum
+}
+
+// FastAggregateVerify checks an aggregated signature of the same message, the public keys must have been
+// checked with VerifyPossession
+func FastAggregateVerify(publicKeys []*G1Point, msg []byte, signature *G2Point) bool {
+	if len(publicKeys) == 0 {
+		return false
+	}
+	return Verify(AggregatePublicKeys(publicKeys...), msg, signature)
+}
+
+// AggregateVerify checks an aggregated signature of different messages, msgs[i] is signed by publicKeys[i]
+func AggregateVerify(publicKeys []*G1Point, msgs [][]byte, signature *G2Point) bool {
+	if len(publicKeys) == 0 || len(publicKeys) != len(msgs) {
+		return false
+	}
+	for _, pk := range publicKeys {
+		if pk.IsInfinity() {
+			return false
+		}
+	}
+	return verify(publicKeys, msgs, signature, SignatureDST)
+}
+
+// verify checks e(-G1, signature) * ∏ e(publicKeys[i], H(msgs[i])) == 1
+func verify(publicKeys []*G1Point, msgs [][]byte, signature *G2Point, dst string) bool {
+	as := []*G1Point{G1Generator().Neg()}
+	bs := []*G2Point{signature}
+	for i := range publicKeys {
+		as = append(as, publicKeys[i])
+		bs = append(bs, hashMessage(msgs[i], dst))
+	}
+	return PairingCheck(as, bs)
+}
+
+// hashMessage cannot fail, the domain separation tags are short
+func hashMessage(msg []byte, dst string) *G2Point {
+	h, _ := HashToG2(msg, []byte(dst))
+	return h
+}
+
+// ScalarToBytes encodes k mod r as the 32 little-endian bytes of CryptoLib.bls12381Mul
+func ScalarToBytes(k *big.Int) []byte {
+	b := new(big.Int).Mod(k, r).FillBytes(make([]byte, ScalarSize))
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
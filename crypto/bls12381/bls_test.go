@@ -0,0 +1,70 @@
+package bls12381
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivateKey_Sign(t *testing.T) {
+	k, err := GeneratePrivateKey()
+	assert.Nil(t, err)
+	k2, err := NewPrivateKey(k.Bytes())
+	assert.Nil(t, err)
+	assert.True(t, k.PublicKey().Equal(k2.PublicKey()))
+
+	msg := []byte("oracle response")
+	sig := k.Sign(msg)
+	assert.True(t, Verify(k.PublicKey(), msg, sig))
+	assert.False(t, Verify(k.PublicKey(), []byte("other"), sig))
+	assert.False(t, Verify(G1Generator(), msg, sig))
+	assert.False(t, Verify(G1Infinity(), msg, G2Infinity()))
+
+	// a proof of possession is not a signature of the public key
+	proof := k.ProvePossession()
+	assert.True(t, VerifyPossession(k.PublicKey(), proof))
+	assert.False(t, Verify(k.PublicKey(), k.PublicKey().Bytes(), proof))
+}
+
+func TestNewPrivateKey(t *testing.T) {
+	_, err := NewPrivateKey(make([]byte, 31))
+	assert.NotNil(t, err)
+	_, err = NewPrivateKey(make([]byte, 32))
+	assert.NotNil(t, err)
+	_, err = NewPrivateKey(r.FillBytes(make([]byte, 32)))
+	assert.NotNil(t, err)
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+	msg := []byte("block 100")
+	var pks []*G1Point
+	var sigs []*G2Point
+	for i := 1; i <= 3; i++ {
+		k, _ := NewPrivateKey(big.NewInt(int64(i * 1000)).FillBytes(make([]byte, 32)))
+		pks = append(pks, k.PublicKey())
+		sigs = append(sigs, k.Sign(msg))
+	}
+	agg := AggregateSignatures(sigs...)
+	assert.True(t, FastAggregateVerify(pks, msg, agg))
+	assert.False(t, FastAggregateVerify(pks[:2], msg, agg))
+	assert.False(t, FastAggregateVerify(nil, msg, agg))
+}
+
+func TestAggregateVerify(t *testing.T) {
+	msgs := [][]byte{[]byte("a"), []byte("b")}
+	k1, _ := NewPrivateKey(big.NewInt(1).FillBytes(make([]byte, 32)))
+	k2, _ := NewPrivateKey(big.NewInt(2).FillBytes(make([]byte, 32)))
+	agg := AggregateSignatures(k1.Sign(msgs[0]), k2.Sign(msgs[1]))
+	pks := []*G1Point{k1.PublicKey(), k2.PublicKey()}
+	assert.True(t, AggregateVerify(pks, msgs, agg))
+	assert.False(t, AggregateVerify(pks, [][]byte{msgs[1], msgs[0]}, agg))
+	assert.False(t, AggregateVerify(pks, msgs[:1], agg))
+}
+
+func TestScalarToBytes(t *testing.T) {
+	b := ScalarToBytes(big.NewInt(0x0102))
+	assert.Equal(t, 32, len(b))
+	assert.Equal(t, []byte{2, 1, 0}, b[:3])
+	assert.Equal(t, ScalarToBytes(big.NewInt(-1)), ScalarToBytes(new(big.Int).Sub(r, big.NewInt(1))))
+}
@@ -0,0 +1,166 @@
+package bls12381
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// field is fp for G1 and fp2 for G2
+type field[F any] interface {
+	add(F) F
+	sub(F) F
+	mul(F) F
+	square() F
+	neg() F
+	inverse() F
+	isZero() bool
+	equal(F) bool
+	sqrt() (F, bool)
+	lexicographicallyLargest() bool
+	bytes() []byte
+}
+
+// point is an affine point on y² = x³ + b, the coordinates are ignored for the point at infinity
+type point[F field[F]] struct {
+	x, y     F
+	infinity bool
+}
+
+func (a point[F]) isOnCurve(b F) bool {
+	if a.infinity {
+		return true
+	}
+	return a.y.square().equal(a.x.square().mul(a.x).add(b))
+}
+
+func (a point[F]) equal(b point[F]) bool {
+	if a.infinity || b.infinity {
+		return a.infinity == b.infinity
+	}
+	return a.x.equal(b.x) && a.y.equal(b.y)
+}
+
+func (a point[F]) neg() point[F] {
+	if a.infinity {
+		return a
+	}
+	return point[F]{x: a.x, y: a.y.neg()}
+}
+
+func (a point[F]) double() point[F] {
+	if a.infinity || a.y.isZero() {
+		return point[F]{infinity: true}
+	}
+	// λ = 3x² / 2y
+	x2 := a.x.square()
+	l := x2.add(x2).add(x2).mul(a.y.add(a.y).inverse())
+	return a.fromLambda(l, a.x)
+}
+
+func (a point[F]) add(b point[F]) point[F] {
+	if a.infinity {
+		return b
+	}
+	if b.infinity {
+		return a
+	}
+	if a.x.equal(b.x) {
+		if a.y.equal(b.y) {
+			return a.double()
+		}
+		return point[F]{infinity: true}
+	}
+	// λ = (y2 - y1) / (x2 - x1)
+	l := b.y.sub(a.y).mul(b.x.sub(a.x).inverse())
+	return a.fromLambda(l, b.x)
+}
+
+// fromLambda returns (x3, y3), x3 = λ² - x1 - x2, y3 = λ(x1 - x3) - y1
+func (a point[F]) fromLambda(l, x2 F) point[F] {
+	x3 := l.square().sub(a.x).sub(x2)
+	y3 := l.mul(a.x.sub(x3)).sub(a.y)
+	return point[F]{x: x3, y: y3}
+}
+
+// mul returns k * a, k may be negative
+func (a point[F]) mul(k *big.Int) point[F] {
+	res := point[F]{infinity: true}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		res = res.double()
+		if k.Bit(i) == 1 {
+			res = res.add(a)
+		}
+	}
+	if k.Sign() < 0 {
+		return res.neg()
+	}
+	return res
+}
+
+func (a point[F]) isTorsionFree() bool {
+	return a.mul(r).infinity
+}
+
+// compressed flags in the first byte, the same as zkcrypto/bls12_381 and Neo
+const (
+	flagCompressed byte = 0x80
+	flagInfinity   byte = 0x40
+	flagSort       byte = 0x20
+	flagMask            = flagCompressed | flagInfinity | flagSort
+)
+
+// compress writes x with the flags, the sort flag is set if y is the lexicographically largest root
+func (a point[F]) compress(size int) []byte {
+	if a.infinity {
+		b := make([]byte, size)
+		b[0] = flagCompressed | flagInfinity
+		return b
+	}
+	b := a.x.bytes()
+	b[0] |= flagCompressed
+	if a.y.lexicographicallyLargest() {
+		b[0] |= flagSort
+	}
+	return b
+}
+
+// decompress reads a compressed point and checks that it is on the curve and in the subgroup of order r
+func decompress[F field[F]](data []byte, size int, b F, fromBytes func([]byte) (F, bool)) (point[F], error) {
+	if len(data) != size {
+		return point[F]{}, fmt.Errorf("invalid compressed point length %d, expected %d", len(data), size)
+	}
+	flags := data[0] & flagMask
+	if flags&flagCompressed == 0 {
+		return point[F]{}, fmt.Errorf("compression flag is not set")
+	}
+	buf := make([]byte, size)
+	copy(buf, data)
+	buf[0] &^= flagMask
+	if flags&flagInfinity != 0 {
+		if flags&flagSort != 0 {
+			return point[F]{}, fmt.Errorf("sort flag is set for the point at infinity")
+		}
+		for _, c := range buf {
+			if c != 0 {
+				return point[F]{}, fmt.Errorf("non-zero coordinate for the point at infinity")
+			}
+		}
+		return point[F]{infinity: true}, nil
+	}
+	x, ok := fromBytes(buf)
+	if !ok {
+		return point[F]{}, fmt.Errorf("x coordinate is not in the field")
+	}
+	y, ok := x.square().mul(x).add(b).sqrt()
+	if !ok {
+		return point[F]{}, fmt.Errorf("point is not on the curve")
+	}
+	if y.lexicographicallyLargest() != (flags&flagSort != 0) {
+		y = y.neg()
+	}
+	a := point[F]{x: x, y: y}
+	if !a.isTorsionFree() {
+		return point[F]{}, fmt.Errorf("point is not in the subgroup")
+	}
+	return a, nil
+}
@@ -0,0 +1,183 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+// fpSize is the length of a serialized base field element
+const fpSize = 48
+
+var (
+	// p is the modulus of the base field
+	p, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	// r is the order of G1, G2 and GT
+	r, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+	pMinus1Over2 = new(big.Int).Rsh(p, 1)
+	pPlus1Over4  = new(big.Int).Rsh(new(big.Int).Add(p, big.NewInt(1)), 2)
+	pMinus3Over4 = new(big.Int).Rsh(new(big.Int).Sub(p, big.NewInt(3)), 2)
+)
+
+// fp is an element of the base field, the value is always reduced and never modified after creation
+type fp struct {
+	v *big.Int
+}
+
+func newFp(x *big.Int) fp {
+	return fp{v: new(big.Int).Mod(x, p)}
+}
+
+func fpFromInt(x int64) fp {
+	return newFp(big.NewInt(x))
+}
+
+func fpFromHex(s string) fp {
+	x, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid field element " + s)
+	}
+	return newFp(x)
+}
+
+// fpFromBytes reads a big-endian element, it fails if the value is not less than p
+func fpFromBytes(b []byte) (fp, bool) {
+	x := new(big.Int).SetBytes(b)
+	if x.Cmp(p) >= 0 {
+		return fp{}, false
+	}
+	return fp{v: x}, true
+}
+
+func (a fp) bytes() []byte {
+	return a.v.FillBytes(make([]byte, fpSize))
+}
+
+func (a fp) add(b fp) fp     { return newFp(new(big.Int).Add(a.v, b.v)) }
+func (a fp) sub(b fp) fp     { return newFp(new(big.Int).Sub(a.v, b.v)) }
+func (a fp) mul(b fp) fp     { return newFp(new(big.Int).Mul(a.v, b.v)) }
+func (a fp) square() fp      { return a.mul(a) }
+func (a fp) neg() fp         { return newFp(new(big.Int).Neg(a.v)) }
+func (a fp) isZero() bool    { return a.v.Sign() == 0 }
+func (a fp) equal(b fp) bool { return a.v.Cmp(b.v) == 0 }
+
+// inverse returns 0 for 0
+func (a fp) inverse() fp {
+	if a.isZero() {
+		return a
+	}
+	return fp{v: new(big.Int).ModInverse(a.v, p)}
+}
+
+func (a fp) exp(e *big.Int) fp {
+	return fp{v: new(big.Int).Exp(a.v, e, p)}
+}
+
+// sqrt returns a square root of a, p = 3 mod 4
+func (a fp) sqrt() (fp, bool) {
+	s := a.exp(pPlus1Over4)
+	return s, s.square().equal(a)
+}
+
+// lexicographicallyLargest is the sort flag of compressed points, true if a > (p - 1) / 2
+func (a fp) lexicographicallyLargest() bool {
+	return a.v.Cmp(pMinus1Over2) > 0
+}
+
+// fp2 is c0 + c1 * i, i² = -1
+type fp2 struct {
+	c0, c1 fp
+}
+
+func fp2Zero() fp2 { return fp2{fpFromInt(0), fpFromInt(0)} }
+func fp2One() fp2  { return fp2{fpFromInt(1), fpFromInt(0)} }
+
+func fp2FromHex(c0, c1 string) fp2 {
+	return fp2{fpFromHex(c0), fpFromHex(c1)}
+}
+
+// fp2FromBytes reads c1 || c0
+func fp2FromBytes(b []byte) (fp2, bool) {
+	c1, ok1 := fpFromBytes(b[:fpSize])
+	c0, ok0 := fpFromBytes(b[fpSize : 2*fpSize])
+	return fp2{c0, c1}, ok0 && ok1
+}
+
+// bytes writes c1 || c0
+func (a fp2) bytes() []byte {
+	return append(a.c1.bytes(), a.c0.bytes()...)
+}
+
+func (a fp2) add(b fp2) fp2 { return fp2{a.c0.add(b.c0), a.c1.add(b.c1)} }
+func (a fp2) sub(b fp2) fp2 { return fp2{a.c0.sub(b.c0), a.c1.sub(b.c1)} }
+func (a fp2) neg() fp2      { return fp2{a.c0.neg(), a.c1.neg()} }
+func (a fp2) isZero() bool  { return a.c0.isZero() && a.c1.isZero() }
+func (a fp2) equal(b fp2) bool {
+	return a.c0.equal(b.c0) && a.c1.equal(b.c1)
+}
+
+func (a fp2) mul(b fp2) fp2 {
+	return fp2{
+		a.c0.mul(b.c0).sub(a.c1.mul(b.c1)),
+		a.c0.mul(b.c1).add(a.c1.mul(b.c0)),
+	}
+}
+
+func (a fp2) square() fp2 { return a.mul(a) }
+
+func (a fp2) mulByFp(b fp) fp2 { return fp2{a.c0.mul(b), a.c1.mul(b)} }
+
+// mulByNonResidue multiplies by ξ = 1 + i, v³ = ξ in fp6
+func (a fp2) mulByNonResidue() fp2 {
+	return fp2{a.c0.sub(a.c1), a.c0.add(a.c1)}
+}
+
+// conjugate is also the Frobenius map of fp2
+func (a fp2) conjugate() fp2 { return fp2{a.c0, a.c1.neg()} }
+
+// norm returns c0² + c1²
+func (a fp2) norm() fp {
+	return a.c0.square().add(a.c1.square())
+}
+
+// inverse returns 0 for 0
+func (a fp2) inverse() fp2 {
+	return a.conjugate().mulByFp(a.norm().inverse())
+}
+
+func (a fp2) exp(e *big.Int) fp2 {
+	res := fp2One()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = res.square()
+		if e.Bit(i) == 1 {
+			res = res.mul(a)
+		}
+	}
+	return res
+}
+
+// sqrt returns a square root of a, algorithm 9 of https://eprint.iacr.org/2012/685.pdf
+func (a fp2) sqrt() (fp2, bool) {
+	if a.isZero() {
+		return a, true
+	}
+	a1 := a.exp(pMinus3Over4)
+	alpha := a1.square().mul(a)
+	x0 := a1.mul(a)
+	var s fp2
+	if alpha.equal(fp2One().neg()) {
+		s = fp2{x0.c1.neg(), x0.c0}
+	} else {
+		s = alpha.add(fp2One()).exp(pMinus1Over2).mul(x0)
+	}
+	return s, s.square().equal(a)
+}
+
+// lexicographicallyLargest compares c1 first, then c0 if c1 is zero
+func (a fp2) lexicographicallyLargest() bool {
+	return a.c1.lexicographicallyLargest() || (a.c1.isZero() && a.c0.lexicographicallyLargest())
+}
+
+// sgn0 is defined in RFC 9380, section 4.1
+func (a fp2) sgn0() bool {
+	return a.c0.v.Bit(0) == 1 || (a.c0.isZero() && a.c1.v.Bit(0) == 1)
+}
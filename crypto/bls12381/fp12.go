@@ -0,0 +1,128 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+// fp6 is c0 + c1 * v + c2 * v², v³ = ξ = 1 + i
+type fp6 struct {
+	c0, c1, c2 fp2
+}
+
+func fp6Zero() fp6 { return fp6{fp2Zero(), fp2Zero(), fp2Zero()} }
+func fp6One() fp6  { return fp6{fp2One(), fp2Zero(), fp2Zero()} }
+
+// fp6FromBytes reads c2 || c1 || c0
+func fp6FromBytes(b []byte) (fp6, bool) {
+	c2, ok2 := fp2FromBytes(b[:2*fpSize])
+	c1, ok1 := fp2FromBytes(b[2*fpSize : 4*fpSize])
+	c0, ok0 := fp2FromBytes(b[4*fpSize : 6*fpSize])
+	return fp6{c0, c1, c2}, ok0 && ok1 && ok2
+}
+
+// bytes writes c2 || c1 || c0
+func (a fp6) bytes() []byte {
+	b := append(a.c2.bytes(), a.c1.bytes()...)
+	return append(b, a.c0.bytes()...)
+}
+
+func (a fp6) add(b fp6) fp6 { return fp6{a.c0.add(b.c0), a.c1.add(b.c1), a.c2.add(b.c2)} }
+func (a fp6) sub(b fp6) fp6 { return fp6{a.c0.sub(b.c0), a.c1.sub(b.c1), a.c2.sub(b.c2)} }
+func (a fp6) neg() fp6      { return fp6{a.c0.neg(), a.c1.neg(), a.c2.neg()} }
+func (a fp6) isZero() bool  { return a.c0.isZero() && a.c1.isZero() && a.c2.isZero() }
+func (a fp6) equal(b fp6) bool {
+	return a.c0.equal(b.c0) && a.c1.equal(b.c1) && a.c2.equal(b.c2)
+}
+
+func (a fp6) mul(b fp6) fp6 {
+	return fp6{
+		a.c0.mul(b.c0).add(a.c1.mul(b.c2).add(a.c2.mul(b.c1)).mulByNonResidue()),
+		a.c0.mul(b.c1).add(a.c1.mul(b.c0)).add(a.c2.mul(b.c2).mulByNonResidue()),
+		a.c0.mul(b.c2).add(a.c1.mul(b.c1)).add(a.c2.mul(b.c0)),
+	}
+}
+
+func (a fp6) square() fp6 { return a.mul(a) }
+
+// mulByV multiplies by v, w² = v in fp12
+func (a fp6) mulByV() fp6 {
+	return fp6{a.c2.mulByNonResidue(), a.c0, a.c1}
+}
+
+func (a fp6) inverse() fp6 {
+	t0 := a.c0.square().sub(a.c1.mul(a.c2).mulByNonResidue())
+	t1 := a.c2.square().mulByNonResidue().sub(a.c0.mul(a.c1))
+	t2 := a.c1.square().sub(a.c0.mul(a.c2))
+	d := a.c0.mul(t0).add(a.c2.mul(t1).add(a.c1.mul(t2)).mulByNonResidue()).inverse()
+	return fp6{t0.mul(d), t1.mul(d), t2.mul(d)}
+}
+
+// fp12 is c0 + c1 * w, w² = v
+type fp12 struct {
+	c0, c1 fp6
+}
+
+func fp12One() fp12 { return fp12{fp6One(), fp6Zero()} }
+
+// fp12FromBytes reads c1 || c0
+func fp12FromBytes(b []byte) (fp12, bool) {
+	c1, ok1 := fp6FromBytes(b[:6*fpSize])
+	c0, ok0 := fp6FromBytes(b[6*fpSize : 12*fpSize])
+	return fp12{c0, c1}, ok0 && ok1
+}
+
+// bytes writes c1 || c0
+func (a fp12) bytes() []byte {
+	return append(a.c1.bytes(), a.c0.bytes()...)
+}
+
+func (a fp12) equal(b fp12) bool { return a.c0.equal(b.c0) && a.c1.equal(b.c1) }
+func (a fp12) isOne() bool       { return a.equal(fp12One()) }
+
+func (a fp12) mul(b fp12) fp12 {
+	return fp12{
+		a.c0.mul(b.c0).add(a.c1.mul(b.c1).mulByV()),
+		a.c0.mul(b.c1).add(a.c1.mul(b.c0)),
+	}
+}
+
+func (a fp12) square() fp12 { return a.mul(a) }
+
+// conjugate is the p⁶ power of a, and the inverse of a unitary element
+func (a fp12) conjugate() fp12 { return fp12{a.c0, a.c1.neg()} }
+
+func (a fp12) inverse() fp12 {
+	d := a.c0.square().sub(a.c1.square().mulByV()).inverse()
+	return fp12{a.c0.mul(d), a.c1.mul(d).neg()}
+}
+
+func (a fp12) exp(e *big.Int) fp12 {
+	res := fp12One()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = res.square()
+		if e.Bit(i) == 1 {
+			res = res.mul(a)
+		}
+	}
+	return res
+}
+
+// frobeniusCoefficients[k] = ξ^(k * (p - 1) / 6), (w^k)^p = w^k * frobeniusCoefficients[k]
+var frobeniusCoefficients = func() [6]fp2 {
+	var c [6]fp2
+	e := new(big.Int).Div(new(big.Int).Sub(p, big.NewInt(1)), big.NewInt(6))
+	xi := fp2One().mulByNonResidue()
+	for k := 0; k < 6; k++ {
+		c[k] = xi.exp(new(big.Int).Mul(e, big.NewInt(int64(k))))
+	}
+	return c
+}()
+
+// frobenius returns a^p, the coefficient of v^j * w^i is the coefficient of w^(i + 2j)
+func (a fp12) frobenius() fp12 {
+	f := frobeniusCoefficients
+	return fp12{
+		fp6{a.c0.c0.conjugate(), a.c0.c1.conjugate().mul(f[2]), a.c0.c2.conjugate().mul(f[4])},
+		fp6{a.c1.c0.conjugate().mul(f[1]), a.c1.c1.conjugate().mul(f[3]), a.c1.c2.conjugate().mul(f[5])},
+	}
+}
@@ -0,0 +1,66 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+// G1Size is the length of a compressed G1 point, the encoding accepted by CryptoLib.bls12381Deserialize
+const G1Size = fpSize
+
+var (
+	g1B         = fpFromInt(4)
+	g1Generator = point[fp]{
+		x: fpFromHex("17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"),
+		y: fpFromHex("08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1"),
+	}
+)
+
+// G1Point is a point of the subgroup of order r on y² = x³ + 4 over Fp, the public keys are G1 points
+type G1Point struct {
+	p point[fp]
+}
+
+// G1Generator returns the generator of G1
+func G1Generator() *G1Point {
+	return &G1Point{p: g1Generator}
+}
+
+// G1Infinity returns the identity of G1
+func G1Infinity() *G1Point {
+	return &G1Point{p: point[fp]{infinity: true}}
+}
+
+// NewG1PointFromBytes decodes a compressed G1 point, it fails if the point is not in G1
+func NewG1PointFromBytes(data []byte) (*G1Point, error) {
+	a, err := decompress(data, G1Size, g1B, fpFromBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &G1Point{p: a}, nil
+}
+
+// Bytes returns the compressed point, the output of CryptoLib.bls12381Serialize
+func (g *G1Point) Bytes() []byte {
+	return g.p.compress(G1Size)
+}
+
+func (g *G1Point) Add(other *G1Point) *G1Point {
+	return &G1Point{p: g.p.add(other.p)}
+}
+
+func (g *G1Point) Neg() *G1Point {
+	return &G1Point{p: g.p.neg()}
+}
+
+// Mul returns k * g, the same as CryptoLib.bls12381Mul
+func (g *G1Point) Mul(k *big.Int) *G1Point {
+	return &G1Point{p: g.p.mul(new(big.Int).Mod(k, r))}
+}
+
+func (g *G1Point) Equal(other *G1Point) bool {
+	return g.p.equal(other.p)
+}
+
+func (g *G1Point) IsInfinity() bool {
+	return g.p.infinity
+}
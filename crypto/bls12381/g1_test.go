@@ -0,0 +1,67 @@
+package bls12381
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+const g1GeneratorHex = "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+
+func TestG1Point_Bytes(t *testing.T) {
+	g := G1Generator()
+	assert.Equal(t, g1GeneratorHex, helper.BytesToHex(g.Bytes()))
+	assert.Equal(t, "c0"+helper.BytesToHex(make([]byte, G1Size-1)), helper.BytesToHex(G1Infinity().Bytes()))
+
+	for _, k := range []int64{1, 2, 3, -1, 1000} {
+		a := g.Mul(big.NewInt(k))
+		b, err := NewG1PointFromBytes(a.Bytes())
+		assert.Nil(t, err)
+		assert.True(t, a.Equal(b))
+	}
+	b, err := NewG1PointFromBytes(G1Infinity().Bytes())
+	assert.Nil(t, err)
+	assert.True(t, b.IsInfinity())
+}
+
+func TestNewG1PointFromBytes(t *testing.T) {
+	valid := helper.HexToBytes(g1GeneratorHex)
+	_, err := NewG1PointFromBytes(valid[1:])
+	assert.NotNil(t, err)
+
+	// uncompressed flag
+	b := append([]byte{}, valid...)
+	b[0] &^= 0x80
+	_, err = NewG1PointFromBytes(b)
+	assert.NotNil(t, err)
+
+	// infinity with a coordinate
+	b = append([]byte{}, valid...)
+	b[0] |= 0x40
+	_, err = NewG1PointFromBytes(b)
+	assert.NotNil(t, err)
+
+	// x = 0 is not on the curve, y² = 4 has roots but (0, 2) is not in G1
+	b = make([]byte, G1Size)
+	b[0] = 0x80
+	_, err = NewG1PointFromBytes(b)
+	assert.NotNil(t, err)
+
+	// x >= p
+	b = p.FillBytes(make([]byte, G1Size))
+	b[0] |= 0x80
+	_, err = NewG1PointFromBytes(b)
+	assert.NotNil(t, err)
+}
+
+func TestG1Point_Add(t *testing.T) {
+	g := G1Generator()
+	assert.True(t, g.Add(g).Equal(g.Mul(big.NewInt(2))))
+	assert.True(t, g.Add(g.Neg()).IsInfinity())
+	assert.True(t, g.Add(G1Infinity()).Equal(g))
+	assert.True(t, g.Mul(r).IsInfinity())
+	assert.True(t, g.Mul(new(big.Int).Add(r, big.NewInt(5))).Equal(g.Mul(big.NewInt(5))))
+}
@@ -0,0 +1,70 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+// G2Size is the length of a compressed G2 point, the encoding accepted by CryptoLib.bls12381Deserialize
+const G2Size = 2 * fpSize
+
+var (
+	g2B         = fp2FromHex("4", "4")
+	g2Generator = point[fp2]{
+		x: fp2FromHex(
+			"024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8",
+			"13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e"),
+		y: fp2FromHex(
+			"0ce5d527727d6e118cc9cdc6da2e351aadfd9baa8cbdd3a76d429a695160d12c923ac9cc3baca289e193548608b82801",
+			"0606c4a02ea734cc32acd2b02bc28b99cb3e287e85a763af267492ab572e99ab3f370d275cec1da1aaa9075ff05f79be"),
+	}
+)
+
+// G2Point is a point of the subgroup of order r on y² = x³ + 4(1 + i) over Fp2, the signatures are G2 points
+type G2Point struct {
+	p point[fp2]
+}
+
+// G2Generator returns the generator of G2
+func G2Generator() *G2Point {
+	return &G2Point{p: g2Generator}
+}
+
+// G2Infinity returns the identity of G2
+func G2Infinity() *G2Point {
+	return &G2Point{p: point[fp2]{infinity: true}}
+}
+
+// NewG2PointFromBytes decodes a compressed G2 point, x.c1 || x.c0, it fails if the point is not in G2
+func NewG2PointFromBytes(data []byte) (*G2Point, error) {
+	a, err := decompress(data, G2Size, g2B, fp2FromBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &G2Point{p: a}, nil
+}
+
+// Bytes returns the compressed point, the output of CryptoLib.bls12381Serialize
+func (g *G2Point) Bytes() []byte {
+	return g.p.compress(G2Size)
+}
+
+func (g *G2Point) Add(other *G2Point) *G2Point {
+	return &G2Point{p: g.p.add(other.p)}
+}
+
+func (g *G2Point) Neg() *G2Point {
+	return &G2Point{p: g.p.neg()}
+}
+
+// Mul returns k * g, the same as CryptoLib.bls12381Mul
+func (g *G2Point) Mul(k *big.Int) *G2Point {
+	return &G2Point{p: g.p.mul(new(big.Int).Mod(k, r))}
+}
+
+func (g *G2Point) Equal(other *G2Point) bool {
+	return g.p.equal(other.p)
+}
+
+func (g *G2Point) IsInfinity() bool {
+	return g.p.infinity
+}
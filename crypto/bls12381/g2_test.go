@@ -0,0 +1,45 @@
+package bls12381
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+const g2GeneratorHex = "93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8"
+
+func TestG2Point_Bytes(t *testing.T) {
+	g := G2Generator()
+	assert.Equal(t, g2GeneratorHex, helper.BytesToHex(g.Bytes()))
+
+	for _, k := range []int64{1, 2, -1, 1000} {
+		a := g.Mul(big.NewInt(k))
+		b, err := NewG2PointFromBytes(a.Bytes())
+		assert.Nil(t, err)
+		assert.True(t, a.Equal(b))
+	}
+	b, err := NewG2PointFromBytes(G2Infinity().Bytes())
+	assert.Nil(t, err)
+	assert.True(t, b.IsInfinity())
+}
+
+func TestNewG2PointFromBytes(t *testing.T) {
+	_, err := NewG2PointFromBytes(helper.HexToBytes(g1GeneratorHex))
+	assert.NotNil(t, err)
+
+	// a point on E2 which is not in G2
+	q := mapToCurve(fp2FromHex("1", "2"))
+	assert.True(t, q.isOnCurve(g2B))
+	_, err = NewG2PointFromBytes(q.compress(G2Size))
+	assert.NotNil(t, err)
+}
+
+func TestG2Point_Add(t *testing.T) {
+	g := G2Generator()
+	assert.True(t, g.Add(g).Equal(g.Mul(big.NewInt(2))))
+	assert.True(t, g.Add(g.Neg()).IsInfinity())
+	assert.True(t, g.Mul(r).IsInfinity())
+}
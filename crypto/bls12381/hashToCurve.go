@@ -0,0 +1,168 @@
+package bls12381
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// the suite BLS12381G2_XMD:SHA-256_SSWU_RO_ of RFC 9380, the simplified SWU map is applied on the 3-isogenous
+// curve y² = x³ + A'x + B' and the result is mapped to E2 with the isogeny
+var (
+	sswuA = fp2FromHex("0", "f0")
+	sswuB = fp2FromHex("3f4", "3f4")
+	sswuZ = fp2{fpFromInt(-2), fpFromInt(-1)}
+
+	isoXNum = []fp2{
+		fp2FromHex("5c759507e8e333ebb5b7a9a47d7ed8532c52d39fd3a042a88b58423c50ae15d5c2638e343d9c71c6238aaaaaaaa97d6",
+			"5c759507e8e333ebb5b7a9a47d7ed8532c52d39fd3a042a88b58423c50ae15d5c2638e343d9c71c6238aaaaaaaa97d6"),
+		fp2FromHex("0",
+			"11560bf17baa99bc32126fced787c88f984f87adf7ae0c7f9a208c6b4f20a4181472aaa9cb8d555526a9ffffffffc71a"),
+		fp2FromHex("11560bf17baa99bc32126fced787c88f984f87adf7ae0c7f9a208c6b4f20a4181472aaa9cb8d555526a9ffffffffc71e",
+			"8ab05f8bdd54cde190937e76bc3e447cc27c3d6fbd7063fcd104635a790520c0a395554e5c6aaaa9354ffffffffe38d"),
+		fp2FromHex("171d6541fa38ccfaed6dea691f5fb614cb14b4e7f4e810aa22d6108f142b85757098e38d0f671c7188e2aaaaaaaa5ed1",
+			"0"),
+	}
+	isoXDen = []fp2{
+		fp2FromHex("0",
+			"1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaa63"),
+		fp2FromHex("c",
+			"1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaa9f"),
+		fp2One(),
+	}
+	isoYNum = []fp2{
+		fp2FromHex("1530477c7ab4113b59a4c18b076d11930f7da5d4a07f649bf54439d87d27e500fc8c25ebf8c92f6812cfc71c71c6d706",
+			"1530477c7ab4113b59a4c18b076d11930f7da5d4a07f649bf54439d87d27e500fc8c25ebf8c92f6812cfc71c71c6d706"),
+		fp2FromHex("0",
+			"5c759507e8e333ebb5b7a9a47d7ed8532c52d39fd3a042a88b58423c50ae15d5c2638e343d9c71c6238aaaaaaaa97be"),
+		fp2FromHex("11560bf17baa99bc32126fced787c88f984f87adf7ae0c7f9a208c6b4f20a4181472aaa9cb8d555526a9ffffffffc71c",
+			"8ab05f8bdd54cde190937e76bc3e447cc27c3d6fbd7063fcd104635a790520c0a395554e5c6aaaa9354ffffffffe38f"),
+		fp2FromHex("124c9ad43b6cf79bfbf7043de3811ad0761b0f37a1e26286b0e977c69aa274524e79097a56dc4bd9e1b371c71c718b10",
+			"0"),
+	}
+	isoYDen = []fp2{
+		fp2FromHex("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffa8fb",
+			"1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffa8fb"),
+		fp2FromHex("0",
+			"1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffa9d3"),
+		fp2FromHex("12",
+			"1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaa99"),
+		fp2One(),
+	}
+
+	// g2HEff clears the cofactor of E2, section 8.8.2 of RFC 9380
+	g2HEff, _ = new(big.Int).SetString("bc69f08f2ee75b3584c6a0ea91b352888e2a8e9145ad7689986ff031508ffe1329c2f178731db956d82bf015d1212b02ec0ec69d7477c1ae954cbc06689f6a359894c0adebbf6b4e8020005aaa95551", 16)
+)
+
+// HashToG2 hashes msg to a G2 point with the suite BLS12381G2_XMD:SHA-256_SSWU_RO_ of RFC 9380, dst is the
+// domain separation tag
+func HashToG2(msg, dst []byte) (*G2Point, error) {
+	u, err := hashToFp2(msg, dst, 2)
+	if err != nil {
+		return nil, err
+	}
+	q := mapToCurve(u[0]).add(mapToCurve(u[1]))
+	return &G2Point{p: q.mul(g2HEff)}, nil
+}
+
+// hashToFp2 is hash_to_field with m = 2 and L = 64
+func hashToFp2(msg, dst []byte, count int) ([]fp2, error) {
+	const l = 64
+	b, err := expandMessageXmd(msg, dst, count*2*l)
+	if err != nil {
+		return nil, err
+	}
+	u := make([]fp2, count)
+	for i := range u {
+		c0 := new(big.Int).SetBytes(b[2*i*l : (2*i+1)*l])
+		c1 := new(big.Int).SetBytes(b[(2*i+1)*l : (2*i+2)*l])
+		u[i] = fp2{newFp(c0), newFp(c1)}
+	}
+	return u, nil
+}
+
+// expandMessageXmd is expand_message_xmd of RFC 9380 with SHA-256
+func expandMessageXmd(msg, dst []byte, length int) ([]byte, error) {
+	ell := (length + sha256.Size - 1) / sha256.Size
+	if ell > 255 || length > 65535 {
+		return nil, fmt.Errorf("requested length %d is too long", length)
+	}
+	if len(dst) > 255 {
+		return nil, fmt.Errorf("domain separation tag is longer than 255 bytes")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+	h := sha256.New()
+	h.Write(make([]byte, sha256.BlockSize))
+	h.Write(msg)
+	h.Write([]byte{byte(length >> 8), byte(length), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+	uniform := append([]byte{}, bi...)
+	for i := 2; i <= ell; i++ {
+		x := make([]byte, sha256.Size)
+		for j := range x {
+			x[j] = b0[j] ^ bi[j]
+		}
+		h.Reset()
+		h.Write(x)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		uniform = append(uniform, bi...)
+	}
+	return uniform[:length], nil
+}
+
+// mapToCurve is the simplified SWU map followed by the 3-isogeny, the result is on E2 but not in G2
+func mapToCurve(u fp2) point[fp2] {
+	zu2 := sswuZ.mul(u.square())
+	tv1 := zu2.square().add(zu2).inverse()
+	var x1 fp2
+	if tv1.isZero() {
+		x1 = sswuB.mul(sswuZ.mul(sswuA).inverse())
+	} else {
+		x1 = sswuB.neg().mul(sswuA.inverse()).mul(tv1.add(fp2One()))
+	}
+	x := x1
+	y, ok := sswuCurve(x1).sqrt()
+	if !ok {
+		x = zu2.mul(x1)
+		y, _ = sswuCurve(x).sqrt()
+	}
+	if u.sgn0() != y.sgn0() {
+		y = y.neg()
+	}
+	return isoMap(x, y)
+}
+
+// sswuCurve returns x³ + A'x + B'
+func sswuCurve(x fp2) fp2 {
+	return x.square().mul(x).add(sswuA.mul(x)).add(sswuB)
+}
+
+func isoMap(x, y fp2) point[fp2] {
+	xDen := polynomial(isoXDen, x)
+	yDen := polynomial(isoYDen, x)
+	if xDen.isZero() || yDen.isZero() {
+		return point[fp2]{infinity: true}
+	}
+	return point[fp2]{
+		x: polynomial(isoXNum, x).mul(xDen.inverse()),
+		y: y.mul(polynomial(isoYNum, x)).mul(yDen.inverse()),
+	}
+}
+
+// polynomial evaluates k[0] + k[1]x + k[2]x² + ...
+func polynomial(k []fp2, x fp2) fp2 {
+	res := k[len(k)-1]
+	for i := len(k) - 2; i >= 0; i-- {
+		res = res.mul(x).add(k[i])
+	}
+	return res
+}
@@ -0,0 +1,36 @@
+package bls12381
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// test vectors of RFC 9380, appendix K.1 and J.10.1
+func TestExpandMessageXmd(t *testing.T) {
+	b, err := expandMessageXmd([]byte{}, []byte("QUUX-V01-CS02-with-expander-SHA256-128"), 0x20)
+	assert.Nil(t, err)
+	assert.Equal(t, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235", helper.BytesToHex(b))
+
+	_, err = expandMessageXmd([]byte{}, make([]byte, 256), 0x20)
+	assert.NotNil(t, err)
+	_, err = expandMessageXmd([]byte{}, []byte("dst"), 256*32)
+	assert.NotNil(t, err)
+}
+
+func TestHashToG2(t *testing.T) {
+	h, err := HashToG2([]byte{}, []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_"))
+	assert.Nil(t, err)
+	expected := point[fp2]{
+		x: fp2FromHex(
+			"0141ebfbdca40eb85b87142e130ab689c673cf60f1a3e98d69335266f30d9b8d4ac44c1038e9dcdd5393faf5c41fb78a",
+			"05cb8437535e20ecffaef7752baddf98034139c38452458baeefab379ba13dff5bf5dd71b72418717047f5b0f37da03d"),
+		y: fp2FromHex(
+			"0503921d7f6a12805e72940b963c0cf3471c7b2a524950ca195d11062ee75ec076daf2d4bc358c4b190c0c98064fdd92",
+			"12424ac32561493f3fe3c260708a12b7c620e7be00099a974e259ddc7d1f6395c3c811cdd19f1e8dbf3e9ecfdcbab8d6"),
+	}
+	assert.True(t, h.p.equal(expected))
+	assert.True(t, h.p.isTorsionFree())
+}
@@ -0,0 +1,140 @@
+package bls12381
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// GTSize is the length of a serialized GT element, the encoding of CryptoLib.bls12381Serialize
+const GTSize = 12 * fpSize
+
+// absX is the absolute value of x, the parameter of BLS12-381, x is negative
+var absX, _ = new(big.Int).SetString("d201000000010000", 16)
+
+// GT is an element of the subgroup of order r of Fp12, the output of the pairing
+type GT struct {
+	f fp12
+}
+
+// GTOne returns the identity of GT
+func GTOne() *GT {
+	return &GT{f: fp12One()}
+}
+
+// NewGTFromBytes decodes a GT element, the coefficients are written from the highest to the lowest, each
+// Fp element is big-endian
+func NewGTFromBytes(data []byte) (*GT, error) {
+	if len(data) != GTSize {
+		return nil, fmt.Errorf("invalid GT length %d, expected %d", len(data), GTSize)
+	}
+	f, ok := fp12FromBytes(data)
+	if !ok {
+		return nil, fmt.Errorf("coefficient is not in the field")
+	}
+	return &GT{f: f}, nil
+}
+
+// Bytes returns the serialized element, the output of CryptoLib.bls12381Serialize
+func (g *GT) Bytes() []byte {
+	return g.f.bytes()
+}
+
+// Mul returns g * other, the same as CryptoLib.bls12381Add for GT
+func (g *GT) Mul(other *GT) *GT {
+	return &GT{f: g.f.mul(other.f)}
+}
+
+// Exp returns g^k, the same as CryptoLib.bls12381Mul for GT
+func (g *GT) Exp(k *big.Int) *GT {
+	k = new(big.Int).Mod(k, r)
+	return &GT{f: g.f.exp(k)}
+}
+
+// Inverse is the conjugate, GT elements are unitary
+func (g *GT) Inverse() *GT {
+	return &GT{f: g.f.conjugate()}
+}
+
+func (g *GT) Equal(other *GT) bool {
+	return g.f.equal(other.f)
+}
+
+func (g *GT) IsOne() bool {
+	return g.f.isOne()
+}
+
+// Pairing returns the optimal ate pairing e(a, b), the same as CryptoLib.bls12381Pairing
+func Pairing(a *G1Point, b *G2Point) *GT {
+	return &GT{f: finalExponentiation(millerLoop(a.p, b.p))}
+}
+
+// PairingCheck returns true if the product of e(as[i], bs[i]) is 1, it only does one final exponentiation
+func PairingCheck(as []*G1Point, bs []*G2Point) bool {
+	if len(as) != len(bs) {
+		return false
+	}
+	f := fp12One()
+	for i := range as {
+		f = f.mul(millerLoop(as[i].p, bs[i].p))
+	}
+	return finalExponentiation(f).isOne()
+}
+
+// millerLoop computes f_{x,Q}(P), Q stays on the twist and the lines are mapped to Fp12 with
+// ψ(x, y) = (x / w², y / w³). The lines are multiplied by w³ and the vertical lines are skipped,
+// both are in proper subfields and removed by the final exponentiation.
+func millerLoop(a point[fp], b point[fp2]) fp12 {
+	if a.infinity || b.infinity {
+		return fp12One()
+	}
+	f := fp12One()
+	t := b
+	for i := absX.BitLen() - 2; i >= 0; i-- {
+		x2 := t.x.square()
+		l := x2.add(x2).add(x2).mul(t.y.add(t.y).inverse())
+		f = f.square().mul(lineFunction(l, t, a))
+		t = t.double()
+		if absX.Bit(i) == 1 {
+			l = b.y.sub(t.y).mul(b.x.sub(t.x).inverse())
+			f = f.mul(lineFunction(l, t, a))
+			t = t.add(b)
+		}
+	}
+	// x is negative
+	return f.conjugate()
+}
+
+// lineFunction evaluates the line of slope l through t at a, (l * xt - yt) - l * xa * v + ya * v * w
+func lineFunction(l fp2, t point[fp2], a point[fp]) fp12 {
+	return fp12{
+		c0: fp6{l.mul(t.x).sub(t.y), l.mulByFp(a.x).neg(), fp2Zero()},
+		c1: fp6{fp2Zero(), fp2{a.y, fpFromInt(0)}, fp2Zero()},
+	}
+}
+
+// finalExponentiation returns f^(3(p¹² - 1) / r), the hard part is the addition chain of zkcrypto/bls12_381
+// (https://eprint.iacr.org/2016/130.pdf) which raises to 3(p⁴ - p² + 1) / r. Neo uses the same chain, so the
+// GT values of CryptoLib are the cubes of the plain pairing.
+func finalExponentiation(f fp12) fp12 {
+	// easy part, (p⁶ - 1)(p² + 1)
+	t2 := f.conjugate().mul(f.inverse())
+	t2 = t2.frobenius().frobenius().mul(t2)
+	// hard part
+	t1 := t2.square().conjugate()
+	t3 := expByX(t2)
+	t4 := t3.square()
+	t5 := t1.mul(t3)
+	t1 = expByX(t5)
+	t0 := expByX(t1)
+	t6 := expByX(t0).mul(t4)
+	t4 = expByX(t6).mul(t5.conjugate()).mul(t2)
+	t1 = t1.mul(t2).frobenius().frobenius().frobenius()
+	t6 = t6.mul(t2.conjugate()).frobenius()
+	t3 = t3.mul(t0).frobenius().frobenius().mul(t1).mul(t6)
+	return t3.mul(t4)
+}
+
+// expByX returns f^x for a unitary f, x is negative
+func expByX(f fp12) fp12 {
+	return f.exp(absX).conjugate()
+}
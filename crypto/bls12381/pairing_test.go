@@ -0,0 +1,49 @@
+package bls12381
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// e(G1, G2) serialized by CryptoLib
+const gtGeneratorHex = "0f41e58663bf08cf068672cbd01a7ec73baca4d72ca93544deff686bfd6df543d48eaa24afe47e1efde449383b67663104c581234d086a9902249b64728ffd21a189e87935a954051c7cdba7b3872629a4fafc05066245cb9108f0242d0fe3ef03350f55a7aefcd3c31b4fcb6ce5771cc6a0e9786ab5973320c806ad360829107ba810c5a09ffdd9be2291a0c25a99a211b8b424cd48bf38fcef68083b0b0ec5c81a93b330ee1a677d0d15ff7b984e8978ef48881e32fac91b93b47333e2ba5706fba23eb7c5af0d9f80940ca771b6ffd5857baaf222eb95a7d2809d61bfe02e1bfd1b68ff02f0b8102ae1c2d5d5ab1a19f26337d205fb469cd6bd15c3d5a04dc88784fbb3d0b2dbdea54d43b2b73f2cbb12d58386a8703e0f948226e47ee89d018107154f25a764bd3c79937a45b84546da634b8f6be14a8061e55cceba478b23f7dacaa35c8ca78beae9624045b4b601b2f522473d171391125ba84dc4007cfbf2f8da752f7c74185203fcca589ac719c34dffbbaad8431dad1c1fb597aaa5193502b86edb8857c273fa075a50512937e0794e1e65a7617c90d8bd66065b1fffe51d7a579973b1315021ec3c19934f1368bb445c7c2d209703f239689ce34c0378a68e72a6b3b216da0e22a5031b54ddff57309396b38c881c4c849ec23e87089a1c5b46e5110b86750ec6a532348868a84045483c92b7af5af689452eafabf1a8943e50439f1d59882a98eaa0170f1250ebd871fc0a92a7b2d83168d0d727272d441befa15c503dd8e90ce98db3e7b6d194f60839c508a84305aaca1789b6"
+
+func TestPairing(t *testing.T) {
+	e := Pairing(G1Generator(), G2Generator())
+	assert.Equal(t, gtGeneratorHex, helper.BytesToHex(e.Bytes()))
+	assert.True(t, e.Exp(r).IsOne())
+
+	// bilinearity
+	a := Pairing(G1Generator().Mul(big.NewInt(5)), G2Generator().Mul(big.NewInt(7)))
+	assert.True(t, a.Equal(e.Exp(big.NewInt(35))))
+	assert.True(t, Pairing(G1Generator().Neg(), G2Generator()).Equal(e.Inverse()))
+	assert.True(t, e.Mul(e.Inverse()).IsOne())
+
+	assert.True(t, Pairing(G1Infinity(), G2Generator()).IsOne())
+	assert.True(t, Pairing(G1Generator(), G2Infinity()).IsOne())
+}
+
+func TestPairingCheck(t *testing.T) {
+	g1, g2 := G1Generator(), G2Generator()
+	k := big.NewInt(12345)
+	assert.True(t, PairingCheck([]*G1Point{g1.Mul(k), g1.Neg()}, []*G2Point{g2, g2.Mul(k)}))
+	assert.False(t, PairingCheck([]*G1Point{g1.Mul(k), g1}, []*G2Point{g2, g2.Mul(k)}))
+	assert.False(t, PairingCheck([]*G1Point{g1}, nil))
+}
+
+func TestNewGTFromBytes(t *testing.T) {
+	e, err := NewGTFromBytes(helper.HexToBytes(gtGeneratorHex))
+	assert.Nil(t, err)
+	assert.True(t, e.Equal(Pairing(G1Generator(), G2Generator())))
+
+	_, err = NewGTFromBytes(make([]byte, GTSize-1))
+	assert.NotNil(t, err)
+	b := make([]byte, GTSize)
+	copy(b, p.Bytes())
+	_, err = NewGTFromBytes(b)
+	assert.NotNil(t, err)
+}
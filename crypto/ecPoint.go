@@ -50,6 +50,11 @@ func NewECPointFromBytes(data []byte) (*ECPoint, error) {
 	return FromBytes(data, &p256)
 }
 
+// NewSecp256k1PointFromBytes return a public key on secp256k1 created from the given []byte.
+func NewSecp256k1PointFromBytes(data []byte) (*ECPoint, error) {
+	return FromBytes(data, &Secp256k1)
+}
+
 // NewECPointFromString return a public key created from the given hex string.
 func NewECPointFromString(s string) (*ECPoint, error) {
 	b, err := hex.DecodeString(s)
@@ -127,53 +132,17 @@ func DecodePoint(encoded []byte, curve *elliptic.Curve) (*ECPoint, error) {
 	return p, nil
 }
 
-// y**2 = x**3 + a*x + b  % p, a = -3
-// x³ - 3x + b
-func decompressPoint(yTilde int, x *big.Int, curve *elliptic.Curve) (*ECPoint, error) {
-	A := big.NewInt(3)
-	B := (*curve).Params().B
-	P := (*curve).Params().P
+var errInvalidCompressedPoint = fmt.Errorf("error computing Y for compressed point")
 
-	xCubed := new(big.Int).Exp(x, A, P)          // x^2
-	threeX := new(big.Int).Mul(x, A)             // x^3
-	threeX.Mod(threeX, P)                        // 3*x
-	ySquared := new(big.Int).Sub(xCubed, threeX) // x^3 - 3*x
-	ySquared.Add(ySquared, B)                    // x^3 - 3*x + B
-	ySquared.Mod(ySquared, P)                    // x^3 - 3*x + B % P
-	y := new(big.Int).ModSqrt(ySquared, P)
-	if y == nil {
-		return nil, fmt.Errorf("error computing Y for compressed point")
-	}
-	if y.Bit(0) != uint(yTilde) {
-		y.Neg(y)
-		y.Mod(y, P)
+// decompressPoint computes Y from y² = x³ + a*x + b, a is -3 for secp256r1 and 0 for secp256k1
+func decompressPoint(yTilde int, x *big.Int, curve *elliptic.Curve) (*ECPoint, error) {
+	y, err := DecompressY(*curve, x, uint(yTilde))
+	if err != nil {
+		return nil, err
 	}
 	return CreateECPoint(x, y, curve)
 }
 
-// decodeCompressedY performs decompression of Y coordinate for given X and Y's least significant bit
-func decodeCompressedY(x *big.Int, ylsb uint) (*big.Int, error) {
-	c := elliptic.P256()
-	cp := c.Params()
-	three := big.NewInt(3)
-
-	xCubed := new(big.Int).Exp(x, three, cp.P)
-	threeX := new(big.Int).Mul(x, three)
-	threeX.Mod(threeX, cp.P)
-	ySquared := new(big.Int).Sub(xCubed, threeX)
-	ySquared.Add(ySquared, cp.B)
-	ySquared.Mod(ySquared, cp.P)
-	y := new(big.Int).ModSqrt(ySquared, cp.P)
-	if y == nil {
-		return nil, fmt.Errorf("error computing Y for compressed point")
-	}
-	if y.Bit(0) != ylsb {
-		y.Neg(y)
-		y.Mod(y, cp.P)
-	}
-	return y, nil
-}
-
 // Deserialize an ECPoint from the given io.Reader.
 func (p *ECPoint) Deserialize(br *io.BinaryReader) {
 	q, err := DeserializeFrom(br, &p.Curve)
@@ -4,8 +4,25 @@ import (
 	"crypto/sha256"
 
 	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
 )
 
+// HashAlgorithm is the hash of the message before it is signed with EcDSA
+type HashAlgorithm byte
+
+const (
+	HashSHA256 HashAlgorithm = iota
+	HashKeccak256
+)
+
+// Hash returns the hash value of b
+func (h HashAlgorithm) Hash(b []byte) []byte {
+	if h == HashKeccak256 {
+		return Keccak256(b)
+	}
+	return Sha256(b)
+}
+
 // Sha256 gets the SHA-256 hash value of b
 func Sha256(b []byte) []byte {
 	sha := sha256.New()
@@ -13,6 +30,13 @@ func Sha256(b []byte) []byte {
 	return sha.Sum(nil)
 }
 
+// Keccak256 gets the Keccak-256 hash value of b, the hash used by Ethereum, not SHA3-256
+func Keccak256(b []byte) []byte {
+	k := sha3.NewLegacyKeccak256()
+	k.Write(b)
+	return k.Sum(nil)
+}
+
 // Hash256 gets the twice SHA-256 hash value of ba
 func Hash256(ba []byte) []byte {
 	sha := sha256.New()
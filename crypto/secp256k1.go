@@ -0,0 +1,39 @@
+package crypto
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Secp256k1 is the curve of Bitcoin and Ethereum, supported by CryptoLib.verifyWithECDsa. The arithmetic is the
+// one of github.com/decred/dcrd/dcrec/secp256k1, which is also used for signing with secp256k1 keys.
+var Secp256k1 elliptic.Curve = secp256k1.S256()
+
+// curveA returns the coefficient a of the curve, a = 0 for secp256k1 and a = -3 for the NIST curves
+func curveA(curve elliptic.Curve) *big.Int {
+	if _, ok := curve.(*secp256k1.KoblitzCurve); ok {
+		return big.NewInt(0)
+	}
+	return big.NewInt(-3)
+}
+
+// DecompressY returns the Y coordinate of the point with X and the least significant bit of Y
+func DecompressY(curve elliptic.Curve, x *big.Int, ylsb uint) (*big.Int, error) {
+	cp := curve.Params()
+	// y² = x³ + a*x + b
+	ySquared := new(big.Int).Exp(x, big.NewInt(3), cp.P)
+	ySquared.Add(ySquared, new(big.Int).Mul(curveA(curve), x))
+	ySquared.Add(ySquared, cp.B)
+	ySquared.Mod(ySquared, cp.P)
+	y := new(big.Int).ModSqrt(ySquared, cp.P)
+	if y == nil {
+		return nil, errInvalidCompressedPoint
+	}
+	if y.Bit(0) != ylsb {
+		y.Neg(y)
+		y.Mod(y, cp.P)
+	}
+	return y, nil
+}
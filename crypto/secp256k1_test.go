@@ -0,0 +1,56 @@
+package crypto
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+func TestSecp256k1(t *testing.T) {
+	params := Secp256k1.Params()
+	assert.True(t, Secp256k1.IsOnCurve(params.Gx, params.Gy))
+	assert.False(t, Secp256k1.IsOnCurve(params.Gx, new(big.Int).Add(params.Gy, big.NewInt(1))))
+
+	// 2G
+	x, y := Secp256k1.Double(params.Gx, params.Gy)
+	assert.Equal(t, "c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5", helper.BytesToHex(x.Bytes()))
+	assert.Equal(t, "1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a", helper.BytesToHex(y.Bytes()))
+	x2, y2 := Secp256k1.ScalarBaseMult([]byte{2})
+	assert.Equal(t, x, x2)
+	assert.Equal(t, y, y2)
+	x3, y3 := Secp256k1.Add(x, y, params.Gx, params.Gy)
+	x4, y4 := Secp256k1.ScalarBaseMult([]byte{3})
+	assert.Equal(t, x3, x4)
+	assert.Equal(t, y3, y4)
+
+	// n * G is the point at infinity
+	x, y = Secp256k1.ScalarBaseMult(params.N.Bytes())
+	assert.Equal(t, 0, x.Sign())
+	assert.Equal(t, 0, y.Sign())
+}
+
+func TestNewSecp256k1PointFromBytes(t *testing.T) {
+	p, err := NewSecp256k1PointFromBytes(helper.HexToBytes("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"))
+	assert.Nil(t, err)
+	assert.Equal(t, Secp256k1.Params().Gy, p.Y)
+	assert.True(t, p.IsOnCurve())
+
+	q, err := NewSecp256k1PointFromBytes(p.EncodePoint(false))
+	assert.Nil(t, err)
+	assert.True(t, p.Equals(q))
+
+	// the same x on secp256r1 is another point
+	r, err := NewECPointFromBytes(helper.HexToBytes("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"))
+	if err == nil {
+		assert.False(t, r.Y.Cmp(p.Y) == 0)
+	}
+}
+
+func TestKeccak256(t *testing.T) {
+	assert.Equal(t, "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", helper.BytesToHex(Keccak256([]byte{})))
+	assert.Equal(t, Keccak256([]byte("neo")), HashKeccak256.Hash([]byte("neo")))
+	assert.Equal(t, Sha256([]byte("neo")), HashSHA256.Hash([]byte("neo")))
+}
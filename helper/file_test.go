@@ -0,0 +1,25 @@
+package helper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+	assert.Nil(t, WriteFileAtomic(path, []byte("old")))
+	assert.Nil(t, WriteFileAtomic(path, []byte("new")))
+	b, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "new", string(b))
+	// no temporary file is left
+	files, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+
+	assert.NotNil(t, WriteFileAtomic(filepath.Join(dir, "missing", "data.json"), []byte("new")))
+}
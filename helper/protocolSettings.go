@@ -1,17 +1,275 @@
 package helper
 
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
 const (
 	Neo3Magic_MainNet     uint32 = 860833102 // NEO3
 	Neo3Magic_TestNet     uint32 = 894710606 // N3T5
 	DefaultAddressVersion byte   = 0x35      // 53
 )
 
+const (
+	DefaultMillisecondsPerBlock        uint32 = 15000
+	DefaultMaxTransactionsPerBlock     uint32 = 512
+	DefaultMemoryPoolMaxTransactions   int    = 50000
+	DefaultMaxTraceableBlocks          uint32 = 2102400
+	DefaultInitialGasDistribution      uint64 = 5200000000000000
+	DefaultMaxValidUntilBlockIncrement uint32 = 86400000 / DefaultMillisecondsPerBlock // 5760, 24 hours
+	DefaultFeePerByte                  int64  = 1000
+	DefaultExecFeeFactor               int64  = 30
+)
+
+// ProtocolSettings are the settings of a neo network, the same as ProtocolSettings in neo.
+// FeePerByte and ExecFeeFactor are not protocol settings but the values of the Policy contract,
+// they are used for calculating network fees without asking the node.
 type ProtocolSettings struct {
-	Magic          uint32
-	AddressVersion byte
+	Magic                       uint32
+	AddressVersion              byte
+	ValidatorsCount             int
+	StandbyCommittee            []string // public keys in hex
+	SeedList                    []string
+	MillisecondsPerBlock        uint32
+	MaxValidUntilBlockIncrement uint32
+	MaxTransactionsPerBlock     uint32
+	MemoryPoolMaxTransactions   int
+	MaxTraceableBlocks          uint32
+	InitialGasDistribution      uint64
+	Hardforks                   map[string]uint32 // hardfork name without the "HF_" prefix -> block height
+	FeePerByte                  int64
+	ExecFeeFactor               int64
+}
+
+// DefaultProtocolSettings is a copy of the MainNet settings, it is used when no settings are given
+var DefaultProtocolSettings = *MainNetProtocolSettings.Copy()
+
+// MainNetProtocolSettings are the settings of N3 MainNet in config.mainnet.json of neo-cli, use a Copy
+// to change them
+var MainNetProtocolSettings = ProtocolSettings{
+	Magic:           Neo3Magic_MainNet,
+	AddressVersion:  DefaultAddressVersion,
+	ValidatorsCount: 7,
+	StandbyCommittee: []string{
+		"03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c",
+		"02df48f60e8f3e01c48ff40b9b7f1310d7a8b2a193188befe1c2e3df740e895093",
+		"03b8d9d5771d8f513aa0869b9cc8d50986403b78c6da36890638c3d46a5adce04a",
+		"02ca0e27697b9c248f6f16e085fd0061e26f44da85b58ee835c110caa5ec3ba554",
+		"024c7b7fb6c310fccf1ba33b082519d82964ea93868d676662d4a59ad548df0e7d",
+		"02aaec38470f6aad0042c6e877cfd8087d2676b0f516fddd362801b9bd3936399e",
+		"02486fd15702c4490a26703112a5cc1d0923fd697a33406bd5a1c00e0013b09a70",
+		"023a36c72844610b4d34d1968662424011bf783ca9d984efa19a20babf5582f3fe",
+		"03708b860c1de5d87f5b151a12c2a99feebd2e8b315ee8e7cf8aa19692a9e18379",
+		"03c6aa6e12638b36e88adc1ccdceac4db9929575c3e03576c617c49cce7114a050",
+		"03204223f8c86b8cd5c89ef12e4f0dbb314172e9241e30c9ef2293790793537cf0",
+		"02a62c915cf19c7f19a50ec217e79fac2439bbaad658493de0c7d8ffa92ab0aa62",
+		"03409f31f0d66bdc2f70a9730b66fe186658f84a8018204db01c106edc36553cd0",
+		"0288342b141c30dc8ffcde0204929bb46aed5756b41ef4a56778d15ada8f0c6654",
+		"020f2887f41474cfeb11fd262e982051c1541418137c02a0f4961af911045de639",
+		"0222038884bbd1d8ff109ed3bdef3542e768eef76c1247aea8bc8171f532928c30",
+		"03d281b42002647f0113f36c7b8efb30db66078dfaaa9ab3ff76d043a98d512fde",
+		"02504acbc1f4b3bdad1d86d6e1a08603771db135a73e61c9d565ae06a1938cd2ad",
+		"0226933336f1b75baa42d42b71d9091508b638046d19abd67f4e119bf64a7cfb4d",
+		"03cdcea66032b82f5c30450e381e5295cae85c5e6943af716cc6b646352a6067dc",
+		"02cd5a5547119e24feaa7c2a0f37b8c9366216bab7054de0065c9be42084003c8a",
+	},
+	SeedList:                    []string{"seed1.neo.org:10333", "seed2.neo.org:10333", "seed3.neo.org:10333", "seed4.neo.org:10333", "seed5.neo.org:10333"},
+	MillisecondsPerBlock:        DefaultMillisecondsPerBlock,
+	MaxValidUntilBlockIncrement: DefaultMaxValidUntilBlockIncrement,
+	MaxTransactionsPerBlock:     DefaultMaxTransactionsPerBlock,
+	MemoryPoolMaxTransactions:   DefaultMemoryPoolMaxTransactions,
+	MaxTraceableBlocks:          DefaultMaxTraceableBlocks,
+	InitialGasDistribution:      DefaultInitialGasDistribution,
+	Hardforks: map[string]uint32{
+		"Aspidochelone": 1730000,
+		"Basilisk":      4120000,
+		"Cockatrice":    5450000,
+		"Domovoi":       5570000,
+		"Echidna":       7300000,
+	},
+	FeePerByte:    DefaultFeePerByte,
+	ExecFeeFactor: DefaultExecFeeFactor,
+}
+
+// TestNetProtocolSettings are the settings of N3 TestNet (N3T5) in config.testnet.json of neo-cli, use a Copy
+// to change them
+var TestNetProtocolSettings = ProtocolSettings{
+	Magic:           Neo3Magic_TestNet,
+	AddressVersion:  DefaultAddressVersion,
+	ValidatorsCount: 7,
+	StandbyCommittee: []string{
+		"023e9b32ea89b94d066e649b124fd50e396ee91369e8e2a6ae1b11c170d022256d",
+		"03009b7540e10f2562e5fd8fac9eaec25166a58b26e412348ff5a86927bfac22a2",
+		"02ba2c70f5996f357a43198705859fae2cfea13e1172962800772b3d588a9d4abd",
+		"03408dcd416396f64783ac587ea1e1593c57d9fea880c8a6a1920e92a259477806",
+		"02a7834be9b32e2981d157cb5bbd3acb42cfd11ea5c3b10224d7a44e98c5910f1b",
+		"0214baf0ceea3a66f17e7e1e839ea25fd8bed6cd82e6bb6e68250189065f44ff01",
+		"030205e9cefaea5a1dfc580af20c8d5aa2468bb0148f1a5e4605fc622c80e604ba",
+	},
+	SeedList:                    []string{"seed1t5.neo.org:20333", "seed2t5.neo.org:20333", "seed3t5.neo.org:20333", "seed4t5.neo.org:20333", "seed5t5.neo.org:20333"},
+	MillisecondsPerBlock:        DefaultMillisecondsPerBlock,
+	MaxValidUntilBlockIncrement: DefaultMaxValidUntilBlockIncrement,
+	MaxTransactionsPerBlock:     5000,
+	MemoryPoolMaxTransactions:   DefaultMemoryPoolMaxTransactions,
+	MaxTraceableBlocks:          DefaultMaxTraceableBlocks,
+	InitialGasDistribution:      DefaultInitialGasDistribution,
+	Hardforks: map[string]uint32{
+		"Aspidochelone": 210000,
+		"Basilisk":      2680000,
+		"Cockatrice":    3967000,
+		"Domovoi":       4144000,
+		"Echidna":       5870000,
+	},
+	FeePerByte:    DefaultFeePerByte,
+	ExecFeeFactor: DefaultExecFeeFactor,
+}
+
+// NewProtocolSettings returns the settings with the default values of neo, Magic is 0
+func NewProtocolSettings() *ProtocolSettings {
+	return &ProtocolSettings{
+		AddressVersion:              DefaultAddressVersion,
+		StandbyCommittee:            []string{},
+		SeedList:                    []string{},
+		MillisecondsPerBlock:        DefaultMillisecondsPerBlock,
+		MaxValidUntilBlockIncrement: DefaultMaxValidUntilBlockIncrement,
+		MaxTransactionsPerBlock:     DefaultMaxTransactionsPerBlock,
+		MemoryPoolMaxTransactions:   DefaultMemoryPoolMaxTransactions,
+		MaxTraceableBlocks:          DefaultMaxTraceableBlocks,
+		InitialGasDistribution:      DefaultInitialGasDistribution,
+		Hardforks:                   map[string]uint32{},
+		FeePerByte:                  DefaultFeePerByte,
+		ExecFeeFactor:               DefaultExecFeeFactor,
+	}
+}
+
+// Copy returns a deep copy of the settings, the slices and the hardforks are not shared
+func (p *ProtocolSettings) Copy() *ProtocolSettings {
+	c := *p
+	c.StandbyCommittee = append([]string{}, p.StandbyCommittee...)
+	c.SeedList = append([]string{}, p.SeedList...)
+	c.Hardforks = make(map[string]uint32, len(p.Hardforks))
+	for name, height := range p.Hardforks {
+		c.Hardforks[name] = height
+	}
+	return &c
+}
+
+// protocolConfiguration is the "ProtocolConfiguration" section of the config files of neo-cli
+type protocolConfiguration struct {
+	Network                     *uint32           `json:"Network"`
+	AddressVersion              *byte             `json:"AddressVersion"`
+	MillisecondsPerBlock        *uint32           `json:"MillisecondsPerBlock"`
+	MaxValidUntilBlockIncrement *uint32           `json:"MaxValidUntilBlockIncrement"`
+	MaxTransactionsPerBlock     *uint32           `json:"MaxTransactionsPerBlock"`
+	MemoryPoolMaxTransactions   *int              `json:"MemoryPoolMaxTransactions"`
+	MaxTraceableBlocks          *uint32           `json:"MaxTraceableBlocks"`
+	InitialGasDistribution      *uint64           `json:"InitialGasDistribution"`
+	ValidatorsCount             *int              `json:"ValidatorsCount"`
+	StandbyCommittee            []string          `json:"StandbyCommittee"`
+	SeedList                    []string          `json:"SeedList"`
+	Hardforks                   map[string]uint32 `json:"Hardforks"`
+}
+
+// LoadProtocolSettings reads the settings from a config file of neo-cli, e.g. config.json or protocol.json.
+// The values missing in the file are the defaults of neo.
+func LoadProtocolSettings(path string) (*ProtocolSettings, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewProtocolSettingsFromJson(b)
+}
+
+// NewProtocolSettingsFromJson parses the settings from the content of a config file of neo-cli
+func NewProtocolSettingsFromJson(b []byte) (*ProtocolSettings, error) {
+	config := struct {
+		ProtocolConfiguration *protocolConfiguration `json:"ProtocolConfiguration"`
+	}{}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return nil, err
+	}
+	c := config.ProtocolConfiguration
+	if c == nil {
+		return nil, fmt.Errorf("ProtocolConfiguration is not found")
+	}
+	p := NewProtocolSettings()
+	if c.Network != nil {
+		p.Magic = *c.Network
+	}
+	if c.AddressVersion != nil {
+		p.AddressVersion = *c.AddressVersion
+	}
+	if c.MillisecondsPerBlock != nil {
+		if *c.MillisecondsPerBlock == 0 {
+			return nil, fmt.Errorf("MillisecondsPerBlock should be positive")
+		}
+		p.MillisecondsPerBlock = *c.MillisecondsPerBlock
+	}
+	// the same as neo, the default MaxValidUntilBlockIncrement lasts for one day
+	p.MaxValidUntilBlockIncrement = 86400000 / p.MillisecondsPerBlock
+	if c.MaxValidUntilBlockIncrement != nil {
+		p.MaxValidUntilBlockIncrement = *c.MaxValidUntilBlockIncrement
+	}
+	if c.MaxTransactionsPerBlock != nil {
+		p.MaxTransactionsPerBlock = *c.MaxTransactionsPerBlock
+	}
+	if c.MemoryPoolMaxTransactions != nil {
+		p.MemoryPoolMaxTransactions = *c.MemoryPoolMaxTransactions
+	}
+	if c.MaxTraceableBlocks != nil {
+		p.MaxTraceableBlocks = *c.MaxTraceableBlocks
+	}
+	if c.InitialGasDistribution != nil {
+		p.InitialGasDistribution = *c.InitialGasDistribution
+	}
+	if c.StandbyCommittee != nil {
+		p.StandbyCommittee = c.StandbyCommittee
+	}
+	p.ValidatorsCount = len(p.StandbyCommittee)
+	if c.ValidatorsCount != nil {
+		p.ValidatorsCount = *c.ValidatorsCount
+	}
+	if p.ValidatorsCount > len(p.StandbyCommittee) && len(p.StandbyCommittee) != 0 {
+		return nil, fmt.Errorf("ValidatorsCount %d is larger than the StandbyCommittee", p.ValidatorsCount)
+	}
+	if c.SeedList != nil {
+		p.SeedList = c.SeedList
+	}
+	for name, height := range c.Hardforks {
+		p.Hardforks[strings.TrimPrefix(name, "HF_")] = height
+	}
+	return p, nil
+}
+
+// IsHardforkEnabled returns true if the hardfork is enabled at the block height
+func (p *ProtocolSettings) IsHardforkEnabled(name string, height uint32) bool {
+	h, ok := p.Hardforks[strings.TrimPrefix(name, "HF_")]
+	return ok && height >= h
+}
+
+// GetMaxValidUntilBlockIncrement returns the default value if it is not set
+func (p *ProtocolSettings) GetMaxValidUntilBlockIncrement() uint32 {
+	if p == nil || p.MaxValidUntilBlockIncrement == 0 {
+		return DefaultMaxValidUntilBlockIncrement
+	}
+	return p.MaxValidUntilBlockIncrement
+}
+
+// GetFeePerByte returns the default value if it is not set
+func (p *ProtocolSettings) GetFeePerByte() int64 {
+	if p == nil || p.FeePerByte == 0 {
+		return DefaultFeePerByte
+	}
+	return p.FeePerByte
 }
 
-var DefaultProtocolSettings = ProtocolSettings{
-	Magic:          Neo3Magic_MainNet,
-	AddressVersion: DefaultAddressVersion,
+// GetExecFeeFactor returns the default value if it is not set
+func (p *ProtocolSettings) GetExecFeeFactor() int64 {
+	if p == nil || p.ExecFeeFactor == 0 {
+		return DefaultExecFeeFactor
+	}
+	return p.ExecFeeFactor
 }
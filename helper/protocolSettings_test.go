@@ -0,0 +1,96 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfig = `{
+  "ApplicationConfiguration": {
+    "Logger": { "Active": false }
+  },
+  "ProtocolConfiguration": {
+    "Network": 894710606,
+    "AddressVersion": 53,
+    "MillisecondsPerBlock": 3000,
+    "MaxTransactionsPerBlock": 5000,
+    "MemoryPoolMaxTransactions": 50000,
+    "MaxTraceableBlocks": 2102400,
+    "Hardforks": {
+      "HF_Aspidochelone": 210000,
+      "HF_Basilisk": 2680000
+    },
+    "InitialGasDistribution": 5200000000000000,
+    "ValidatorsCount": 1,
+    "StandbyCommittee": [
+      "023e9b32ea89b94d066e649b124fd50e396ee91369e8e2a6ae1b11c170d022256d",
+      "03009b7540e10f2562e5fd8fac9eaec25166a58b26e412348ff5a86927bfac22a2"
+    ],
+    "SeedList": [
+      "seed1t5.neo.org:20333"
+    ]
+  }
+}`
+
+func TestNewProtocolSettingsFromJson(t *testing.T) {
+	p, err := NewProtocolSettingsFromJson([]byte(testConfig))
+	assert.Nil(t, err)
+	assert.Equal(t, Neo3Magic_TestNet, p.Magic)
+	assert.Equal(t, DefaultAddressVersion, p.AddressVersion)
+	assert.Equal(t, uint32(3000), p.MillisecondsPerBlock)
+	// one day of blocks when it is not set
+	assert.Equal(t, uint32(28800), p.MaxValidUntilBlockIncrement)
+	assert.Equal(t, uint32(5000), p.MaxTransactionsPerBlock)
+	assert.Equal(t, 1, p.ValidatorsCount)
+	assert.Equal(t, 2, len(p.StandbyCommittee))
+	assert.Equal(t, []string{"seed1t5.neo.org:20333"}, p.SeedList)
+	assert.Equal(t, uint32(210000), p.Hardforks["Aspidochelone"])
+	assert.Equal(t, DefaultFeePerByte, p.FeePerByte)
+
+	_, err = NewProtocolSettingsFromJson([]byte(`{"ApplicationConfiguration":{}}`))
+	assert.NotNil(t, err)
+	_, err = NewProtocolSettingsFromJson([]byte(`{"ProtocolConfiguration":{"MillisecondsPerBlock":0}}`))
+	assert.NotNil(t, err)
+	_, err = NewProtocolSettingsFromJson([]byte(`{"ProtocolConfiguration":{"ValidatorsCount":3,"StandbyCommittee":["023e9b32ea89b94d066e649b124fd50e396ee91369e8e2a6ae1b11c170d022256d"]}}`))
+	assert.NotNil(t, err)
+	p, err = NewProtocolSettingsFromJson([]byte(`{"ProtocolConfiguration":{"MaxValidUntilBlockIncrement":100}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(100), p.MaxValidUntilBlockIncrement)
+}
+
+func TestProtocolSettings_IsHardforkEnabled(t *testing.T) {
+	p := MainNetProtocolSettings
+	assert.False(t, p.IsHardforkEnabled("Aspidochelone", 1729999))
+	assert.True(t, p.IsHardforkEnabled("Aspidochelone", 1730000))
+	assert.True(t, p.IsHardforkEnabled("HF_Basilisk", 4120000))
+	assert.False(t, p.IsHardforkEnabled("Unknown", 10000000))
+}
+
+func TestProtocolSettings_Copy(t *testing.T) {
+	assert.Equal(t, MainNetProtocolSettings, DefaultProtocolSettings)
+	DefaultProtocolSettings.Hardforks["Aspidochelone"] = 0
+	DefaultProtocolSettings.StandbyCommittee[0] = ""
+	defer func() { DefaultProtocolSettings = *MainNetProtocolSettings.Copy() }()
+	assert.Equal(t, uint32(1730000), MainNetProtocolSettings.Hardforks["Aspidochelone"])
+	assert.NotEqual(t, "", MainNetProtocolSettings.StandbyCommittee[0])
+
+	for _, p := range []ProtocolSettings{MainNetProtocolSettings, TestNetProtocolSettings} {
+		assert.Equal(t, p, *p.Copy())
+		assert.True(t, p.ValidatorsCount <= len(p.StandbyCommittee))
+	}
+	assert.Equal(t, 21, len(MainNetProtocolSettings.StandbyCommittee))
+	assert.Equal(t, 7, len(TestNetProtocolSettings.StandbyCommittee))
+}
+
+func TestProtocolSettings_Getters(t *testing.T) {
+	var p *ProtocolSettings
+	assert.Equal(t, DefaultMaxValidUntilBlockIncrement, p.GetMaxValidUntilBlockIncrement())
+	assert.Equal(t, DefaultFeePerByte, p.GetFeePerByte())
+	assert.Equal(t, DefaultExecFeeFactor, p.GetExecFeeFactor())
+
+	p = &ProtocolSettings{MaxValidUntilBlockIncrement: 100, FeePerByte: 20, ExecFeeFactor: 1}
+	assert.Equal(t, uint32(100), p.GetMaxValidUntilBlockIncrement())
+	assert.Equal(t, int64(20), p.GetFeePerByte())
+	assert.Equal(t, int64(1), p.GetExecFeeFactor())
+}
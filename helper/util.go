@@ -266,3 +266,22 @@ func ConvertBigIntToUint64(t *big.Int, decimals int) uint64 {
 	}
 	return r.Uint64()
 }
+
+// ConvertBigIntToDecimalString is the reverse of ConvertFloat64StringToBigInt, the trailing zeros are removed
+func ConvertBigIntToDecimalString(t *big.Int, decimals int) string {
+	if decimals <= 0 {
+		return t.String()
+	}
+	s := new(big.Int).Abs(t).String()
+	for len(s) <= decimals {
+		s = "0" + s
+	}
+	intPart, decPart := s[:len(s)-decimals], strings.TrimRight(s[len(s)-decimals:], "0")
+	if t.Sign() < 0 {
+		intPart = "-" + intPart
+	}
+	if decPart == "" {
+		return intPart
+	}
+	return intPart + "." + decPart
+}
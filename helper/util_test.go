@@ -143,3 +143,10 @@ func TestUp(t *testing.T) {
 	bi := ConvertFloat64StringToBigInt(strconv.FormatUint(5698643, 10), 36-8-2)
 	fmt.Println(bi.String())
 }
+
+func TestConvertBigIntToDecimalString(t *testing.T) {
+	assert.Equal(t, "1.5", ConvertBigIntToDecimalString(big.NewInt(150000000), 8))
+	assert.Equal(t, "0.00000001", ConvertBigIntToDecimalString(big.NewInt(1), 8))
+	assert.Equal(t, "-2", ConvertBigIntToDecimalString(big.NewInt(-200), 2))
+	assert.Equal(t, "100", ConvertBigIntToDecimalString(big.NewInt(100), 0))
+}
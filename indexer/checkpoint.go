@@ -0,0 +1,45 @@
+package indexer
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// Checkpoint stores the next block height to be indexed
+type Checkpoint interface {
+	// Load returns false if no height has been saved yet
+	Load() (uint32, bool, error)
+	Save(next uint32) error
+}
+
+// FileCheckpoint keeps the height in a text file
+type FileCheckpoint struct {
+	Path string
+}
+
+func NewFileCheckpoint(path string) *FileCheckpoint {
+	return &FileCheckpoint{Path: path}
+}
+
+func (c *FileCheckpoint) Load() (uint32, bool, error) {
+	b, err := os.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	next, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid checkpoint file %s: %v", c.Path, err)
+	}
+	return uint32(next), true, nil
+}
+
+func (c *FileCheckpoint) Save(next uint32) error {
+	return helper.WriteFileAtomic(c.Path, []byte(strconv.FormatUint(uint64(next), 10)))
+}
@@ -0,0 +1,51 @@
+package indexer
+
+import (
+	"bytes"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// Filter selects the notifications passed to the sink, empty fields match everything
+type Filter struct {
+	Contracts  []*helper.UInt160
+	EventNames []string
+
+	// By default, when Contracts is set, application logs are only fetched for the transactions whose script
+	// contains one of the contract hashes. Set InternalCalls to also catch the notifications of contracts
+	// called by other contracts, this fetches the application log of every transaction.
+	InternalCalls bool
+}
+
+// needLog tells whether the application log of a tx with the base64 encoded script is needed
+func (f *Filter) needLog(script string) bool {
+	if len(f.Contracts) == 0 || f.InternalCalls {
+		return true
+	}
+	b, err := crypto.Base64Decode(script)
+	if err != nil {
+		return true
+	}
+	for _, c := range f.Contracts {
+		if bytes.Contains(b, c.ToByteArray()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) match(contract *helper.UInt160, eventName string) bool {
+	if len(f.Contracts) != 0 && !contract.ExistsIn(f.Contracts) {
+		return false
+	}
+	if len(f.EventNames) == 0 {
+		return true
+	}
+	for _, e := range f.EventNames {
+		if e == eventName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,320 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
+
+const (
+	DefaultConcurrency  = 4
+	DefaultPollInterval = 5 * time.Second
+)
+
+// Flusher can be implemented by a sink which buffers its output, Flush is called before each checkpoint
+type Flusher interface {
+	Flush() error
+}
+
+// Follower reads blocks from a node in order and streams them into a sink.
+// dBFT gives one block finality, so a block is never rolled back once it is returned by the node,
+// the follower only moves forward and resumes from the checkpoint.
+// Delivery is at least once: blocks after the last saved checkpoint are delivered again after a restart.
+type Follower struct {
+	Client       rpc.IRpcClient
+	Sink         Sink
+	Checkpoint   Checkpoint // optional
+	Filter       Filter
+	StartHeight  uint32 // used when the checkpoint is empty
+	Concurrency  int    // number of blocks fetched in parallel
+	PollInterval time.Duration
+
+	// IncludeBlockLogs fetches the application log of the block itself, for the notifications in OnPersist and PostPersist
+	IncludeBlockLogs bool
+}
+
+type blockData struct {
+	block    *models.RpcBlock
+	blockLog *models.RpcApplicationLog
+	txLogs   []*models.RpcApplicationLog
+}
+
+func NewFollower(client rpc.IRpcClient, sink Sink, checkpoint Checkpoint) *Follower {
+	if client == nil || sink == nil {
+		return nil
+	}
+	return &Follower{
+		Client:       client,
+		Sink:         sink,
+		Checkpoint:   checkpoint,
+		Concurrency:  DefaultConcurrency,
+		PollInterval: DefaultPollInterval,
+	}
+}
+
+// Run follows the chain until the context is done or an error occurs
+func (f *Follower) Run(ctx context.Context) error {
+	next, err := f.load()
+	if err != nil {
+		return err
+	}
+	for {
+		next, err = f.syncFrom(ctx, next)
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.pollInterval()):
+		}
+	}
+}
+
+// Sync indexes the blocks from the checkpoint to the current block height, and returns the next height to index
+func (f *Follower) Sync(ctx context.Context) (uint32, error) {
+	next, err := f.load()
+	if err != nil {
+		return 0, err
+	}
+	return f.syncFrom(ctx, next)
+}
+
+func (f *Follower) syncFrom(ctx context.Context, next uint32) (uint32, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return next, err
+		}
+		response := f.Client.GetBlockCount()
+		if response.HasError() {
+			return next, fmt.Errorf(response.GetErrorInfo())
+		}
+		count := uint32(response.Result)
+		if next >= count {
+			return next, nil
+		}
+		end := next + uint32(f.concurrency())
+		if end > count {
+			end = count
+		}
+		blocks, fetchErr := f.fetchRange(next, end)
+		for _, b := range blocks {
+			if err := f.deliver(b); err != nil {
+				return next, err
+			}
+			next++
+		}
+		if len(blocks) != 0 {
+			if err := f.save(next); err != nil {
+				return next, err
+			}
+		}
+		if fetchErr != nil {
+			return next, fetchErr
+		}
+	}
+}
+
+// fetchRange fetches the blocks in [start, end) in parallel,
+// it returns the blocks before the first failed one together with the error
+func (f *Follower) fetchRange(start, end uint32) ([]*blockData, error) {
+	n := int(end - start)
+	results := make([]*blockData, n)
+	errs := make([]error, n)
+	wg := sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = f.fetchBlock(start + uint32(i))
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return results[:i], fmt.Errorf("failed to fetch block %d: %v", start+uint32(i), err)
+		}
+	}
+	return results, nil
+}
+
+func (f *Follower) fetchBlock(index uint32) (*blockData, error) {
+	response := f.Client.GetBlock(strconv.FormatUint(uint64(index), 10))
+	if response.HasError() {
+		return nil, fmt.Errorf(response.GetErrorInfo())
+	}
+	block := response.Result
+	if uint32(block.Index) != index {
+		return nil, fmt.Errorf("node returned block %d", block.Index)
+	}
+	data := &blockData{
+		block:  &block,
+		txLogs: make([]*models.RpcApplicationLog, len(block.Tx)),
+	}
+	if f.IncludeBlockLogs {
+		log, err := f.getApplicationLog(block.Hash)
+		if err != nil {
+			return nil, err
+		}
+		data.blockLog = log
+	}
+	for i, trx := range block.Tx {
+		if !f.Filter.needLog(trx.Script) {
+			continue
+		}
+		log, err := f.getApplicationLog(trx.Hash)
+		if err != nil {
+			return nil, err
+		}
+		data.txLogs[i] = log
+	}
+	return data, nil
+}
+
+func (f *Follower) getApplicationLog(hash string) (*models.RpcApplicationLog, error) {
+	response := f.Client.GetApplicationLog(hash)
+	if response.HasError() {
+		return nil, fmt.Errorf(response.GetErrorInfo())
+	}
+	return &response.Result, nil
+}
+
+func (f *Follower) deliver(data *blockData) error {
+	b := data.block
+	index := uint32(b.Index)
+	err := f.Sink.OnBlock(&Block{RpcBlockHeader: b.RpcBlockHeader, TxCount: len(b.Tx)})
+	if err != nil {
+		return err
+	}
+	if data.blockLog != nil {
+		err = f.deliverNotifications(b, "", data.blockLog)
+		if err != nil {
+			return err
+		}
+	}
+	for i, trx := range b.Tx {
+		err = f.Sink.OnTransaction(&Transaction{
+			BlockIndex: index,
+			BlockTime:  b.Time,
+			Tx:         trx,
+			Log:        data.txLogs[i],
+		})
+		if err != nil {
+			return err
+		}
+		if data.txLogs[i] != nil {
+			err = f.deliverNotifications(b, trx.Hash, data.txLogs[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Follower) deliverNotifications(b *models.RpcBlock, txId string, log *models.RpcApplicationLog) error {
+	index := uint32(b.Index)
+	for _, execution := range log.Executions {
+		for i, n := range execution.Notifications {
+			contract, err := helper.UInt160FromString(n.Contract)
+			if err != nil {
+				return fmt.Errorf("invalid notification contract %s in block %d: %v", n.Contract, index, err)
+			}
+			if !f.Filter.match(contract, n.EventName) {
+				continue
+			}
+			state, err := DecodeState(n.State)
+			if err != nil {
+				return fmt.Errorf("failed to decode %s notification in block %d: %v", n.EventName, index, err)
+			}
+			err = f.Sink.OnNotification(&Notification{
+				BlockIndex: index,
+				BlockTime:  b.Time,
+				TxId:       txId,
+				Trigger:    execution.Trigger,
+				VMState:    execution.VMState,
+				Index:      i,
+				Contract:   contract,
+				EventName:  n.EventName,
+				State:      state,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DecodeState converts the state of a notification, which is always an Array, to its items
+func DecodeState(state models.InvokeStack) ([]models.InvokeStack, error) {
+	if state.Type != "Array" {
+		return nil, fmt.Errorf("expected Array, got %s", state.Type)
+	}
+	if state.Value == nil {
+		return []models.InvokeStack{}, nil
+	}
+	values, ok := state.Value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid Array value")
+	}
+	result := make([]models.InvokeStack, len(values))
+	for i, v := range values {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid stack item at %d", i)
+		}
+		t, ok := m["type"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid stack item type at %d", i)
+		}
+		result[i] = models.InvokeStack{Type: t, Value: m["value"]}
+	}
+	return result, nil
+}
+
+func (f *Follower) load() (uint32, error) {
+	if f.Checkpoint == nil {
+		return f.StartHeight, nil
+	}
+	next, ok, err := f.Checkpoint.Load()
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return f.StartHeight, nil
+	}
+	return next, nil
+}
+
+func (f *Follower) save(next uint32) error {
+	if flusher, ok := f.Sink.(Flusher); ok {
+		if err := flusher.Flush(); err != nil {
+			return err
+		}
+	}
+	if f.Checkpoint == nil {
+		return nil
+	}
+	return f.Checkpoint.Save(next)
+}
+
+func (f *Follower) concurrency() int {
+	if f.Concurrency <= 0 {
+		return 1
+	}
+	return f.Concurrency
+}
+
+func (f *Follower) pollInterval() time.Duration {
+	if f.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+	return f.PollInterval
+}
@@ -0,0 +1,197 @@
+package indexer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+type memorySink struct {
+	blocks        []*Block
+	transactions  []*Transaction
+	notifications []*Notification
+}
+
+func (s *memorySink) OnBlock(block *Block) error {
+	s.blocks = append(s.blocks, block)
+	return nil
+}
+
+func (s *memorySink) OnTransaction(trx *Transaction) error {
+	s.transactions = append(s.transactions, trx)
+	return nil
+}
+
+func (s *memorySink) OnNotification(notification *Notification) error {
+	s.notifications = append(s.notifications, notification)
+	return nil
+}
+
+var other, _ = helper.UInt160FromString("0x0000000000000000000000000000000000000001")
+
+func transferLog(txId string, contract *helper.UInt160) rpc.GetApplicationLogResponse {
+	return rpc.GetApplicationLogResponse{
+		Result: models.RpcApplicationLog{
+			TxId: txId,
+			Executions: []models.RpcExecution{
+				{
+					Trigger: "Application",
+					VMState: "HALT",
+					Notifications: []models.RpcNotification{
+						{
+							Contract:  "0x" + contract.String(),
+							EventName: "Transfer",
+							State: models.InvokeStack{
+								Type: "Array",
+								Value: []interface{}{
+									map[string]interface{}{"type": "Any"},
+									map[string]interface{}{"type": "ByteString", "value": "3hp9/4lZks1aPkUjlOcRnH6PdZc="},
+									map[string]interface{}{"type": "Integer", "value": "100"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func testBlock(index int, txs ...models.RpcTransaction) rpc.GetBlockResponse {
+	return rpc.GetBlockResponse{
+		Result: models.RpcBlock{
+			RpcBlockHeader: models.RpcBlockHeader{Hash: "0xb" + string(rune('0'+index)), Index: index, Time: 1000 + index},
+			Tx:             txs,
+		},
+	}
+}
+
+func newClientMock() *rpc.RpcClientMock {
+	gasScript := crypto.Base64Encode(tx.GasToken.ToByteArray())
+	otherScript := crypto.Base64Encode(other.ToByteArray())
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 3})
+	clientMock.On("GetBlock", "0").Return(testBlock(0))
+	clientMock.On("GetBlock", "1").Return(testBlock(1,
+		models.RpcTransaction{Hash: "0x11", Script: gasScript},
+		models.RpcTransaction{Hash: "0x12", Script: otherScript}))
+	clientMock.On("GetBlock", "2").Return(testBlock(2,
+		models.RpcTransaction{Hash: "0x21", Script: otherScript}))
+	clientMock.On("GetApplicationLog", "0x11").Return(transferLog("0x11", tx.GasToken))
+	clientMock.On("GetApplicationLog", "0x12").Return(transferLog("0x12", other))
+	clientMock.On("GetApplicationLog", "0x21").Return(transferLog("0x21", other))
+	return clientMock
+}
+
+func TestFollower_Sync(t *testing.T) {
+	clientMock := newClientMock()
+	sink := &memorySink{}
+	checkpoint := NewFileCheckpoint(filepath.Join(t.TempDir(), "height"))
+	f := NewFollower(clientMock, sink, checkpoint)
+	f.Concurrency = 2
+
+	next, err := f.Sync(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), next)
+	assert.Equal(t, 3, len(sink.blocks))
+	for i, b := range sink.blocks {
+		assert.Equal(t, i, b.Index)
+	}
+	assert.Equal(t, 3, len(sink.transactions))
+	assert.Equal(t, "0x11", sink.transactions[0].Tx.Hash)
+	assert.Equal(t, uint32(2), sink.transactions[2].BlockIndex)
+	assert.Equal(t, 3, len(sink.notifications))
+	assert.Equal(t, "Transfer", sink.notifications[0].EventName)
+	assert.Equal(t, "100", sink.notifications[0].State[2].Value)
+
+	h, ok, err := checkpoint.Load()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(3), h)
+
+	// resume from the checkpoint, nothing to do
+	sink2 := &memorySink{}
+	f = NewFollower(clientMock, sink2, checkpoint)
+	next, err = f.Sync(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), next)
+	assert.Equal(t, 0, len(sink2.blocks))
+}
+
+func TestFollower_Filter(t *testing.T) {
+	clientMock := newClientMock()
+	sink := &memorySink{}
+	f := NewFollower(clientMock, sink, nil)
+	f.Filter = Filter{Contracts: []*helper.UInt160{tx.GasToken}, EventNames: []string{"Transfer"}}
+
+	_, err := f.Sync(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(sink.transactions))
+	assert.NotNil(t, sink.transactions[0].Log)
+	assert.Nil(t, sink.transactions[1].Log)
+	assert.Equal(t, 1, len(sink.notifications))
+	assert.True(t, sink.notifications[0].Contract.Equals(tx.GasToken))
+	clientMock.AssertNotCalled(t, "GetApplicationLog", "0x12")
+	clientMock.AssertNotCalled(t, "GetApplicationLog", "0x21")
+
+	sink = &memorySink{}
+	f.Sink = sink
+	f.Filter = Filter{Contracts: []*helper.UInt160{tx.GasToken}, InternalCalls: true}
+	_, err = f.Sync(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(sink.notifications))
+	clientMock.AssertCalled(t, "GetApplicationLog", "0x21")
+}
+
+func TestFollower_FetchError(t *testing.T) {
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 3})
+	clientMock.On("GetBlock", "0").Return(testBlock(0))
+	clientMock.On("GetBlock", "1").Return(rpc.GetBlockResponse{
+		ErrorResponse: rpc.ErrorResponse{Error: rpc.RpcError{Code: -100, Message: "Unknown block"}},
+	})
+	clientMock.On("GetBlock", "2").Return(testBlock(2))
+	sink := &memorySink{}
+	checkpoint := NewFileCheckpoint(filepath.Join(t.TempDir(), "height"))
+	f := NewFollower(clientMock, sink, checkpoint)
+
+	next, err := f.Sync(context.Background())
+	assert.NotNil(t, err)
+	assert.Equal(t, uint32(1), next)
+	assert.Equal(t, 1, len(sink.blocks))
+	h, _, _ := checkpoint.Load()
+	assert.Equal(t, uint32(1), h)
+}
+
+func TestJsonLinesSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.jsonl")
+	sink, err := NewJsonLinesSink(path)
+	assert.Nil(t, err)
+	f := NewFollower(newClientMock(), sink, nil)
+	_, err = f.Sync(context.Background())
+	assert.Nil(t, err)
+	assert.Nil(t, sink.Close())
+
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer file.Close()
+	kinds := map[string]int{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := jsonLine{}
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &line))
+		kinds[line.Kind]++
+	}
+	assert.Equal(t, map[string]int{"block": 3, "transaction": 3, "notification": 3}, kinds)
+}
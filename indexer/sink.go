@@ -0,0 +1,114 @@
+package indexer
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
+
+// Sink receives the indexed data, the calls are made in block order by a single goroutine
+type Sink interface {
+	OnBlock(block *Block) error
+	OnTransaction(trx *Transaction) error
+	OnNotification(notification *Notification) error
+}
+
+// Block is a block fetched by the follower
+type Block struct {
+	models.RpcBlockHeader
+	TxCount int `json:"txcount"`
+}
+
+// Transaction is a transaction in an indexed block, Log is nil if the application log is not fetched
+type Transaction struct {
+	BlockIndex uint32                    `json:"blockindex"`
+	BlockTime  int                       `json:"blocktime"`
+	Tx         models.RpcTransaction     `json:"tx"`
+	Log        *models.RpcApplicationLog `json:"log,omitempty"`
+}
+
+// Notification is a decoded notification which passes the filter,
+// TxId is empty for notifications emitted in OnPersist and PostPersist
+type Notification struct {
+	BlockIndex uint32               `json:"blockindex"`
+	BlockTime  int                  `json:"blocktime"`
+	TxId       string               `json:"txid,omitempty"`
+	Trigger    string               `json:"trigger"`
+	VMState    string               `json:"vmstate"`
+	Index      int                  `json:"index"` // position of the notification in its execution
+	Contract   *helper.UInt160      `json:"contract"`
+	EventName  string               `json:"eventname"`
+	State      []models.InvokeStack `json:"state"`
+}
+
+// JsonLinesSink writes every record as one json object per line,
+// e.g. {"kind":"block","data":{...}}
+type JsonLinesSink struct {
+	file   *os.File
+	writer *bufio.Writer
+	lock   sync.Mutex
+}
+
+type jsonLine struct {
+	Kind string      `json:"kind"`
+	Data interface{} `json:"data"`
+}
+
+// NewJsonLinesSink opens the file in append mode so that a resumed follower continues the same file
+func NewJsonLinesSink(path string) (*JsonLinesSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &JsonLinesSink{
+		file:   f,
+		writer: bufio.NewWriter(f),
+	}, nil
+}
+
+func (s *JsonLinesSink) OnBlock(block *Block) error {
+	return s.write("block", block)
+}
+
+func (s *JsonLinesSink) OnTransaction(trx *Transaction) error {
+	return s.write("transaction", trx)
+}
+
+func (s *JsonLinesSink) OnNotification(notification *Notification) error {
+	return s.write("notification", notification)
+}
+
+// Flush writes the buffered lines to disk, it is called by the follower before saving a checkpoint
+func (s *JsonLinesSink) Flush() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err := s.writer.Flush()
+	if err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *JsonLinesSink) Close() error {
+	err := s.Flush()
+	if err != nil {
+		_ = s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+func (s *JsonLinesSink) write(kind string, data interface{}) error {
+	b, err := json.Marshal(jsonLine{Kind: kind, Data: data})
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err = s.writer.Write(append(b, '\n'))
+	return err
+}
@@ -128,6 +128,9 @@ func (br *BinaryReader) ReadVarBytesWithMaxLimit(max int) []byte {
 		return nil
 	}
 	n := br.ReadVarUIntWithMaxLimit(uint64(max))
+	if br.Err != nil {
+		return nil
+	}
 	b := make([]byte, n)
 	br.ReadLE(b)
 	return b
@@ -44,12 +44,12 @@ func (w *BinaryWriter) WriteVarUInt(val uint64) {
 		w.Err = binary.Write(w.w, binary.LittleEndian, uint8(val))
 		return
 	}
-	if val < 0xffff {
+	if val <= 0xffff {
 		w.Err = binary.Write(w.w, binary.LittleEndian, byte(0xfd))
 		w.Err = binary.Write(w.w, binary.LittleEndian, uint16(val))
 		return
 	}
-	if val < 0xffffffff {
+	if val <= 0xffffffff {
 		w.Err = binary.Write(w.w, binary.LittleEndian, byte(0xfe))
 		w.Err = binary.Write(w.w, binary.LittleEndian, uint32(val))
 		return
@@ -61,6 +61,32 @@ func TestBinaryWriter_WriteVarUInt(t *testing.T) {
 	assert.Equal(t, b.Bytes(), bin)
 }
 
+// the largest value of each size is written in that size, the same as neo
+func TestBinaryWriter_WriteVarUInt_Boundaries(t *testing.T) {
+	cases := []struct {
+		val uint64
+		bin []byte
+	}{
+		{0xfc, []byte{0xfc}},
+		{0xfd, []byte{0xfd, 0xfd, 0x00}},
+		{0xffff, []byte{0xfd, 0xff, 0xff}},
+		{0x10000, []byte{0xfe, 0x00, 0x00, 0x01, 0x00}},
+		{0xffffffff, []byte{0xfe, 0xff, 0xff, 0xff, 0xff}},
+		{0x100000000, []byte{0xff, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00}},
+	}
+	for _, c := range cases {
+		b := new(bytes.Buffer)
+		bw := NewBinaryWriterFromIO(b)
+		bw.WriteVarUInt(c.val)
+		assert.Nil(t, bw.Err)
+		assert.Equal(t, c.bin, b.Bytes())
+
+		br := NewBinaryReaderFromBuf(c.bin)
+		assert.Equal(t, c.val, br.ReadVarUIntWithMaxLimit(^uint64(0)))
+		assert.Nil(t, br.Err)
+	}
+}
+
 func TestBinaryWriter_WriteVarBytes(t *testing.T) {
 	var (
 		b          = new(bytes.Buffer)
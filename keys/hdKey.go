@@ -0,0 +1,166 @@
+package keys
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+)
+
+const (
+	HardenedOffset uint32 = 0x80000000
+	NeoCoinType    uint32 = 888
+)
+
+// slip10Seed is the HMAC key of the master node of secp256r1 in SLIP-10
+var slip10Seed = []byte("Nist256p1 seed")
+
+// HDKey is a private node of SLIP-10 on secp256r1, the same derivation as BIP-32 with the curve of neo,
+// used with the BIP-44 path of neo m/44'/888'/account'/change/index.
+type HDKey struct {
+	PrivateKey []byte
+	ChainCode  []byte
+	Depth      byte
+	Index      uint32
+}
+
+// NewMasterKey derives the master node from a seed, e.g. the BIP-39 seed of a mnemonic
+func NewMasterKey(seed []byte) (*HDKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed length %d is invalid", len(seed))
+	}
+	n := elliptic.P256().Params().N
+	i := hmacSha512(slip10Seed, seed)
+	for {
+		k := new(big.Int).SetBytes(i[:32])
+		if k.Sign() != 0 && k.Cmp(n) < 0 {
+			break
+		}
+		i = hmacSha512(slip10Seed, i)
+	}
+	return &HDKey{
+		PrivateKey: i[:32],
+		ChainCode:  i[32:],
+	}, nil
+}
+
+// Child derives the child node, index >= HardenedOffset for a hardened child
+func (k *HDKey) Child(index uint32) (*HDKey, error) {
+	n := elliptic.P256().Params().N
+	data := make([]byte, 0, 37)
+	if index >= HardenedOffset {
+		data = append(append(data, 0), k.PrivateKey...)
+	} else {
+		pair, err := NewKeyPair(k.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, pair.PublicKey.EncodePoint(true)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+	for {
+		i := hmacSha512(k.ChainCode, data)
+		il := new(big.Int).SetBytes(i[:32])
+		child := new(big.Int).Add(il, new(big.Int).SetBytes(k.PrivateKey))
+		child.Mod(child, n)
+		if il.Cmp(n) < 0 && child.Sign() != 0 {
+			return &HDKey{
+				PrivateKey: child.FillBytes(make([]byte, 32)),
+				ChainCode:  i[32:],
+				Depth:      k.Depth + 1,
+				Index:      index,
+			}, nil
+		}
+		// invalid key, SLIP-10 tries again with the right half
+		data = binary.BigEndian.AppendUint32(append([]byte{1}, i[32:]...), index)
+	}
+}
+
+// Derive derives the node of the path from this node, e.g. "m/44'/888'/0'/0/0"
+func (k *HDKey) Derive(path string) (*HDKey, error) {
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	node := k
+	for _, index := range indexes {
+		node, err = node.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// GetKeyPair returns the key pair of the node
+func (k *HDKey) GetKeyPair() (*KeyPair, error) {
+	return NewKeyPair(k.PrivateKey)
+}
+
+// GetPublicKey returns the public key of the node
+func (k *HDKey) GetPublicKey() (*crypto.ECPoint, error) {
+	pair, err := NewKeyPair(k.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return pair.PublicKey, nil
+}
+
+// ParseDerivationPath parses a path like "m/44'/888'/0'/0/0", "h" or "H" also marks a hardened index
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("derivation path should start with m: %s", path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H")
+		if hardened {
+			part = part[:len(part)-1]
+		}
+		i, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(i) >= HardenedOffset {
+			return nil, fmt.Errorf("invalid index %s in derivation path %s", part, path)
+		}
+		index := uint32(i)
+		if hardened {
+			index += HardenedOffset
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+// NeoDerivationPath returns the BIP-44 path of neo, m/44'/888'/account'/change/index
+func NeoDerivationPath(account, change, index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%d'/%d/%d", NeoCoinType, account, change, index)
+}
+
+// NewKeyPairFromMnemonic derives the key pair of the neo path from a mnemonic
+func NewKeyPairFromMnemonic(mnemonic string, passphrase string, account, change, index uint32) (*KeyPair, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	node, err := master.Derive(NeoDerivationPath(account, change, index))
+	if err != nil {
+		return nil, err
+	}
+	return node.GetKeyPair()
+}
+
+func hmacSha512(key []byte, data []byte) []byte {
+	h := hmac.New(sha512.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,91 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// test vector 1 of SLIP-10 for nist256p1
+func TestNewMasterKey(t *testing.T) {
+	master, err := NewMasterKey(helper.HexToBytes("000102030405060708090a0b0c0d0e0f"))
+	assert.Nil(t, err)
+	assert.Equal(t, "beeb672fe4621673f722f38529c07392fecaa61015c80c34f29ce8b41b3cb6ea", helper.BytesToHex(master.ChainCode))
+	assert.Equal(t, "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2", helper.BytesToHex(master.PrivateKey))
+	p, err := master.GetPublicKey()
+	assert.Nil(t, err)
+	assert.Equal(t, "0266874dc6ade47b3ecd096745ca09bcd29638dd52c2c12117b11ed3e458cfa9e8", p.String())
+
+	child, err := master.Derive("m/0'")
+	assert.Nil(t, err)
+	assert.Equal(t, "3460cea53e6a6bb5fb391eeef3237ffd8724bf0a40e94943c98b83825342ee11", helper.BytesToHex(child.ChainCode))
+	assert.Equal(t, "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c", helper.BytesToHex(child.PrivateKey))
+	assert.Equal(t, byte(1), child.Depth)
+	assert.Equal(t, HardenedOffset, child.Index)
+
+	// the rest of the vector, with normal children as in the neo path
+	cases := []struct {
+		path, chainCode, privateKey, publicKey string
+	}{
+		{"m/0'/1", "4187afff1aafa8445010097fb99d23aee9f599450c7bd140b6826ac22ba21d0c",
+			"284e9d38d07d21e4e281b645089a94f4cf5a5a81369acf151a1c3a57f18b2129",
+			"03526c63f8d0b4bbbf9c80df553fe66742df4676b241dabefdef67733e070f6844"},
+		{"m/0'/1/2'", "98c7514f562e64e74170cc3cf304ee1ce54d6b6da4f880f313e8204c2a185318",
+			"694596e8a54f252c960eb771a3c41e7e32496d03b954aeb90f61635b8e092aa7",
+			"0359cf160040778a4b14c5f4d7b76e327ccc8c4a6086dd9451b7482b5a4972dda0"},
+		{"m/0'/1/2'/2", "ba96f776a5c3907d7fd48bde5620ee374d4acfd540378476019eab70790c63a0",
+			"5996c37fd3dd2679039b23ed6f70b506c6b56b3cb5e424681fb0fa64caf82aaa",
+			"029f871f4cb9e1c97f9f4de9ccd0d4a2f2a171110c61178f84430062230833ff20"},
+		{"m/0'/1/2'/2/1000000000", "b9b7b82d326bb9cb5b5b121066feea4eb93d5241103c9e7a18aad40f1dde8059",
+			"21c4f269ef0a5fd1badf47eeacebeeaa3de22eb8e5b0adcd0f27dd99d34d0119",
+			"02216cd26d31147f72427a453c443ed2cde8a1e53c9cc44e5ddf739725413fe3f4"},
+	}
+	for _, c := range cases {
+		node, err := master.Derive(c.path)
+		assert.Nil(t, err)
+		assert.Equal(t, c.chainCode, helper.BytesToHex(node.ChainCode), c.path)
+		assert.Equal(t, c.privateKey, helper.BytesToHex(node.PrivateKey), c.path)
+		p, err := node.GetPublicKey()
+		assert.Nil(t, err)
+		assert.Equal(t, c.publicKey, p.String(), c.path)
+	}
+
+	_, err = NewMasterKey([]byte{1, 2, 3})
+	assert.NotNil(t, err)
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	indexes, err := ParseDerivationPath(NeoDerivationPath(1, 0, 2))
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{44 + HardenedOffset, 888 + HardenedOffset, 1 + HardenedOffset, 0, 2}, indexes)
+	indexes, err = ParseDerivationPath("m/44h/888H/0")
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{44 + HardenedOffset, 888 + HardenedOffset, 0}, indexes)
+	indexes, err = ParseDerivationPath("m")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(indexes))
+
+	for _, path := range []string{"", "44'/888'", "m/x", "m/2147483648", "m//0"} {
+		_, err = ParseDerivationPath(path)
+		assert.NotNil(t, err, path)
+	}
+}
+
+// The seed and the derivation are checked by the BIP-39 and SLIP-10 vectors, this pins the key of m/44'/888'/0'/0/0
+// so that a change of either is caught. It is not a vector exported from a wallet.
+func TestNewKeyPairFromMnemonic(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	pair, err := NewKeyPairFromMnemonic(mnemonic, "", 0, 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "38bcb1943801333aecdb9099b368ca8ea5b13a2c22862f8e1be77a46ed88738b", helper.BytesToHex(pair.PrivateKey))
+	assert.Equal(t, "023c73be53bc3bbbacf6af57850efd294f07f1d8e324f8bb88df9274a188eac4b0", pair.PublicKey.String())
+
+	other, err := NewKeyPairFromMnemonic(mnemonic, "", 0, 0, 1)
+	assert.Nil(t, err)
+	assert.NotEqual(t, pair.PrivateKey, other.PrivateKey)
+
+	_, err = NewKeyPairFromMnemonic("zoo zoo zoo", "", 0, 0, 0)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,10 @@
+package keys
+
+import "github.com/joeqian10/neo3-gogogo/crypto"
+
+// ISigner signs with a private key of secp256r1, the key may be kept in memory, in an HSM or by a remote service.
+// Sign returns the 64 bytes signature r||s of sha256(message), the same as KeyPair.Sign.
+type ISigner interface {
+	GetPublicKey() *crypto.ECPoint
+	Sign(message []byte) ([]byte, error)
+}
@@ -5,10 +5,10 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
-	"crypto/sha256"
 	"fmt"
 	"math/big"
 
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"golang.org/x/crypto/scrypt"
 	"golang.org/x/text/unicode/norm"
 
@@ -27,6 +27,9 @@ func (kps KeyPairSlice) Swap(i, j int) { kps[i], kps[j] = kps[j], kps[i] }
 type KeyPair struct {
 	PrivateKey []byte
 	PublicKey  *crypto.ECPoint
+	// Options are used by Sign, and so by the witness helpers in tx and by the wallet. The zero value keeps the
+	// randomized signatures of crypto/ecdsa.
+	Options SignatureOptions
 }
 
 const (
@@ -35,17 +38,27 @@ const (
 	P = 8
 )
 
+// NewKeyPair creates a key pair on secp256r1, the curve of neo accounts
 func NewKeyPair(privateKey []byte) (*KeyPair, error) {
+	return NewKeyPairWithCurve(privateKey, crypto.P256)
+}
+
+// NewKeyPairWithCurve creates a key pair on the curve, e.g. crypto.Secp256k1 for the keys of Ethereum
+func NewKeyPairWithCurve(privateKey []byte, curve elliptic.Curve) (*KeyPair, error) {
 	length := len(privateKey)
 	if length != 32 {
 		return nil, fmt.Errorf("argument length is wrong %v", length)
 	}
-	ecdsaKey := ToECDsa(privateKey)
+	d := new(big.Int).SetBytes(privateKey)
+	if d.Sign() == 0 || d.Cmp(curve.Params().N) >= 0 {
+		return nil, fmt.Errorf("private key is out of range")
+	}
+	ecdsaKey := ToECDsaWithCurve(privateKey, curve)
 	pubKey, err := crypto.CreateECPoint(ecdsaKey.X, ecdsaKey.Y, &ecdsaKey.Curve)
 	if err != nil {
 		return nil, err
 	}
-	key := &KeyPair{privateKey, pubKey}
+	key := &KeyPair{PrivateKey: privateKey, PublicKey: pubKey}
 	return key, nil
 }
 
@@ -108,12 +121,24 @@ func NewKeyPairFromNEP2(nep2 string, passphrase string, version byte, N, R, P in
 }
 
 func GenerateKeyPair() (*KeyPair, error) {
-	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	return GenerateKeyPairWithCurve(crypto.P256)
+}
+
+// GenerateKeyPairWithCurve generates a random key pair on the curve
+func GenerateKeyPairWithCurve(curve elliptic.Curve) (*KeyPair, error) {
+	if curve == crypto.Secp256k1 {
+		key, err := secp256k1.GeneratePrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		return NewKeyPairWithCurve(key.Serialize(), curve)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(curve, rand.Reader)
 	if err != nil {
 		return nil, err
 	}
-	privateKey := ecdsaKey.D.Bytes()
-	return NewKeyPair(privateKey)
+	privateKey := ecdsaKey.D.FillBytes(make([]byte, 32))
+	return NewKeyPairWithCurve(privateKey, curve)
 }
 
 func (p *KeyPair) CompareTo(q *KeyPair) int {
@@ -125,13 +150,23 @@ func (p *KeyPair) CompareTo(q *KeyPair) int {
 
 // ToECDsa converts the key to a usable ecdsa.PrivateKey for signing data.
 func (p *KeyPair) ToECDsa() *ecdsa.PrivateKey {
-	return ToECDsa(p.PrivateKey)
+	return ToECDsaWithCurve(p.PrivateKey, p.GetCurve())
+}
+
+// GetCurve returns the curve of the key pair, secp256r1 by default
+func (p *KeyPair) GetCurve() elliptic.Curve {
+	return curveOf(p.PublicKey)
 }
 
-// ToECDsa converts the private key byte[] to a usable ecdsa.PrivateKey for signing data.
+// ToECDsa converts the private key byte[] on secp256r1 to a usable ecdsa.PrivateKey for signing data.
 func ToECDsa(key []byte) *ecdsa.PrivateKey {
+	return ToECDsaWithCurve(key, crypto.P256)
+}
+
+// ToECDsaWithCurve converts the private key byte[] on the curve to a usable ecdsa.PrivateKey for signing data.
+func ToECDsaWithCurve(key []byte, curve elliptic.Curve) *ecdsa.PrivateKey {
 	ecdsaKey := new(ecdsa.PrivateKey)
-	ecdsaKey.PublicKey.Curve = elliptic.P256()
+	ecdsaKey.PublicKey.Curve = curve
 	ecdsaKey.D = new(big.Int).SetBytes(key)
 	ecdsaKey.PublicKey.X, ecdsaKey.PublicKey.Y = ecdsaKey.PublicKey.Curve.ScalarBaseMult(key)
 	return ecdsaKey
@@ -174,29 +209,81 @@ func (p *KeyPair) ExportWithPassword(password string, version byte, N, R, P int)
 	return crypto.Base58CheckEncode(buffer), nil
 }
 
+// GetPublicKey implements ISigner, a KeyPair is the signer of the keys in memory
+func (p *KeyPair) GetPublicKey() *crypto.ECPoint {
+	return p.PublicKey
+}
+
 // String implements the Stringer interface.
 func (p *KeyPair) String() string {
 	return helper.BytesToHex(p.PrivateKey)
 }
 
-// Sign signs message with KeyPair
+// Sign signs the SHA-256 hash of message with KeyPair
 func (p *KeyPair) Sign(message []byte) ([]byte, error) {
+	return p.SignWithHash(message, crypto.HashSHA256)
+}
+
+// SignWithHash signs the hash of message, e.g. crypto.HashKeccak256 for the signatures verified by Ethereum
+func (p *KeyPair) SignWithHash(message []byte, hash crypto.HashAlgorithm) ([]byte, error) {
+	return p.SignHash(hash.Hash(message))
+}
+
+// SignHash signs a hash value with the Options of the key pair, the signature is r||s of 64 bytes
+func (p *KeyPair) SignHash(hash []byte) ([]byte, error) {
+	return p.SignHashWithOptions(hash, p.Options)
+}
+
+// SignWithOptions signs the SHA-256 hash of message, e.g. with deterministic nonces for reproducible signatures
+func (p *KeyPair) SignWithOptions(message []byte, options SignatureOptions) ([]byte, error) {
+	return p.SignHashWithOptions(crypto.Sha256(message), options)
+}
+
+// SignHashWithOptions signs a hash value, the signature is r||s of 64 bytes. Keys on secp256k1 are signed by
+// github.com/decred/dcrd/dcrec/secp256k1, whose signatures always use the nonce of RFC 6979 and a low S.
+func (p *KeyPair) SignHashWithOptions(hash []byte, options SignatureOptions) ([]byte, error) {
+	if p.GetCurve() == crypto.Secp256k1 {
+		return signSecp256k1(p.PrivateKey, hash), nil
+	}
 	privateKey := p.ToECDsa()
-	hash := sha256.Sum256(message)
-	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash[:])
+	var sig *Signature
+	if options.Deterministic {
+		var err error
+		sig, err = signDeterministic(privateKey.Curve, privateKey.D, hash)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash)
+		if err != nil {
+			return nil, err
+		}
+		sig = &Signature{R: r, S: s}
+	}
+	if options.LowS {
+		sig = sig.ToLowS(privateKey.Curve)
+	}
+	return sig.Bytes(privateKey.Curve), nil
+}
 
+// SignRecoverable signs the hash of message and appends the recovery id, the signature is r||s||v of 65 bytes
+// with v in {0, 1}, the format of Ethereum (add 27 to v for ecrecover). S is in the lower half of the order.
+func (p *KeyPair) SignRecoverable(message []byte, hash crypto.HashAlgorithm) ([]byte, error) {
+	digest := hash.Hash(message)
+	options := p.Options
+	options.LowS = true
+	signature, err := p.SignHashWithOptions(digest, options)
 	if err != nil {
 		return nil, err
 	}
-
-	params := privateKey.Curve.Params()
-	curveOrderByteSize := params.P.BitLen() / 8
-	rBytes, sBytes := r.Bytes(), s.Bytes()
-	signature := make([]byte, curveOrderByteSize*2)
-	copy(signature[curveOrderByteSize-len(rBytes):], rBytes)
-	copy(signature[curveOrderByteSize*2-len(sBytes):], sBytes)
-
-	return signature, nil
+	curve := p.GetCurve()
+	for v := byte(0); v < 2; v++ {
+		q, err := RecoverPubKeyWithRecoveryId(curve, digest, signature, v)
+		if err == nil && q.Equals(p.PublicKey) {
+			return append(signature, v), nil
+		}
+	}
+	return nil, fmt.Errorf("failed to find the recovery id")
 }
 
 // ExistsIn returns true if p is in list
@@ -209,17 +296,27 @@ func (p *KeyPair) ExistsIn(list []KeyPair) bool {
 	return false
 }
 
-// VerifySignature returns true if the signature is valid and corresponds to the hash and public key
+// VerifySignature returns true if the signature is valid and corresponds to the SHA-256 hash and public key
 func VerifySignature(message []byte, signature []byte, p *crypto.ECPoint) bool {
-	hash := sha256.Sum256(message)
-	publicKey := p.ToECDsa()
+	return VerifySignatureWithHash(message, signature, p, crypto.HashSHA256)
+}
 
-	if p.X == nil || p.Y == nil {
+// VerifySignatureWithHash returns true if the signature is valid and corresponds to the hash and public key,
+// the curve is the curve of the public key
+func VerifySignatureWithHash(message []byte, signature []byte, p *crypto.ECPoint, hash crypto.HashAlgorithm) bool {
+	if p == nil || p.X == nil || p.Y == nil {
+		return false
+	}
+	publicKey := p.ToECDsa()
+	if publicKey.Curve == nil {
+		publicKey.Curve = crypto.P256
+	}
+	// r||s, or r||s||v of a recoverable signature
+	sig, err := parseSignature(publicKey.Curve, signature)
+	if err != nil {
 		return false
 	}
-	rBytes := new(big.Int).SetBytes(signature[0:32])
-	sBytes := new(big.Int).SetBytes(signature[32:64])
-	return ecdsa.Verify(publicKey, hash[:], rBytes, sBytes)
+	return ecdsa.Verify(publicKey, hash.Hash(message), sig.R, sig.S)
 }
 
 // VerifyMultiSig returns true if the multi-signature is valid and corresponds to the hash and public keys
@@ -1,10 +1,12 @@
 package keys
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 )
 
@@ -135,3 +137,33 @@ func TestVerifySignature2(t *testing.T) {
 	actual := VerifySignature(sample, signedData, wrongPubKey)
 	assert.Equal(t, false, actual)
 }
+
+func TestNewKeyPairWithCurve(t *testing.T) {
+	pair, err := NewKeyPairWithCurve(helper.HexToBytes("0000000000000000000000000000000000000000000000000000000000000001"), crypto.Secp256k1)
+	assert.Nil(t, err)
+	assert.Equal(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", pair.PublicKey.String())
+	assert.Equal(t, crypto.Secp256k1, pair.GetCurve())
+
+	_, err = NewKeyPairWithCurve(make([]byte, 32), crypto.Secp256k1)
+	assert.NotNil(t, err)
+	_, err = NewKeyPairWithCurve(crypto.Secp256k1.Params().N.Bytes(), crypto.Secp256k1)
+	assert.NotNil(t, err)
+
+	pair, err = GenerateKeyPairWithCurve(crypto.Secp256k1)
+	assert.Nil(t, err)
+	sig, err := pair.Sign([]byte("neo"))
+	assert.Nil(t, err)
+	assert.True(t, VerifySignature([]byte("neo"), sig, pair.PublicKey))
+
+	// secp256k1 keys are signed by decred, which matches the RFC 6979 nonce with a low S
+	hash := crypto.Sha256([]byte("neo"))
+	expected, err := signDeterministic(crypto.Secp256k1, new(big.Int).SetBytes(pair.PrivateKey), hash)
+	assert.Nil(t, err)
+	expected = expected.ToLowS(crypto.Secp256k1)
+	assert.Equal(t, expected.Bytes(crypto.Secp256k1), sig)
+	for _, options := range []SignatureOptions{{}, {Deterministic: true}, {LowS: true}} {
+		s, err := pair.SignWithOptions([]byte("neo"), options)
+		assert.Nil(t, err)
+		assert.Equal(t, sig, s)
+	}
+}
@@ -0,0 +1,105 @@
+package keys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+var wordIndex map[string]int
+
+func init() {
+	wordIndex = make(map[string]int, len(EnglishWordlist))
+	for i, w := range EnglishWordlist {
+		wordIndex[w] = i
+	}
+}
+
+// NewMnemonic generates a BIP-39 mnemonic of 12, 15, 18, 21 or 24 words from 128 to 256 bits of random entropy
+func NewMnemonic(bits int) (string, error) {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", fmt.Errorf("entropy bits should be 128, 160, 192, 224 or 256, got %d", bits)
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+	return NewMnemonicFromEntropy(entropy)
+}
+
+// NewMnemonicFromEntropy encodes the entropy with its checksum into English words
+func NewMnemonicFromEntropy(entropy []byte) (string, error) {
+	n := len(entropy) * 8
+	if n < 128 || n > 256 || n%32 != 0 {
+		return "", fmt.Errorf("entropy length %d is invalid", len(entropy))
+	}
+	hash := sha256.Sum256(entropy)
+	data := append(append([]byte{}, entropy...), hash[0])
+	count := (n + n/32) / 11
+	words := make([]string, count)
+	for i := 0; i < count; i++ {
+		index := 0
+		for j := 0; j < 11; j++ {
+			bit := i*11 + j
+			index = index<<1 | int(data[bit/8]>>(7-bit%8)&1)
+		}
+		words[i] = EnglishWordlist[index]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy decodes the words and verifies the checksum
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	count := len(words)
+	if count < 12 || count > 24 || count%3 != 0 {
+		return nil, fmt.Errorf("mnemonic should have 12, 15, 18, 21 or 24 words, got %d", count)
+	}
+	data := make([]byte, (count*11+7)/8)
+	for i, w := range words {
+		index, ok := wordIndex[strings.ToLower(w)]
+		if !ok {
+			return nil, fmt.Errorf("invalid mnemonic word %s", w)
+		}
+		for j := 0; j < 11; j++ {
+			if index>>(10-j)&1 == 1 {
+				bit := i*11 + j
+				data[bit/8] |= 1 << (7 - bit%8)
+			}
+		}
+	}
+	checksumBits := count / 3
+	entropy := data[:(count*11-checksumBits)/8]
+	hash := sha256.Sum256(entropy)
+	if hash[0]>>(8-checksumBits) != data[len(entropy)]>>(8-checksumBits) {
+		return nil, fmt.Errorf("invalid mnemonic checksum")
+	}
+	return entropy, nil
+}
+
+// IsMnemonicValid returns true if the mnemonic has valid words and checksum
+func IsMnemonicValid(mnemonic string) bool {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err == nil
+}
+
+// MnemonicToSeed returns the 64 bytes BIP-39 seed, the passphrase can be empty.
+// The mnemonic is validated first, use MnemonicToSeedUnchecked for the mnemonics of other wordlists.
+func MnemonicToSeed(mnemonic string, passphrase string) ([]byte, error) {
+	if _, err := MnemonicToEntropy(mnemonic); err != nil {
+		return nil, err
+	}
+	return MnemonicToSeedUnchecked(mnemonic, passphrase), nil
+}
+
+// MnemonicToSeedUnchecked derives the seed without checking the words
+func MnemonicToSeedUnchecked(mnemonic string, passphrase string) []byte {
+	m := strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	return pbkdf2.Key([]byte(m), []byte(salt), 2048, 64, sha512.New)
+}
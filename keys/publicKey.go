@@ -30,18 +30,51 @@ func RecoverPubKeyFromSigOnSecp256r1(message, signature []byte) ([]*crypto.ECPoi
 	return RecoverPubKeyFromSig(crypto.P256, message, signature)
 }
 
-// RecoverPubKeyFromSig recovers a point on a given curve from a signature
+// RecoverPubKeyFromSigOnSecp256k1 recovers the points on Secp256k1 from a signature of the hash of message
+func RecoverPubKeyFromSigOnSecp256k1(message, signature []byte, hash crypto.HashAlgorithm) ([]*crypto.ECPoint, error) {
+	if len(signature) != 64 {
+		return nil, fmt.Errorf("invalid signature length")
+	}
+	return RecoverPubKeyFromHash(crypto.Secp256k1, hash.Hash(message), signature)
+}
+
+// RecoverPubKeyFromSig recovers the points on a given curve from a signature of the SHA-256 hash of message
 func RecoverPubKeyFromSig(curve elliptic.Curve, message, signature []byte) ([]*crypto.ECPoint, error) {
-	hash := crypto.Sha256(message)
-	//curve := crypto.P256
-	bitLen := (curve.Params().BitSize + 7) / 8
-	R := new(big.Int).SetBytes(signature[0:bitLen])
-	S := new(big.Int).SetBytes(signature[bitLen:])
+	return RecoverPubKeyFromHash(curve, crypto.Sha256(message), signature)
+}
+
+// RecoverPubKeyFromHash recovers the points on a given curve from a signature of the hash value
+func RecoverPubKeyFromHash(curve elliptic.Curve, hash, signature []byte) ([]*crypto.ECPoint, error) {
+	sig, err := parseSignature(curve, signature)
+	if err != nil {
+		return nil, err
+	}
+	return recoverKeyFromSignature(curve, sig, hash, true)
+}
+
+// RecoverPubKeyWithRecoveryId recovers the only point of the recovery id, e.g. v of an Ethereum signature minus 27
+func RecoverPubKeyWithRecoveryId(curve elliptic.Curve, hash, signature []byte, recoveryId byte) (*crypto.ECPoint, error) {
+	sig, err := parseSignature(curve, signature)
+	if err != nil {
+		return nil, err
+	}
+	return recoverKey(curve, sig, hash, int(recoveryId>>1), uint(recoveryId&1), true)
+}
+
+func parseSignature(curve elliptic.Curve, signature []byte) (*Signature, error) {
+	byteLen := (curve.Params().BitSize + 7) / 8
+	if len(signature) != 2*byteLen && (len(signature) != 2*byteLen+1 || !isRecoveryId(signature[2*byteLen])) {
+		return nil, fmt.Errorf("invalid signature length")
+	}
 	sig := &Signature{
-		R: R,
-		S: S,
+		R: new(big.Int).SetBytes(signature[0:byteLen]),
+		S: new(big.Int).SetBytes(signature[byteLen : 2*byteLen]),
+	}
+	n := curve.Params().N
+	if sig.R.Sign() == 0 || sig.S.Sign() == 0 || sig.R.Cmp(n) >= 0 || sig.S.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("invalid signature")
 	}
-	return recoverKeyFromSignature(crypto.P256, sig, hash, true)
+	return sig, nil
 }
 
 // recoverKeyFromSignature recovers a public key from the signature "sig" on the
@@ -58,61 +91,11 @@ func recoverKeyFromSignature(curve elliptic.Curve, sig *Signature, hash []byte,
 	result := []*crypto.ECPoint{}
 
 	for i := 0; uint64(i) <= h.Uint64(); i++ {
-
-		// 1.1 x = (n * i) + r
-		Rx := new(big.Int).Mul(curve.Params().N, new(big.Int).SetInt64(int64(i)))
-		Rx.Add(Rx, sig.R)
-		if Rx.Cmp(curve.Params().P) != -1 {
-			continue
-		}
-
-		// convert 02<Rx> to point R. (step 1.2 and 1.3). If we are on an odd
-		// iteration then 1.6 will be done with -R, so we calculate the other
-		// term when uncompressing the point.
-		//Ry, err := decodeCompressedY(curve, Rx, uint(i))
 		for j := uint(0); j <= 1; j++ {
-			Ry, err := decodeCompressedY(curve, Rx, j)
+			Q, err := recoverKey(curve, sig, hash, i, j, doChecks)
 			if err != nil {
 				continue
 			}
-
-			// 1.4 Check n*R is point at infinity
-			if doChecks {
-				nRx, nRy := curve.ScalarMult(Rx, Ry, curve.Params().N.Bytes())
-				if nRx.Sign() != 0 || nRy.Sign() != 0 {
-					continue
-				}
-			}
-
-			// 1.5 calculate e from message using the same algorithm as ecdsa
-			// signature calculation.
-			e := hashToInt(hash, curve)
-
-			// Step 1.6.1:
-			// We calculate the two terms sR and eG separately multiplied by the
-			// inverse of r (from the signature). We then add them to calculate
-			// Q = r^-1(sR-eG)
-			invr := new(big.Int).ModInverse(sig.R, curve.Params().N)
-
-			// first term.
-			invrS := new(big.Int).Mul(invr, sig.S)
-			invrS.Mod(invrS, curve.Params().N)
-			sRx, sRy := curve.ScalarMult(Rx, Ry, invrS.Bytes())
-
-			// second term.
-			e.Neg(e)
-			e.Mod(e, curve.Params().N)
-			e.Mul(e, invr)
-			e.Mod(e, curve.Params().N)
-			minuseGx, minuseGy := curve.ScalarBaseMult(e.Bytes())
-
-			// TODO: this would be faster if we did a mult and add in one
-			// step to prevent the jacobian conversion back and forth.
-			Qx, Qy := curve.Add(sRx, sRy, minuseGx, minuseGy)
-			Q, err := crypto.CreateECPoint(Qx, Qy, &curve)
-			if err != nil {
-				return nil, err
-			}
 			result = append(result, Q)
 		}
 	}
@@ -122,6 +105,62 @@ func recoverKeyFromSignature(curve elliptic.Curve, sig *Signature, hash []byte,
 	return result, nil
 }
 
+// recoverKey recovers the point of R with x = r + i*n and the parity j of y, the recovery id is 2*i + j
+func recoverKey(curve elliptic.Curve, sig *Signature, hash []byte, i int, j uint, doChecks bool) (*crypto.ECPoint, error) {
+	// 1.1 x = (n * i) + r
+	Rx := new(big.Int).Mul(curve.Params().N, new(big.Int).SetInt64(int64(i)))
+	Rx.Add(Rx, sig.R)
+	if Rx.Cmp(curve.Params().P) != -1 {
+		return nil, fmt.Errorf("invalid recovery id")
+	}
+
+	// convert 02<Rx> to point R. (step 1.2 and 1.3). If we are on an odd
+	// iteration then 1.6 will be done with -R, so we calculate the other
+	// term when uncompressing the point.
+	Ry, err := crypto.DecompressY(curve, Rx, j)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1.4 Check n*R is point at infinity
+	if doChecks {
+		nRx, nRy := curve.ScalarMult(Rx, Ry, curve.Params().N.Bytes())
+		if nRx.Sign() != 0 || nRy.Sign() != 0 {
+			return nil, fmt.Errorf("invalid signature")
+		}
+	}
+
+	// 1.5 calculate e from message using the same algorithm as ecdsa
+	// signature calculation.
+	e := hashToInt(hash, curve)
+
+	// Step 1.6.1:
+	// We calculate the two terms sR and eG separately multiplied by the
+	// inverse of r (from the signature). We then add them to calculate
+	// Q = r^-1(sR-eG)
+	invr := new(big.Int).ModInverse(sig.R, curve.Params().N)
+
+	// first term.
+	invrS := new(big.Int).Mul(invr, sig.S)
+	invrS.Mod(invrS, curve.Params().N)
+	sRx, sRy := curve.ScalarMult(Rx, Ry, invrS.Bytes())
+
+	// second term.
+	e.Neg(e)
+	e.Mod(e, curve.Params().N)
+	e.Mul(e, invr)
+	e.Mod(e, curve.Params().N)
+	minuseGx, minuseGy := curve.ScalarBaseMult(e.Bytes())
+
+	// TODO: this would be faster if we did a mult and add in one
+	// step to prevent the jacobian conversion back and forth.
+	Qx, Qy := curve.Add(sRx, sRy, minuseGx, minuseGy)
+	if Qx.Sign() == 0 && Qy.Sign() == 0 {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	return crypto.CreateECPoint(Qx, Qy, &curve)
+}
+
 // hashToInt converts a hash value to an integer. There is some disagreement
 // about how this is done. [NSA] suggests that this is done in the obvious
 // manner, but [SECG] truncates the hash to the bit-length of the curve order
@@ -142,26 +181,3 @@ func hashToInt(hash []byte, c elliptic.Curve) *big.Int {
 	}
 	return ret
 }
-
-// decodeCompressedY performs decompression of Y coordinate for given X and Y's least significant bit
-func decodeCompressedY(curve elliptic.Curve, x *big.Int, ylsb uint) (*big.Int, error) {
-
-	cp := curve.Params()
-	three := big.NewInt(3)
-
-	xCubed := new(big.Int).Exp(x, three, cp.P)
-	threeX := new(big.Int).Mul(x, three)
-	threeX.Mod(threeX, cp.P)
-	ySquared := new(big.Int).Sub(xCubed, threeX)
-	ySquared.Add(ySquared, cp.B)
-	ySquared.Mod(ySquared, cp.P)
-	y := new(big.Int).ModSqrt(ySquared, cp.P)
-	if y == nil {
-		return nil, fmt.Errorf("error computing Y for compressed point")
-	}
-	if y.Bit(0) != ylsb {
-		y.Neg(y)
-		y.Mod(y, cp.P)
-	}
-	return y, nil
-}
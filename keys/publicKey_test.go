@@ -2,6 +2,7 @@ package keys
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -92,3 +93,49 @@ func TestVerifySignature3(t *testing.T) {
 //	pubKey, _ := RecoverPubKeyFromSigOnSecp256r1(msg, sig)
 //	fmt.Println(pubKey.String())
 //}
+
+func TestRecoverPubKeyFromSigOnSecp256k1(t *testing.T) {
+	pair, err := NewKeyPairWithCurve(helper.HexToBytes("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"), crypto.Secp256k1)
+	assert.Nil(t, err)
+	// the Ethereum address is the last 20 bytes of the Keccak-256 of the uncompressed key
+	address := crypto.Keccak256(pair.PublicKey.EncodePoint(false)[1:])[12:]
+	assert.Equal(t, "2c7536e3605d9c16a7a3d7b1898e529396a65c23", helper.BytesToHex(address))
+
+	msg := []byte("Hello World")
+	for _, hash := range []crypto.HashAlgorithm{crypto.HashSHA256, crypto.HashKeccak256} {
+		sig, err := pair.SignWithHash(msg, hash)
+		assert.Nil(t, err)
+		assert.True(t, VerifySignatureWithHash(msg, sig, pair.PublicKey, hash))
+		pubKeys, err := RecoverPubKeyFromSigOnSecp256k1(msg, sig, hash)
+		assert.Nil(t, err)
+		found := false
+		for _, p := range pubKeys {
+			found = found || p.Equals(pair.PublicKey)
+		}
+		assert.True(t, found)
+
+		sig, err = pair.SignRecoverable(msg, hash)
+		assert.Nil(t, err)
+		assert.Equal(t, 65, len(sig))
+		s := new(big.Int).SetBytes(sig[32:64])
+		assert.True(t, s.Cmp(new(big.Int).Rsh(crypto.Secp256k1.Params().N, 1)) <= 0)
+		p, err := RecoverPubKeyWithRecoveryId(crypto.Secp256k1, hash.Hash(msg), sig[:64], sig[64])
+		assert.Nil(t, err)
+		assert.True(t, p.Equals(pair.PublicKey))
+	}
+	sig, _ := pair.SignWithHash(msg, crypto.HashKeccak256)
+	assert.False(t, VerifySignatureWithHash(msg, sig, pair.PublicKey, crypto.HashSHA256))
+	assert.False(t, VerifySignatureWithHash(msg, sig[:63], pair.PublicKey, crypto.HashKeccak256))
+	assert.True(t, VerifySignatureWithHash(msg, append(sig, 27), pair.PublicKey, crypto.HashKeccak256))
+	assert.False(t, VerifySignatureWithHash(msg, append(sig, 0, 0), pair.PublicKey, crypto.HashKeccak256))
+	_, err = RecoverPubKeyFromHash(crypto.Secp256k1, crypto.Keccak256(msg), append(sig, 0xff))
+	assert.NotNil(t, err)
+
+	// recoverable signatures on secp256r1
+	r1, _ := NewKeyPair(helper.HexToBytes(KeyCases[0].PrivateKey))
+	sig, err = r1.SignRecoverable(msg, crypto.HashSHA256)
+	assert.Nil(t, err)
+	p, err := RecoverPubKeyWithRecoveryId(crypto.P256, crypto.Sha256(msg), sig[:64], sig[64])
+	assert.Nil(t, err)
+	assert.True(t, p.Equals(r1.PublicKey))
+}
@@ -0,0 +1,115 @@
+package keys
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// signDeterministic signs hash with the nonce of RFC 6979, section 3.2, HMAC-SHA256 is the HMAC
+func signDeterministic(curve elliptic.Curve, d *big.Int, hash []byte) (*Signature, error) {
+	n := curve.Params().N
+	e := bits2int(hash, n)
+	nonces := newNonceGenerator(n, d, hash)
+	for i := 0; i < 64; i++ {
+		k := nonces.next()
+		rx, _ := curve.ScalarBaseMult(k.Bytes())
+		r := new(big.Int).Mod(rx, n)
+		if r.Sign() == 0 {
+			continue
+		}
+		// s = k⁻¹(e + r * d) mod n
+		s := new(big.Int).Mul(r, d)
+		s.Add(s, e)
+		s.Mul(s, new(big.Int).ModInverse(k, n))
+		s.Mod(s, n)
+		if s.Sign() != 0 {
+			return &Signature{R: r, S: s}, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to find a nonce")
+}
+
+// nonceGenerator is the HMAC_DRBG of RFC 6979, section 3.2
+type nonceGenerator struct {
+	n    *big.Int
+	k, v []byte
+}
+
+func newNonceGenerator(n, d *big.Int, hash []byte) *nonceGenerator {
+	rolen := (n.BitLen() + 7) / 8
+	x := d.FillBytes(make([]byte, rolen))
+	// bits2octets(h1)
+	h := bits2int(hash, n)
+	if h.Cmp(n) >= 0 {
+		h.Sub(h, n)
+	}
+	h1 := h.FillBytes(make([]byte, rolen))
+
+	g := &nonceGenerator{
+		n: n,
+		k: make([]byte, sha256.Size),
+		v: make([]byte, sha256.Size),
+	}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+	g.k = g.mac(g.v, []byte{0x00}, x, h1)
+	g.v = g.mac(g.v)
+	g.k = g.mac(g.v, []byte{0x01}, x, h1)
+	g.v = g.mac(g.v)
+	return g
+}
+
+func (g *nonceGenerator) mac(data ...[]byte) []byte {
+	m := hmac.New(sha256.New, g.k)
+	for _, b := range data {
+		m.Write(b)
+	}
+	return m.Sum(nil)
+}
+
+// next returns the next candidate of k in [1, n - 1]
+func (g *nonceGenerator) next() *big.Int {
+	qlen := g.n.BitLen()
+	for {
+		var t []byte
+		for len(t)*8 < qlen {
+			g.v = g.mac(g.v)
+			t = append(t, g.v...)
+		}
+		k := bits2int(t, g.n)
+		g.k = g.mac(g.v, []byte{0x00})
+		g.v = g.mac(g.v)
+		if k.Sign() > 0 && k.Cmp(g.n) < 0 {
+			return k
+		}
+	}
+}
+
+// bits2int takes the leftmost bits of b as long as the order n, the same as crypto/ecdsa does with the hash
+func bits2int(b []byte, n *big.Int) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if excess := len(b)*8 - n.BitLen(); excess > 0 {
+		v.Rsh(v, uint(excess))
+	}
+	return v
+}
+
+// signSecp256k1 signs hash with the ecdsa package of decred, which is used by btcd, instead of crypto/ecdsa on
+// the generic curve arithmetic, the signature is r||s
+func signSecp256k1(privateKey []byte, hash []byte) []byte {
+	key := secp256k1.PrivKeyFromBytes(privateKey)
+	defer key.Zero()
+	sig := ecdsa.Sign(key, hash)
+	r, s := sig.R(), sig.S()
+	signature := make([]byte, 64)
+	r.PutBytesUnchecked(signature[:32])
+	s.PutBytesUnchecked(signature[32:])
+	return signature
+}
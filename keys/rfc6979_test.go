@@ -0,0 +1,66 @@
+package keys
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// RFC 6979, appendix A.2.5, P-256 with SHA-256
+func TestKeyPair_SignWithOptions(t *testing.T) {
+	pair, err := NewKeyPair(helper.HexToBytes("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721"))
+	assert.Nil(t, err)
+	options := SignatureOptions{Deterministic: true}
+
+	signature, err := pair.SignWithOptions([]byte("sample"), options)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.ToLower("EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716"+
+		"F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8"), helper.BytesToHex(signature))
+
+	signature, err = pair.SignWithOptions([]byte("test"), options)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.ToLower("F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367"+
+		"019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083"), helper.BytesToHex(signature))
+	assert.True(t, VerifySignature([]byte("test"), signature, pair.PublicKey))
+
+	// the s of "sample" is high
+	options.LowS = true
+	signature, err = pair.SignWithOptions([]byte("sample"), options)
+	assert.Nil(t, err)
+	s := new(big.Int).SetBytes(helper.HexToBytes("f7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8"))
+	assert.Equal(t, new(big.Int).Sub(crypto.P256.Params().N, s), new(big.Int).SetBytes(signature[32:]))
+	assert.True(t, VerifySignatureStrict([]byte("sample"), signature, pair.PublicKey))
+}
+
+func TestKeyPair_Sign_Options(t *testing.T) {
+	pair, _ := NewKeyPair(helper.HexToBytes("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721"))
+
+	pair.Options = SignatureOptions{Deterministic: true, LowS: true}
+	s1, err := pair.Sign([]byte("message"))
+	assert.Nil(t, err)
+	s2, _ := pair.Sign([]byte("message"))
+	assert.Equal(t, s1, s2)
+
+	// secp256k1
+	k1, _ := NewKeyPairWithCurve(helper.HexToBytes("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"), crypto.Secp256k1)
+	s1, _ = k1.SignWithHash([]byte("message"), crypto.HashKeccak256)
+	s2, _ = k1.SignWithHash([]byte("message"), crypto.HashKeccak256)
+	assert.Equal(t, s1, s2)
+	assert.True(t, VerifySignatureWithHash([]byte("message"), s1, k1.PublicKey, crypto.HashKeccak256))
+
+	// the options of another key pair are not used
+	other, _ := NewKeyPair(pair.PrivateKey)
+	s1, _ = other.Sign([]byte("message"))
+	s2, _ = other.Sign([]byte("message"))
+	assert.NotEqual(t, s1, s2)
+
+	pair.Options = SignatureOptions{}
+	s1, _ = pair.Sign([]byte("message"))
+	s2, _ = pair.Sign([]byte("message"))
+	assert.NotEqual(t, s1, s2)
+}
@@ -1,9 +1,138 @@
 package keys
 
-import "math/big"
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+)
 
 // Signature is a type representing an ecdsa signature.
 type Signature struct {
 	R *big.Int
 	S *big.Int
 }
+
+// SignatureOptions controls how KeyPair makes signatures and how NormalizeSignature accepts them
+type SignatureOptions struct {
+	// Deterministic derives the nonce from the private key and the hash with RFC 6979 instead of crypto/rand,
+	// the same key and message always give the same signature
+	Deterministic bool
+	// LowS replaces s with n - s if s > n / 2, both are valid and the high one is a malleated copy
+	LowS bool
+	// Strict rejects signatures that are not exactly r||s or canonical DER
+	Strict bool
+}
+
+// ParseSignature decodes a signature of r||s or ASN.1 DER. Without strict, r||s||v of a recoverable signature is
+// accepted too, v is the recovery id in [0, 3] or in [27, 30] of Ethereum, and it is dropped.
+func ParseSignature(signature []byte, curve elliptic.Curve, strict bool) (*Signature, error) {
+	byteLen := (curve.Params().BitSize + 7) / 8
+	var sig *Signature
+	if len(signature) == 2*byteLen {
+		sig = &Signature{
+			R: new(big.Int).SetBytes(signature[:byteLen]),
+			S: new(big.Int).SetBytes(signature[byteLen:]),
+		}
+	} else if der, ok := parseDER(signature); ok {
+		sig = der
+	} else if !strict && len(signature) == 2*byteLen+1 && isRecoveryId(signature[2*byteLen]) {
+		sig = &Signature{
+			R: new(big.Int).SetBytes(signature[:byteLen]),
+			S: new(big.Int).SetBytes(signature[byteLen : 2*byteLen]),
+		}
+	} else {
+		return nil, fmt.Errorf("invalid signature encoding of length %d", len(signature))
+	}
+	n := curve.Params().N
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || sig.R.Cmp(n) >= 0 || sig.S.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("invalid signature: r or s is out of range")
+	}
+	return sig, nil
+}
+
+func isRecoveryId(v byte) bool {
+	return v <= 3 || (v >= 27 && v <= 30)
+}
+
+// parseDER only accepts the canonical encoding, the lengths and the integers must be minimal
+func parseDER(signature []byte) (*Signature, bool) {
+	var inner cryptobyte.String
+	r, s := new(big.Int), new(big.Int)
+	input := cryptobyte.String(signature)
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) || !input.Empty() ||
+		!inner.ReadASN1Integer(r) || !inner.ReadASN1Integer(s) || !inner.Empty() {
+		return nil, false
+	}
+	return &Signature{R: r, S: s}, true
+}
+
+// Bytes returns r||s, each is padded to the byte length of the curve
+func (sig *Signature) Bytes(curve elliptic.Curve) []byte {
+	byteLen := (curve.Params().BitSize + 7) / 8
+	result := make([]byte, 2*byteLen)
+	sig.R.FillBytes(result[:byteLen])
+	sig.S.FillBytes(result[byteLen:])
+	return result
+}
+
+// DER returns the ASN.1 DER encoding of the signature
+func (sig *Signature) DER() []byte {
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BigInt(sig.R)
+		b.AddASN1BigInt(sig.S)
+	})
+	return b.BytesOrPanic()
+}
+
+// IsLowS returns true if s <= n / 2
+func (sig *Signature) IsLowS(curve elliptic.Curve) bool {
+	return sig.S.Cmp(new(big.Int).Rsh(curve.Params().N, 1)) <= 0
+}
+
+// ToLowS returns the signature with s <= n / 2, it is still valid for the same key and message
+func (sig *Signature) ToLowS(curve elliptic.Curve) *Signature {
+	if sig.IsLowS(curve) {
+		return sig
+	}
+	return &Signature{R: sig.R, S: new(big.Int).Sub(curve.Params().N, sig.S)}
+}
+
+// NormalizeSignature converts a signature of r||s or DER to the r||s used by neo with the options, e.g. to check
+// the signatures of an HSM or a remote signer
+func NormalizeSignature(signature []byte, curve elliptic.Curve, options SignatureOptions) ([]byte, error) {
+	sig, err := ParseSignature(signature, curve, options.Strict)
+	if err != nil {
+		return nil, err
+	}
+	if options.LowS {
+		sig = sig.ToLowS(curve)
+	}
+	return sig.Bytes(curve), nil
+}
+
+// VerifySignatureStrict is VerifySignature which rejects the malformed encodings and the signatures with a high s
+func VerifySignatureStrict(message []byte, signature []byte, p *crypto.ECPoint) bool {
+	if p == nil || p.X == nil || p.Y == nil {
+		return false
+	}
+	curve := curveOf(p)
+	sig, err := ParseSignature(signature, curve, true)
+	if err != nil || !sig.IsLowS(curve) {
+		return false
+	}
+	return VerifySignature(message, sig.Bytes(curve), p)
+}
+
+// curveOf returns the curve of the public key, secp256r1 by default
+func curveOf(p *crypto.ECPoint) elliptic.Curve {
+	if p == nil || p.Curve == nil {
+		return crypto.P256
+	}
+	return p.Curve
+}
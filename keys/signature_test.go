@@ -0,0 +1,88 @@
+package keys
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+func TestParseSignature(t *testing.T) {
+	sig := &Signature{R: big.NewInt(1), S: big.NewInt(0x80)}
+	der := sig.DER()
+	// the integer 0x80 needs a leading zero
+	assert.Equal(t, "300702010102020080", helper.BytesToHex(der))
+
+	for _, strict := range []bool{true, false} {
+		parsed, err := ParseSignature(der, crypto.P256, strict)
+		assert.Nil(t, err)
+		assert.Equal(t, sig, parsed)
+		parsed, err = ParseSignature(sig.Bytes(crypto.P256), crypto.P256, strict)
+		assert.Nil(t, err)
+		assert.Equal(t, sig, parsed)
+	}
+
+	// a recoverable signature is only accepted without strict
+	raw := append(sig.Bytes(crypto.P256), 1)
+	parsed, err := ParseSignature(raw, crypto.P256, false)
+	assert.Nil(t, err)
+	assert.Equal(t, sig, parsed)
+	_, err = ParseSignature(raw, crypto.P256, true)
+	assert.NotNil(t, err)
+	raw[64] = 28
+	_, err = ParseSignature(raw, crypto.P256, false)
+	assert.Nil(t, err)
+	// anything else longer than r||s is not truncated
+	raw[64] = 4
+	_, err = ParseSignature(raw, crypto.P256, false)
+	assert.NotNil(t, err)
+	_, err = ParseSignature(append(sig.Bytes(crypto.P256), 0, 0), crypto.P256, false)
+	assert.NotNil(t, err)
+
+	// non-minimal integer, trailing data, zero and out of range values
+	invalid := [][]byte{
+		{0x30, 0x07, 0x02, 0x02, 0x00, 0x01, 0x02, 0x01, 0x01},
+		append(append([]byte{}, der...), 0x00),
+		(&Signature{R: big.NewInt(0), S: big.NewInt(1)}).DER(),
+		(&Signature{R: crypto.P256.Params().N, S: big.NewInt(1)}).DER(),
+		{0x30, 0x01},
+	}
+	for _, b := range invalid {
+		_, err = ParseSignature(b, crypto.P256, true)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestNormalizeSignature(t *testing.T) {
+	n := crypto.P256.Params().N
+	high := &Signature{R: big.NewInt(1), S: new(big.Int).Sub(n, big.NewInt(1))}
+	assert.False(t, high.IsLowS(crypto.P256))
+	assert.True(t, high.ToLowS(crypto.P256).IsLowS(crypto.P256))
+	assert.Equal(t, int64(1), high.ToLowS(crypto.P256).S.Int64())
+
+	b, err := NormalizeSignature(high.DER(), crypto.P256, SignatureOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, high.Bytes(crypto.P256), b)
+	b, err = NormalizeSignature(high.DER(), crypto.P256, SignatureOptions{LowS: true})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), new(big.Int).SetBytes(b[32:]).Int64())
+}
+
+func TestVerifySignatureStrict(t *testing.T) {
+	pair, _ := GenerateKeyPair()
+	message := []byte("message")
+	signature, _ := pair.SignWithOptions(message, SignatureOptions{LowS: true})
+	assert.True(t, VerifySignatureStrict(message, signature, pair.PublicKey))
+
+	sig, _ := ParseSignature(signature, crypto.P256, true)
+	assert.True(t, VerifySignatureStrict(message, sig.DER(), pair.PublicKey))
+
+	// the malleated signature is valid but rejected in strict mode
+	malleated := &Signature{R: sig.R, S: new(big.Int).Sub(crypto.P256.Params().N, sig.S)}
+	assert.True(t, VerifySignature(message, malleated.Bytes(crypto.P256), pair.PublicKey))
+	assert.False(t, VerifySignatureStrict(message, malleated.Bytes(crypto.P256), pair.PublicKey))
+	assert.False(t, VerifySignatureStrict(message, append(signature, 0), pair.PublicKey))
+}
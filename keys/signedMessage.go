@@ -0,0 +1,131 @@
+package keys
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// MessageFormat is how an off-chain message is serialized before it is signed
+type MessageFormat byte
+
+const (
+	// MessageFormatPlain signs the message as it is, the same as KeyPair.Sign
+	MessageFormatPlain MessageFormat = iota
+	// MessageFormatSalted is signMessage of NeoLine and Neon, 0x010001f0 || varint || salt + message || 0x0000
+	MessageFormatSalted
+	// MessageFormatWithoutSalt is signMessageWithoutSalt of NeoLine and Neon, the salted format with an empty salt
+	MessageFormatWithoutSalt
+)
+
+// messagePrefix makes the serialized message an invalid transaction, so that it can't be replayed on chain
+var messagePrefix = []byte{0x01, 0x00, 0x01, 0xf0}
+
+// SignedMessage is the result of signMessage of the dApi of NeoLine and Neon, the signature is in Data
+type SignedMessage struct {
+	PublicKey string `json:"publicKey"`
+	Data      string `json:"data"`
+	Salt      string `json:"salt,omitempty"`
+	Message   string `json:"message"`
+}
+
+// SerializeMessage returns the bytes which are signed for the message in the format
+func SerializeMessage(message, salt string, format MessageFormat) ([]byte, error) {
+	switch format {
+	case MessageFormatPlain:
+		return []byte(message), nil
+	case MessageFormatWithoutSalt:
+		salt = ""
+		fallthrough
+	case MessageFormatSalted:
+		parameter := []byte(salt + message)
+		data := append([]byte{}, messagePrefix...)
+		data = append(data, helper.VarIntFromInt(len(parameter)).Bytes()...)
+		data = append(data, parameter...)
+		return append(data, 0x00, 0x00), nil
+	default:
+		return nil, fmt.Errorf("unknown message format %d", format)
+	}
+}
+
+// SignMessage signs an off-chain message, a random salt of 32 hex characters is used for MessageFormatSalted
+func SignMessage(signer ISigner, message string, format MessageFormat) (*SignedMessage, error) {
+	salt := ""
+	if format == MessageFormatSalted {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		salt = helper.BytesToHex(b)
+	}
+	data, err := SerializeMessage(message, salt, format)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedMessage{
+		PublicKey: signer.GetPublicKey().String(),
+		Data:      helper.BytesToHex(signature),
+		Salt:      salt,
+		Message:   message,
+	}, nil
+}
+
+// VerifyMessage returns true if the signature of the message is valid for its public key
+func VerifyMessage(m *SignedMessage, format MessageFormat) bool {
+	publicKey, err := crypto.NewECPointFromString(m.PublicKey)
+	if err != nil {
+		return false
+	}
+	data, signature, err := m.decode(format)
+	if err != nil {
+		return false
+	}
+	return VerifySignature(data, signature, publicKey)
+}
+
+// VerifyMessageFromAddress returns true if the message is signed by the account of the address, e.g. for
+// login-with-wallet. Without a public key in the message, the public keys are recovered from the signature.
+func VerifyMessageFromAddress(m *SignedMessage, format MessageFormat, address string, version byte) bool {
+	data, signature, err := m.decode(format)
+	if err != nil {
+		return false
+	}
+	var candidates []*crypto.ECPoint
+	if m.PublicKey != "" {
+		publicKey, err := crypto.NewECPointFromString(m.PublicKey)
+		if err != nil {
+			return false
+		}
+		candidates = []*crypto.ECPoint{publicKey}
+	} else if candidates, err = RecoverPubKeyFromSigOnSecp256r1(data, signature); err != nil {
+		return false
+	}
+	for _, publicKey := range candidates {
+		if PublicKeyToAddress(publicKey, version) == address && VerifySignature(data, signature, publicKey) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *SignedMessage) decode(format MessageFormat) ([]byte, []byte, error) {
+	data, err := SerializeMessage(m.Message, m.Salt, format)
+	if err != nil {
+		return nil, nil, err
+	}
+	signature, err := hex.DecodeString(m.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(signature) != 64 {
+		return nil, nil, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	return data, signature, nil
+}
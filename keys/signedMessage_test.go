@@ -0,0 +1,46 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+func TestSerializeMessage(t *testing.T) {
+	b, err := SerializeMessage("hi", "ab", MessageFormatSalted)
+	assert.Nil(t, err)
+	assert.Equal(t, "010001f0"+"04"+"61626869"+"0000", helper.BytesToHex(b))
+	b, _ = SerializeMessage("hi", "ab", MessageFormatWithoutSalt)
+	assert.Equal(t, "010001f0"+"02"+"6869"+"0000", helper.BytesToHex(b))
+	b, _ = SerializeMessage("hi", "ab", MessageFormatPlain)
+	assert.Equal(t, "6869", helper.BytesToHex(b))
+	_, err = SerializeMessage("hi", "", MessageFormat(9))
+	assert.NotNil(t, err)
+}
+
+func TestSignMessage(t *testing.T) {
+	pair, _ := NewKeyPairFromWIF(KeyCases[0].Wif)
+	address := PublicKeyToAddress(pair.PublicKey, helper.DefaultAddressVersion)
+	for _, format := range []MessageFormat{MessageFormatPlain, MessageFormatSalted, MessageFormatWithoutSalt} {
+		m, err := SignMessage(pair, "login 1700000000", format)
+		assert.Nil(t, err)
+		assert.Equal(t, format == MessageFormatSalted, len(m.Salt) == 32)
+		assert.True(t, VerifyMessage(m, format))
+		assert.True(t, VerifyMessageFromAddress(m, format, address, helper.DefaultAddressVersion))
+		assert.False(t, VerifyMessageFromAddress(m, format, "NdtB8RXRmJ7Nhw1FPTm7E6HoDZGnDw37nf", helper.DefaultAddressVersion))
+
+		// recover the public key
+		m.PublicKey = ""
+		assert.True(t, VerifyMessageFromAddress(m, format, address, helper.DefaultAddressVersion))
+
+		m.Message = "login 1700000001"
+		assert.False(t, VerifyMessageFromAddress(m, format, address, helper.DefaultAddressVersion))
+	}
+
+	m, _ := SignMessage(pair, "message", MessageFormatSalted)
+	assert.False(t, VerifyMessage(m, MessageFormatWithoutSalt))
+	m.Data = "00"
+	assert.False(t, VerifyMessage(m, MessageFormatSalted))
+}
@@ -1,12 +1,15 @@
 package nep17
 
 import (
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/rpc"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/tx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"math/big"
+	"path/filepath"
 	"testing"
 )
 
@@ -162,3 +165,24 @@ func TestNep17Helper_BalanceOf(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, big.NewInt(8913620128), b)
 }
+
+// TestNep17Helper_Replay uses fixtures recorded against a local stub node in the response format of neo-cli, not
+// against MainNet or TestNet
+func TestNep17Helper_Replay(t *testing.T) {
+	replay, err := rpc.NewReplayHttpClient(filepath.Join("testdata", "replay"))
+	assert.Nil(t, err)
+	nh := NewNep17Helper(tx.GasToken, rpc.NewClientWithHttpClient("http://localhost:20332", replay))
+
+	symbol, err := nh.Symbol()
+	assert.Nil(t, err)
+	assert.Equal(t, "GAS", symbol)
+	decimals, err := nh.Decimals()
+	assert.Nil(t, err)
+	assert.Equal(t, 8, decimals)
+	totalSupply, err := nh.TotalSupply()
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(5204843915467600), totalSupply)
+	b, err := nh.BalanceOf(helper.UInt160FromBytes(crypto.Hash160([]byte{0x01})))
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(1500000000), b)
+}
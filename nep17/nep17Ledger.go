@@ -0,0 +1,356 @@
+package nep17
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"sync"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/indexer"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+const TransferEvent = "Transfer"
+
+// Transfer is a decoded nep17 Transfer notification, From is nil for minting and To is nil for burning
+type Transfer struct {
+	Token      *helper.UInt160
+	BlockIndex uint32
+	BlockTime  int
+	TxId       string // empty for transfers in OnPersist and PostPersist, e.g. GAS fee burning and rewards
+	Index      int    // position of the notification in its execution
+	From       *helper.UInt160
+	To         *helper.UInt160
+	Amount     *big.Int
+}
+
+// LedgerEntry is one side of a transfer seen by a tracked account, Balance is the running balance after it
+type LedgerEntry struct {
+	*Transfer
+	Account *helper.UInt160
+	Delta   *big.Int
+	Balance *big.Int
+}
+
+// Mismatch is a difference found when reconciling the ledger with balanceOf
+type Mismatch struct {
+	Token      *helper.UInt160
+	Account    *helper.UInt160
+	BlockIndex uint32
+	Ledger     *big.Int
+	Chain      *big.Int
+}
+
+// Nep17Ledger builds the transfer history of accounts from Transfer notifications, without the TokensTracker plugin.
+// It implements indexer.Sink, so it is fed by an indexer.Follower filtered on the tokens and the Transfer event.
+// Running balances start from zero unless an opening balance is set, so the follower should start at the deploy
+// height of the tokens or at the height of the opening balances. GAS fees are burnt in OnPersist and rewards are
+// minted in PostPersist, NewLedgerFollower fetches the block logs for them when GAS is tracked.
+type Nep17Ledger struct {
+	Tokens     []*helper.UInt160
+	Accounts   []*helper.UInt160 // empty to track every account
+	height     uint32
+	synced     bool
+	entries    []*LedgerEntry
+	balances   map[string]*big.Int // token + account -> balance
+	mismatches []*Mismatch
+	lock       sync.RWMutex
+}
+
+func NewNep17Ledger(tokens []*helper.UInt160, accounts []*helper.UInt160) *Nep17Ledger {
+	return &Nep17Ledger{
+		Tokens:   tokens,
+		Accounts: accounts,
+		balances: map[string]*big.Int{},
+	}
+}
+
+// NewLedgerFollower creates a follower which only fetches what the ledger needs. The ledger is kept in memory, so
+// use a LedgerCheckpoint to persist it with the height, a follower with another checkpoint fails to resume unless
+// the ledger is restored to the saved height first.
+func NewLedgerFollower(client rpc.IRpcClient, ledger *Nep17Ledger, checkpoint indexer.Checkpoint) *indexer.Follower {
+	f := indexer.NewFollower(client, ledger, checkpoint)
+	if f == nil {
+		return nil
+	}
+	if checkpoint != nil {
+		f.Checkpoint = &ledgerGuard{Checkpoint: checkpoint, ledger: ledger}
+	}
+	f.Filter = indexer.Filter{
+		Contracts:     ledger.Tokens,
+		EventNames:    []string{TransferEvent},
+		InternalCalls: true, // transfers are often made by other contracts, e.g. dex swaps
+	}
+	// only native contracts run in OnPersist and PostPersist, and GAS is the only one transferring there
+	f.IncludeBlockLogs = tx.GasToken.ExistsIn(ledger.Tokens)
+	return f
+}
+
+// SetOpeningBalance sets the balance of an account before the first indexed block
+func (l *Nep17Ledger) SetOpeningBalance(token, account *helper.UInt160, balance *big.Int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.balances[balanceKey(token, account)] = new(big.Int).Set(balance)
+}
+
+func (l *Nep17Ledger) OnBlock(block *indexer.Block) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.height = uint32(block.Index)
+	l.synced = true
+	return nil
+}
+
+func (l *Nep17Ledger) OnTransaction(trx *indexer.Transaction) error {
+	return nil
+}
+
+// OnNotification records a Transfer, notifications of failed executions are ignored. A malformed Transfer of a
+// tracked token is an error, since skipping it would leave wrong balances, the follower stops at its block.
+func (l *Nep17Ledger) OnNotification(n *indexer.Notification) error {
+	if n.VMState != "HALT" || n.EventName != TransferEvent || !n.Contract.ExistsIn(l.Tokens) {
+		return nil
+	}
+	t, err := DecodeTransfer(n)
+	if err != nil {
+		return fmt.Errorf("malformed Transfer of %s at block %d, tx %s: %v", n.Contract.String(), n.BlockIndex, n.TxId, err)
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if t.From != nil && l.tracks(t.From) {
+		l.record(t, t.From, new(big.Int).Neg(t.Amount))
+	}
+	if t.To != nil && l.tracks(t.To) {
+		l.record(t, t.To, t.Amount)
+	}
+	return nil
+}
+
+func (l *Nep17Ledger) tracks(account *helper.UInt160) bool {
+	return len(l.Accounts) == 0 || account.ExistsIn(l.Accounts)
+}
+
+func (l *Nep17Ledger) record(t *Transfer, account *helper.UInt160, delta *big.Int) {
+	key := balanceKey(t.Token, account)
+	balance, ok := l.balances[key]
+	if !ok {
+		balance = big.NewInt(0)
+	}
+	balance = new(big.Int).Add(balance, delta)
+	l.balances[key] = balance
+	l.entries = append(l.entries, &LedgerEntry{
+		Transfer: t,
+		Account:  account,
+		Delta:    delta,
+		Balance:  balance,
+	})
+}
+
+// DecodeTransfer decodes a nep17 Transfer notification, whose state is [from, to, amount]
+func DecodeTransfer(n *indexer.Notification) (*Transfer, error) {
+	if len(n.State) != 3 {
+		return nil, fmt.Errorf("invalid Transfer state count: %d", len(n.State))
+	}
+	from, err := decodeAccount(n.State[0])
+	if err != nil {
+		return nil, err
+	}
+	to, err := decodeAccount(n.State[1])
+	if err != nil {
+		return nil, err
+	}
+	s, ok := n.State[2].Value.(string)
+	if n.State[2].Type != "Integer" || !ok {
+		return nil, fmt.Errorf("invalid Transfer amount type: %s", n.State[2].Type)
+	}
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid Transfer amount: %s", s)
+	}
+	return &Transfer{
+		Token:      n.Contract,
+		BlockIndex: n.BlockIndex,
+		BlockTime:  n.BlockTime,
+		TxId:       n.TxId,
+		Index:      n.Index,
+		From:       from,
+		To:         to,
+		Amount:     amount,
+	}, nil
+}
+
+func decodeAccount(s models.InvokeStack) (*helper.UInt160, error) {
+	if s.Type == "Any" && s.Value == nil {
+		return nil, nil
+	}
+	v, ok := s.Value.(string)
+	if s.Type != "ByteString" || !ok {
+		return nil, fmt.Errorf("invalid Transfer account type: %s", s.Type)
+	}
+	b, err := crypto.Base64Decode(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != helper.UINT160SIZE {
+		return nil, fmt.Errorf("invalid Transfer account length: %d", len(b))
+	}
+	return helper.UInt160FromBytes(b), nil
+}
+
+// Height returns the last indexed block, false if no block is indexed yet
+func (l *Nep17Ledger) Height() (uint32, bool) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.height, l.synced
+}
+
+// BalanceOf returns the running balance of an account
+func (l *Nep17Ledger) BalanceOf(token, account *helper.UInt160) *big.Int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	balance, ok := l.balances[balanceKey(token, account)]
+	if !ok {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(balance)
+}
+
+// History returns the entries of an account for a token in chain order, token can be nil for all tokens
+func (l *Nep17Ledger) History(token, account *helper.UInt160) []*LedgerEntry {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	result := []*LedgerEntry{}
+	for _, e := range l.entries {
+		if e.Account.Equals(account) && (token == nil || e.Token.Equals(token)) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Entries returns all the entries in chain order
+func (l *Nep17Ledger) Entries() []*LedgerEntry {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return append([]*LedgerEntry{}, l.entries...)
+}
+
+// Mismatches returns all the mismatches found by Reconcile
+func (l *Nep17Ledger) Mismatches() []*Mismatch {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return append([]*Mismatch{}, l.mismatches...)
+}
+
+// Reconcile compares the running balances with balanceOf on chain. balanceOf returns the balance at the latest block,
+// so the ledger must be synced to the current block height, and an error is returned if a new block is persisted
+// during the check, in which case it can be retried after syncing again.
+// The accounts checked are Accounts, or every account seen so far if Accounts is empty.
+func (l *Nep17Ledger) Reconcile(client rpc.IRpcClient) ([]*Mismatch, error) {
+	height, synced := l.Height()
+	if !synced {
+		return nil, fmt.Errorf("ledger has not indexed any block")
+	}
+	if err := checkHeight(client, height); err != nil {
+		return nil, err
+	}
+	accounts := l.trackedAccounts()
+	found := []*Mismatch{}
+	for _, token := range l.Tokens {
+		nep17Helper := NewNep17Helper(token, client)
+		for _, account := range accounts {
+			chain, err := nep17Helper.BalanceOf(account)
+			if err != nil {
+				return nil, err
+			}
+			ledger := l.BalanceOf(token, account)
+			if ledger.Cmp(chain) != 0 {
+				found = append(found, &Mismatch{
+					Token:      token,
+					Account:    account,
+					BlockIndex: height,
+					Ledger:     ledger,
+					Chain:      chain,
+				})
+			}
+		}
+	}
+	if err := checkHeight(client, height); err != nil {
+		return nil, err
+	}
+	l.lock.Lock()
+	l.mismatches = append(l.mismatches, found...)
+	l.lock.Unlock()
+	return found, nil
+}
+
+func checkHeight(client rpc.IRpcClient, height uint32) error {
+	response := client.GetBlockCount()
+	if response.HasError() {
+		return fmt.Errorf(response.GetErrorInfo())
+	}
+	if uint32(response.Result) != height+1 {
+		return fmt.Errorf("ledger is at block %d, but the chain is at block %d", height, response.Result-1)
+	}
+	return nil
+}
+
+func (l *Nep17Ledger) trackedAccounts() []*helper.UInt160 {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	if len(l.Accounts) != 0 {
+		return l.Accounts
+	}
+	accounts := []*helper.UInt160{}
+	for _, e := range l.entries {
+		if !e.Account.ExistsIn(accounts) {
+			accounts = append(accounts, e.Account)
+		}
+	}
+	return accounts
+}
+
+// WriteCsv writes all the entries for auditing, amounts are in the smallest unit of the token
+func (l *Nep17Ledger) WriteCsv(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"block", "time", "txid", "index", "token", "account", "from", "to", "delta", "balance"})
+	if err != nil {
+		return err
+	}
+	for _, e := range l.Entries() {
+		err = cw.Write([]string{
+			strconv.FormatUint(uint64(e.BlockIndex), 10),
+			strconv.Itoa(e.BlockTime),
+			e.TxId,
+			strconv.Itoa(e.Index),
+			"0x" + e.Token.String(),
+			"0x" + e.Account.String(),
+			accountString(e.From),
+			accountString(e.To),
+			e.Delta.String(),
+			e.Balance.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func accountString(u *helper.UInt160) string {
+	if u == nil {
+		return ""
+	}
+	return "0x" + u.String()
+}
+
+func balanceKey(token, account *helper.UInt160) string {
+	return token.String() + account.String()
+}
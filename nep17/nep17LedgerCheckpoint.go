@@ -0,0 +1,190 @@
+package nep17
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/indexer"
+)
+
+// LedgerSnapshot is the persisted state of a Nep17Ledger, Next is the next block height to index
+type LedgerSnapshot struct {
+	Next     uint32             `json:"next"`
+	Height   uint32             `json:"height"`
+	Synced   bool               `json:"synced"`
+	Balances []*SnapshotBalance `json:"balances"`
+	Entries  []*SnapshotEntry   `json:"entries"`
+}
+
+type SnapshotBalance struct {
+	Token   *helper.UInt160 `json:"token"`
+	Account *helper.UInt160 `json:"account"`
+	Balance *big.Int        `json:"balance"`
+}
+
+type SnapshotEntry struct {
+	Token      *helper.UInt160 `json:"token"`
+	BlockIndex uint32          `json:"blockindex"`
+	BlockTime  int             `json:"blocktime"`
+	TxId       string          `json:"txid,omitempty"`
+	Index      int             `json:"index"`
+	From       *helper.UInt160 `json:"from,omitempty"`
+	To         *helper.UInt160 `json:"to,omitempty"`
+	Amount     *big.Int        `json:"amount"`
+	Account    *helper.UInt160 `json:"account"`
+	Delta      *big.Int        `json:"delta"`
+	Balance    *big.Int        `json:"balance"`
+}
+
+// Snapshot returns the state of the ledger after the blocks lower than next are indexed
+func (l *Nep17Ledger) Snapshot(next uint32) (*LedgerSnapshot, error) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	if next != 0 && (!l.synced || l.height+1 != next) {
+		return nil, fmt.Errorf("ledger is not at block %d", next-1)
+	}
+	s := &LedgerSnapshot{
+		Next:     next,
+		Height:   l.height,
+		Synced:   l.synced,
+		Balances: make([]*SnapshotBalance, 0, len(l.balances)),
+		Entries:  make([]*SnapshotEntry, len(l.entries)),
+	}
+	for key, balance := range l.balances {
+		token, err := helper.UInt160FromString(key[:2*helper.UINT160SIZE])
+		if err != nil {
+			return nil, err
+		}
+		account, err := helper.UInt160FromString(key[2*helper.UINT160SIZE:])
+		if err != nil {
+			return nil, err
+		}
+		s.Balances = append(s.Balances, &SnapshotBalance{Token: token, Account: account, Balance: balance})
+	}
+	for i, e := range l.entries {
+		s.Entries[i] = &SnapshotEntry{
+			Token:      e.Token,
+			BlockIndex: e.BlockIndex,
+			BlockTime:  e.BlockTime,
+			TxId:       e.TxId,
+			Index:      e.Index,
+			From:       e.From,
+			To:         e.To,
+			Amount:     e.Amount,
+			Account:    e.Account,
+			Delta:      e.Delta,
+			Balance:    e.Balance,
+		}
+	}
+	return s, nil
+}
+
+// Restore replaces the state of the ledger with a snapshot, the mismatches found so far are cleared
+func (l *Nep17Ledger) Restore(s *LedgerSnapshot) error {
+	balances := map[string]*big.Int{}
+	for _, b := range s.Balances {
+		if b.Token == nil || b.Account == nil || b.Balance == nil {
+			return fmt.Errorf("invalid balance in ledger snapshot")
+		}
+		balances[balanceKey(b.Token, b.Account)] = b.Balance
+	}
+	entries := make([]*LedgerEntry, len(s.Entries))
+	for i, e := range s.Entries {
+		if e.Token == nil || e.Account == nil || e.Amount == nil || e.Delta == nil || e.Balance == nil {
+			return fmt.Errorf("invalid entry %d in ledger snapshot", i)
+		}
+		entries[i] = &LedgerEntry{
+			Transfer: &Transfer{
+				Token:      e.Token,
+				BlockIndex: e.BlockIndex,
+				BlockTime:  e.BlockTime,
+				TxId:       e.TxId,
+				Index:      e.Index,
+				From:       e.From,
+				To:         e.To,
+				Amount:     e.Amount,
+			},
+			Account: e.Account,
+			Delta:   e.Delta,
+			Balance: e.Balance,
+		}
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.height = s.Height
+	l.synced = s.Synced
+	l.balances = balances
+	l.entries = entries
+	l.mismatches = nil
+	return nil
+}
+
+// LedgerCheckpoint saves the state of the ledger together with the next height in one json file,
+// so that a restarted follower resumes with the balances and entries of the blocks already indexed
+type LedgerCheckpoint struct {
+	Path   string
+	Ledger *Nep17Ledger
+}
+
+func NewLedgerCheckpoint(path string, ledger *Nep17Ledger) *LedgerCheckpoint {
+	return &LedgerCheckpoint{
+		Path:   path,
+		Ledger: ledger,
+	}
+}
+
+// Load restores the ledger from the file and returns the next height to index
+func (c *LedgerCheckpoint) Load() (uint32, bool, error) {
+	b, err := os.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	s := &LedgerSnapshot{}
+	err = json.Unmarshal(b, s)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid ledger checkpoint file %s: %v", c.Path, err)
+	}
+	err = c.Ledger.Restore(s)
+	if err != nil {
+		return 0, false, err
+	}
+	return s.Next, true, nil
+}
+
+// Save writes the ledger with the height, it replaces the file atomically
+func (c *LedgerCheckpoint) Save(next uint32) error {
+	s, err := c.Ledger.Snapshot(next)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return helper.WriteFileAtomic(c.Path, b)
+}
+
+// ledgerGuard refuses to resume from a checkpoint the ledger has not been restored to,
+// otherwise the follower would continue with empty balances and every reconciliation would be wrong
+type ledgerGuard struct {
+	indexer.Checkpoint
+	ledger *Nep17Ledger
+}
+
+func (g *ledgerGuard) Load() (uint32, bool, error) {
+	next, ok, err := g.Checkpoint.Load()
+	if err != nil || !ok {
+		return next, ok, err
+	}
+	height, synced := g.ledger.Height()
+	if !synced || height+1 != next {
+		return 0, false, fmt.Errorf("checkpoint is at block %d but the ledger is not restored to it, use a LedgerCheckpoint or Restore the ledger first", next)
+	}
+	return next, true, nil
+}
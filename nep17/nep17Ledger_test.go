@@ -0,0 +1,180 @@
+package nep17
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/indexer"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+var alice, _ = helper.UInt160FromString("0x0000000000000000000000000000000000000001")
+var bob, _ = helper.UInt160FromString("0x0000000000000000000000000000000000000002")
+
+func accountItem(u *helper.UInt160) models.InvokeStack {
+	if u == nil {
+		return models.InvokeStack{Type: "Any"}
+	}
+	return models.InvokeStack{Type: "ByteString", Value: crypto.Base64Encode(u.ToByteArray())}
+}
+
+func transferNotification(block uint32, from, to *helper.UInt160, amount string) *indexer.Notification {
+	return &indexer.Notification{
+		BlockIndex: block,
+		TxId:       "0x1234",
+		Trigger:    "Application",
+		VMState:    "HALT",
+		Contract:   tx.GasToken,
+		EventName:  TransferEvent,
+		State: []models.InvokeStack{
+			accountItem(from),
+			accountItem(to),
+			{Type: "Integer", Value: amount},
+		},
+	}
+}
+
+func balanceResponse(value string) rpc.InvokeResultResponse {
+	return rpc.InvokeResultResponse{
+		Result: models.InvokeResult{
+			State: "HALT",
+			Stack: []models.InvokeStack{{Type: "Integer", Value: value}},
+		},
+	}
+}
+
+func TestNep17Ledger(t *testing.T) {
+	ledger := NewNep17Ledger([]*helper.UInt160{tx.GasToken}, nil)
+	ledger.SetOpeningBalance(tx.GasToken, bob, big.NewInt(5))
+
+	assert.Nil(t, ledger.OnBlock(&indexer.Block{RpcBlockHeader: models.RpcBlockHeader{Index: 10}}))
+	assert.Nil(t, ledger.OnNotification(transferNotification(10, nil, alice, "100")))
+	assert.Nil(t, ledger.OnNotification(transferNotification(10, alice, bob, "30")))
+	// failed execution
+	n := transferNotification(10, alice, bob, "50")
+	n.VMState = "FAULT"
+	assert.Nil(t, ledger.OnNotification(n))
+	// malformed transfer of a tracked token
+	n = transferNotification(10, alice, bob, "-1")
+	assert.NotNil(t, ledger.OnNotification(n))
+	n.Contract = bob // not tracked
+	assert.Nil(t, ledger.OnNotification(n))
+	assert.Nil(t, ledger.OnNotification(transferNotification(10, bob, nil, "1")))
+
+	assert.Equal(t, big.NewInt(70), ledger.BalanceOf(tx.GasToken, alice))
+	assert.Equal(t, big.NewInt(34), ledger.BalanceOf(tx.GasToken, bob))
+	history := ledger.History(tx.GasToken, alice)
+	assert.Equal(t, 2, len(history))
+	assert.Nil(t, history[0].From)
+	assert.Equal(t, big.NewInt(-30), history[1].Delta)
+	assert.Equal(t, big.NewInt(70), history[1].Balance)
+	assert.Equal(t, 4, len(ledger.Entries()))
+
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 11})
+	clientMock.On("InvokeFunction", tx.GasToken.String(), "balanceOf", []models.RpcContractParameter{{Type: "Hash160", Value: alice}}, nil, false).
+		Return(balanceResponse("70"))
+	clientMock.On("InvokeFunction", tx.GasToken.String(), "balanceOf", []models.RpcContractParameter{{Type: "Hash160", Value: bob}}, nil, false).
+		Return(balanceResponse("35"))
+	mismatches, err := ledger.Reconcile(clientMock)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mismatches))
+	assert.True(t, mismatches[0].Account.Equals(bob))
+	assert.Equal(t, big.NewInt(34), mismatches[0].Ledger)
+	assert.Equal(t, big.NewInt(35), mismatches[0].Chain)
+	assert.Equal(t, uint32(10), mismatches[0].BlockIndex)
+	assert.Equal(t, 1, len(ledger.Mismatches()))
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, ledger.WriteCsv(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 5, len(lines))
+	assert.True(t, strings.HasSuffix(lines[2], ",-30,70"))
+}
+
+func TestNep17Ledger_ReconcileNotSynced(t *testing.T) {
+	ledger := NewNep17Ledger([]*helper.UInt160{tx.GasToken}, []*helper.UInt160{alice})
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 12})
+	_, err := ledger.Reconcile(clientMock)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, ledger.OnBlock(&indexer.Block{RpcBlockHeader: models.RpcBlockHeader{Index: 10}}))
+	_, err = ledger.Reconcile(clientMock)
+	assert.NotNil(t, err)
+	clientMock.AssertNotCalled(t, "InvokeFunction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNewLedgerFollower(t *testing.T) {
+	ledger := NewNep17Ledger([]*helper.UInt160{tx.GasToken}, nil)
+	f := NewLedgerFollower(new(rpc.RpcClientMock), ledger, nil)
+	assert.Equal(t, []string{TransferEvent}, f.Filter.EventNames)
+	assert.True(t, f.Filter.InternalCalls)
+	assert.True(t, f.IncludeBlockLogs)
+
+	f = NewLedgerFollower(new(rpc.RpcClientMock), NewNep17Ledger([]*helper.UInt160{tx.NeoToken}, nil), nil)
+	assert.False(t, f.IncludeBlockLogs)
+}
+
+func TestLedgerCheckpoint(t *testing.T) {
+	ledger := NewNep17Ledger([]*helper.UInt160{tx.GasToken}, nil)
+	ledger.SetOpeningBalance(tx.GasToken, bob, big.NewInt(5))
+	assert.Nil(t, ledger.OnBlock(&indexer.Block{RpcBlockHeader: models.RpcBlockHeader{Index: 10}}))
+	assert.Nil(t, ledger.OnNotification(transferNotification(10, nil, alice, "100")))
+	assert.Nil(t, ledger.OnNotification(transferNotification(10, alice, bob, "30")))
+
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	checkpoint := NewLedgerCheckpoint(path, ledger)
+	assert.NotNil(t, checkpoint.Save(12)) // the ledger is at block 10
+	assert.Nil(t, checkpoint.Save(11))
+
+	restored := NewNep17Ledger([]*helper.UInt160{tx.GasToken}, nil)
+	next, ok, err := NewLedgerCheckpoint(path, restored).Load()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(11), next)
+	height, synced := restored.Height()
+	assert.True(t, synced)
+	assert.Equal(t, uint32(10), height)
+	assert.Equal(t, big.NewInt(70), restored.BalanceOf(tx.GasToken, alice))
+	assert.Equal(t, big.NewInt(35), restored.BalanceOf(tx.GasToken, bob))
+	assert.Equal(t, ledger.Entries(), restored.Entries())
+
+	// a follower resumes from a LedgerCheckpoint
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 11})
+	f := NewLedgerFollower(clientMock, restored, NewLedgerCheckpoint(path, restored))
+	next, err = f.Sync(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(11), next)
+}
+
+func TestNewLedgerFollower_EmptyLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "height")
+	assert.Nil(t, indexer.NewFileCheckpoint(path).Save(11))
+
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 11})
+	ledger := NewNep17Ledger([]*helper.UInt160{tx.GasToken}, nil)
+	f := NewLedgerFollower(clientMock, ledger, indexer.NewFileCheckpoint(path))
+	_, err := f.Sync(context.Background())
+	assert.NotNil(t, err)
+	clientMock.AssertNotCalled(t, "GetBlockCount")
+
+	// resuming is allowed once the ledger is restored to the checkpoint
+	assert.Nil(t, ledger.Restore(&LedgerSnapshot{Height: 10, Synced: true}))
+	next, err := f.Sync(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(11), next)
+}
@@ -0,0 +1,461 @@
+package notary
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/joeqian10/neo3-gogogo/wallet"
+)
+
+const PolicyContractId = "0xcc5e4edd9f5f8dba8bb65734541df7a1c081c67b"
+
+var PolicyContract, _ = helper.UInt160FromString(PolicyContractId)
+
+// NotaryHelper builds the transactions for the Notary service: the deposit, the main transaction signed by some
+// of its signers, and the fallback transaction paid from the deposit if the main one is not completed in time.
+// The keys are taken from the wallet of Helper.
+type NotaryHelper struct {
+	Helper *wallet.WalletHelper
+	Client rpc.IRpcClient
+}
+
+func NewNotaryHelper(wh *wallet.WalletHelper) *NotaryHelper {
+	if wh == nil {
+		return nil
+	}
+	return &NotaryHelper{
+		Helper: wh,
+		Client: wh.Client,
+	}
+}
+
+// GetFeePerKey returns the fee of the NotaryAssisted attribute for each key, it is set in the Policy contract
+func (n *NotaryHelper) GetFeePerKey() (int64, error) {
+	args := []models.RpcContractParameter{
+		{Type: "Integer", Value: byte(tx.NotaryAssisted)},
+	}
+	response := n.Client.InvokeFunction(PolicyContract.String(), "getAttributeFee", args, nil, false)
+	return popInteger(response)
+}
+
+// GetMaxNotValidBeforeDelta returns the max distance between NotValidBefore of a fallback and the current height,
+// and between it and ValidUntilBlock
+func (n *NotaryHelper) GetMaxNotValidBeforeDelta() (uint32, error) {
+	response := n.Client.InvokeFunction(tx.NotaryContract.String(), "getMaxNotValidBeforeDelta", nil, nil, false)
+	i, err := popInteger(response)
+	return uint32(i), err
+}
+
+// BalanceOf returns the GAS deposited for the account
+func (n *NotaryHelper) BalanceOf(account *helper.UInt160) (*big.Int, error) {
+	args := []models.RpcContractParameter{
+		{Type: "Hash160", Value: account},
+	}
+	response := n.Client.InvokeFunction(tx.NotaryContract.String(), "balanceOf", args, nil, false)
+	i, err := popInteger(response)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(i), nil
+}
+
+// ExpirationOf returns the height till which the deposit of the account is locked
+func (n *NotaryHelper) ExpirationOf(account *helper.UInt160) (uint32, error) {
+	args := []models.RpcContractParameter{
+		{Type: "Hash160", Value: account},
+	}
+	response := n.Client.InvokeFunction(tx.NotaryContract.String(), "expirationOf", args, nil, false)
+	i, err := popInteger(response)
+	return uint32(i), err
+}
+
+// CalculateDeposit returns the GAS to add to the deposit of account so that it can pay for the fallback,
+// a new deposit is at least twice the fee per key
+func (n *NotaryHelper) CalculateDeposit(account *helper.UInt160, fallback *tx.Transaction) (*big.Int, error) {
+	balance, err := n.BalanceOf(account)
+	if err != nil {
+		return nil, err
+	}
+	need := big.NewInt(fallback.GetSystemFee() + fallback.GetNetworkFee())
+	if balance.Sign() == 0 {
+		feePerKey, err := n.GetFeePerKey()
+		if err != nil {
+			return nil, err
+		}
+		if min := big.NewInt(2 * feePerKey); need.Cmp(min) < 0 {
+			need = min
+		}
+	}
+	need.Sub(need, balance)
+	if need.Sign() < 0 {
+		need.SetInt64(0)
+	}
+	return need, nil
+}
+
+// MakeDepositTransaction makes a transaction which transfers GAS from the account to the Notary contract,
+// the deposit belongs to "to", or to the account if "to" is nil, and is locked till the block of height till
+func (n *NotaryHelper) MakeDepositTransaction(from *helper.UInt160, to *helper.UInt160, amount *big.Int, till uint32, magic uint32) (*tx.Transaction, error) {
+	height, err := n.Helper.GetBlockHeight()
+	if err != nil {
+		return nil, err
+	}
+	if till < height+2 {
+		return nil, fmt.Errorf("deposit should be locked till at least %d", height+2)
+	}
+	receiver := sc.ContractParameter{Type: sc.Hash160}
+	if to != nil {
+		receiver.Value = to
+	}
+	sb := sc.NewScriptBuilder()
+	sb.EmitDynamicCall(tx.GasToken, "transfer", []interface{}{
+		sc.ContractParameter{Type: sc.Hash160, Value: from},
+		sc.ContractParameter{Type: sc.Hash160, Value: tx.NotaryContract},
+		sc.ContractParameter{Type: sc.Integer, Value: amount},
+		sc.ContractParameter{Type: sc.Array, Value: []sc.ContractParameter{
+			receiver,
+			{Type: sc.Integer, Value: till},
+		}},
+	})
+	sb.Emit(sc.ASSERT)
+	script, err := sb.ToArray()
+	if err != nil {
+		return nil, err
+	}
+	balance, err := n.Helper.GetBalanceFromAccount(tx.GasToken, from)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("insufficient GAS")
+	}
+	balanceGas := []*wallet.AccountAndBalance{{Account: from, Value: new(big.Int).Sub(balance, amount)}}
+	cosigners := []*tx.Signer{tx.NewSigner(from, tx.CalledByEntry)}
+	trx, err := n.Helper.MakeTransaction(script, cosigners, nil, balanceGas)
+	if err != nil {
+		return nil, err
+	}
+	return n.Helper.SignTransaction(trx, magic)
+}
+
+// Deposit sends a transaction made by MakeDepositTransaction
+func (n *NotaryHelper) Deposit(from *helper.UInt160, to *helper.UInt160, amount *big.Int, till uint32, magic uint32) (string, error) {
+	trx, err := n.MakeDepositTransaction(from, to, amount, till, magic)
+	if err != nil {
+		return "", err
+	}
+	return n.send(trx)
+}
+
+// CalculateNetworkFee calculates the network fee of a transaction with the Notary contract as a signer.
+// The Notary witness is priced like a signature check, and the NotaryAssisted attribute costs (NKeys+1) times
+// the fee per key. Other signers are calculated by Helper, their verification scripts are taken from the wallet
+// or from the witnesses of the transaction.
+func (n *NotaryHelper) CalculateNetworkFee(trx *tx.Transaction) (uint64, error) {
+	index := notaryIndex(trx)
+	if index < 0 {
+		return n.Helper.CalculateNetworkFee(trx)
+	}
+	signers := append([]*tx.Signer{}, trx.GetSigners()[:index]...)
+	signers = append(signers, trx.GetSigners()[index+1:]...)
+	witnesses := trx.GetWitnesses()
+	if len(witnesses) == len(trx.GetSigners()) {
+		witnesses = append(append([]*tx.Witness{}, witnesses[:index]...), witnesses[index+1:]...)
+	}
+	others := &tx.Transaction{}
+	others.SetVersion(trx.GetVersion())
+	others.SetNonce(trx.GetNonce())
+	others.SetValidUntilBlock(trx.GetValidUntilBlock())
+	others.SetSigners(signers)
+	others.SetAttributes(trx.GetAttributes())
+	others.SetScript(trx.GetScript())
+	others.SetWitnesses(witnesses)
+	fee, err := n.Helper.CalculateNetworkFee(others)
+	if err != nil {
+		return 0, err
+	}
+	notarySigner := tx.NewSigner(tx.NotaryContract, tx.None)
+	notaryWitness := &tx.Witness{InvocationScript: DummyInvocationScript(), VerificationScript: []byte{}}
+	size := notarySigner.GetSize() + notaryWitness.GetSize()
+	settings := n.Helper.GetProtocolSettings()
+	fee += uint64(int64(size) * settings.GetFeePerByte())
+	fee += uint64(settings.GetExecFeeFactor() * (sc.OpCodePrices[sc.PUSHDATA1] + tx.ECDsaVerifyPrice))
+	if a := getNotaryAssisted(trx); a != nil {
+		feePerKey, err := n.GetFeePerKey()
+		if err != nil {
+			return 0, err
+		}
+		fee += uint64((int64(a.NKeys) + 1) * feePerKey)
+	}
+	return fee, nil
+}
+
+// MakeMainTransaction makes an unsigned transaction whose witnesses are collected by the Notary service from nKeys keys.
+// The first cosigner is the sender, the Notary contract is appended as the last signer. ValidUntilBlock is set to
+// the current height plus the max NotValidBefore delta, so that a fallback can be made for it.
+func (n *NotaryHelper) MakeMainTransaction(script []byte, cosigners []*tx.Signer, nKeys byte) (*tx.Transaction, error) {
+	if len(cosigners) == 0 {
+		return nil, fmt.Errorf("main transaction should have at least one signer besides the Notary contract")
+	}
+	height, err := n.Helper.GetBlockHeight()
+	if err != nil {
+		return nil, err
+	}
+	delta, err := n.GetMaxNotValidBeforeDelta()
+	if err != nil {
+		return nil, err
+	}
+	rb, err := helper.GenerateRandomBytes(4)
+	if err != nil {
+		return nil, err
+	}
+	signers := append([]*tx.Signer{}, cosigners...)
+	signers = append(signers, tx.NewSigner(tx.NotaryContract, tx.None))
+	trx := tx.NewTransaction()
+	trx.SetNonce(helper.BytesToUInt32(rb))
+	trx.SetScript(script)
+	trx.SetValidUntilBlock(height + delta)
+	trx.SetSigners(signers)
+	trx.SetAttributes([]tx.ITransactionAttribute{tx.NewNotaryAssistedAttribute(nKeys)})
+	if err = trx.ValidateAttributes(); err != nil {
+		return nil, err
+	}
+	if err = n.setFees(trx, cosigners[0].Account); err != nil {
+		return nil, err
+	}
+	return trx, nil
+}
+
+// MakeFallbackTransaction makes an unsigned fallback for the main transaction, it is sent by the Notary contract and
+// paid from the deposit of account. It can be persisted from the block after notValidBefore if the main transaction
+// is not, and the script is RET if it is nil.
+func (n *NotaryHelper) MakeFallbackTransaction(main *tx.Transaction, account *helper.UInt160, notValidBefore uint32, script []byte) (*tx.Transaction, error) {
+	height, err := n.Helper.GetBlockHeight()
+	if err != nil {
+		return nil, err
+	}
+	delta, err := n.GetMaxNotValidBeforeDelta()
+	if err != nil {
+		return nil, err
+	}
+	vub := main.GetValidUntilBlock()
+	if notValidBefore > height+delta {
+		return nil, fmt.Errorf("NotValidBefore %d should not be higher than %d", notValidBefore, height+delta)
+	}
+	if vub > notValidBefore+delta {
+		return nil, fmt.Errorf("NotValidBefore %d should not be lower than %d", notValidBefore, vub-delta)
+	}
+	if script == nil {
+		script = []byte{byte(sc.RET)}
+	}
+	rb, err := helper.GenerateRandomBytes(4)
+	if err != nil {
+		return nil, err
+	}
+	trx := tx.NewTransaction()
+	trx.SetNonce(helper.BytesToUInt32(rb))
+	trx.SetScript(script)
+	trx.SetValidUntilBlock(vub)
+	trx.SetSigners([]*tx.Signer{tx.NewSigner(tx.NotaryContract, tx.None), tx.NewSigner(account, tx.None)})
+	trx.SetAttributes([]tx.ITransactionAttribute{
+		tx.NewNotValidBeforeAttribute(notValidBefore),
+		tx.NewConflictsAttribute(main.GetHash()),
+		tx.NewNotaryAssistedAttribute(0),
+	})
+	if err = trx.ValidateAttributes(); err != nil {
+		return nil, err
+	}
+	if err = n.setFees(trx, nil); err != nil {
+		return nil, err
+	}
+
+	deposit, err := n.BalanceOf(account)
+	if err != nil {
+		return nil, err
+	}
+	if deposit.Cmp(big.NewInt(trx.GetSystemFee()+trx.GetNetworkFee())) < 0 {
+		return nil, fmt.Errorf("insufficient notary deposit of %s", account.String())
+	}
+	expiration, err := n.ExpirationOf(account)
+	if err != nil {
+		return nil, err
+	}
+	if expiration <= vub {
+		return nil, fmt.Errorf("notary deposit of %s expires at %d, before ValidUntilBlock %d", account.String(), expiration, vub)
+	}
+	return trx, nil
+}
+
+// SignTransaction signs a notary assisted transaction with the accounts in the wallet. The Notary witness gets the
+// dummy invocation script, and a multi-signature witness keeps the signatures made so far, the service completes
+// the rest. A signer not in the wallet keeps the witness already set in the transaction, or gets an empty one.
+func (n *NotaryHelper) SignTransaction(trx *tx.Transaction, magic uint32) (*tx.Transaction, error) {
+	hashes := trx.GetScriptHashesForVerifying()
+	owned := []*helper.UInt160{}
+	for _, hash := range hashes {
+		if n.Helper.Contains(hash) {
+			owned = append(owned, hash)
+		}
+	}
+	ctx := wallet.NewContractParametersContract(&partialVerifiable{trx, owned})
+	_, err := n.Helper.Sign(ctx, magic)
+	if err != nil {
+		return nil, err
+	}
+	old := trx.GetWitnesses()
+	witnesses := make([]*tx.Witness, len(hashes))
+	for i, hash := range hashes {
+		switch {
+		case hash.Equals(tx.NotaryContract):
+			witnesses[i] = &tx.Witness{InvocationScript: DummyInvocationScript(), VerificationScript: []byte{}}
+		case n.Helper.Contains(hash):
+			witnesses[i], err = getWitness(ctx, hash)
+			if err != nil {
+				return nil, err
+			}
+		case len(old) == len(hashes):
+			witnesses[i] = old[i]
+		default:
+			witnesses[i] = &tx.Witness{InvocationScript: []byte{}, VerificationScript: []byte{}}
+		}
+	}
+	trx.SetWitnesses(witnesses)
+	return trx, nil
+}
+
+// MakeRequest makes a fallback for the signed main transaction, signs it and wraps both into a signed request
+func (n *NotaryHelper) MakeRequest(main *tx.Transaction, account *helper.UInt160, notValidBefore uint32, magic uint32) (*P2PNotaryRequest, error) {
+	fallback, err := n.MakeFallbackTransaction(main, account, notValidBefore, nil)
+	if err != nil {
+		return nil, err
+	}
+	fallback, err = n.SignTransaction(fallback, magic)
+	if err != nil {
+		return nil, err
+	}
+	request := NewP2PNotaryRequest(main, fallback)
+	ctx := wallet.NewContractParametersContract(request)
+	_, err = n.Helper.Sign(ctx, magic)
+	if err != nil {
+		return nil, err
+	}
+	witnesses, err := ctx.GetWitnesses()
+	if err != nil {
+		return nil, err
+	}
+	request.SetWitnesses(witnesses)
+	return request, request.Validate()
+}
+
+// SubmitRequest sends the request to the Notary service, it returns the hash of the fallback transaction
+func (n *NotaryHelper) SubmitRequest(request *P2PNotaryRequest) (string, error) {
+	if err := request.Validate(); err != nil {
+		return "", err
+	}
+	response := n.Client.SubmitNotaryRequest(crypto.Base64Encode(request.ToByteArray()))
+	if response.HasError() {
+		return "", fmt.Errorf(response.GetErrorInfo())
+	}
+	return response.Result.Hash, nil
+}
+
+// setFees sets the system fee and network fee, and checks the GAS of payer if it is not nil
+func (n *NotaryHelper) setFees(trx *tx.Transaction, payer *helper.UInt160) error {
+	gasConsumed, err := n.Helper.GetGasConsumed(trx.GetScript(), models.CreateRpcSigners(trx.GetSigners()))
+	if err != nil {
+		return err
+	}
+	if gasConsumed < 0 {
+		gasConsumed = 0
+	}
+	trx.SetSystemFee(gasConsumed)
+	netFee, err := n.CalculateNetworkFee(trx)
+	if err != nil {
+		return err
+	}
+	trx.SetNetworkFee(int64(netFee))
+	if payer == nil {
+		return nil
+	}
+	balance, err := n.Helper.GetBalanceFromAccount(tx.GasToken, payer)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(big.NewInt(trx.GetSystemFee()+trx.GetNetworkFee())) < 0 {
+		return fmt.Errorf("insufficient GAS")
+	}
+	return nil
+}
+
+func (n *NotaryHelper) send(trx *tx.Transaction) (string, error) {
+	response := n.Client.SendRawTransaction(crypto.Base64Encode(trx.ToByteArray()))
+	if response.HasError() {
+		return "", fmt.Errorf(response.GetErrorInfo())
+	}
+	return response.Result.Hash, nil
+}
+
+// partialVerifiable limits the signing context to the given script hashes
+type partialVerifiable struct {
+	*tx.Transaction
+	hashes []*helper.UInt160
+}
+
+func (p *partialVerifiable) GetScriptHashesForVerifying() []*helper.UInt160 {
+	return p.hashes
+}
+
+// getWitness makes the witness of hash from the context, an incomplete multi-signature witness only pushes the
+// signatures it has, ordered by the public keys like a complete one
+func getWitness(ctx *wallet.ContractParametersContext, hash *helper.UInt160) (*tx.Witness, error) {
+	item, ok := ctx.ContextItems[*hash]
+	if !ok {
+		return nil, fmt.Errorf("no witness for %s in the wallet", hash.String())
+	}
+	sb := sc.NewScriptBuilder()
+	if b, _, points := sc.ByteSlice(item.Script).IsMultiSigContractWithPoints(); b && len(item.Signatures) > 0 {
+		for i := len(points) - 1; i >= 0; i-- {
+			if signature, ok := item.Signatures[points[i].String()]; ok {
+				sb.EmitPushBytes(signature)
+			}
+		}
+	} else {
+		for j := len(item.Parameters) - 1; j >= 0; j-- {
+			if item.Parameters[j].Value == nil {
+				return nil, fmt.Errorf("witness for %s is not completed", hash.String())
+			}
+			sb.EmitPushParameter(item.Parameters[j])
+		}
+	}
+	is, err := sb.ToArray()
+	if err != nil {
+		return nil, err
+	}
+	vs := item.Script
+	if vs == nil {
+		vs = []byte{}
+	}
+	return &tx.Witness{InvocationScript: is, VerificationScript: vs}, nil
+}
+
+func popInteger(response rpc.InvokeResultResponse) (int64, error) {
+	stacks, err := rpc.PopInvokeStacks(response)
+	if err != nil {
+		return 0, err
+	}
+	if len(stacks) == 0 || stacks[0].Type != "Integer" {
+		return 0, fmt.Errorf("invalid result, expecting Integer")
+	}
+	s, ok := stacks[0].Value.(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid result, expecting Integer")
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
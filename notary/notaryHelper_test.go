@@ -0,0 +1,181 @@
+package notary
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/joeqian10/neo3-gogogo/wallet"
+)
+
+const feePerKey = 10000000
+
+// stubNode answers the rpc calls used by NotaryHelper like a node with the Notary service enabled
+type stubNode struct {
+	height   int
+	deposit  int64
+	till     int
+	payloads []string
+	sent     []string
+}
+
+func (s *stubNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := rpc.RpcRequest{}
+	_ = json.NewDecoder(r.Body).Decode(&request)
+	var result interface{}
+	switch request.Method {
+	case "getblockcount":
+		result = s.height + 1
+	case "invokescript":
+		script, _ := crypto.Base64Decode(request.Params[0].(string))
+		integer := func(v int64) interface{} {
+			return map[string]interface{}{
+				"state": "HALT", "gasconsumed": "1000000",
+				"stack": []interface{}{map[string]interface{}{"type": "Integer", "value": big.NewInt(v).String()}},
+			}
+		}
+		switch {
+		case bytes.Contains(script, []byte("getAttributeFee")):
+			result = integer(feePerKey)
+		case bytes.Contains(script, []byte("getMaxNotValidBeforeDelta")):
+			result = integer(140)
+		case bytes.Contains(script, []byte("expirationOf")):
+			result = integer(int64(s.till))
+		case bytes.Contains(script, []byte("balanceOf")) && bytes.Contains(script, tx.NotaryContract.ToByteArray()):
+			result = integer(s.deposit)
+		case bytes.Contains(script, []byte("balanceOf")):
+			result = integer(100 * tx.GasFactor)
+		default:
+			result = map[string]interface{}{"state": "HALT", "gasconsumed": "1000000", "stack": []interface{}{}}
+		}
+	case "sendrawtransaction":
+		s.sent = append(s.sent, request.Params[0].(string))
+		result = map[string]string{"hash": "0x01"}
+	case "submitnotaryrequest":
+		s.payloads = append(s.payloads, request.Params[0].(string))
+		result = map[string]string{"hash": "0x02"}
+	default:
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "error": map[string]interface{}{"code": -32601, "message": "Method not found"}})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+}
+
+func newTestHelper(t *testing.T, node *stubNode) (*NotaryHelper, *helper.UInt160, *sc.Contract) {
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	pairs := make([]*keys.KeyPair, 2)
+	points := make([]*crypto.ECPoint, 2)
+	for i := range pairs {
+		pairs[i], _ = keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[i].PrivateKey))
+		points[i] = pairs[i].PublicKey
+	}
+	multi, err := sc.CreateMultiSigContract(2, points)
+	assert.Nil(t, err)
+	name := "notary"
+	w, _ := wallet.NewNEP6Wallet("", &helper.DefaultProtocolSettings, &name, wallet.DefaultScryptParameters)
+	_ = w.Unlock("")
+	account, err := w.CreateAccountWithPrivateKey(pairs[0].PrivateKey)
+	assert.Nil(t, err)
+	_, err = w.CreateAccountWithContract(multi, pairs[0])
+	assert.Nil(t, err)
+	n := NewNotaryHelper(wallet.NewWalletHelperFromWallet(rpc.NewClient(server.URL), w))
+	return n, account.GetScriptHash(), multi
+}
+
+func TestNotaryHelper_Deposit(t *testing.T) {
+	node := &stubNode{height: 100}
+	n, account, _ := newTestHelper(t, node)
+
+	_, err := n.MakeDepositTransaction(account, nil, big.NewInt(tx.GasFactor), 101, helper.Neo3Magic_MainNet)
+	assert.NotNil(t, err)
+	_, err = n.Deposit(account, nil, big.NewInt(tx.GasFactor), 1000, helper.Neo3Magic_MainNet)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(node.sent))
+
+	b, _ := crypto.Base64Decode(node.sent[0])
+	trx := &tx.Transaction{}
+	trx.Deserialize(io.NewBinaryReaderFromBuf(b))
+	assert.True(t, account.Equals(trx.GetSender()))
+	assert.True(t, bytes.Contains(trx.GetScript(), tx.NotaryContract.ToByteArray()))
+	// [null, 1000] as the data of onNEP17Payment
+	assert.True(t, bytes.Contains(trx.GetScript(), []byte{byte(sc.PUSHINT16), 0xe8, 0x03, byte(sc.PUSHNULL), byte(sc.PUSH2), byte(sc.PACK)}))
+	assert.True(t, tx.VerifySignatureWitness(tx.GetSignData(trx, helper.Neo3Magic_MainNet), trx.GetWitnesses()[0]))
+
+	fallback := tx.NewTransaction()
+	fallback.SetSystemFee(0)
+	fallback.SetNetworkFee(3 * feePerKey)
+	need, err := n.CalculateDeposit(account, fallback)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3*feePerKey), need.Int64())
+	fallback.SetNetworkFee(feePerKey)
+	need, _ = n.CalculateDeposit(account, fallback)
+	assert.Equal(t, int64(2*feePerKey), need.Int64())
+	node.deposit = feePerKey
+	need, _ = n.CalculateDeposit(account, fallback)
+	assert.Equal(t, int64(0), need.Int64())
+}
+
+func TestNotaryHelper_SubmitRequest(t *testing.T) {
+	node := &stubNode{height: 100, deposit: tx.GasFactor, till: 1000}
+	n, account, multi := newTestHelper(t, node)
+
+	main, err := n.MakeMainTransaction([]byte{byte(sc.PUSH1)}, []*tx.Signer{tx.NewSigner(multi.GetScriptHash(), tx.CalledByEntry)}, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(240), main.GetValidUntilBlock())
+	assert.True(t, tx.NotaryContract.Equals(main.GetSigners()[1].Account))
+	main, err = n.SignTransaction(main, helper.Neo3Magic_MainNet)
+	assert.Nil(t, err)
+	// one of the two signatures, the other one is collected by the service
+	assert.Equal(t, 66, len(main.GetWitnesses()[0].InvocationScript))
+	assert.Equal(t, multi.Script, main.GetWitnesses()[0].VerificationScript)
+	assert.Equal(t, DummyInvocationScript(), main.GetWitnesses()[1].InvocationScript)
+
+	// the fee covers the complete witness and the attribute of 2 keys
+	assert.True(t, main.GetNetworkFee() > 3*feePerKey+2*tx.ExecFeeFactor*tx.ECDsaVerifyPrice)
+
+	_, err = n.MakeRequest(main, account, 99, helper.Neo3Magic_MainNet)
+	assert.NotNil(t, err)
+	_, err = n.MakeRequest(main, account, 241, helper.Neo3Magic_MainNet)
+	assert.NotNil(t, err)
+	request, err := n.MakeRequest(main, account, 170, helper.Neo3Magic_MainNet)
+	assert.Nil(t, err)
+	fallback := request.FallbackTransaction
+	assert.True(t, tx.NotaryContract.Equals(fallback.GetSender()))
+	assert.Equal(t, main.GetValidUntilBlock(), fallback.GetValidUntilBlock())
+	assert.True(t, fallback.GetAttributes()[1].(*tx.ConflictsAttribute).Hash.Equals(main.GetHash()))
+	assert.True(t, fallback.GetNetworkFee() > feePerKey)
+	assert.True(t, tx.VerifySignatureWitness(tx.GetSignData(fallback, helper.Neo3Magic_MainNet), fallback.GetWitnesses()[1]))
+
+	hash, err := n.SubmitRequest(request)
+	assert.Nil(t, err)
+	assert.Equal(t, "0x02", hash)
+	assert.Equal(t, 1, len(node.payloads))
+
+	b, _ := crypto.Base64Decode(node.payloads[0])
+	received := &P2PNotaryRequest{}
+	br := io.NewBinaryReaderFromBuf(b)
+	received.Deserialize(br)
+	assert.Nil(t, br.Err)
+	assert.Nil(t, received.Validate())
+	assert.True(t, request.GetHash().Equals(received.GetHash()))
+	assert.True(t, main.GetHash().Equals(received.MainTransaction.GetHash()))
+	assert.True(t, tx.VerifySignatureWitness(tx.GetSignData(received, helper.Neo3Magic_MainNet), received.Witness))
+
+	// the deposit should not expire before the fallback
+	node.till = 240
+	_, err = n.MakeRequest(main, account, 170, helper.Neo3Magic_MainNet)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,162 @@
+package notary
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+// P2PNotaryRequest is the payload sent to the Notary service, it carries the main transaction with the
+// witnesses known by the sender, and a fallback transaction which is persisted if the main one is not
+// completed before NotValidBefore of the fallback. The witness is made by the sender of the fallback.
+type P2PNotaryRequest struct {
+	MainTransaction     *tx.Transaction
+	FallbackTransaction *tx.Transaction
+	Witness             *tx.Witness
+
+	_hash *helper.UInt256
+}
+
+func NewP2PNotaryRequest(main *tx.Transaction, fallback *tx.Transaction) *P2PNotaryRequest {
+	return &P2PNotaryRequest{
+		MainTransaction:     main,
+		FallbackTransaction: fallback,
+		Witness:             &tx.Witness{InvocationScript: []byte{}, VerificationScript: []byte{}},
+	}
+}
+
+func (r *P2PNotaryRequest) GetHash() *helper.UInt256 {
+	if r._hash == nil {
+		r._hash = tx.CalculateHash(r)
+	}
+	return r._hash
+}
+
+func (r *P2PNotaryRequest) GetSize() int {
+	return r.MainTransaction.GetSize() + r.FallbackTransaction.GetSize() + r.Witness.GetSize()
+}
+
+func (r *P2PNotaryRequest) GetWitnesses() []*tx.Witness {
+	return []*tx.Witness{r.Witness}
+}
+
+func (r *P2PNotaryRequest) SetWitnesses(value []*tx.Witness) {
+	if len(value) != 1 {
+		return
+	}
+	r.Witness = value[0]
+}
+
+// GetScriptHashesForVerifying returns the sender of the fallback transaction, which pays for it from its deposit
+func (r *P2PNotaryRequest) GetScriptHashesForVerifying() []*helper.UInt160 {
+	return []*helper.UInt160{r.FallbackTransaction.GetSigners()[1].Account}
+}
+
+func (r *P2PNotaryRequest) Deserialize(br *io.BinaryReader) {
+	r.DeserializeUnsigned(br)
+	r.Witness = &tx.Witness{}
+	r.Witness.Deserialize(br)
+}
+
+// DeserializeUnsigned reads both transactions with their witnesses
+func (r *P2PNotaryRequest) DeserializeUnsigned(br *io.BinaryReader) {
+	r.MainTransaction = &tx.Transaction{}
+	r.MainTransaction.Deserialize(br)
+	r.FallbackTransaction = &tx.Transaction{}
+	r.FallbackTransaction.Deserialize(br)
+	r._hash = nil
+}
+
+func (r *P2PNotaryRequest) Serialize(bw *io.BinaryWriter) {
+	r.SerializeUnsigned(bw)
+	r.Witness.Serialize(bw)
+}
+
+// SerializeUnsigned writes both transactions with their witnesses, the request hash is calculated from them
+func (r *P2PNotaryRequest) SerializeUnsigned(bw *io.BinaryWriter) {
+	r.MainTransaction.Serialize(bw)
+	r.FallbackTransaction.Serialize(bw)
+}
+
+// ToByteArray returns the signed payload
+func (r *P2PNotaryRequest) ToByteArray() []byte {
+	buf := io.NewBufBinaryWriter()
+	r.Serialize(buf.BinaryWriter)
+	if buf.Err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// Validate checks the request like the Notary service does before accepting it into the pool
+func (r *P2PNotaryRequest) Validate() error {
+	if r.MainTransaction == nil || r.FallbackTransaction == nil || r.Witness == nil {
+		return fmt.Errorf("incomplete notary request")
+	}
+	main, fallback := r.MainTransaction, r.FallbackTransaction
+	if a := getNotaryAssisted(main); a == nil {
+		return fmt.Errorf("main transaction should have the NotaryAssisted attribute")
+	}
+	if notaryIndex(main) < 0 {
+		return fmt.Errorf("main transaction should have the Notary contract as a signer")
+	}
+	if a := getNotaryAssisted(fallback); a == nil || a.NKeys != 0 {
+		return fmt.Errorf("fallback transaction should have the NotaryAssisted attribute with 0 keys")
+	}
+	signers := fallback.GetSigners()
+	if len(signers) != 2 || !signers[0].Account.Equals(tx.NotaryContract) || signers[0].Scopes != tx.None {
+		return fmt.Errorf("fallback transaction should have the Notary contract as the sender and one more signer")
+	}
+	witnesses := fallback.GetWitnesses()
+	if len(witnesses) != 2 || !bytes.Equal(witnesses[0].InvocationScript, DummyInvocationScript()) || len(witnesses[0].VerificationScript) != 0 {
+		return fmt.Errorf("fallback transaction should have the dummy witness of the Notary contract")
+	}
+	var nvb *tx.NotValidBeforeAttribute
+	conflicts := false
+	for _, attribute := range fallback.GetAttributes() {
+		switch a := attribute.(type) {
+		case *tx.NotValidBeforeAttribute:
+			nvb = a
+		case *tx.ConflictsAttribute:
+			conflicts = conflicts || a.Hash.Equals(main.GetHash())
+		}
+	}
+	if nvb == nil {
+		return fmt.Errorf("fallback transaction should have the NotValidBefore attribute")
+	}
+	if !conflicts {
+		return fmt.Errorf("fallback transaction should conflict with the main transaction")
+	}
+	if fallback.GetValidUntilBlock() != main.GetValidUntilBlock() {
+		return fmt.Errorf("fallback transaction ValidUntilBlock %d should equal the main one %d", fallback.GetValidUntilBlock(), main.GetValidUntilBlock())
+	}
+	return fallback.ValidateAttributes()
+}
+
+// DummyInvocationScript is the invocation script of the Notary contract witness before the service signs,
+// it pushes a signature of 64 zero bytes
+func DummyInvocationScript() []byte {
+	return append([]byte{byte(sc.PUSHDATA1), 64}, make([]byte, 64)...)
+}
+
+func getNotaryAssisted(trx *tx.Transaction) *tx.NotaryAssistedAttribute {
+	for _, attribute := range trx.GetAttributes() {
+		if a, ok := attribute.(*tx.NotaryAssistedAttribute); ok {
+			return a
+		}
+	}
+	return nil
+}
+
+func notaryIndex(trx *tx.Transaction) int {
+	for i, signer := range trx.GetSigners() {
+		if signer.Account.Equals(tx.NotaryContract) {
+			return i
+		}
+	}
+	return -1
+}
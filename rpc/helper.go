@@ -2,7 +2,9 @@ package rpc
 
 import (
 	"fmt"
+	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/tx"
 )
 
 func PopInvokeStacks(response InvokeResultResponse) ([]models.InvokeStack, error) {
@@ -24,3 +26,26 @@ func PopInvokeStacks(response InvokeResultResponse) ([]models.InvokeStack, error
 
 	return result.Stack, nil
 }
+
+// GetProtocolSettings gets the protocol settings of the node by getversion
+func GetProtocolSettings(client IRpcClient) (*helper.ProtocolSettings, error) {
+	response := client.GetVersion()
+	if response.HasError() {
+		return nil, fmt.Errorf(response.GetErrorInfo())
+	}
+	return response.Result.Protocol.ToProtocolSettings(), nil
+}
+
+// signersOrWitnessesOf converts the signers or the witnesses of invokescript and invokecontractverify, they can be
+// []models.RpcSigner, []*tx.Signer with the rules, or []models.RpcWitness
+func signersOrWitnessesOf(signersOrWitnesses interface{}) (interface{}, bool) {
+	switch v := signersOrWitnesses.(type) {
+	case []models.RpcSigner:
+		return v, true
+	case []*tx.Signer:
+		return models.CreateRpcSigners(v), true
+	case []models.RpcWitness:
+		return v, true
+	}
+	return nil, false
+}
@@ -34,3 +34,28 @@ func TestPopInvokeStack(t *testing.T) {
 	assert.Nil(t, e)
 	assert.Equal(t, "Integer", p[0].Type)
 }
+
+func TestGetProtocolSettings(t *testing.T) {
+	clientMock := new(RpcClientMock)
+	clientMock.On("GetVersion").Return(GetVersionResponse{
+		Result: models.RpcVersion{
+			Protocol: models.RpcProtocol{
+				AddressVersion:       53,
+				Network:              894710606,
+				ValidatorsCount:      7,
+				MillisecondsPerBlock: 15000,
+				Hardforks:            []models.RpcHardfork{{Name: "Aspidochelone", BlockHeight: 210000}},
+				SeedList:             []string{"seed1t5.neo.org:20333"},
+			},
+		},
+	})
+	settings, err := GetProtocolSettings(clientMock)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(894710606), settings.Magic)
+	assert.Equal(t, 7, settings.ValidatorsCount)
+	// not returned by the node
+	assert.Equal(t, uint32(5760), settings.MaxValidUntilBlockIncrement)
+	assert.True(t, settings.IsHardforkEnabled("HF_Aspidochelone", 210000))
+	assert.False(t, settings.IsHardforkEnabled("Basilisk", 210000))
+	assert.Equal(t, []string{"seed1t5.neo.org:20333"}, settings.SeedList)
+}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
 )
 
@@ -14,14 +15,15 @@ type IRpcClient interface {
 	GetBlockCount() GetBlockCountResponse
 	GetBlockHash(index uint32) GetBlockHashResponse
 	GetBlockHeader(hashOrIndex string) GetBlockHeaderResponse
-	GetBlockHeaderCount() GetBlockCountResponse // todo
+	GetBlockHeaderCount() GetBlockCountResponse
 	GetContractState(hash string) GetContractStateResponse
 	GetRawMemPool() GetRawMemPoolResponse
 	GetRawTransaction(hash string) GetRawTransactionResponse
 	GetStorage(scriptHash string, key string) GetStorageResponse
+	FindStorage(scriptHash string, prefix string, start int) FindStorageResponse
 	GetTransactionHeight(hash string) GetTransactionHeightResponse
 	GetNextBlockValidators() GetNextBlockValidatorsResponse
-	GetCandidates() GetCandidatesResponse // todo
+	GetCandidates() GetCandidatesResponse
 	GetCommittee() GetCommitteeResponse
 	GetNativeContracts() GetNativeContractsResponse
 
@@ -31,12 +33,14 @@ type IRpcClient interface {
 	GetVersion() GetVersionResponse
 	SendRawTransaction(tx string) SendRawTransactionResponse
 	SubmitBlock(block string) SubmitBlockResponse
+	SubmitNotaryRequest(payload string) SubmitNotaryRequestResponse
 
 	// Plugins
 	GetApplicationLog(txId string) GetApplicationLogResponse
 	GetNep11Balances(address string) GetNep11BalancesResponse
 	GetNep11Transfers(address string, startTime *int, endTime *int) GetNep11TransfersResponse
 	GetNep11Properties(assetHash string, tokenId string) GetNep11PropertiesResponse
+	GetNep11PropertiesPage(assetHash string, owner *helper.UInt160, count int32, previous *Nep11PropertiesPage) (*Nep11PropertiesPage, error)
 	GetNep17Balances(address string) GetNep17BalancesResponse
 	GetNep17Transfers(address string, startTimestamp *int, endTimestamp *int) GetNep17TransfersResponse
 
@@ -46,12 +50,15 @@ type IRpcClient interface {
 	TraverseIterator(sessionId string, iteratorId string, count int32) TraverseIteratorResponse
 	TerminateSession(sessionId string) TerminateSessionResponse
 	GetUnclaimedGas(address string) GetUnclaimedGasResponse
+	GetUnclaimedGasAt(account *helper.UInt160, end uint32) GetUnclaimedGasResponse
 
 	// State
 	GetProof(rootHash, contractScriptHash, storeKey string) GetProofResponse
 	GetStateHeight() GetStateHeightResponse
 	GetStateRoot(blockHeight uint32) GetStateRootResponse
 	VerifyProof(rootHash string, proofInBase64 string) VerifyProofResponse
+	GetState(rootHash, contractScriptHash, key string) GetStateResponse
+	FindStates(rootHash, contractScriptHash, prefix, from string, count int) FindStatesResponse
 
 	// utilities
 	ListPlugins() ListPluginsResponse
@@ -68,7 +75,8 @@ type IRpcClient interface {
 	ListAddress() ListAddressResponse
 	OpenWallet(path string, password string) OpenWalletResponse
 	SendFrom(assetId string, from string, to string, amount string, signers []string) SendFromResponse
-	SendMany(fromAddress string, toAddresses []string, signerAddresses []string) SendManyResponse
+	SendMany(fromAddress string, outputs []models.RpcTransferOut, signerAddresses []string) SendManyResponse
 	SendToAddress(assetId string, toAddress string, amount string) SendToAddressResponse
+	CancelTransaction(txId string, signerAddresses []string, extraFee string) CancelTransactionResponse
 	InvokeContractVerify(scriptHash string, args []models.RpcContractParameter, signersOrWitnesses interface{}) InvokeResultResponse
 }
@@ -1,5 +1,11 @@
 package models
 
+import (
+	"encoding/json"
+
+	"github.com/joeqian10/neo3-gogogo/sc"
+)
+
 type RpcContractParameter struct {
 	Type  string      `json:"type"`
 	Value interface{} `json:"value"`
@@ -8,3 +14,29 @@ type RpcContractParameter struct {
 func NewRpcContractParameter(t string, v interface{}) RpcContractParameter {
 	return RpcContractParameter{Type: t, Value: v}
 }
+
+// NewRpcContractParameterFromContractParameter converts the parameter to the json format of the node, the Value
+// is the decoded json, e.g. a string for Hash160 and a []interface{} for Array
+func NewRpcContractParameterFromContractParameter(p *sc.ContractParameter) (RpcContractParameter, error) {
+	var result RpcContractParameter
+	data, err := json.Marshal(p)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// ToContractParameter converts the parameter back to sc.ContractParameter, the Value can be the json of the node
+// or a Go value of the type, e.g. a *helper.UInt160 for Hash160
+func (p *RpcContractParameter) ToContractParameter() (*sc.ContractParameter, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	result := &sc.ContractParameter{}
+	if err = json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRpcContractParameter_ToContractParameter(t *testing.T) {
+	gas, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	rcp := NewRpcContractParameter("Hash160", gas)
+	p, err := rcp.ToContractParameter()
+	assert.Nil(t, err)
+	assert.Equal(t, sc.Hash160, p.Type)
+	assert.Equal(t, gas.String(), p.Value.(*helper.UInt160).String())
+
+	rcp, err = NewRpcContractParameterFromContractParameter(&sc.ContractParameter{Type: sc.Array, Value: []sc.ContractParameter{
+		{Type: sc.Integer, Value: 1},
+		{Type: sc.String, Value: "a"},
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, "Array", rcp.Type)
+	assert.Equal(t, map[string]interface{}{"type": "Integer", "value": "1"}, rcp.Value.([]interface{})[0])
+	p, err = rcp.ToContractParameter()
+	assert.Nil(t, err)
+	assert.Equal(t, "a", p.Value.([]sc.ContractParameter)[1].Value)
+}
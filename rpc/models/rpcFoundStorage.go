@@ -0,0 +1,13 @@
+package models
+
+// RpcFoundStorage is a page of the storage entries returned by findstorage
+type RpcFoundStorage struct {
+	Truncated bool              `json:"truncated"`
+	Next      int               `json:"next"`
+	Results   []RpcStorageEntry `json:"results"`
+}
+
+type RpcStorageEntry struct {
+	Key   string `json:"key"`   // base64
+	Value string `json:"value"` // base64
+}
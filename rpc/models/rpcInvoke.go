@@ -1,5 +1,16 @@
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/vm"
+)
+
 type InvokeResult struct {
 	Script           string                      `json:"script"`
 	State            string                      `json:"state"`
@@ -13,8 +24,10 @@ type InvokeResult struct {
 	PendingSignature RpcContractParameterContext `json:"pendingsignature,omitempty"`
 }
 
+// RpcDiagnostic is returned with useDiagnostic, InvokedContracts is the tree of the calls from the script
 type RpcDiagnostic struct {
-	InvokedContracts []RpcInvocationTreeNode `json:"invokedcontracts"`
+	InvokedContracts RpcInvocationTreeNode `json:"invokedcontracts"`
+	StorageChanges   []RpcStorageChange    `json:"storagechanges"`
 }
 
 type RpcInvocationTreeNode struct {
@@ -52,3 +65,316 @@ func ConvertInvokeStackArray(s InvokeStack) []InvokeStack {
 	}
 	return result
 }
+
+// stackItemJson is an InvokeStack with the value not decoded yet
+type stackItemJson struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ToContractParameter converts the stack item to a parameter, ByteString and Buffer are ByteArray, Struct is Array,
+// Array and Map are converted recursively
+func (s *InvokeStack) ToContractParameter() (*sc.ContractParameter, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var item stackItemJson
+	if err = json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	return item.toContractParameter()
+}
+
+func (s *stackItemJson) toContractParameter() (*sc.ContractParameter, error) {
+	var t sc.ContractParameterType
+	switch s.Type {
+	case "Any":
+		return &sc.ContractParameter{Type: sc.Any}, nil
+	case "InteropInterface":
+		return &sc.ContractParameter{Type: sc.InteropInterface}, nil
+	case "Boolean":
+		t = sc.Boolean
+	case "Integer":
+		t = sc.Integer
+	case "ByteString", "Buffer":
+		t = sc.ByteArray
+	case "Array", "Struct":
+		var items []stackItemJson
+		if err := json.Unmarshal(s.Value, &items); err != nil {
+			return nil, err
+		}
+		result := make([]sc.ContractParameter, len(items))
+		for i := range items {
+			p, err := items[i].toContractParameter()
+			if err != nil {
+				return nil, err
+			}
+			result[i] = *p
+		}
+		return &sc.ContractParameter{Type: sc.Array, Value: result}, nil
+	case "Map":
+		var pairs []struct {
+			Key   stackItemJson `json:"key"`
+			Value stackItemJson `json:"value"`
+		}
+		if err := json.Unmarshal(s.Value, &pairs); err != nil {
+			return nil, err
+		}
+		result := make([]sc.ContractParameterPair, len(pairs))
+		for i := range pairs {
+			key, err := pairs[i].Key.toContractParameter()
+			if err != nil {
+				return nil, err
+			}
+			value, err := pairs[i].Value.toContractParameter()
+			if err != nil {
+				return nil, err
+			}
+			result[i] = sc.ContractParameterPair{Key: *key, Value: *value}
+		}
+		return &sc.ContractParameter{Type: sc.Map, Value: result}, nil
+	default:
+		return nil, fmt.Errorf("stack item type %s can't be converted to a parameter", s.Type)
+	}
+	// Boolean, Integer and ByteArray have the same json values as the parameters
+	data, err := json.Marshal(stackItemJson{Type: t.String(), Value: s.Value})
+	if err != nil {
+		return nil, err
+	}
+	result := &sc.ContractParameter{}
+	if err = json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// NewInvokeStackFromContractParameter converts the parameter to the stack item pushed by EmitPushParameter, the
+// String, Hash160, Hash256, PublicKey and Signature parameters are ByteString
+func NewInvokeStackFromContractParameter(p *sc.ContractParameter) (InvokeStack, error) {
+	var result InvokeStack
+	item, err := stackItemOf(p)
+	if err != nil {
+		return result, err
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+func stackItemOf(p *sc.ContractParameter) (map[string]interface{}, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	// decode again so that the value has one of the types of UnmarshalJSON
+	cp := &sc.ContractParameter{}
+	if err = json.Unmarshal(data, cp); err != nil {
+		return nil, err
+	}
+	if cp.Value == nil {
+		if cp.Type == sc.InteropInterface {
+			return map[string]interface{}{"type": "InteropInterface"}, nil
+		}
+		return map[string]interface{}{"type": "Any"}, nil
+	}
+	byteString := func(b []byte) map[string]interface{} {
+		return map[string]interface{}{"type": "ByteString", "value": crypto.Base64Encode(b)}
+	}
+	switch v := cp.Value.(type) {
+	case bool:
+		return map[string]interface{}{"type": "Boolean", "value": v}, nil
+	case *big.Int:
+		return map[string]interface{}{"type": "Integer", "value": v.String()}, nil
+	case []byte:
+		return byteString(v), nil
+	case string:
+		return byteString([]byte(v)), nil
+	case *helper.UInt160:
+		return byteString(v.ToByteArray()), nil
+	case *helper.UInt256:
+		return byteString(v.ToByteArray()), nil
+	case *crypto.ECPoint:
+		return byteString(v.EncodePoint(true)), nil
+	case []sc.ContractParameter:
+		items := make([]interface{}, len(v))
+		for i := range v {
+			if items[i], err = stackItemOf(&v[i]); err != nil {
+				return nil, err
+			}
+		}
+		return map[string]interface{}{"type": "Array", "value": items}, nil
+	case []sc.ContractParameterPair:
+		pairs := make([]interface{}, len(v))
+		for i := range v {
+			key, err := stackItemOf(&v[i].Key)
+			if err != nil {
+				return nil, err
+			}
+			value, err := stackItemOf(&v[i].Value)
+			if err != nil {
+				return nil, err
+			}
+			pairs[i] = map[string]interface{}{"key": key, "value": value}
+		}
+		return map[string]interface{}{"type": "Map", "value": pairs}, nil
+	default:
+		return nil, fmt.Errorf("%s parameter can't be converted to a stack item", cp.Type)
+	}
+}
+
+// ToStackItem converts the stack item of the node to vm.StackItem, Pointer and InteropInterface are not supported
+func (s *InvokeStack) ToStackItem() (vm.StackItem, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return vm.StackItem{}, err
+	}
+	var item stackItemJson
+	if err = json.Unmarshal(data, &item); err != nil {
+		return vm.StackItem{}, err
+	}
+	return item.toStackItem()
+}
+
+func (s *stackItemJson) toStackItem() (vm.StackItem, error) {
+	t, err := vm.NewStackItemTypeFromString(s.Type)
+	if err != nil {
+		return vm.StackItem{}, fmt.Errorf("invalid stack item type %s", s.Type)
+	}
+	switch t {
+	case vm.Any:
+		return vm.NewNullStackItem(), nil
+	case vm.Boolean:
+		var b bool
+		err = json.Unmarshal(s.Value, &b)
+		return vm.NewBooleanStackItem(b), err
+	case vm.Integer:
+		var v string
+		if err = json.Unmarshal(s.Value, &v); err != nil {
+			return vm.StackItem{}, err
+		}
+		i, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return vm.StackItem{}, fmt.Errorf("invalid integer %s", v)
+		}
+		return vm.NewIntegerStackItem(i), nil
+	case vm.ByteString, vm.Buffer:
+		var v string
+		if err = json.Unmarshal(s.Value, &v); err != nil {
+			return vm.StackItem{}, err
+		}
+		b, err := crypto.Base64Decode(v)
+		if err != nil {
+			return vm.StackItem{}, err
+		}
+		return vm.StackItem{Type: t, Value: b}, nil
+	case vm.Array, vm.Struct:
+		var items []stackItemJson
+		if err = json.Unmarshal(s.Value, &items); err != nil {
+			return vm.StackItem{}, err
+		}
+		result := make([]vm.StackItem, len(items))
+		for i := range items {
+			if result[i], err = items[i].toStackItem(); err != nil {
+				return vm.StackItem{}, err
+			}
+		}
+		return vm.StackItem{Type: t, Value: result}, nil
+	case vm.Map:
+		var pairs []struct {
+			Key   stackItemJson `json:"key"`
+			Value stackItemJson `json:"value"`
+		}
+		if err = json.Unmarshal(s.Value, &pairs); err != nil {
+			return vm.StackItem{}, err
+		}
+		result := make([]vm.StackItemPair, len(pairs))
+		for i := range pairs {
+			if result[i].Key, err = pairs[i].Key.toStackItem(); err != nil {
+				return vm.StackItem{}, err
+			}
+			if result[i].Value, err = pairs[i].Value.toStackItem(); err != nil {
+				return vm.StackItem{}, err
+			}
+		}
+		return vm.NewMapStackItem(result), nil
+	default:
+		return vm.StackItem{}, fmt.Errorf("%s stack item is not supported", s.Type)
+	}
+}
+
+// NewInvokeStackFromStackItem converts vm.StackItem to the stack item of the node
+func NewInvokeStackFromStackItem(item vm.StackItem) (InvokeStack, error) {
+	var result InvokeStack
+	v, err := invokeStackOf(item)
+	if err != nil {
+		return result, err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+func invokeStackOf(item vm.StackItem) (map[string]interface{}, error) {
+	result := map[string]interface{}{"type": item.Type.String()}
+	switch item.Type {
+	case vm.Any:
+	case vm.Boolean:
+		b, err := item.GetBoolean()
+		if err != nil {
+			return nil, err
+		}
+		result["value"] = b
+	case vm.Integer:
+		i, err := item.GetInteger()
+		if err != nil {
+			return nil, err
+		}
+		result["value"] = i.String()
+	case vm.ByteString, vm.Buffer:
+		b, err := item.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		result["value"] = crypto.Base64Encode(b)
+	case vm.Array, vm.Struct:
+		items, err := item.GetItems()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(items))
+		for i := range items {
+			if values[i], err = invokeStackOf(items[i]); err != nil {
+				return nil, err
+			}
+		}
+		result["value"] = values
+	case vm.Map:
+		pairs, err := item.GetPairs()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(pairs))
+		for i := range pairs {
+			key, err := invokeStackOf(pairs[i].Key)
+			if err != nil {
+				return nil, err
+			}
+			value, err := invokeStackOf(pairs[i].Value)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = map[string]interface{}{"key": key, "value": value}
+		}
+		result["value"] = values
+	default:
+		return nil, fmt.Errorf("%s stack item is not supported", item.Type)
+	}
+	return result, nil
+}
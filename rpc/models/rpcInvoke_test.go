@@ -0,0 +1,71 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/vm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvokeStack_ToContractParameter(t *testing.T) {
+	s := InvokeStack{Type: "Array", Value: []interface{}{
+		map[string]interface{}{"type": "ByteString", "value": "AQI="},
+		map[string]interface{}{"type": "Map", "value": []interface{}{
+			map[string]interface{}{
+				"key":   map[string]interface{}{"type": "Integer", "value": "1"},
+				"value": map[string]interface{}{"type": "Boolean", "value": true},
+			},
+		}},
+		map[string]interface{}{"type": "Any"},
+	}}
+	p, err := s.ToContractParameter()
+	assert.Nil(t, err)
+	items := p.Value.([]sc.ContractParameter)
+	assert.Equal(t, sc.ByteArray, items[0].Type)
+	assert.Equal(t, []byte{0x01, 0x02}, items[0].Value)
+	pairs := items[1].Value.([]sc.ContractParameterPair)
+	assert.Equal(t, 0, big.NewInt(1).Cmp(pairs[0].Key.Value.(*big.Int)))
+	assert.Equal(t, true, pairs[0].Value.Value)
+	assert.Equal(t, sc.Any, items[2].Type)
+
+	s2, err := NewInvokeStackFromContractParameter(p)
+	assert.Nil(t, err)
+	assert.Equal(t, s, s2)
+
+	_, err = (&InvokeStack{Type: "Pointer", Value: 1}).ToContractParameter()
+	assert.NotNil(t, err)
+}
+
+func TestInvokeStack_ToStackItem(t *testing.T) {
+	s := InvokeStack{Type: "Struct", Value: []interface{}{
+		map[string]interface{}{"type": "Buffer", "value": "AQI="},
+		map[string]interface{}{"type": "Integer", "value": "-7"},
+		map[string]interface{}{"type": "Map", "value": []interface{}{
+			map[string]interface{}{
+				"key":   map[string]interface{}{"type": "ByteString", "value": "YQ=="},
+				"value": map[string]interface{}{"type": "Any"},
+			},
+		}},
+	}}
+	item, err := s.ToStackItem()
+	assert.Nil(t, err)
+	assert.Equal(t, vm.Struct, item.Type)
+	items, _ := item.GetItems()
+	assert.Equal(t, vm.NewBufferStackItem([]byte{0x01, 0x02}), items[0])
+	i, _ := items[1].GetInteger()
+	assert.Equal(t, int64(-7), i.Int64())
+
+	// the storage value of StdLib.serialize can be decoded and returned in the format of the node
+	b, err := vm.SerializeStackItem(item)
+	assert.Nil(t, err)
+	decoded, err := vm.DeserializeStackItem(b)
+	assert.Nil(t, err)
+	s2, err := NewInvokeStackFromStackItem(decoded)
+	assert.Nil(t, err)
+	assert.Equal(t, s, s2)
+
+	_, err = (&InvokeStack{Type: "InteropInterface", Interface: "IIterator", Id: "1"}).ToStackItem()
+	assert.NotNil(t, err)
+}
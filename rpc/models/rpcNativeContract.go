@@ -1,9 +1,23 @@
 package models
 
+// RpcNativeContract is the contract state of a native contract, UpdateHistory is only returned by the nodes before
+// neo 3.1
 type RpcNativeContract struct {
 	Id            int                 `json:"id"`
+	UpdateCounter uint16              `json:"updatecounter"`
 	Hash          string              `json:"hash"`
 	Nef           RpcNefFile          `json:"nef"`
 	Manifest      RpcContractManifest `json:"manifest"`
-	UpdateHistory []uint			  `json:"updatehistory"`
+	UpdateHistory []uint              `json:"updatehistory,omitempty"`
+}
+
+// ToContractState converts the native contract to the same model as getcontractstate
+func (c *RpcNativeContract) ToContractState() RpcContractState {
+	return RpcContractState{
+		Id:            c.Id,
+		UpdateCounter: c.UpdateCounter,
+		Hash:          c.Hash,
+		Nef:           c.Nef,
+		Manifest:      c.Manifest,
+	}
 }
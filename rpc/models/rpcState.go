@@ -4,3 +4,12 @@ type RpcStateHeight struct {
 	LocalRootIndex uint32 `json:"localrootindex"`
 	ValidateRootIndex uint32 `json:"validatedrootindex"`
 }
+
+// RpcFoundStates is a page of the storage entries returned by findstates, the proofs of the first and the last
+// entries are only returned if there are any results
+type RpcFoundStates struct {
+	FirstProof string            `json:"firstProof,omitempty"` // base64
+	LastProof  string            `json:"lastProof,omitempty"`  // base64
+	Truncated  bool              `json:"truncated"`
+	Results    []RpcStorageEntry `json:"results"`
+}
@@ -1,5 +1,11 @@
 package models
 
+import (
+	"encoding/json"
+
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
 type RpcTransaction struct {
 	Hash            string                    `json:"hash"`
 	Size            int                       `json:"size"`
@@ -18,7 +24,37 @@ type RpcTransaction struct {
 	Blocktime       int                       `json:"blocktime"`
 }
 
+// RpcTransactionAttribute combines all types of attributes into one struct
 type RpcTransactionAttribute struct {
-	Usage string `json:"usage"`
-	Data  string `json:"data"`
+	Type   string  `json:"type"`
+	Height *uint32 `json:"height,omitempty"` // NotValidBefore
+	Hash   string  `json:"hash,omitempty"`   // Conflicts
+	NKeys  *uint8  `json:"nkeys,omitempty"`  // NotaryAssisted
+	Id     *uint64 `json:"id,omitempty"`     // OracleResponse
+	Code   string  `json:"code,omitempty"`   // OracleResponse
+	Result *string `json:"result,omitempty"` // OracleResponse, base64 encoded
+}
+
+// NewRpcTransactionFromTransaction converts the transaction to the json format of the node
+func NewRpcTransactionFromTransaction(trx *tx.Transaction) (RpcTransaction, error) {
+	var result RpcTransaction
+	data, err := json.Marshal(trx)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// ToTransaction converts the transaction back to tx.Transaction, the hash is checked if it is not empty
+func (t *RpcTransaction) ToTransaction() (*tx.Transaction, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	result := &tx.Transaction{}
+	if err = json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
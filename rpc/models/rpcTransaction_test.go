@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/joeqian10/neo3-gogogo/tx/conditions"
+)
+
+func newTestTransaction() *tx.Transaction {
+	gas, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	f := false
+	signer := tx.NewSigner(gas, tx.WitnessRules)
+	signer.Rules = []*tx.WitnessRule{{Action: tx.Deny, Condition: conditions.NewWitnessCondition(conditions.Boolean, &f)}}
+	trx := tx.NewTransaction()
+	trx.SetNonce(1072419131)
+	trx.SetSystemFee(9977780)
+	trx.SetNetworkFee(1230610)
+	trx.SetValidUntilBlock(2105487)
+	trx.SetSigners([]*tx.Signer{signer})
+	trx.SetAttributes([]tx.ITransactionAttribute{
+		&tx.OracleResponseAttribute{Id: 0, Code: tx.NotFound, Result: []byte{}},
+		tx.NewNotValidBeforeAttribute(0),
+	})
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetWitnesses([]*tx.Witness{{InvocationScript: []byte{}, VerificationScript: []byte{byte(sc.PUSH1)}}})
+	return trx
+}
+
+func TestNewRpcTransactionFromTransaction(t *testing.T) {
+	trx := newTestTransaction()
+	r, err := NewRpcTransactionFromTransaction(trx)
+	assert.Nil(t, err)
+	assert.Equal(t, "0x"+trx.GetHash().String(), r.Hash)
+	assert.Equal(t, trx.GetSize(), r.Size)
+	assert.Equal(t, 1072419131, r.Nonce)
+	assert.Equal(t, "9977780", r.SysFee)
+	assert.Equal(t, "WitnessRules", r.Signers[0].Scopes)
+	assert.Equal(t, false, r.Signers[0].Rules[0].Condition.Expression)
+	assert.Equal(t, "OracleResponse", r.Attributes[0].Type)
+	assert.Equal(t, uint64(0), *r.Attributes[0].Id)
+	assert.Equal(t, "NotFound", r.Attributes[0].Code)
+	assert.Equal(t, "", *r.Attributes[0].Result)
+	assert.Equal(t, uint32(0), *r.Attributes[1].Height)
+	assert.Equal(t, "EQ==", r.Witnesses[0].Verification)
+
+	trx2, err := r.ToTransaction()
+	assert.Nil(t, err)
+	assert.Equal(t, trx.ToByteArray(), trx2.ToByteArray())
+
+	// the fields of the chain are ignored
+	r.BlockHash, r.Confirmations, r.Blocktime = "0x01", 10, 1000
+	trx2, err = r.ToTransaction()
+	assert.Nil(t, err)
+	assert.Equal(t, trx.GetHash(), trx2.GetHash())
+
+	r.Nonce++
+	_, err = r.ToTransaction()
+	assert.NotNil(t, err)
+	r.Hash = ""
+	trx2, err = r.ToTransaction()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1072419132), trx2.GetNonce())
+}
+
+func TestCreateRpcSigner(t *testing.T) {
+	signer := newTestTransaction().GetSigners()[0]
+	data, _ := json.Marshal(CreateRpcSigner(signer))
+	s := &tx.Signer{}
+	assert.Nil(t, json.Unmarshal(data, s))
+	b1, _ := json.Marshal(signer)
+	b2, _ := json.Marshal(s)
+	assert.Equal(t, string(b1), string(b2))
+	assert.Equal(t, false, *s.Rules[0].Condition.GetCondition().(*bool))
+}
@@ -1,21 +1,74 @@
 package models
 
+import "github.com/joeqian10/neo3-gogogo/helper"
+
 type RpcVersion struct {
 	TcpPort   int         `json:"tcpPort"`
 	WsPort    int         `json:"wsPort"`
 	Nonce     string      `json:"nonce"`
 	UserAgent string      `json:"useragent"`
 	Protocol  RpcProtocol `json:"protocol"`
+	Rpc       RpcSettings `json:"rpc"`
+}
+
+// RpcSettings are the settings of RpcServer returned by neo 3.5 and later
+type RpcSettings struct {
+	MaxIteratorResultItems int  `json:"maxiteratorresultitems"`
+	SessionEnabled         bool `json:"sessionenabled"`
 }
 
 type RpcProtocol struct {
-	AddressVersion              byte   `json:"addressversion"`
-	Network                     uint32 `json:"network"`
-	ValidatorsCount             int32  `json:"validatorscount"`
-	MillisecondsPerBlock        uint32 `json:"msperblock"`
-	MaxTraceableBlocks          uint32 `json:"maxtraceableblocks"`
-	MaxValidUntilBlockIncrement uint32 `json:"maxvaliduntilblockincrement"`
-	MaxTransactionsPerBlock     uint32 `json:"maxtransactionsperblock"`
-	MemoryPoolMaxTransactions   int32  `json:"memorypoolmaxtransactions"`
-	InitialGasDistribution      uint64 `json:"initialgasdistribution"`
+	AddressVersion              byte          `json:"addressversion"`
+	Network                     uint32        `json:"network"`
+	ValidatorsCount             int32         `json:"validatorscount"`
+	MillisecondsPerBlock        uint32        `json:"msperblock"`
+	MaxTraceableBlocks          uint32        `json:"maxtraceableblocks"`
+	MaxValidUntilBlockIncrement uint32        `json:"maxvaliduntilblockincrement"`
+	MaxTransactionsPerBlock     uint32        `json:"maxtransactionsperblock"`
+	MemoryPoolMaxTransactions   int32         `json:"memorypoolmaxtransactions"`
+	InitialGasDistribution      uint64        `json:"initialgasdistribution"`
+	Hardforks                   []RpcHardfork `json:"hardforks"`
+	StandbyCommittee            []string      `json:"standbycommittee"`
+	SeedList                    []string      `json:"seedlist"`
+}
+
+type RpcHardfork struct {
+	Name        string `json:"name"`
+	BlockHeight uint32 `json:"blockheight"`
+}
+
+// ToProtocolSettings converts the protocol returned by getversion, the fields not returned by old nodes keep the defaults
+func (p *RpcProtocol) ToProtocolSettings() *helper.ProtocolSettings {
+	s := helper.NewProtocolSettings()
+	s.Magic = p.Network
+	s.AddressVersion = p.AddressVersion
+	s.ValidatorsCount = int(p.ValidatorsCount)
+	if p.MillisecondsPerBlock != 0 {
+		s.MillisecondsPerBlock = p.MillisecondsPerBlock
+	}
+	if p.MaxTraceableBlocks != 0 {
+		s.MaxTraceableBlocks = p.MaxTraceableBlocks
+	}
+	if p.MaxValidUntilBlockIncrement != 0 {
+		s.MaxValidUntilBlockIncrement = p.MaxValidUntilBlockIncrement
+	}
+	if p.MaxTransactionsPerBlock != 0 {
+		s.MaxTransactionsPerBlock = p.MaxTransactionsPerBlock
+	}
+	if p.MemoryPoolMaxTransactions != 0 {
+		s.MemoryPoolMaxTransactions = int(p.MemoryPoolMaxTransactions)
+	}
+	if p.InitialGasDistribution != 0 {
+		s.InitialGasDistribution = p.InitialGasDistribution
+	}
+	for _, h := range p.Hardforks {
+		s.Hardforks[h.Name] = h.BlockHeight
+	}
+	if p.StandbyCommittee != nil {
+		s.StandbyCommittee = p.StandbyCommittee
+	}
+	if p.SeedList != nil {
+		s.SeedList = p.SeedList
+	}
+	return s
 }
@@ -19,7 +19,7 @@ type RpcWitnessRule struct {
 // RpcWitnessCondition combines all types of conditions into one struct
 type RpcWitnessCondition struct {
 	Type        string                `json:"type"`                  // type of this condition
-	Expression  interface{}           `json:"expression,omitempty"`  // BooleanCondition: "true" || "false" | NotCondition: !RpcWitnessCondition
+	Expression  interface{}           `json:"expression,omitempty"`  // BooleanCondition: true || false | NotCondition: !RpcWitnessCondition
 	Expressions []RpcWitnessCondition `json:"expressions,omitempty"` // AndCondition: RpcWitnessCondition && RpcWitnessCondition | OrCondition: RpcWitnessCondition || RpcWitnessCondition
 	Hash        string                `json:"hash,omitempty"`        // ScriptHashCondition | CalledByContractCondition: UInt160.ToString()
 	Group       string                `json:"group,omitempty"`       // GroupCondition | CalledByGroupCondition: ECPoint.ToString()
@@ -29,12 +29,7 @@ func CreateRpcWitnessCondition(c *conditions.WitnessCondition) RpcWitnessConditi
 	r := RpcWitnessCondition{Type: c.Type.String()}
 	switch c.Type {
 	case conditions.Boolean:
-		b := c.GetCondition().(*bool)
-		if *b {
-			r.Expression = "true"
-		} else {
-			r.Expression = "false"
-		}
+		r.Expression = *c.GetCondition().(*bool)
 		break
 	case conditions.Not:
 		inner := CreateRpcWitnessCondition(c.GetCondition().(*conditions.WitnessCondition))
@@ -0,0 +1,276 @@
+package rpc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Fixture is a request and the response of the node, it is the file format of RecordingHttpClient and
+// ReplayHttpClient
+type Fixture struct {
+	Request  RpcRequest      `json:"request"`
+	Response json.RawMessage `json:"response"`
+	// Index is the order of the fixtures of the same request, e.g. getblockcount while waiting for a block
+	Index int `json:"index,omitempty"`
+}
+
+// key is the method with the params in canonical json, the requests of the same key are the same
+func (f *Fixture) key() (string, error) {
+	params, err := canonicalJson(f.Request.Params)
+	if err != nil {
+		return "", err
+	}
+	return f.Request.Method + " " + params, nil
+}
+
+// canonicalJson marshals the value again after decoding, so the keys of the objects are sorted and the numbers are kept
+func canonicalJson(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var value interface{}
+	if err = decoder.Decode(&value); err != nil {
+		return "", err
+	}
+	b, err = json.Marshal(value)
+	return string(b), err
+}
+
+// setResponseId sets the id of the response to the id of the request
+func setResponseId(response []byte, id int) ([]byte, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(response, &m); err != nil {
+		return nil, err
+	}
+	m["id"], _ = json.Marshal(id)
+	return json.Marshal(m)
+}
+
+func readRequest(req *http.Request) (RpcRequest, []byte, error) {
+	var request RpcRequest
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return request, nil, err
+	}
+	_ = req.Body.Close()
+	err = json.Unmarshal(body, &request)
+	return request, body, err
+}
+
+// RecordingHttpClient sends the requests with Client and saves each request and response as a fixture in Dir, the
+// ids are normalized to 1. The file name is the method with a hash of the params, and the index for a repeated request.
+type RecordingHttpClient struct {
+	Client IHttpClient
+	Dir    string
+	counts map[string]int
+	lock   sync.Mutex
+}
+
+func NewRecordingHttpClient(client IHttpClient, dir string) *RecordingHttpClient {
+	return &RecordingHttpClient{Client: client, Dir: dir, counts: map[string]int{}}
+}
+
+func (c *RecordingHttpClient) Do(req *http.Request) (*http.Response, error) {
+	request, body, err := readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	res, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	response, err := ioutil.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(response))
+	if err = c.save(request, response); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *RecordingHttpClient) save(request RpcRequest, response []byte) error {
+	request.ID = 1
+	normalized, err := setResponseId(response, 1)
+	if err != nil {
+		return fmt.Errorf("invalid response of %s: %v", request.Method, err)
+	}
+	f := &Fixture{Request: request, Response: normalized}
+	key, err := f.key()
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	f.Index = c.counts[key]
+	c.counts[key]++
+	c.lock.Unlock()
+
+	hash := sha256.Sum256([]byte(key))
+	name := fmt.Sprintf("%s-%x", request.Method, hash[:4])
+	if f.Index > 0 {
+		name += fmt.Sprintf("-%d", f.Index)
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(c.Dir, name+".json"), append(data, '\n'), 0644)
+}
+
+// ReplayHttpClient returns the responses of the fixtures with the same method and params as the requests, the id of
+// a response is set to the id of its request. The fixtures of a repeated request are returned by their indexes, and
+// the last one is returned again after that.
+type ReplayHttpClient struct {
+	// IgnoreParams are the methods matched without the params, e.g. sendrawtransaction of a transaction with a
+	// random nonce
+	IgnoreParams map[string]bool
+	fixtures     map[string][]*Fixture // by method
+	served       map[string]int
+	requests     []RpcRequest
+	lock         sync.Mutex
+}
+
+func newReplayHttpClient() *ReplayHttpClient {
+	return &ReplayHttpClient{IgnoreParams: map[string]bool{}, fixtures: map[string][]*Fixture{}, served: map[string]int{}}
+}
+
+// NewReplayHttpClient loads the fixtures of the json files in the directory, a file is a fixture or an array of them
+func NewReplayHttpClient(dir string) (*ReplayHttpClient, error) {
+	c := newReplayHttpClient()
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no fixture in %s", dir)
+	}
+	for _, file := range files {
+		fixtures, err := readFixtures(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range fixtures {
+			c.Add(f)
+		}
+	}
+	return c, nil
+}
+
+// readFixtures reads a file of one fixture, or of an array of fixtures
+func readFixtures(file string) ([]*Fixture, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []*Fixture
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		err = json.Unmarshal(data, &fixtures)
+	} else {
+		f := &Fixture{}
+		err = json.Unmarshal(data, f)
+		fixtures = append(fixtures, f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %v", file, err)
+	}
+	return fixtures, nil
+}
+
+// Add adds a fixture, e.g. one made in a test
+func (c *ReplayHttpClient) Add(f *Fixture) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	fixtures := append(c.fixtures[f.Request.Method], f)
+	sort.SliceStable(fixtures, func(i, j int) bool { return fixtures[i].Index < fixtures[j].Index })
+	c.fixtures[f.Request.Method] = fixtures
+}
+
+func (c *ReplayHttpClient) Do(req *http.Request) (*http.Response, error) {
+	request, _, err := readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	c.requests = append(c.requests, request)
+	c.lock.Unlock()
+	f, err := c.match(request)
+	if err != nil {
+		return nil, err
+	}
+	response, err := setResponseId(f.Response, request.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(response)),
+		Request:    req,
+	}, nil
+}
+
+// Requests returns the requests of the method in the order they are received, e.g. to check the transaction of
+// sendrawtransaction when its params are ignored
+func (c *ReplayHttpClient) Requests(method string) []RpcRequest {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var requests []RpcRequest
+	for _, r := range c.requests {
+		if r.Method == method {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+func (c *ReplayHttpClient) match(request RpcRequest) (*Fixture, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := request.Method
+	if !c.IgnoreParams[request.Method] {
+		f := &Fixture{Request: request}
+		k, err := f.key()
+		if err != nil {
+			return nil, err
+		}
+		key = k
+	}
+	var matched []*Fixture
+	for _, f := range c.fixtures[request.Method] {
+		if c.IgnoreParams[request.Method] {
+			matched = append(matched, f)
+		} else if k, err := f.key(); err == nil && k == key {
+			matched = append(matched, f)
+		}
+	}
+	if len(matched) == 0 {
+		params, _ := canonicalJson(request.Params)
+		return nil, fmt.Errorf("no fixture for %s %s", request.Method, params)
+	}
+	i := c.served[key]
+	if i >= len(matched) {
+		i = len(matched) - 1
+	} else {
+		c.served[key]++
+	}
+	return matched[i], nil
+}
@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newNodeServer returns a server which answers getblockcount with an increasing count and getblockhash with the index
+func newNodeServer() *httptest.Server {
+	count := 100
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request RpcRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		var result interface{}
+		switch request.Method {
+		case "getblockcount":
+			result = count
+			count++
+		case "getblockhash":
+			result = fmt.Sprintf("0x%064v", request.Params[0])
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	}))
+}
+
+func newHttpRequest(request RpcRequest) *http.Request {
+	body, _ := json.Marshal(request)
+	req, _ := http.NewRequest("POST", "http://localhost:10332", bytes.NewReader(body))
+	return req
+}
+
+func TestRecordingHttpClient(t *testing.T) {
+	server := newNodeServer()
+	defer server.Close()
+	dir := t.TempDir()
+
+	client := NewClientWithHttpClient(server.URL, NewRecordingHttpClient(http.DefaultClient, dir))
+	require.NotNil(t, client)
+	assert.Equal(t, 100, client.GetBlockCount().Result)
+	assert.Equal(t, 101, client.GetBlockCount().Result)
+	assert.Equal(t, "0x"+fmt.Sprintf("%064d", 5), client.GetBlockHash(5).Result)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(files))
+
+	replay, err := NewReplayHttpClient(dir)
+	require.Nil(t, err)
+	client = NewClientWithHttpClient("http://localhost:10332", replay)
+	assert.Equal(t, 100, client.GetBlockCount().Result)
+	assert.Equal(t, 101, client.GetBlockCount().Result)
+	// the last fixture is repeated
+	assert.Equal(t, 101, client.GetBlockCount().Result)
+	assert.Equal(t, "0x"+fmt.Sprintf("%064d", 5), client.GetBlockHash(5).Result)
+
+	// no fixture of the params
+	_, err = replay.Do(newHttpRequest(NewRequest("getblockhash", []interface{}{6})))
+	assert.NotNil(t, err)
+}
+
+func TestReplayHttpClient_IgnoreParams(t *testing.T) {
+	replay := newReplayHttpClient()
+	replay.Add(&Fixture{
+		Request:  NewRequest("sendrawtransaction", []interface{}{"AA=="}),
+		Response: json.RawMessage(`{"jsonrpc":"2.0","id":1,"result":{"hash":"0x01"}}`),
+	})
+	_, err := replay.Do(newHttpRequest(NewRequest("sendrawtransaction", []interface{}{"AQ=="})))
+	assert.NotNil(t, err)
+
+	replay.IgnoreParams["sendrawtransaction"] = true
+	client := NewClientWithHttpClient("http://localhost:10332", replay)
+	r := client.SendRawTransaction("AQ==")
+	assert.False(t, r.HasError())
+	assert.Equal(t, "0x01", r.Result.Hash)
+
+	// the requests are kept to check the params
+	requests := replay.Requests("sendrawtransaction")
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "AQ==", requests[1].Params[0])
+	assert.Equal(t, 0, len(replay.Requests("getblockcount")))
+}
+
+func TestNewReplayHttpClient(t *testing.T) {
+	_, err := NewReplayHttpClient(t.TempDir())
+	assert.NotNil(t, err)
+
+	replay, err := NewReplayHttpClient(filepath.Join("testdata", "conformance"))
+	require.Nil(t, err)
+	client := NewClientWithHttpClient("http://localhost:10332", replay)
+	r := client.GetBestBlockHash()
+	assert.False(t, r.HasError())
+}
@@ -35,12 +35,6 @@ type GetBlockHeaderResponse struct {
 	Result models.RpcBlockHeader `json:"result"`
 }
 
-type GetBlockSysFeeResponse struct {
-	RpcResponse
-	ErrorResponse
-	Result string `json:"result"`
-}
-
 type GetContractStateResponse struct {
 	RpcResponse
 	ErrorResponse
@@ -65,6 +59,12 @@ type GetStorageResponse struct {
 	Result string `json:"result"`
 }
 
+type FindStorageResponse struct {
+	RpcResponse
+	ErrorResponse
+	Result models.RpcFoundStorage `json:"result"`
+}
+
 type GetTransactionHeightResponse struct {
 	RpcResponse
 	ErrorResponse
@@ -145,6 +145,7 @@ func (n *RpcClient) GetBlockHeaderCount() GetBlockCountResponse {
 	return response
 }
 
+// GetContractState accepts the script hash, the id or the name of a native contract
 func (n *RpcClient) GetContractState(scriptHash string) GetContractStateResponse {
 	response := GetContractStateResponse{}
 	params := []interface{}{scriptHash}
@@ -173,6 +174,15 @@ func (n *RpcClient) GetStorage(scripthash string, key string) GetStorageResponse
 	return response
 }
 
+// FindStorage returns the storage entries of the contract with the prefix in base64, from the index start, a page is
+// truncated by the node, the next page starts from Next
+func (n *RpcClient) FindStorage(scripthash string, prefix string, start int) FindStorageResponse {
+	response := FindStorageResponse{}
+	params := []interface{}{scripthash, prefix, start}
+	_ = n.makeRequest("findstorage", params, &response)
+	return response
+}
+
 func (n *RpcClient) GetTransactionHeight(txid string) GetTransactionHeightResponse {
 	response := GetTransactionHeightResponse{}
 	params := []interface{}{txid}
@@ -201,6 +211,7 @@ func (n *RpcClient) GetCommittee() GetCommitteeResponse {
 	return response
 }
 
+// GetNativeContracts returns the contract states of the native contracts
 func (n *RpcClient) GetNativeContracts() GetNativeContractsResponse {
 	response := GetNativeContractsResponse{}
 	params := []interface{}{}
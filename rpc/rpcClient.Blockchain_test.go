@@ -322,6 +322,39 @@ func TestRpcClient_GetStorage(t *testing.T) {
 	assert.Equal(t, "410321048096980021020702280100", r)
 }
 
+func TestRpcClient_FindStorage(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"truncated": true,
+				"next": 2,
+				"results": [
+					{
+						"key": "FNiuc+BlUuJwNAtjqLyr+Sd6GqyZ",
+						"value": "QQMhBICWmAAhAgcCKAEA"
+					},
+					{
+						"key": "FOiNnaHpGhK8LzmH7K5E3n4F0zbm",
+						"value": "QQMhAQEhAgcCKAEA"
+					}
+				]
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.FindStorage("0xd2a4cff31913016155e38e474a2c06d08be276cf", "FA==", 0)
+	r := response.Result
+	assert.Equal(t, true, r.Truncated)
+	assert.Equal(t, 2, r.Next)
+	assert.Equal(t, 2, len(r.Results))
+	assert.Equal(t, "FNiuc+BlUuJwNAtjqLyr+Sd6GqyZ", r.Results[0].Key)
+	assert.Equal(t, "QQMhAQEhAgcCKAEA", r.Results[1].Value)
+}
+
 func TestRpcClient_GetTransactionHeight(t *testing.T) {
 	var client = new(HttpClientMock)
 	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
@@ -28,6 +28,14 @@ type SendRawTransactionResponse struct {
 	} `json:"result"`
 }
 
+type SubmitNotaryRequestResponse struct {
+	RpcResponse
+	ErrorResponse
+	Result struct {
+		Hash string `json:"hash"`
+	} `json:"result"`
+}
+
 type SubmitBlockResponse struct {
 	RpcResponse
 	ErrorResponse
@@ -70,3 +78,12 @@ func (n *RpcClient) SubmitBlock(blockHex string) SubmitBlockResponse {
 	_ = n.makeRequest("submitblock", params, &response)
 	return response
 }
+
+// SubmitNotaryRequest sends a P2PNotaryRequest payload in base64 to a node with the Notary service enabled,
+// the hash in the result is the hash of the fallback transaction
+func (n *RpcClient) SubmitNotaryRequest(payload string) SubmitNotaryRequestResponse {
+	response := SubmitNotaryRequestResponse{}
+	params := []interface{}{payload}
+	_ = n.makeRequest("submitnotaryrequest", params, &response)
+	return response
+}
@@ -113,3 +113,21 @@ func TestRpcClient_SubmitBlock(t *testing.T) {
 	r := response.Result
 	assert.Equal(t, "0x407c75ed84bc2cb70303fbdb791d45b56ccef7209813c53da1c2456c1241294a", r.Hash)
 }
+
+func TestRpcClient_SubmitNotaryRequest(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"hash": "0x65eb8e8d6b5e1d1b9e0b35b4c28ef6a2aea5f39a8cd9a1e2b9e1b7fe4e62bd7c"
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.SubmitNotaryRequest("")
+	r := response.Result
+	assert.Equal(t, "0x65eb8e8d6b5e1d1b9e0b35b4c28ef6a2aea5f39a8cd9a1e2b9e1b7fe4e62bd7c", r.Hash)
+}
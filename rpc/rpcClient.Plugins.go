@@ -1,6 +1,12 @@
 package rpc
 
-import "github.com/joeqian10/neo3-gogogo/rpc/models"
+import (
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
 
 type GetApplicationLogResponse struct {
 	RpcResponse
@@ -71,7 +77,8 @@ func (n *RpcClient) GetNep11Transfers(address string, startTime *int, endTime *i
 	return response
 }
 
-// GetNep11Properties needs the TokensTracker plugin
+// GetNep11Properties needs the TokensTracker plugin, the properties other than name, description, image and tokenURI
+// are in base64, a null property is an empty string
 func (n *RpcClient) GetNep11Properties(assetHash string, tokenId string) GetNep11PropertiesResponse {
 	response := GetNep11PropertiesResponse{}
 	params := []interface{}{assetHash, tokenId}
@@ -79,6 +86,91 @@ func (n *RpcClient) GetNep11Properties(assetHash string, tokenId string) GetNep1
 	return response
 }
 
+// Nep11PropertiesPage is a page of the tokens of a NEP-11 contract with their properties, the next page is read from
+// the iterator of the session which is kept open until the last page
+type Nep11PropertiesPage struct {
+	Tokens   []Nep11TokenProperties
+	Session  string // empty if this is the last page
+	Iterator string
+}
+
+// Nep11TokenProperties is a token id in hex with the result of getnep11properties
+type Nep11TokenProperties struct {
+	TokenId    string
+	Properties map[string]string
+}
+
+// HasMore returns true if there are more tokens after this page
+func (p *Nep11PropertiesPage) HasMore() bool {
+	return p.Session != ""
+}
+
+// GetNep11PropertiesPage needs the TokensTracker plugin and the sessions of RpcServer. It gets the properties of up to
+// count tokens, the tokens of owner by tokensOf, or all the tokens by tokens if owner is nil. Pass the previous page
+// to get the next one, the first page is got with nil. The session is terminated when the last page is read or an
+// error occurs.
+func (n *RpcClient) GetNep11PropertiesPage(assetHash string, owner *helper.UInt160, count int32, previous *Nep11PropertiesPage) (*Nep11PropertiesPage, error) {
+	if count <= 0 || count > 100 {
+		return nil, fmt.Errorf("count should be in [1, 100]")
+	}
+	page := &Nep11PropertiesPage{}
+	if previous == nil {
+		method, args := "tokens", []models.RpcContractParameter(nil)
+		if owner != nil {
+			method, args = "tokensOf", []models.RpcContractParameter{{Type: "Hash160", Value: owner}}
+		}
+		response := n.InvokeFunction(assetHash, method, args, nil, false)
+		stacks, err := PopInvokeStacks(response)
+		if err != nil {
+			return nil, err
+		}
+		if len(stacks) == 0 || stacks[0].Type != "InteropInterface" || stacks[0].Id == "" || response.Result.Session == "" {
+			return nil, fmt.Errorf("%s of %s did not return an iterator, sessions may be disabled", method, assetHash)
+		}
+		page.Session, page.Iterator = response.Result.Session, stacks[0].Id
+	} else {
+		if !previous.HasMore() {
+			return nil, fmt.Errorf("no more tokens")
+		}
+		page.Session, page.Iterator = previous.Session, previous.Iterator
+	}
+	err := n.readNep11PropertiesPage(assetHash, count, page)
+	if err != nil || len(page.Tokens) < int(count) {
+		_ = n.TerminateSession(page.Session)
+		page.Session, page.Iterator = "", ""
+	}
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (n *RpcClient) readNep11PropertiesPage(assetHash string, count int32, page *Nep11PropertiesPage) error {
+	response := n.TraverseIterator(page.Session, page.Iterator, count)
+	if response.HasError() {
+		return fmt.Errorf(response.GetErrorInfo())
+	}
+	for _, item := range response.Result {
+		v, ok := item.Value.(string)
+		if !ok || item.Type != "ByteString" {
+			return fmt.Errorf("invalid token id of type %s", item.Type)
+		}
+		tokenId, err := crypto.Base64Decode(v)
+		if err != nil {
+			return err
+		}
+		properties := n.GetNep11Properties(assetHash, helper.BytesToHex(tokenId))
+		if properties.HasError() {
+			return fmt.Errorf(properties.GetErrorInfo())
+		}
+		page.Tokens = append(page.Tokens, Nep11TokenProperties{
+			TokenId:    helper.BytesToHex(tokenId),
+			Properties: properties.Result,
+		})
+	}
+	return nil
+}
+
 // GetNep17Balances needs the TokensTracker plugin
 func (n *RpcClient) GetNep17Balances(address string) GetNep17BalancesResponse {
 	response := GetNep17BalancesResponse{}
@@ -100,6 +192,6 @@ func (n *RpcClient) GetNep17Transfers(address string, startTime *int, endTime *i
 	} else {
 		params = []interface{}{address}
 	}
-	_ = n.makeRequest("getnep17balances", params, &response)
+	_ = n.makeRequest("getnep17transfers", params, &response)
 	return response
 }
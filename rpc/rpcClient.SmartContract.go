@@ -6,6 +6,7 @@ import (
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
 	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
 )
 
 type InvokeResultResponse struct {
@@ -96,15 +97,14 @@ func (n *RpcClient) InvokeFunction(scriptHash string, method string, args []mode
 	//return response
 }
 
-// InvokeScript params: scriptInBase64 is necessary, set signersOrWitnesses = nil, useDiagnostic = false if not necessary
+// InvokeScript params: scriptInBase64 is necessary, set signersOrWitnesses = nil, useDiagnostic = false if not necessary,
+// signersOrWitnesses can be []models.RpcSigner, []*tx.Signer or []models.RpcWitness
 func (n *RpcClient) InvokeScript(scriptInBase64 string, signersOrWitnesses interface{}, useDiagnostic bool) InvokeResultResponse {
 	response := InvokeResultResponse{}
 	params := []interface{}{scriptInBase64}
 
-	if signers, ok := signersOrWitnesses.([]models.RpcSigner); ok {
+	if signers, ok := signersOrWitnessesOf(signersOrWitnesses); ok {
 		params = append(params, signers) // params[1]
-	} else if witnesses, ok := signersOrWitnesses.([]models.RpcWitness); ok {
-		params = append(params, witnesses) // params[1]
 	}
 
 	if useDiagnostic {
@@ -132,6 +132,7 @@ func (n *RpcClient) TerminateSession(sessionId string) TerminateSessionResponse
 	return response
 }
 
+// GetUnclaimedGas gets the gas an account can claim in the next block, address can also be a script hash
 func (n *RpcClient) GetUnclaimedGas(address string) GetUnclaimedGasResponse {
 	response := GetUnclaimedGasResponse{}
 	params := []interface{}{address}
@@ -139,6 +140,33 @@ func (n *RpcClient) GetUnclaimedGas(address string) GetUnclaimedGasResponse {
 	return response
 }
 
+// GetUnclaimedGasAt gets the gas an account can claim at the block end by NeoToken.unclaimedGas, end should not be
+// higher than the next block. Address of the result is the script hash of the account.
+func (n *RpcClient) GetUnclaimedGasAt(account *helper.UInt160, end uint32) GetUnclaimedGasResponse {
+	args := []models.RpcContractParameter{
+		{Type: "Hash160", Value: account},
+		{Type: "Integer", Value: end},
+	}
+	invoke := n.InvokeFunction(tx.NeoTokenId, "unclaimedGas", args, nil, false)
+	response := GetUnclaimedGasResponse{RpcResponse: invoke.RpcResponse}
+	stacks, err := PopInvokeStacks(invoke)
+	if err != nil {
+		response.ErrorResponse = ErrorResponse{Error: RpcError{Code: -1, Message: err.Error()}, NetError: invoke.NetError}
+		return response
+	}
+	if len(stacks) == 0 || stacks[0].Type != "Integer" {
+		response.ErrorResponse = ErrorResponse{Error: RpcError{Code: -1, Message: "invalid result of unclaimedGas"}}
+		return response
+	}
+	unclaimed, ok := stacks[0].Value.(string)
+	if !ok {
+		response.ErrorResponse = ErrorResponse{Error: RpcError{Code: -1, Message: "invalid result of unclaimedGas"}}
+		return response
+	}
+	response.Result = models.UnclaimedGas{Unclaimed: unclaimed, Address: "0x" + account.String()}
+	return response
+}
+
 func (n *RpcClient) InvokeFunctionAndIterate(scriptHash string, method string, args []models.RpcContractParameter,
 	signersOrWitnesses interface{}, useDiagnostic bool, count int32) ([][]models.InvokeStack, error) {
 
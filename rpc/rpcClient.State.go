@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"fmt"
+
 	"github.com/joeqian10/neo3-gogogo/mpt"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
 )
@@ -29,6 +31,18 @@ type VerifyProofResponse struct {
 	Result string `json:"result"` // base64
 }
 
+type GetStateResponse struct {
+	RpcResponse
+	ErrorResponse
+	Result string `json:"result"` // base64
+}
+
+type FindStatesResponse struct {
+	RpcResponse
+	ErrorResponse
+	Result models.RpcFoundStates `json:"result"`
+}
+
 func (n *RpcClient) GetProof(rootHash, contractScriptHash, storeKey string) GetProofResponse {
 	response := GetProofResponse{}
 	params := []interface{}{rootHash, contractScriptHash, storeKey}
@@ -56,3 +70,40 @@ func (n *RpcClient) VerifyProof(rootHash string, proofInBase64 string) VerifyPro
 	_ = n.makeRequest("verifyproof", params, &response)
 	return response
 }
+
+// GetState returns the value of the key in base64 in the storage of the contract at the state root
+func (n *RpcClient) GetState(rootHash, contractScriptHash, key string) GetStateResponse {
+	response := GetStateResponse{}
+	params := []interface{}{rootHash, contractScriptHash, key}
+	_ = n.makeRequest("getstate", params, &response)
+	return response
+}
+
+// FindStates returns the entries with the prefix in the storage of the contract at the state root, after the key from,
+// both in base64. Set from = "" to start from the first entry and count = 0 for the max count of the node.
+func (n *RpcClient) FindStates(rootHash, contractScriptHash, prefix, from string, count int) FindStatesResponse {
+	response := FindStatesResponse{}
+	params := []interface{}{rootHash, contractScriptHash, prefix}
+	if from != "" || count > 0 {
+		params = append(params, from)
+	}
+	if count > 0 {
+		params = append(params, count)
+	}
+	_ = n.makeRequest("findstates", params, &response)
+	return response
+}
+
+// GetStateRoots returns the state roots of count blocks from the height start, there is no range method in the
+// StateService plugin so it requests them one by one
+func (n *RpcClient) GetStateRoots(start uint32, count uint32) ([]mpt.StateRoot, error) {
+	roots := make([]mpt.StateRoot, 0, count)
+	for i := uint32(0); i < count; i++ {
+		response := n.GetStateRoot(start + i)
+		if response.HasError() {
+			return nil, fmt.Errorf("state root of block %d: %s", start+i, response.GetErrorInfo())
+		}
+		roots = append(roots, response.Result)
+	}
+	return roots, nil
+}
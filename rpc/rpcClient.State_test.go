@@ -84,3 +84,35 @@ func TestRpcClient_VerifyProof(t *testing.T) {
 	r := response.Result
 	assert.Equal(t, "EQwhA6oFL7y45bM6TWYlNvhoRkHwQQnx1eac3abwhIkChqEQtBMHOzuw", r)
 }
+
+func TestRpcClient_GetStateRoots(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"version": 0,
+				"index": 1234,
+				"roothash": "0x3d39da5b227e3f02f5210b24690a0523162788668e490363c6a39813bb162e51",
+				"witnesses": []
+			}
+		}`))),
+	}, nil).Once()
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"error": {
+				"code": -100,
+				"message": "Unknown state root"
+			}
+		}`))),
+	}, nil).Once()
+
+	roots, err := rpc.GetStateRoots(1234, 2)
+	assert.Nil(t, roots)
+	assert.EqualError(t, err, "state root of block 1235: Unknown state root")
+	client.AssertNumberOfCalls(t, "Do", 2)
+}
@@ -74,6 +74,12 @@ type SendToAddressResponse struct {
 	Result models.RpcTransaction `json:"result"`
 }
 
+type CancelTransactionResponse struct {
+	RpcResponse
+	ErrorResponse
+	Result models.RpcTransaction `json:"result"`
+}
+
 func (n *RpcClient) CloseWallet() CloseWalletResponse {
 	response := CloseWalletResponse{}
 	params := []interface{}{}
@@ -147,13 +153,14 @@ func (n *RpcClient) SendFrom(assetId string, fromAddress string, toAddress strin
 	return response
 }
 
-func (n *RpcClient) SendMany(fromAddress string, toAddresses []string, signerAddresses []string) SendManyResponse {
+// SendMany transfers the assets to the outputs, set fromAddress = "" to send from any address of the wallet
+func (n *RpcClient) SendMany(fromAddress string, outputs []models.RpcTransferOut, signerAddresses []string) SendManyResponse {
 	response := SendManyResponse{}
 	var params []interface{}
 	if fromAddress != "" {
 		params = append(params, fromAddress)
 	}
-	params = append(params, toAddresses)
+	params = append(params, outputs)
 	if len(signerAddresses) > 0 {
 		params = append(params, signerAddresses)
 	}
@@ -168,6 +175,20 @@ func (n *RpcClient) SendToAddress(assetId string, toAddress string, amount strin
 	return response
 }
 
+// CancelTransaction sends a conflicting transaction with a higher fee to replace the transaction in the memory pool,
+// the signers are the addresses of the signers of the transaction, set extraFee = "" if not necessary
+func (n *RpcClient) CancelTransaction(txId string, signerAddresses []string, extraFee string) CancelTransactionResponse {
+	response := CancelTransactionResponse{}
+	params := []interface{}{txId, signerAddresses}
+	if extraFee != "" {
+		params = append(params, extraFee)
+	}
+	_ = n.makeRequest("canceltransaction", params, &response)
+	return response
+}
+
+// InvokeContractVerify runs the verify method of the contract, signersOrWitnesses can be []models.RpcSigner,
+// []*tx.Signer with the witness rules or []models.RpcWitness
 func (n *RpcClient) InvokeContractVerify(scriptHash string, args []models.RpcContractParameter,
 	signersOrWitnesses interface{}) InvokeResultResponse {
 
@@ -176,16 +197,11 @@ func (n *RpcClient) InvokeContractVerify(scriptHash string, args []models.RpcCon
 	if args != nil {
 		params = append(params, args)
 	}
-	if signers, ok := signersOrWitnesses.([]models.RpcSigner); ok {
+	if signers, ok := signersOrWitnessesOf(signersOrWitnesses); ok {
 		if len(params) == 1 {
 			params = append(params, []models.RpcContractParameter{})
 		}
 		params = append(params, signers)
-	} else if witnesses, ok := signersOrWitnesses.([]models.RpcWitness); ok {
-		if len(params) == 1 {
-			params = append(params, []models.RpcContractParameter{})
-		}
-		params = append(params, witnesses)
 	}
 	_ = n.makeRequest("invokecontractverify", params, &response)
 	return response
@@ -261,7 +261,12 @@ func TestRpcClient_SendMany(t *testing.T) {
 		  }`))),
 	}, nil)
 
-	response := rpc.SendMany("NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ", []string{"NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW"}, []string{"NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ"})
+	outputs := []models.RpcTransferOut{{
+		Asset:   "0xd2a4cff31913016155e38e474a2c06d08be276cf",
+		Value:   "1",
+		Address: "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW",
+	}}
+	response := rpc.SendMany("NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ", outputs, []string{"NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ"})
 	r := response.Result
 	assert.Equal(t, "0x542e64a9048bbe1ee565b840c41ccf9b5a1ef11f52e5a6858a523938a20c53ec", r.Hash)
 }
@@ -33,6 +33,16 @@ func NewClient(endpoint string) *RpcClient {
 	return &RpcClient{Endpoint: u, httpClient: netClient, _url: endpoint}
 }
 
+// NewClientWithHttpClient creates a client which sends the requests with httpClient, e.g. a ReplayHttpClient in tests
+func NewClientWithHttpClient(endpoint string, httpClient IHttpClient) *RpcClient {
+	client := NewClient(endpoint)
+	if client == nil || httpClient == nil {
+		return nil
+	}
+	client.httpClient = httpClient
+	return client
+}
+
 func (n *RpcClient) SetBasicAuth(user string, pass string) {
 	n.userName = user
 	n.password = pass
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -46,11 +47,6 @@ func (r *RpcClientMock) GetBlockHeaderCount() GetBlockCountResponse {
 	return args.Get(0).(GetBlockCountResponse)
 }
 
-func (r *RpcClientMock) GetBlockSysFee(height int) GetBlockSysFeeResponse {
-	args := r.Called(height)
-	return args.Get(0).(GetBlockSysFeeResponse)
-}
-
 func (r *RpcClientMock) GetContractState(s string) GetContractStateResponse {
 	args := r.Called(s)
 	return args.Get(0).(GetContractStateResponse)
@@ -71,6 +67,11 @@ func (r *RpcClientMock) GetStorage(s1 string, s2 string) GetStorageResponse {
 	return args.Get(0).(GetStorageResponse)
 }
 
+func (r *RpcClientMock) FindStorage(s1 string, s2 string, start int) FindStorageResponse {
+	args := r.Called(s1, s2, start)
+	return args.Get(0).(FindStorageResponse)
+}
+
 func (r *RpcClientMock) GetTransactionHeight(s string) GetTransactionHeightResponse {
 	args := r.Called(s)
 	return args.Get(0).(GetTransactionHeightResponse)
@@ -123,6 +124,11 @@ func (r *RpcClientMock) SubmitBlock(s string) SubmitBlockResponse {
 	return args.Get(0).(SubmitBlockResponse)
 }
 
+func (r *RpcClientMock) SubmitNotaryRequest(s string) SubmitNotaryRequestResponse {
+	args := r.Called(s)
+	return args.Get(0).(SubmitNotaryRequestResponse)
+}
+
 // ---------------- start section: Plugins ----------------
 
 func (r *RpcClientMock) GetApplicationLog(s string) GetApplicationLogResponse {
@@ -145,6 +151,12 @@ func (r *RpcClientMock) GetNep11Properties(s1 string, s2 string) GetNep11Propert
 	return args.Get(0).(GetNep11PropertiesResponse)
 }
 
+func (r *RpcClientMock) GetNep11PropertiesPage(s string, u *helper.UInt160, c int32, p *Nep11PropertiesPage) (*Nep11PropertiesPage, error) {
+	args := r.Called(s, u, c, p)
+	page, _ := args.Get(0).(*Nep11PropertiesPage)
+	return page, args.Error(1)
+}
+
 func (r *RpcClientMock) GetNep17Balances(s string) GetNep17BalancesResponse {
 	args := r.Called(s)
 	return args.Get(0).(GetNep17BalancesResponse)
@@ -183,6 +195,11 @@ func (r *RpcClientMock) GetUnclaimedGas(s string) GetUnclaimedGasResponse {
 	return args.Get(0).(GetUnclaimedGasResponse)
 }
 
+func (r *RpcClientMock) GetUnclaimedGasAt(u *helper.UInt160, end uint32) GetUnclaimedGasResponse {
+	args := r.Called(u, end)
+	return args.Get(0).(GetUnclaimedGasResponse)
+}
+
 // ---------------- start section: State ----------------
 
 func (r *RpcClientMock) GetProof(s1, s2, s3 string) GetProofResponse {
@@ -205,6 +222,16 @@ func (r *RpcClientMock) VerifyProof(s string, p string) VerifyProofResponse {
 	return args.Get(0).(VerifyProofResponse)
 }
 
+func (r *RpcClientMock) GetState(s1, s2, s3 string) GetStateResponse {
+	args := r.Called(s1, s2, s3)
+	return args.Get(0).(GetStateResponse)
+}
+
+func (r *RpcClientMock) FindStates(s1, s2, s3, s4 string, c int) FindStatesResponse {
+	args := r.Called(s1, s2, s3, s4, c)
+	return args.Get(0).(FindStatesResponse)
+}
+
 // ---------------- start section: Utilities ----------------
 
 func (r *RpcClientMock) ListPlugins() ListPluginsResponse {
@@ -269,8 +296,8 @@ func (r *RpcClientMock) SendFrom(s1, s2, s3, s4 string, ss []string) SendFromRes
 	return args.Get(0).(SendFromResponse)
 }
 
-func (r *RpcClientMock) SendMany(s string, ss []string, sn []string) SendManyResponse {
-	args := r.Called(s, s, sn)
+func (r *RpcClientMock) SendMany(s string, o []models.RpcTransferOut, sn []string) SendManyResponse {
+	args := r.Called(s, o, sn)
 	return args.Get(0).(SendManyResponse)
 }
 
@@ -279,6 +306,11 @@ func (r *RpcClientMock) SendToAddress(s1, s2, s3 string) SendToAddressResponse {
 	return args.Get(0).(SendToAddressResponse)
 }
 
+func (r *RpcClientMock) CancelTransaction(s string, ss []string, f string) CancelTransactionResponse {
+	args := r.Called(s, ss, f)
+	return args.Get(0).(CancelTransactionResponse)
+}
+
 func (r *RpcClientMock) InvokeContractVerify(s string, a []models.RpcContractParameter, sw interface{}) InvokeResultResponse {
 	args := r.Called(s, a, sw)
 	return args.Get(0).(InvokeResultResponse)
@@ -186,7 +186,10 @@ func IsMultiSigContract(script []byte) (bool, int, int, []crypto.ECPoint) {
 			return false, 0, 0, nil
 		}
 		// add point
-		point, _ := crypto.DecodePoint(script[i+1:i+34], &crypto.P256)
+		point, err := crypto.DecodePoint(script[i+1:i+34], &crypto.P256)
+		if err != nil {
+			return false, 0, 0, nil
+		}
 		points = append(points, *point)
 		i += 34
 		n++
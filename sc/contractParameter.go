@@ -1,8 +1,16 @@
 package sc
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"sort"
 	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
 )
 
 type ContractParameterType byte
@@ -32,6 +40,13 @@ type ContractParameter struct {
 	Value interface{}
 }
 
+// ContractParameterPair is a key-value pair of a Map parameter, a Map with a []ContractParameterPair value keeps the
+// order of the pairs
+type ContractParameterPair struct {
+	Key   ContractParameter `json:"key"`
+	Value ContractParameter `json:"value"`
+}
+
 func NewContractParameterTypeFromString(s string) (ContractParameterType, error) {
 	t := strings.ToLower(s)
 	switch t {
@@ -89,7 +104,7 @@ func (cpt ContractParameterType) String() string {
 		s = "Signature"
 	case 0x20:
 		s = "Array"
-	case 0x21:
+	case 0x22:
 		s = "Map"
 	case 0x30:
 		s = "InteropInterface"
@@ -100,3 +115,311 @@ func (cpt ContractParameterType) String() string {
 	}
 	return s
 }
+
+// contractParameterJson is the format of the node, {"type":"Hash160","value":"0x..."}
+type contractParameterJson struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// MarshalJSON encodes the parameter in the format of the node. Integer is a decimal string, ByteArray and Signature
+// are base64, Hash160 and Hash256 are big-endian hex with 0x, PublicKey is the hex of the compressed point, Array is
+// a list of parameters and Map is a list of key-value pairs. The Value can be any value of EmitPushParameter.
+func (p ContractParameter) MarshalJSON() ([]byte, error) {
+	if p.Type.String() == "" {
+		return nil, fmt.Errorf("invalid param type %d", byte(p.Type))
+	}
+	result := contractParameterJson{Type: p.Type.String()}
+	if p.Value != nil {
+		v, err := p.marshalValue()
+		if err != nil {
+			return nil, fmt.Errorf("%s parameter: %w", p.Type, err)
+		}
+		result.Value = v
+	}
+	return json.Marshal(result)
+}
+
+func (p ContractParameter) marshalValue() ([]byte, error) {
+	invalid := fmt.Errorf("invalid value %T", p.Value)
+	switch p.Type {
+	case Boolean:
+		if v, ok := p.Value.(bool); ok {
+			return json.Marshal(v)
+		}
+	case Integer:
+		if v, ok := bigIntOf(p.Value); ok {
+			return json.Marshal(v.String())
+		}
+	case ByteArray, Signature:
+		switch v := p.Value.(type) {
+		case []byte:
+			return json.Marshal(base64.StdEncoding.EncodeToString(v))
+		case string:
+			if p.Type == ByteArray {
+				return json.Marshal(base64.StdEncoding.EncodeToString([]byte(v)))
+			}
+		}
+	case String:
+		if v, ok := p.Value.(string); ok {
+			return json.Marshal(v)
+		}
+	case Hash160:
+		switch v := p.Value.(type) {
+		case *helper.UInt160:
+			return v.MarshalJSON()
+		case helper.UInt160:
+			return v.MarshalJSON()
+		}
+	case Hash256:
+		switch v := p.Value.(type) {
+		case *helper.UInt256:
+			return v.MarshalJSON()
+		case helper.UInt256:
+			return v.MarshalJSON()
+		}
+	case PublicKey:
+		switch v := p.Value.(type) {
+		case *crypto.ECPoint:
+			return json.Marshal(helper.BytesToHex(v.EncodePoint(true)))
+		case crypto.ECPoint:
+			return json.Marshal(helper.BytesToHex(v.EncodePoint(true)))
+		case []byte:
+			return json.Marshal(helper.BytesToHex(v))
+		}
+	case Array:
+		switch v := p.Value.(type) {
+		case []ContractParameter, []*ContractParameter:
+			return json.Marshal(v)
+		case []interface{}:
+			items := make([]ContractParameter, len(v))
+			for i, item := range v {
+				cp, err := parameterOf(item)
+				if err != nil {
+					return nil, fmt.Errorf("[%d]: %w", i, err)
+				}
+				items[i] = cp
+			}
+			return json.Marshal(items)
+		}
+	case Map:
+		switch v := p.Value.(type) {
+		case []ContractParameterPair:
+			return json.Marshal(v)
+		case map[interface{}]interface{}:
+			return marshalMap(v)
+		}
+	}
+	return nil, invalid
+}
+
+// marshalMap sorts the pairs by the keys, so the same map always gives the same json
+func marshalMap(m map[interface{}]interface{}) ([]byte, error) {
+	type pair struct {
+		key  []byte
+		pair ContractParameterPair
+	}
+	pairs := make([]pair, 0, len(m))
+	for k, v := range m {
+		key, err := parameterOf(k)
+		if err != nil {
+			return nil, fmt.Errorf("[%v]: %w", k, err)
+		}
+		value, err := parameterOf(v)
+		if err != nil {
+			return nil, fmt.Errorf("[%v]: %w", k, err)
+		}
+		b, err := json.Marshal(key)
+		if err != nil {
+			return nil, fmt.Errorf("[%v]: %w", k, err)
+		}
+		pairs = append(pairs, pair{key: b, pair: ContractParameterPair{Key: key, Value: value}})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return bytes.Compare(pairs[i].key, pairs[j].key) < 0
+	})
+	result := make([]ContractParameterPair, len(pairs))
+	for i := range pairs {
+		result[i] = pairs[i].pair
+	}
+	return json.Marshal(result)
+}
+
+// parameterOf returns the parameter of a Go value in an array or a map, e.g. a string is a String parameter
+func parameterOf(v interface{}) (ContractParameter, error) {
+	switch value := v.(type) {
+	case nil:
+		return ContractParameter{Type: Any}, nil
+	case ContractParameter:
+		return value, nil
+	case *ContractParameter:
+		return *value, nil
+	case bool:
+		return ContractParameter{Type: Boolean, Value: value}, nil
+	case string:
+		return ContractParameter{Type: String, Value: value}, nil
+	case []byte:
+		return ContractParameter{Type: ByteArray, Value: value}, nil
+	case *helper.UInt160, helper.UInt160:
+		return ContractParameter{Type: Hash160, Value: value}, nil
+	case *helper.UInt256, helper.UInt256:
+		return ContractParameter{Type: Hash256, Value: value}, nil
+	case *crypto.ECPoint, crypto.ECPoint:
+		return ContractParameter{Type: PublicKey, Value: value}, nil
+	case []interface{}:
+		return ContractParameter{Type: Array, Value: value}, nil
+	case map[interface{}]interface{}:
+		return ContractParameter{Type: Map, Value: value}, nil
+	}
+	if _, ok := bigIntOf(v); ok {
+		return ContractParameter{Type: Integer, Value: v}, nil
+	}
+	return ContractParameter{}, fmt.Errorf("invalid value %T", v)
+}
+
+// bigIntOf converts the integer types and big.Int to *big.Int
+func bigIntOf(v interface{}) (*big.Int, bool) {
+	switch i := v.(type) {
+	case int8:
+		return big.NewInt(int64(i)), true
+	case uint8:
+		return big.NewInt(int64(i)), true
+	case int16:
+		return big.NewInt(int64(i)), true
+	case uint16:
+		return big.NewInt(int64(i)), true
+	case int32:
+		return big.NewInt(int64(i)), true
+	case uint32:
+		return big.NewInt(int64(i)), true
+	case int64:
+		return big.NewInt(i), true
+	case uint64:
+		return new(big.Int).SetUint64(i), true
+	case int:
+		return big.NewInt(int64(i)), true
+	case uint:
+		return new(big.Int).SetUint64(uint64(i)), true
+	case big.Int:
+		return &i, true
+	case *big.Int:
+		return i, i != nil
+	default:
+		return nil, false
+	}
+}
+
+// UnmarshalJSON decodes the format of the node, the Value is bool for Boolean, *big.Int for Integer, []byte for
+// ByteArray and Signature, string for String, *helper.UInt160 for Hash160, *helper.UInt256 for Hash256,
+// *crypto.ECPoint for PublicKey, []ContractParameter for Array and []ContractParameterPair for Map
+func (p *ContractParameter) UnmarshalJSON(data []byte) error {
+	var j contractParameterJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	t, err := NewContractParameterTypeFromString(j.Type)
+	if err != nil {
+		return fmt.Errorf("invalid param type %s", j.Type)
+	}
+	p.Type, p.Value = t, nil
+	if len(j.Value) == 0 || string(j.Value) == "null" {
+		return nil
+	}
+	if err = p.unmarshalValue(j.Value); err != nil {
+		return fmt.Errorf("%s parameter: %w", t, err)
+	}
+	return nil
+}
+
+func (p *ContractParameter) unmarshalValue(data json.RawMessage) error {
+	var s string
+	switch p.Type {
+	case Any, InteropInterface, Void:
+		return nil
+	case Boolean:
+		var b bool
+		if err := json.Unmarshal(data, &b); err == nil {
+			p.Value = b
+			return nil
+		}
+		// some clients send "true" and "false"
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		switch strings.ToLower(s) {
+		case "true":
+			p.Value = true
+		case "false":
+			p.Value = false
+		default:
+			return fmt.Errorf("invalid boolean %s", s)
+		}
+		return nil
+	case Integer:
+		// a number is accepted besides the string of the node
+		if err := json.Unmarshal(data, &s); err != nil {
+			s = string(data)
+		}
+		i, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return fmt.Errorf("invalid integer %s", s)
+		}
+		p.Value = i
+		return nil
+	case Array:
+		var items []ContractParameter
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		p.Value = items
+		return nil
+	case Map:
+		var pairs []ContractParameterPair
+		if err := json.Unmarshal(data, &pairs); err != nil {
+			return err
+		}
+		p.Value = pairs
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	switch p.Type {
+	case ByteArray, Signature:
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return err
+		}
+		p.Value = b
+	case String:
+		p.Value = s
+	case Hash160:
+		if len(strings.TrimPrefix(s, "0x")) != 2*helper.UINT160SIZE {
+			return fmt.Errorf("invalid hash %s", s)
+		}
+		u, err := helper.UInt160FromString(s)
+		if err != nil {
+			return err
+		}
+		p.Value = u
+	case Hash256:
+		if len(strings.TrimPrefix(s, "0x")) != 2*helper.UINT256SIZE {
+			return fmt.Errorf("invalid hash %s", s)
+		}
+		u, err := helper.UInt256FromString(s)
+		if err != nil {
+			return err
+		}
+		p.Value = u
+	case PublicKey:
+		point, err := crypto.NewECPointFromString(s)
+		if err != nil {
+			return err
+		}
+		p.Value = point
+	default:
+		return fmt.Errorf("invalid param type %s", p.Type)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package sc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractParameterType_String(t *testing.T) {
+	assert.Equal(t, "Map", Map.String())
+	tp, err := NewContractParameterTypeFromString(Map.String())
+	assert.Nil(t, err)
+	assert.Equal(t, Map, tp)
+}
+
+func TestContractParameter_MarshalJSON(t *testing.T) {
+	gas, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	p := ContractParameter{Type: Array, Value: []ContractParameter{
+		{Type: Hash160, Value: gas},
+		{Type: Integer, Value: 100},
+		{Type: ByteArray, Value: []byte{0x01, 0x02}},
+		{Type: PublicKey, Value: G},
+		{Type: Boolean, Value: true},
+		{Type: Any},
+		{Type: Map, Value: []ContractParameterPair{
+			{Key: ContractParameter{Type: String, Value: "a"}, Value: ContractParameter{Type: Integer, Value: big.NewInt(-1)}},
+		}},
+	}}
+	b, err := json.Marshal(p)
+	assert.Nil(t, err)
+	expected := `{"type":"Array","value":[` +
+		`{"type":"Hash160","value":"0xd2a4cff31913016155e38e474a2c06d08be276cf"},` +
+		`{"type":"Integer","value":"100"},` +
+		`{"type":"ByteArray","value":"AQI="},` +
+		`{"type":"PublicKey","value":"` + helper.BytesToHex(G.EncodePoint(true)) + `"},` +
+		`{"type":"Boolean","value":true},` +
+		`{"type":"Any"},` +
+		`{"type":"Map","value":[{"key":{"type":"String","value":"a"},"value":{"type":"Integer","value":"-1"}}]}]}`
+	assert.Equal(t, expected, string(b))
+
+	var decoded ContractParameter
+	err = json.Unmarshal(b, &decoded)
+	assert.Nil(t, err)
+	items := decoded.Value.([]ContractParameter)
+	assert.Equal(t, 7, len(items))
+	assert.Equal(t, gas.String(), items[0].Value.(*helper.UInt160).String())
+	assert.Equal(t, int64(100), items[1].Value.(*big.Int).Int64())
+	assert.Equal(t, []byte{0x01, 0x02}, items[2].Value)
+	assert.True(t, G.Equals(items[3].Value.(*crypto.ECPoint)))
+	assert.Equal(t, true, items[4].Value)
+	assert.Nil(t, items[5].Value)
+	pairs := items[6].Value.([]ContractParameterPair)
+	assert.Equal(t, "a", pairs[0].Key.Value)
+
+	// the decoded parameter is encoded to the same json and the same script
+	b2, err := json.Marshal(decoded)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, string(b2))
+	sb1, sb2 := NewScriptBuilder(), NewScriptBuilder()
+	sb1.EmitPushParameter(&p)
+	sb2.EmitPushParameter(&decoded)
+	s1, err := sb1.ToArray()
+	assert.Nil(t, err)
+	s2, err := sb2.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, s1, s2)
+}
+
+func TestContractParameter_UnmarshalJSON(t *testing.T) {
+	var p ContractParameter
+	assert.Nil(t, json.Unmarshal([]byte(`{"type":"Boolean","value":"true"}`), &p))
+	assert.Equal(t, true, p.Value)
+	assert.Nil(t, json.Unmarshal([]byte(`{"type":"Integer","value":12}`), &p))
+	assert.Equal(t, int64(12), p.Value.(*big.Int).Int64())
+	assert.Nil(t, json.Unmarshal([]byte(`{"type":"Signature","value":null}`), &p))
+	assert.Nil(t, p.Value)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"Hash160","value":"0x0102"}`), &p))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"Integer","value":"1.5"}`), &p))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"Unknown"}`), &p))
+	_, err := json.Marshal(ContractParameter{Type: Hash160, Value: "0x0102"})
+	assert.NotNil(t, err)
+}
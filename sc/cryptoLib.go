@@ -0,0 +1,131 @@
+package sc
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/crypto/bls12381"
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+const CryptoLibId = "0x726cb6e0cd8628a1350a611384688911ab75f51b"
+
+var CryptoLib, _ = helper.UInt160FromString(CryptoLibId)
+
+// NamedCurveHash is the curveHash argument of CryptoLib.verifyWithECDsa, the curve and the hash of the message
+type NamedCurveHash byte
+
+const (
+	Secp256k1SHA256    NamedCurveHash = 22
+	Secp256r1SHA256    NamedCurveHash = 23
+	Secp256k1Keccak256 NamedCurveHash = 122
+	Secp256r1Keccak256 NamedCurveHash = 123
+)
+
+// NewNamedCurveHash returns the NamedCurveHash of the curve and hash algorithm
+func NewNamedCurveHash(curve elliptic.Curve, hash crypto.HashAlgorithm) (NamedCurveHash, error) {
+	var c NamedCurveHash
+	switch curve {
+	case crypto.Secp256k1:
+		c = Secp256k1SHA256
+	case crypto.P256:
+		c = Secp256r1SHA256
+	default:
+		return 0, fmt.Errorf("curve %s is not supported by CryptoLib", curve.Params().Name)
+	}
+	switch hash {
+	case crypto.HashSHA256:
+		return c, nil
+	case crypto.HashKeccak256:
+		return c + 100, nil
+	default:
+		return 0, fmt.Errorf("hash algorithm %d is not supported by CryptoLib", hash)
+	}
+}
+
+// EmitVerifyWithECDsa emits a call of CryptoLib.verifyWithECDsa, which pushes true if the signature of message
+// is valid. The public key is compressed, e.g. crypto.ECPoint.EncodePoint(true).
+func (sb *ScriptBuilder) EmitVerifyWithECDsa(message, publicKey, signature []byte, curveHash NamedCurveHash) {
+	sb.EmitDynamicCall(CryptoLib, "verifyWithECDsa", []interface{}{message, publicKey, signature, int(curveHash)})
+}
+
+// MakeVerifyWithECDsaScript makes a script which verifies the signature of message with the public key, the
+// NamedCurveHash is taken from the curve of the public key and the hash algorithm
+func MakeVerifyWithECDsaScript(message []byte, publicKey *crypto.ECPoint, signature []byte, hash crypto.HashAlgorithm) ([]byte, error) {
+	curveHash, err := NewNamedCurveHash(publicKey.Curve, hash)
+	if err != nil {
+		return nil, err
+	}
+	sb := NewScriptBuilder()
+	sb.EmitVerifyWithECDsa(message, publicKey.EncodePoint(true), signature, curveHash)
+	return sb.ToArray()
+}
+
+// below methods emit the bls12381 methods of CryptoLib with the encodings of crypto/bls12381, each of them
+// leaves its result on the stack
+
+// EmitBls12381Deserialize pushes the point of a compressed G1 or G2 point or a serialized GT element
+func (sb *ScriptBuilder) EmitBls12381Deserialize(data []byte) {
+	sb.EmitDynamicCall(CryptoLib, "bls12381Deserialize", []interface{}{data})
+}
+
+// EmitBls12381Serialize serializes the point on the top of the stack
+func (sb *ScriptBuilder) EmitBls12381Serialize() {
+	sb.emitCryptoLibCall("bls12381Serialize", 1)
+}
+
+// EmitBls12381Equal pushes true if the two points are equal
+func (sb *ScriptBuilder) EmitBls12381Equal(x, y []byte) {
+	sb.EmitBls12381Deserialize(y)
+	sb.EmitBls12381Deserialize(x)
+	sb.emitCryptoLibCall("bls12381Equal", 2)
+}
+
+// EmitBls12381Add pushes the sum of the two points of the same group
+func (sb *ScriptBuilder) EmitBls12381Add(x, y []byte) {
+	sb.EmitBls12381Deserialize(y)
+	sb.EmitBls12381Deserialize(x)
+	sb.emitCryptoLibCall("bls12381Add", 2)
+}
+
+// EmitBls12381Mul pushes scalar * x, or -scalar * x if neg is true
+func (sb *ScriptBuilder) EmitBls12381Mul(x []byte, scalar *big.Int, neg bool) {
+	sb.EmitPushBool(neg)
+	sb.EmitPushBytes(bls12381.ScalarToBytes(scalar))
+	sb.EmitBls12381Deserialize(x)
+	sb.emitCryptoLibCall("bls12381Mul", 3)
+}
+
+// EmitBls12381Pairing pushes the pairing of a G1 point and a G2 point
+func (sb *ScriptBuilder) EmitBls12381Pairing(g1, g2 []byte) {
+	sb.EmitBls12381Deserialize(g2)
+	sb.EmitBls12381Deserialize(g1)
+	sb.emitCryptoLibCall("bls12381Pairing", 2)
+}
+
+// emitCryptoLibCall calls the method with the top count items of the stack as arguments, the first argument
+// is on the top
+func (sb *ScriptBuilder) emitCryptoLibCall(method string, count int) {
+	sb.EmitPushInteger(count)
+	sb.Emit(PACK)
+	sb.EmitPushObject(All)
+	sb.EmitPushString(method)
+	sb.EmitPushSerializable(CryptoLib)
+	sb.EmitSysCall(System_Contract_Call.ToInteropMethodHash())
+}
+
+// MakeBls12381VerifyScript makes a script which pushes true if the BLS signature of msg is valid, the message
+// is hashed offline since CryptoLib has no hash to curve, e(publicKey, H(msg)) == e(G1, signature)
+func MakeBls12381VerifyScript(publicKey *bls12381.G1Point, msg []byte, signature *bls12381.G2Point) ([]byte, error) {
+	h, err := bls12381.HashToG2(msg, []byte(bls12381.SignatureDST))
+	if err != nil {
+		return nil, err
+	}
+	sb := NewScriptBuilder()
+	sb.EmitBls12381Pairing(bls12381.G1Generator().Bytes(), signature.Bytes())
+	sb.EmitBls12381Pairing(publicKey.Bytes(), h.Bytes())
+	sb.emitCryptoLibCall("bls12381Equal", 2)
+	return sb.ToArray()
+}
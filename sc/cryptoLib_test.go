@@ -0,0 +1,74 @@
+package sc
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/crypto/bls12381"
+)
+
+func TestNewNamedCurveHash(t *testing.T) {
+	c, err := NewNamedCurveHash(crypto.Secp256k1, crypto.HashSHA256)
+	assert.Nil(t, err)
+	assert.Equal(t, Secp256k1SHA256, c)
+	c, _ = NewNamedCurveHash(crypto.Secp256k1, crypto.HashKeccak256)
+	assert.Equal(t, Secp256k1Keccak256, c)
+	c, _ = NewNamedCurveHash(crypto.P256, crypto.HashSHA256)
+	assert.Equal(t, Secp256r1SHA256, c)
+	c, _ = NewNamedCurveHash(crypto.P256, crypto.HashKeccak256)
+	assert.Equal(t, Secp256r1Keccak256, c)
+
+	_, err = NewNamedCurveHash(p256, crypto.HashAlgorithm(9))
+	assert.NotNil(t, err)
+}
+
+func TestMakeVerifyWithECDsaScript(t *testing.T) {
+	params := crypto.Secp256k1.Params()
+	k1, _ := crypto.CreateECPoint(params.Gx, params.Gy, &crypto.Secp256k1)
+	message := []byte("message")
+	signature := make([]byte, 64)
+	script, err := MakeVerifyWithECDsaScript(message, k1, signature, crypto.HashKeccak256)
+	assert.Nil(t, err)
+
+	// the arguments are pushed in reverse: curveHash, signature, public key, message
+	expected := append([]byte{byte(PUSHINT8), 122, byte(PUSHDATA1), 64}, signature...)
+	expected = append(append(expected, byte(PUSHDATA1), 33), k1.EncodePoint(true)...)
+	expected = append(append(expected, byte(PUSHDATA1), byte(len(message))), message...)
+	expected = append(expected, byte(PUSH4), byte(PACK))
+	assert.True(t, bytes.HasPrefix(script, expected))
+	assert.True(t, bytes.Contains(script, []byte("verifyWithECDsa")))
+	assert.True(t, bytes.Contains(script, CryptoLib.ToByteArray()))
+}
+
+func TestScriptBuilder_EmitBls12381Add(t *testing.T) {
+	x := bls12381.G1Generator().Bytes()
+	y := bls12381.G1Generator().Mul(big.NewInt(2)).Bytes()
+	sb := NewScriptBuilder()
+	sb.EmitBls12381Add(x, y)
+	sb.EmitBls12381Serialize()
+	script, err := sb.ToArray()
+	assert.Nil(t, err)
+
+	// y is deserialized first so that x is on the top when the arguments are packed
+	assert.True(t, bytes.HasPrefix(script, append([]byte{byte(PUSHDATA1), 48}, y...)))
+	assert.True(t, bytes.Index(script, y) < bytes.Index(script, x))
+	assert.Equal(t, 2, bytes.Count(script, []byte("bls12381Deserialize")))
+	addIndex := bytes.Index(script, []byte("bls12381Add"))
+	assert.Equal(t, []byte{byte(PUSH2), byte(PACK)}, script[addIndex-5:addIndex-3])
+	assert.True(t, bytes.Index(script, []byte("bls12381Serialize")) > addIndex)
+}
+
+func TestMakeBls12381VerifyScript(t *testing.T) {
+	k, _ := bls12381.GeneratePrivateKey()
+	msg := []byte("message")
+	script, err := MakeBls12381VerifyScript(k.PublicKey(), msg, k.Sign(msg))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, bytes.Count(script, []byte("bls12381Pairing")))
+	assert.Equal(t, 4, bytes.Count(script, []byte("bls12381Deserialize")))
+	assert.True(t, bytes.Contains(script, k.PublicKey().Bytes()))
+	assert.True(t, bytes.Contains(script, []byte("bls12381Equal")))
+}
@@ -0,0 +1,259 @@
+package sc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/vm"
+)
+
+// Instruction is a decoded NeoVM instruction
+type Instruction struct {
+	Offset int
+	OpCode OpCode
+	// Operand is the operand without the size prefix of PUSHDATA
+	Operand []byte
+	// Targets are the absolute offsets of jumps, calls, ENDTRY and PUSHA, or the catch and finally offsets of TRY,
+	// -1 for a TRY without catch or finally
+	Targets []int
+	// Comment describes the operand, e.g. the name of a SYSCALL or the method of System.Contract.Call
+	Comment string
+	// Size is the size of the instruction with its operand
+	Size int
+}
+
+// operandSizes are the fixed operand sizes, the size prefixes of PUSHDATA are in operandSizePrefixes
+var operandSizes = map[OpCode]int{
+	PUSHINT8: 1, PUSHINT16: 2, PUSHINT32: 4, PUSHINT64: 8, PUSHINT128: 16, PUSHINT256: 32,
+	PUSHA: 4,
+	JMP:   1, JMPIF: 1, JMPIFNOT: 1, JMPEQ: 1, JMPNE: 1, JMPGT: 1, JMPGE: 1, JMPLT: 1, JMPLE: 1, CALL: 1, ENDTRY: 1,
+	JMP_L: 4, JMPIF_L: 4, JMPIFNOT_L: 4, JMPEQ_L: 4, JMPNE_L: 4, JMPGT_L: 4, JMPGE_L: 4, JMPLT_L: 4, JMPLE_L: 4,
+	CALL_L: 4, ENDTRY_L: 4,
+	CALLT: 2, TRY: 2, TRY_L: 8, SYSCALL: 4,
+	INITSSLOT: 1, INITSLOT: 2,
+	LDSFLD: 1, STSFLD: 1, LDLOC: 1, STLOC: 1, LDARG: 1, STARG: 1,
+	NEWARRAY_T: 1, ISTYPE: 1, CONVERT: 1,
+}
+
+var operandSizePrefixes = map[OpCode]int{PUSHDATA1: 1, PUSHDATA2: 2, PUSHDATA4: 4}
+
+// nativeContracts are the names of the native contracts by their hashes
+var nativeContracts = func() map[helper.UInt160]string {
+	m := map[helper.UInt160]string{}
+	for _, name := range []string{"ContractManagement", "StdLib", "CryptoLib", "LedgerContract", "NeoToken",
+		"GasToken", "PolicyContract", "RoleManagement", "OracleContract", "Notary"} {
+		m[*GetNativeContractHash(name)] = name
+	}
+	return m
+}()
+
+// GetNativeContractHash returns the hash of a native contract, it is deployed by the zero address with a
+// checksum of 0
+func GetNativeContractHash(name string) *helper.UInt160 {
+	return GetContractHash(helper.UInt160Zero, 0, name)
+}
+
+// GetContractHash returns the hash of a contract deployed by sender, with the checksum of the nef file and the name
+// in the manifest
+func GetContractHash(sender *helper.UInt160, nefCheckSum uint32, name string) *helper.UInt160 {
+	sb := NewScriptBuilder()
+	sb.Emit(ABORT)
+	sb.EmitPushBytes(sender.ToByteArray())
+	sb.EmitPushInteger(int64(nefCheckSum))
+	sb.EmitPushString(name)
+	script, _ := sb.ToArray()
+	return helper.UInt160FromBytes(crypto.Hash160(script))
+}
+
+// Disassemble decodes the script, the operands are checked against the length of the script
+func Disassemble(script []byte) ([]*Instruction, error) {
+	instructions := make([]*Instruction, 0)
+	for offset := 0; offset < len(script); {
+		ins, err := decodeInstruction(script, offset)
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, ins)
+		offset += ins.Size
+	}
+	annotateCalls(instructions)
+	return instructions, nil
+}
+
+func decodeInstruction(script []byte, offset int) (*Instruction, error) {
+	op := OpCode(script[offset])
+	if _, ok := opCodeNames[op]; !ok {
+		return nil, fmt.Errorf("invalid opcode 0x%02X at %d", byte(op), offset)
+	}
+	ins := &Instruction{Offset: offset, OpCode: op, Size: 1}
+	size := operandSizes[op]
+	if prefix, ok := operandSizePrefixes[op]; ok {
+		if offset+1+prefix > len(script) {
+			return nil, fmt.Errorf("size prefix of %s at %d is out of the script", op, offset)
+		}
+		b := script[offset+1 : offset+1+prefix]
+		switch prefix {
+		case 1:
+			size = int(b[0])
+		case 2:
+			size = int(binary.LittleEndian.Uint16(b))
+		default:
+			s := binary.LittleEndian.Uint32(b)
+			if uint64(s) > uint64(len(script)) {
+				return nil, fmt.Errorf("operand of %s at %d is out of the script", op, offset)
+			}
+			size = int(s)
+		}
+		ins.Size += prefix
+	}
+	if offset+ins.Size+size > len(script) {
+		return nil, fmt.Errorf("operand of %s at %d is out of the script", op, offset)
+	}
+	ins.Operand = script[offset+ins.Size : offset+ins.Size+size]
+	ins.Size += size
+	ins.decodeOperand()
+	return ins, nil
+}
+
+func (ins *Instruction) decodeOperand() {
+	op, b := ins.OpCode, ins.Operand
+	switch {
+	case op >= PUSHINT8 && op <= PUSHINT256:
+		ins.Comment = helper.BigIntFromNeoBytes(b).String()
+	case op >= PUSHDATA1 && op <= PUSHDATA4:
+		ins.Comment = describeData(b)
+	case op == TRY:
+		ins.Targets = []int{ins.tryTarget(int(int8(b[0]))), ins.tryTarget(int(int8(b[1])))}
+	case op == TRY_L:
+		ins.Targets = []int{
+			ins.tryTarget(int(int32(binary.LittleEndian.Uint32(b[:4])))),
+			ins.tryTarget(int(int32(binary.LittleEndian.Uint32(b[4:])))),
+		}
+	case op == SYSCALL:
+		hash := uint(binary.LittleEndian.Uint32(b))
+		if s, ok := InteropServiceFromHash(hash); ok {
+			ins.Comment = string(s)
+		}
+	case op == CALLT:
+		ins.Comment = fmt.Sprintf("token %d", binary.LittleEndian.Uint16(b))
+	case op == NEWARRAY_T || op == ISTYPE || op == CONVERT:
+		ins.Comment = vm.StackItemType(b[0]).String()
+	case len(b) == 1:
+		ins.Targets = []int{ins.Offset + int(int8(b[0]))}
+	case len(b) == 4:
+		ins.Targets = []int{ins.Offset + int(int32(binary.LittleEndian.Uint32(b)))}
+	}
+	// the slots have one byte operands which are not offsets
+	switch op {
+	case INITSSLOT, LDSFLD, STSFLD, LDLOC, STLOC, LDARG, STARG:
+		ins.Targets = nil
+		ins.Comment = fmt.Sprintf("%d", b[0])
+	case INITSLOT:
+		ins.Comment = fmt.Sprintf("%d locals, %d arguments", b[0], b[1])
+	}
+}
+
+// tryTarget returns -1 for an offset of 0, which means there is no catch or finally block
+func (ins *Instruction) tryTarget(offset int) int {
+	if offset == 0 {
+		return -1
+	}
+	return ins.Offset + offset
+}
+
+// describeData shows the data as a string if it is printable, or as a script hash or a public key by its length
+func describeData(b []byte) string {
+	if len(b) > 0 && isPrintable(b) {
+		return fmt.Sprintf("%q", string(b))
+	}
+	switch len(b) {
+	case 20:
+		return "0x" + helper.UInt160FromBytes(b).String()
+	case 33:
+		if p, err := crypto.NewECPointFromBytes(b); err == nil {
+			return "public key " + p.String()
+		}
+	}
+	return ""
+}
+
+func isPrintable(b []byte) bool {
+	for _, r := range string(b) {
+		if r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// annotateCalls describes System.Contract.Call with the contract and the method pushed before it
+func annotateCalls(instructions []*Instruction) {
+	for i, ins := range instructions {
+		if ins.OpCode != SYSCALL || ins.Comment != string(System_Contract_Call) || i < 2 {
+			continue
+		}
+		hash, method := instructions[i-1], instructions[i-2]
+		if hash.OpCode != PUSHDATA1 || len(hash.Operand) != 20 || method.OpCode != PUSHDATA1 {
+			continue
+		}
+		contract := helper.UInt160FromBytes(hash.Operand)
+		name := "0x" + contract.String()
+		if native, ok := nativeContracts[*contract]; ok {
+			name = native
+		}
+		ins.Comment = fmt.Sprintf("%s %s.%s", System_Contract_Call, name, string(method.Operand))
+	}
+}
+
+// String formats the instruction as a line of the listing, offset, opcode, operand and comment
+func (ins *Instruction) String() string {
+	var operand string
+	switch {
+	case ins.Targets != nil:
+		targets := make([]string, len(ins.Targets))
+		for i, t := range ins.Targets {
+			if t < 0 {
+				targets[i] = "none"
+			} else {
+				targets[i] = fmt.Sprintf("%04X", t)
+			}
+		}
+		operand = strings.Join(targets, ", ")
+	case ins.OpCode >= PUSHDATA1 && ins.OpCode <= PUSHDATA4:
+		operand = "0x" + helper.BytesToHex(ins.Operand)
+	case len(ins.Operand) > 0 && ins.Comment == "":
+		operand = "0x" + helper.BytesToHex(ins.Operand)
+	}
+	line := fmt.Sprintf("%04X  %-10s", ins.Offset, ins.OpCode.String())
+	if operand != "" {
+		line += " " + operand
+	}
+	if ins.Comment != "" {
+		line += " ; " + ins.Comment
+	}
+	return strings.TrimRight(line, " ")
+}
+
+// FormatScript disassembles the script into a listing, a standard signature or multi-signature verification
+// script is described in the first line
+func FormatScript(script []byte) (string, error) {
+	instructions, err := Disassemble(script)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if IsSignatureContract(script) {
+		sb.WriteString("; signature contract\n")
+	} else if ok, m, n, _ := IsMultiSigContract(script); ok {
+		sb.WriteString(fmt.Sprintf("; %d-of-%d multi-signature contract\n", m, n))
+	}
+	for _, ins := range instructions {
+		sb.WriteString(ins.String())
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,96 @@
+package sc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNativeContractHash(t *testing.T) {
+	assert.Equal(t, CryptoLib.String(), GetNativeContractHash("CryptoLib").String())
+	assert.Equal(t, "d2a4cff31913016155e38e474a2c06d08be276cf", GetNativeContractHash("GasToken").String())
+}
+
+func TestGetContractHash(t *testing.T) {
+	sender := helper.UInt160FromBytes(crypto.Hash160([]byte{0x01}))
+	script := []byte{byte(ABORT), byte(PUSHDATA1), 20}
+	script = append(script, sender.ToByteArray()...)
+	script = append(script, byte(PUSHINT32), 0x78, 0x56, 0x34, 0x12)
+	script = append(script, byte(PUSHDATA1), 4, 't', 'e', 's', 't')
+	expected := helper.UInt160FromBytes(crypto.Hash160(script))
+	assert.Equal(t, expected.String(), GetContractHash(sender, 0x12345678, "test").String())
+	assert.Equal(t, GetNativeContractHash("GasToken").String(), GetContractHash(helper.UInt160Zero, 0, "GasToken").String())
+}
+
+func TestDisassemble_Jumps(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.Emit(TRY, 0x05, 0x00)      // 0000, catch at 0005
+	sb.EmitJump(JMP, 2)           // 0003 -> 0005
+	sb.EmitJump(JMPIF_L, -5)      // 0005 -> 0000
+	sb.Emit(INITSLOT, 0x01, 0x02) // 000A
+	sb.Emit(CONVERT, 0x28)        // 000D
+	sb.EmitPushInteger(1000)      // 0010
+	sb.Emit(RET)                  // 0013
+	script, err := sb.ToArray()
+	assert.Nil(t, err)
+
+	instructions, err := Disassemble(script)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(instructions))
+	assert.Equal(t, []int{5, -1}, instructions[0].Targets)
+	assert.Equal(t, []int{5}, instructions[1].Targets)
+	assert.Equal(t, JMPIF_L, instructions[2].OpCode)
+	assert.Equal(t, []int{0}, instructions[2].Targets)
+	assert.Equal(t, "1 locals, 2 arguments", instructions[3].Comment)
+	assert.Equal(t, "ByteString", instructions[4].Comment)
+	assert.Equal(t, "1000", instructions[5].Comment)
+	assert.Equal(t, 0x12, instructions[6].Offset)
+
+	assert.Equal(t, "0000  TRY        0005, none", instructions[0].String())
+	assert.Equal(t, "000F  PUSHINT16  ; 1000", instructions[5].String())
+}
+
+func TestDisassemble_Invalid(t *testing.T) {
+	_, err := Disassemble([]byte{byte(PUSHDATA1), 0x05, 0x01})
+	assert.NotNil(t, err)
+	_, err = Disassemble([]byte{byte(PUSHDATA4), 0xff, 0xff})
+	assert.NotNil(t, err)
+	_, err = Disassemble([]byte{byte(JMP_L), 0x00})
+	assert.NotNil(t, err)
+	_, err = Disassemble([]byte{0xff})
+	assert.NotNil(t, err)
+}
+
+func TestDisassemble_ContractCall(t *testing.T) {
+	script, err := MakeScript(GetNativeContractHash("NeoToken"), "balanceOf", []interface{}{helper.UInt160FromBytes(make([]byte, 20))})
+	assert.Nil(t, err)
+
+	instructions, err := Disassemble(script)
+	assert.Nil(t, err)
+	last := instructions[len(instructions)-1]
+	assert.Equal(t, SYSCALL, last.OpCode)
+	assert.Equal(t, "System.Contract.Call NeoToken.balanceOf", last.Comment)
+	assert.Equal(t, `"balanceOf"`, instructions[len(instructions)-3].Comment)
+}
+
+func TestFormatScript(t *testing.T) {
+	script, err := CreateSignatureRedeemScript(G)
+	assert.Nil(t, err)
+	listing, err := FormatScript(script)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(listing), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, "; signature contract", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "0000  PUSHDATA1  0x"+helper.BytesToHex(G.EncodePoint(true))))
+	assert.Equal(t, "0023  SYSCALL    ; System.Crypto.CheckSig", lines[2])
+
+	script, err = CreateMultiSigRedeemScript(2, []*crypto.ECPoint{G, G, G})
+	assert.Nil(t, err)
+	listing, err = FormatScript(script)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(listing, "; 2-of-3 multi-signature contract\n0000  PUSH2\n"))
+	assert.True(t, strings.HasSuffix(listing, "SYSCALL    ; System.Crypto.CheckMultisig\n"))
+}
@@ -24,8 +24,71 @@ var (
 	// -----Crypto-----
 	System_Crypto_CheckSig      InteropService = "System.Crypto.CheckSig"
 	System_Crypto_CheckMultisig InteropService = "System.Crypto.CheckMultisig"
+
+	// -----Iterator-----
+	System_Iterator_Next  InteropService = "System.Iterator.Next"
+	System_Iterator_Value InteropService = "System.Iterator.Value"
+
+	// -----Runtime-----
+	System_Runtime_Platform               InteropService = "System.Runtime.Platform"
+	System_Runtime_GetNetwork             InteropService = "System.Runtime.GetNetwork"
+	System_Runtime_GetAddressVersion      InteropService = "System.Runtime.GetAddressVersion"
+	System_Runtime_GetTrigger             InteropService = "System.Runtime.GetTrigger"
+	System_Runtime_GetTime                InteropService = "System.Runtime.GetTime"
+	System_Runtime_GetScriptContainer     InteropService = "System.Runtime.GetScriptContainer"
+	System_Runtime_GetExecutingScriptHash InteropService = "System.Runtime.GetExecutingScriptHash"
+	System_Runtime_GetCallingScriptHash   InteropService = "System.Runtime.GetCallingScriptHash"
+	System_Runtime_GetEntryScriptHash     InteropService = "System.Runtime.GetEntryScriptHash"
+	System_Runtime_LoadScript             InteropService = "System.Runtime.LoadScript"
+	System_Runtime_CheckWitness           InteropService = "System.Runtime.CheckWitness"
+	System_Runtime_GetInvocationCounter   InteropService = "System.Runtime.GetInvocationCounter"
+	System_Runtime_GetRandom              InteropService = "System.Runtime.GetRandom"
+	System_Runtime_Log                    InteropService = "System.Runtime.Log"
+	System_Runtime_Notify                 InteropService = "System.Runtime.Notify"
+	System_Runtime_GetNotifications       InteropService = "System.Runtime.GetNotifications"
+	System_Runtime_GasLeft                InteropService = "System.Runtime.GasLeft"
+	System_Runtime_BurnGas                InteropService = "System.Runtime.BurnGas"
+	System_Runtime_CurrentSigners         InteropService = "System.Runtime.CurrentSigners"
+
+	// -----Storage-----
+	System_Storage_GetContext         InteropService = "System.Storage.GetContext"
+	System_Storage_GetReadOnlyContext InteropService = "System.Storage.GetReadOnlyContext"
+	System_Storage_AsReadOnly         InteropService = "System.Storage.AsReadOnly"
+	System_Storage_Get                InteropService = "System.Storage.Get"
+	System_Storage_Find               InteropService = "System.Storage.Find"
+	System_Storage_Put                InteropService = "System.Storage.Put"
+	System_Storage_Delete             InteropService = "System.Storage.Delete"
 )
 
+// interopServices are all the services above, to resolve the hash in a SYSCALL
+var interopServices = func() map[uint]InteropService {
+	m := map[uint]InteropService{}
+	for _, s := range []InteropService{
+		System_Contract_Call, System_Contract_CallNative, System_Contract_IsStandard, System_Contract_GetCallFlags,
+		System_Contract_CreateStandardAccount, System_Contract_CreateMultisigAccount,
+		System_Contract_NativeOnPersist, System_Contract_NativePostPersist,
+		System_Crypto_CheckSig, System_Crypto_CheckMultisig,
+		System_Iterator_Next, System_Iterator_Value,
+		System_Runtime_Platform, System_Runtime_GetNetwork, System_Runtime_GetAddressVersion,
+		System_Runtime_GetTrigger, System_Runtime_GetTime, System_Runtime_GetScriptContainer,
+		System_Runtime_GetExecutingScriptHash, System_Runtime_GetCallingScriptHash, System_Runtime_GetEntryScriptHash,
+		System_Runtime_LoadScript, System_Runtime_CheckWitness, System_Runtime_GetInvocationCounter,
+		System_Runtime_GetRandom, System_Runtime_Log, System_Runtime_Notify, System_Runtime_GetNotifications,
+		System_Runtime_GasLeft, System_Runtime_BurnGas, System_Runtime_CurrentSigners,
+		System_Storage_GetContext, System_Storage_GetReadOnlyContext, System_Storage_AsReadOnly,
+		System_Storage_Get, System_Storage_Find, System_Storage_Put, System_Storage_Delete,
+	} {
+		m[s.ToInteropMethodHash()] = s
+	}
+	return m
+}()
+
+// InteropServiceFromHash returns the interop service of the hash in a SYSCALL
+func InteropServiceFromHash(hash uint) (InteropService, bool) {
+	s, ok := interopServices[hash]
+	return s, ok
+}
+
 // ToInteropMethodHash converts a method name to 32 bytes hash
 func (p InteropService) ToInteropMethodHash() uint {
 	temp := crypto.Sha256([]byte(p))
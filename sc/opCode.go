@@ -1,5 +1,7 @@
 package sc
 
+import "fmt"
+
 //https://github.com/neo-project/neo-vm/blob/master/src/neo-vm/OpCode.cs
 
 type OpCode byte
@@ -12,6 +14,8 @@ const (
 	PUSHINT64  OpCode = 0x03 // Operand GetSize = 8. Pushes a 8-bytes signed integer onto the stack.
 	PUSHINT128 OpCode = 0x04 // Operand GetSize = 16. Pushes a 16-bytes signed integer onto the stack.
 	PUSHINT256 OpCode = 0x05 // Operand GetSize = 32. Pushes a 32-bytes signed integer onto the stack.
+	PUSHT      OpCode = 0x08 // Pushes the boolean value true onto the stack.
+	PUSHF      OpCode = 0x09 // Pushes the boolean value false onto the stack.
 	PUSHA      OpCode = 0x0A // Converts the 4-bytes offset to a "Pointer", and pushes it onto the stack.
 	PUSHNULL   OpCode = 0x0B // "null" is pushed onto the stack.
 	PUSHDATA1  OpCode = 0x0C // Operand SizePrefix = 1. The next byte contains the number of bytes to be pushed onto the stack.
@@ -169,6 +173,8 @@ const (
 	MOD         OpCode = 0xA2 // Returns the remainder after dividing a by b.
 	POW         OpCode = 0xA3 // The result of raising value to the exponent power.
 	SQRT        OpCode = 0xA4 // Returns the square root of a specified number.
+	MODMUL      OpCode = 0xA5 // Performs modulus operation on the product of a and b.
+	MODPOW      OpCode = 0xA6 // The result of raising value to the exponent power, modulo the modulus.
 	SHL         OpCode = 0xA8 // Shifts a left b bits preserving sign.
 	SHR         OpCode = 0xA9 // Shifts a right b bits preserving sign.
 	NOT         OpCode = 0xAA // If the input is 0 or 1 it is flipped. Otherwise the output will be 0.
@@ -210,6 +216,10 @@ const (
 	ISNULL  OpCode = 0xD8 // Returns "true" if the input is "null"; "false" otherwise.
 	ISTYPE  OpCode = 0xD9 // Operand GetSize = 1. Returns "true" if the top item of the stack is of the specified type; "false" otherwise.
 	CONVERT OpCode = 0xDB // Operand GetSize = 1. Converts the top item of the stack to the specified type.
+
+	// Extensions
+	ABORTMSG  OpCode = 0xE0 // Turns the vm state to FAULT immediately with the message on the top of the stack.
+	ASSERTMSG OpCode = 0xE1 // Pops the message and the condition, if the condition is false, exits with the message.
 )
 
 var OpCodePrices = map[OpCode]int64{
@@ -219,6 +229,8 @@ var OpCodePrices = map[OpCode]int64{
 	PUSHINT64:  1 << 0,
 	PUSHINT128: 1 << 2,
 	PUSHINT256: 1 << 2,
+	PUSHT:      1 << 0,
+	PUSHF:      1 << 0,
 	PUSHA:      1 << 2,
 	PUSHNULL:   1 << 0,
 	PUSHDATA1:  1 << 3,
@@ -368,6 +380,8 @@ var OpCodePrices = map[OpCode]int64{
 	MOD:         1 << 3,
 	POW:         1 << 6,
 	SQRT:        1 << 11,
+	MODMUL:      1 << 5,
+	MODPOW:      1 << 11,
 	SHL:         1 << 3,
 	SHR:         1 << 3,
 	NOT:         1 << 2,
@@ -407,4 +421,212 @@ var OpCodePrices = map[OpCode]int64{
 	ISNULL:  1 << 1,
 	ISTYPE:  1 << 1,
 	CONVERT: 1 << 13,
+
+	ABORTMSG:  0,
+	ASSERTMSG: 1 << 0,
+}
+
+var opCodeNames = map[OpCode]string{
+	PUSHINT8:     "PUSHINT8",
+	PUSHINT16:    "PUSHINT16",
+	PUSHINT32:    "PUSHINT32",
+	PUSHINT64:    "PUSHINT64",
+	PUSHINT128:   "PUSHINT128",
+	PUSHINT256:   "PUSHINT256",
+	PUSHT:        "PUSHT",
+	PUSHF:        "PUSHF",
+	PUSHA:        "PUSHA",
+	PUSHNULL:     "PUSHNULL",
+	PUSHDATA1:    "PUSHDATA1",
+	PUSHDATA2:    "PUSHDATA2",
+	PUSHDATA4:    "PUSHDATA4",
+	PUSHM1:       "PUSHM1",
+	PUSH0:        "PUSH0",
+	PUSH1:        "PUSH1",
+	PUSH2:        "PUSH2",
+	PUSH3:        "PUSH3",
+	PUSH4:        "PUSH4",
+	PUSH5:        "PUSH5",
+	PUSH6:        "PUSH6",
+	PUSH7:        "PUSH7",
+	PUSH8:        "PUSH8",
+	PUSH9:        "PUSH9",
+	PUSH10:       "PUSH10",
+	PUSH11:       "PUSH11",
+	PUSH12:       "PUSH12",
+	PUSH13:       "PUSH13",
+	PUSH14:       "PUSH14",
+	PUSH15:       "PUSH15",
+	PUSH16:       "PUSH16",
+	NOP:          "NOP",
+	JMP:          "JMP",
+	JMP_L:        "JMP_L",
+	JMPIF:        "JMPIF",
+	JMPIF_L:      "JMPIF_L",
+	JMPIFNOT:     "JMPIFNOT",
+	JMPIFNOT_L:   "JMPIFNOT_L",
+	JMPEQ:        "JMPEQ",
+	JMPEQ_L:      "JMPEQ_L",
+	JMPNE:        "JMPNE",
+	JMPNE_L:      "JMPNE_L",
+	JMPGT:        "JMPGT",
+	JMPGT_L:      "JMPGT_L",
+	JMPGE:        "JMPGE",
+	JMPGE_L:      "JMPGE_L",
+	JMPLT:        "JMPLT",
+	JMPLT_L:      "JMPLT_L",
+	JMPLE:        "JMPLE",
+	JMPLE_L:      "JMPLE_L",
+	CALL:         "CALL",
+	CALL_L:       "CALL_L",
+	CALLA:        "CALLA",
+	CALLT:        "CALLT",
+	ABORT:        "ABORT",
+	ASSERT:       "ASSERT",
+	THROW:        "THROW",
+	TRY:          "TRY",
+	TRY_L:        "TRY_L",
+	ENDTRY:       "ENDTRY",
+	ENDTRY_L:     "ENDTRY_L",
+	ENDFINALLY:   "ENDFINALLY",
+	RET:          "RET",
+	SYSCALL:      "SYSCALL",
+	DEPTH:        "DEPTH",
+	DROP:         "DROP",
+	NIP:          "NIP",
+	XDROP:        "XDROP",
+	CLEAR:        "CLEAR",
+	DUP:          "DUP",
+	OVER:         "OVER",
+	PICK:         "PICK",
+	TUCK:         "TUCK",
+	SWAP:         "SWAP",
+	ROT:          "ROT",
+	ROLL:         "ROLL",
+	REVERSE3:     "REVERSE3",
+	REVERSE4:     "REVERSE4",
+	REVERSEN:     "REVERSEN",
+	INITSSLOT:    "INITSSLOT",
+	INITSLOT:     "INITSLOT",
+	LDSFLD0:      "LDSFLD0",
+	LDSFLD1:      "LDSFLD1",
+	LDSFLD2:      "LDSFLD2",
+	LDSFLD3:      "LDSFLD3",
+	LDSFLD4:      "LDSFLD4",
+	LDSFLD5:      "LDSFLD5",
+	LDSFLD6:      "LDSFLD6",
+	LDSFLD:       "LDSFLD",
+	STSFLD0:      "STSFLD0",
+	STSFLD1:      "STSFLD1",
+	STSFLD2:      "STSFLD2",
+	STSFLD3:      "STSFLD3",
+	STSFLD4:      "STSFLD4",
+	STSFLD5:      "STSFLD5",
+	STSFLD6:      "STSFLD6",
+	STSFLD:       "STSFLD",
+	LDLOC0:       "LDLOC0",
+	LDLOC1:       "LDLOC1",
+	LDLOC2:       "LDLOC2",
+	LDLOC3:       "LDLOC3",
+	LDLOC4:       "LDLOC4",
+	LDLOC5:       "LDLOC5",
+	LDLOC6:       "LDLOC6",
+	LDLOC:        "LDLOC",
+	STLOC0:       "STLOC0",
+	STLOC1:       "STLOC1",
+	STLOC2:       "STLOC2",
+	STLOC3:       "STLOC3",
+	STLOC4:       "STLOC4",
+	STLOC5:       "STLOC5",
+	STLOC6:       "STLOC6",
+	STLOC:        "STLOC",
+	LDARG0:       "LDARG0",
+	LDARG1:       "LDARG1",
+	LDARG2:       "LDARG2",
+	LDARG3:       "LDARG3",
+	LDARG4:       "LDARG4",
+	LDARG5:       "LDARG5",
+	LDARG6:       "LDARG6",
+	LDARG:        "LDARG",
+	STARG0:       "STARG0",
+	STARG1:       "STARG1",
+	STARG2:       "STARG2",
+	STARG3:       "STARG3",
+	STARG4:       "STARG4",
+	STARG5:       "STARG5",
+	STARG6:       "STARG6",
+	STARG:        "STARG",
+	NEWBUFFER:    "NEWBUFFER",
+	MEMCPY:       "MEMCPY",
+	CAT:          "CAT",
+	SUBSTR:       "SUBSTR",
+	LEFT:         "LEFT",
+	RIGHT:        "RIGHT",
+	INVERT:       "INVERT",
+	AND:          "AND",
+	OR:           "OR",
+	XOR:          "XOR",
+	EQUAL:        "EQUAL",
+	NOTEQUAL:     "NOTEQUAL",
+	SIGN:         "SIGN",
+	ABS:          "ABS",
+	NEGATE:       "NEGATE",
+	INC:          "INC",
+	DEC:          "DEC",
+	ADD:          "ADD",
+	SUB:          "SUB",
+	MUL:          "MUL",
+	DIV:          "DIV",
+	MOD:          "MOD",
+	POW:          "POW",
+	SQRT:         "SQRT",
+	MODMUL:       "MODMUL",
+	MODPOW:       "MODPOW",
+	SHL:          "SHL",
+	SHR:          "SHR",
+	NOT:          "NOT",
+	BOOLAND:      "BOOLAND",
+	BOOLOR:       "BOOLOR",
+	NZ:           "NZ",
+	NUMEQUAL:     "NUMEQUAL",
+	NUMNOTEQUAL:  "NUMNOTEQUAL",
+	LT:           "LT",
+	LE:           "LE",
+	GT:           "GT",
+	GE:           "GE",
+	MIN:          "MIN",
+	MAX:          "MAX",
+	WITHIN:       "WITHIN",
+	PACK:         "PACK",
+	UNPACK:       "UNPACK",
+	NEWARRAY0:    "NEWARRAY0",
+	NEWARRAY:     "NEWARRAY",
+	NEWARRAY_T:   "NEWARRAY_T",
+	NEWSTRUCT0:   "NEWSTRUCT0",
+	NEWSTRUCT:    "NEWSTRUCT",
+	NEWMAP:       "NEWMAP",
+	SIZE:         "SIZE",
+	HASKEY:       "HASKEY",
+	KEYS:         "KEYS",
+	VALUES:       "VALUES",
+	PICKITEM:     "PICKITEM",
+	APPEND:       "APPEND",
+	SETITEM:      "SETITEM",
+	REVERSEITEMS: "REVERSEITEMS",
+	REMOVE:       "REMOVE",
+	CLEARITEMS:   "CLEARITEMS",
+	POPITEM:      "POPITEM",
+	ISNULL:       "ISNULL",
+	ISTYPE:       "ISTYPE",
+	CONVERT:      "CONVERT",
+	ABORTMSG:     "ABORTMSG",
+	ASSERTMSG:    "ASSERTMSG",
+}
+
+// String returns the name of the opcode, or its value in hex if it is unknown
+func (op OpCode) String() string {
+	if name, ok := opCodeNames[op]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%02X", byte(op))
 }
@@ -0,0 +1,283 @@
+package sc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// ContractCall is a System.Contract.Call found in a script
+type ContractCall struct {
+	// Offset is the offset of the SYSCALL
+	Offset    int
+	Contract  *helper.UInt160
+	Method    string
+	CallFlags CallFlags
+	// Args are the arguments in the order of the method, an argument is nil if it can't be known before running
+	// the script, e.g. the result of another call
+	Args []*ContractParameter
+}
+
+// ScriptAnalysis is the result of AnalyzeScript
+type ScriptAnalysis struct {
+	Calls []*ContractCall
+	// HasControlFlow is true if the script has jumps, calls, exceptions or aborts, then the calls may not all be
+	// executed or some may be executed more than once
+	HasControlFlow bool
+	// Warnings describe the instructions that are not understood, the calls can be incomplete if there are any
+	Warnings []string
+}
+
+// IsSimple returns true if the script is only a sequence of calls with known arguments
+func (a *ScriptAnalysis) IsSimple() bool {
+	return !a.HasControlFlow && len(a.Warnings) == 0
+}
+
+// controlFlowOpCodes are the instructions that change the order of execution
+var controlFlowOpCodes = map[OpCode]bool{
+	JMP: true, JMP_L: true, JMPIF: true, JMPIF_L: true, JMPIFNOT: true, JMPIFNOT_L: true,
+	JMPEQ: true, JMPEQ_L: true, JMPNE: true, JMPNE_L: true, JMPGT: true, JMPGT_L: true,
+	JMPGE: true, JMPGE_L: true, JMPLT: true, JMPLT_L: true, JMPLE: true, JMPLE_L: true,
+	CALL: true, CALL_L: true, CALLA: true, CALLT: true, PUSHA: true,
+	ABORT: true, ABORTMSG: true, THROW: true, TRY: true, TRY_L: true,
+	ENDTRY: true, ENDTRY_L: true, ENDFINALLY: true,
+}
+
+// AnalyzeScript extracts the contract calls from an invocation script, e.g. the script of a transaction. It
+// follows the patterns of ScriptBuilder, EmitDynamicCall, CreateArray, CreateMap and EmitPushParameter, with a
+// symbolic evaluation stack.
+func AnalyzeScript(script []byte) (*ScriptAnalysis, error) {
+	instructions, err := Disassemble(script)
+	if err != nil {
+		return nil, err
+	}
+	a := &ScriptAnalysis{Calls: []*ContractCall{}, Warnings: []string{}}
+	s := &symbolicStack{}
+	for _, ins := range instructions {
+		op := ins.OpCode
+		switch {
+		case op >= PUSHINT8 && op <= PUSHINT256:
+			s.push(&ContractParameter{Type: Integer, Value: helper.BigIntFromNeoBytes(ins.Operand)})
+		case op >= PUSHM1 && op <= PUSH16:
+			s.push(&ContractParameter{Type: Integer, Value: big.NewInt(int64(op) - int64(PUSH0))})
+		case op == PUSHT || op == PUSHF:
+			s.push(&ContractParameter{Type: Boolean, Value: op == PUSHT})
+		case op >= PUSHDATA1 && op <= PUSHDATA4:
+			s.push(&ContractParameter{Type: ByteArray, Value: ins.Operand})
+		case op == PUSHNULL:
+			s.push(&ContractParameter{Type: Any})
+		case op == NEWARRAY0:
+			s.push(&ContractParameter{Type: Array, Value: []*ContractParameter{}})
+		case op == NEWMAP:
+			s.push(&ContractParameter{Type: Map, Value: map[interface{}]interface{}{}})
+		case op == PACK:
+			s.push(s.pack())
+		case op == SETITEM:
+			if !s.setItem() {
+				a.warn(ins, "unknown map")
+			}
+		case op == DUP:
+			s.push(s.peek())
+		case op == DROP || op == ASSERT:
+			s.pop()
+		case op == NOP:
+		case op == RET:
+			if ins.Offset+ins.Size != len(script) {
+				a.HasControlFlow = true
+			}
+		case op == SYSCALL:
+			if uint(binary.LittleEndian.Uint32(ins.Operand)) != System_Contract_Call.ToInteropMethodHash() {
+				a.warn(ins, "syscall "+ins.Comment)
+				s.clear()
+				continue
+			}
+			call, err := s.contractCall(ins.Offset)
+			if err != nil {
+				a.warn(ins, err.Error())
+			} else {
+				a.Calls = append(a.Calls, call)
+			}
+			// the result of the call
+			s.push(nil)
+		case controlFlowOpCodes[op]:
+			a.HasControlFlow = true
+			a.warn(ins, "control flow")
+			s.clear()
+		default:
+			a.warn(ins, "unsupported instruction")
+			s.clear()
+		}
+	}
+	return a, nil
+}
+
+func (a *ScriptAnalysis) warn(ins *Instruction, message string) {
+	a.Warnings = append(a.Warnings, fmt.Sprintf("%04X %s: %s", ins.Offset, ins.OpCode, message))
+}
+
+// symbolicStack is the evaluation stack with the values known before running the script, nil is unknown
+type symbolicStack struct {
+	items []*ContractParameter
+}
+
+func (s *symbolicStack) push(item *ContractParameter) {
+	s.items = append(s.items, item)
+}
+
+// pop returns nil if the stack is empty, the item was pushed before an instruction that is not understood
+func (s *symbolicStack) pop() *ContractParameter {
+	if len(s.items) == 0 {
+		return nil
+	}
+	item := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return item
+}
+
+func (s *symbolicStack) peek() *ContractParameter {
+	if len(s.items) == 0 {
+		return nil
+	}
+	return s.items[len(s.items)-1]
+}
+
+func (s *symbolicStack) clear() {
+	s.items = nil
+}
+
+// pack pops the count and the items, the item on top is the first one of the array
+func (s *symbolicStack) pack() *ContractParameter {
+	count, ok := integerOf(s.pop())
+	if !ok || !count.IsInt64() || count.Int64() < 0 || count.Int64() > int64(len(s.items)) {
+		s.clear()
+		return nil
+	}
+	items := make([]*ContractParameter, count.Int64())
+	for i := range items {
+		items[i] = s.pop()
+	}
+	return &ContractParameter{Type: Array, Value: items}
+}
+
+// setItem pops the value, the key and the map, the map is still on the stack because of the DUP before it
+func (s *symbolicStack) setItem() bool {
+	value, key, m := s.pop(), s.pop(), s.pop()
+	if m == nil || m.Type != Map || key == nil {
+		return false
+	}
+	m.Value.(map[interface{}]interface{})[mapKeyOf(key)] = value
+	return true
+}
+
+// mapKeyOf converts a key to a value that EmitPushObject and CreateMap can emit again
+func mapKeyOf(key *ContractParameter) interface{} {
+	switch key.Type {
+	case ByteArray:
+		return string(key.Value.([]byte))
+	case Integer, Boolean:
+		return key.Value
+	default:
+		return key
+	}
+}
+
+// contractCall pops the hash, the method, the call flags and the arguments of System.Contract.Call
+func (s *symbolicStack) contractCall(offset int) (*ContractCall, error) {
+	hash, method, flags, args := s.pop(), s.pop(), s.pop(), s.pop()
+	if hash == nil || hash.Type != ByteArray || len(hash.Value.([]byte)) != 20 {
+		return nil, fmt.Errorf("unknown contract")
+	}
+	if method == nil || method.Type != ByteArray {
+		return nil, fmt.Errorf("unknown method")
+	}
+	f, ok := integerOf(flags)
+	if !ok || !f.IsInt64() || f.Int64() < 0 || f.Int64() > int64(All) {
+		return nil, fmt.Errorf("unknown call flags")
+	}
+	if args == nil || args.Type != Array {
+		return nil, fmt.Errorf("unknown arguments")
+	}
+	return &ContractCall{
+		Offset:    offset,
+		Contract:  helper.UInt160FromBytes(hash.Value.([]byte)),
+		Method:    string(method.Value.([]byte)),
+		CallFlags: CallFlags(f.Int64()),
+		Args:      args.Value.([]*ContractParameter),
+	}, nil
+}
+
+func integerOf(p *ContractParameter) (*big.Int, bool) {
+	if p == nil || p.Type != Integer {
+		return nil, false
+	}
+	return p.Value.(*big.Int), true
+}
+
+// Nep17Transfer is a call of the NEP-17 method transfer(from, to, amount, data)
+type Nep17Transfer struct {
+	Token  *helper.UInt160
+	From   *helper.UInt160
+	To     *helper.UInt160
+	Amount *big.Int
+	Data   *ContractParameter
+}
+
+// nativeTokens are the symbols and the decimals of the native NEP-17 tokens
+var nativeTokens = map[helper.UInt160]struct {
+	symbol   string
+	decimals int
+}{
+	*GetNativeContractHash("NeoToken"): {"NEO", 0},
+	*GetNativeContractHash("GasToken"): {"GAS", 8},
+}
+
+// Nep17Transfer returns the transfer if the call is a NEP-17 transfer with known accounts and amount
+func (c *ContractCall) Nep17Transfer() (*Nep17Transfer, bool) {
+	if c.Method != "transfer" || len(c.Args) != 4 {
+		return nil, false
+	}
+	from, ok1 := hash160Of(c.Args[0])
+	to, ok2 := hash160Of(c.Args[1])
+	amount, ok3 := integerOf(c.Args[2])
+	if !ok1 || !ok2 || !ok3 {
+		return nil, false
+	}
+	return &Nep17Transfer{Token: c.Contract, From: from, To: to, Amount: amount, Data: c.Args[3]}, true
+}
+
+func hash160Of(p *ContractParameter) (*helper.UInt160, bool) {
+	if p == nil || p.Type != ByteArray || len(p.Value.([]byte)) != 20 {
+		return nil, false
+	}
+	return helper.UInt160FromBytes(p.Value.([]byte)), true
+}
+
+// Nep17Transfers returns the NEP-17 transfers of the calls
+func (a *ScriptAnalysis) Nep17Transfers() []*Nep17Transfer {
+	transfers := []*Nep17Transfer{}
+	for _, c := range a.Calls {
+		if t, ok := c.Nep17Transfer(); ok {
+			transfers = append(transfers, t)
+		}
+	}
+	return transfers
+}
+
+// Summary describes the transfer, e.g. "send 1.5 GAS to Nxxx from Nyyy". The symbol and the decimals of NEO and
+// GAS are known, the amount of other tokens is in the smallest unit, use SummaryWithToken for them.
+func (t *Nep17Transfer) Summary(version byte) string {
+	if token, ok := nativeTokens[*t.Token]; ok {
+		return t.SummaryWithToken(token.symbol, token.decimals, version)
+	}
+	return fmt.Sprintf("send %s of token 0x%s to %s from %s", t.Amount.String(), t.Token.String(),
+		crypto.ScriptHashToAddress(t.To, version), crypto.ScriptHashToAddress(t.From, version))
+}
+
+// SummaryWithToken describes the transfer with the symbol and the decimals of the token, e.g. from Nep17Helper
+func (t *Nep17Transfer) SummaryWithToken(symbol string, decimals int, version byte) string {
+	return fmt.Sprintf("send %s %s to %s from %s", helper.ConvertBigIntToDecimalString(t.Amount, decimals), symbol,
+		crypto.ScriptHashToAddress(t.To, version), crypto.ScriptHashToAddress(t.From, version))
+}
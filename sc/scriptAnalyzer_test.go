@@ -0,0 +1,86 @@
+package sc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeScript_Nep17Transfer(t *testing.T) {
+	gas := GetNativeContractHash("GasToken")
+	from, _ := crypto.AddressToScriptHash("NVTiAjNgagDkTr5HTzDmQP9kPwPHN5BgVq", helper.DefaultAddressVersion)
+	to, _ := crypto.AddressToScriptHash("NgaiKFjurmNmiRzDRQGs44yzByXuSkdGPF", helper.DefaultAddressVersion)
+	sb := NewScriptBuilder()
+	sb.EmitDynamicCall(gas, "transfer", []interface{}{from, to, big.NewInt(150000000), ContractParameter{Type: Any}})
+	sb.Emit(ASSERT)
+	sb.EmitDynamicCallObj(gas, "balanceOf", ReadOnly, []interface{}{from})
+	script, err := sb.ToArray()
+	assert.Nil(t, err)
+
+	a, err := AnalyzeScript(script)
+	assert.Nil(t, err)
+	assert.True(t, a.IsSimple())
+	assert.Equal(t, 2, len(a.Calls))
+	assert.Equal(t, gas.String(), a.Calls[0].Contract.String())
+	assert.Equal(t, "transfer", a.Calls[0].Method)
+	assert.Equal(t, All, a.Calls[0].CallFlags)
+	assert.Equal(t, 4, len(a.Calls[0].Args))
+	assert.Equal(t, Any, a.Calls[0].Args[3].Type)
+	assert.Equal(t, ReadOnly, a.Calls[1].CallFlags)
+	assert.Equal(t, from.ToByteArray(), a.Calls[1].Args[0].Value)
+
+	transfers := a.Nep17Transfers()
+	assert.Equal(t, 1, len(transfers))
+	assert.Equal(t, 0, big.NewInt(150000000).Cmp(transfers[0].Amount))
+	assert.Equal(t, "send 1.5 GAS to NgaiKFjurmNmiRzDRQGs44yzByXuSkdGPF from NVTiAjNgagDkTr5HTzDmQP9kPwPHN5BgVq",
+		transfers[0].Summary(helper.DefaultAddressVersion))
+}
+
+func TestAnalyzeScript_Arguments(t *testing.T) {
+	contract := helper.UInt160FromBytes(make([]byte, 20))
+	sb := NewScriptBuilder()
+	sb.EmitDynamicCall(contract, "put", []interface{}{
+		[]byte{0x01},
+		ContractParameter{Type: Array, Value: []ContractParameter{{Type: Integer, Value: 1}, {Type: Boolean, Value: false}}},
+		ContractParameter{Type: Map, Value: map[interface{}]interface{}{"key": "value"}},
+	})
+	script, err := sb.ToArray()
+	assert.Nil(t, err)
+
+	a, err := AnalyzeScript(script)
+	assert.Nil(t, err)
+	assert.True(t, a.IsSimple())
+	assert.Equal(t, 1, len(a.Calls))
+	args := a.Calls[0].Args
+	assert.Equal(t, []byte{0x01}, args[0].Value)
+	array := args[1].Value.([]*ContractParameter)
+	assert.Equal(t, 2, len(array))
+	assert.Equal(t, int64(1), array[0].Value.(*big.Int).Int64())
+	assert.Equal(t, Integer, array[1].Type)
+	m := args[2].Value.(map[interface{}]interface{})
+	assert.Equal(t, []byte("value"), m["key"].(*ContractParameter).Value)
+	_, ok := a.Calls[0].Nep17Transfer()
+	assert.False(t, ok)
+}
+
+func TestAnalyzeScript_ControlFlow(t *testing.T) {
+	contract := helper.UInt160FromBytes(make([]byte, 20))
+	sb := NewScriptBuilder()
+	sb.EmitPushBool(true)
+	sb.EmitJump(JMPIF, 2)
+	sb.EmitDynamicCall(contract, "name", nil)
+	sb.EmitSysCall(System_Runtime_GetTime.ToInteropMethodHash())
+	script, err := sb.ToArray()
+	assert.Nil(t, err)
+
+	a, err := AnalyzeScript(script)
+	assert.Nil(t, err)
+	assert.False(t, a.IsSimple())
+	assert.True(t, a.HasControlFlow)
+	assert.Equal(t, 1, len(a.Calls))
+	assert.Equal(t, 2, len(a.Warnings))
+	assert.Equal(t, "0001 JMPIF: control flow", a.Warnings[0])
+}
@@ -2,11 +2,15 @@ package sc
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"go/types"
 	"math/big"
+	"reflect"
+	"sort"
 	"strings"
 
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
 )
@@ -22,6 +26,14 @@ func (sb *ScriptBuilder) addError(err error) {
 	}
 }
 
+// addPathError prefixes the error with the path of the argument, e.g. "args[2][1]: data is empty"
+func (sb *ScriptBuilder) addPathError(path string, err error) {
+	if path != "" {
+		err = fmt.Errorf("%s: %w", path, err)
+	}
+	sb.addError(err)
+}
+
 // Initializes a new instance of the "ScriptBuilder" class.
 func NewScriptBuilder() ScriptBuilder {
 	return ScriptBuilder{
@@ -30,17 +42,26 @@ func NewScriptBuilder() ScriptBuilder {
 	}
 }
 
-// Converts the value of this instance to a byte array, pops out all errors.
+// Converts the value of this instance to a byte array, pops out all errors. The script is a copy, it is not changed
+// by Reset and the next emits.
 func (sb *ScriptBuilder) ToArray() ([]byte, error) {
+	script := make([]byte, sb.buff.Len())
+	copy(script, sb.buff.Bytes())
 	if len(sb.errs) == 0 {
-		return sb.buff.Bytes(), nil
+		return script, nil
 	}
 
 	var ss []string
 	for _, err := range sb.errs {
 		ss = append(ss, err.Error())
 	}
-	return sb.buff.Bytes(), fmt.Errorf(strings.Join(ss, "\n"))
+	return script, errors.New(strings.Join(ss, "\n"))
+}
+
+// Reset clears the script and the errors, the memory is kept for the next script, e.g. in a loop
+func (sb *ScriptBuilder) Reset() {
+	sb.buff.Reset()
+	sb.errs = sb.errs[:0]
 }
 
 // Emits an "Instruction" with the specified "OpCode" and operand.
@@ -80,6 +101,14 @@ func (sb *ScriptBuilder) EmitJump(op OpCode, offset int) {
 
 // Emits a push "Instruction" with the specified number.
 func (sb *ScriptBuilder) EmitPushBigInt(number *big.Int) {
+	sb.pushBigInt(number, "")
+}
+
+func (sb *ScriptBuilder) pushBigInt(number *big.Int, path string) {
+	if number == nil {
+		sb.addPathError(path, fmt.Errorf("number is nil"))
+		return
+	}
 	if number.Cmp(big.NewInt(-1)) >= 0 && number.Cmp(big.NewInt(16)) <= 0 { // >=-1 || <=16
 		var b = byte(number.Int64())
 		sb.Emit(PUSH0 + OpCode(b))
@@ -100,45 +129,43 @@ func (sb *ScriptBuilder) EmitPushBigInt(number *big.Int) {
 	} else if len(data) <= 32 {
 		sb.Emit(PUSHINT256, helper.PadRight(data, 32, number.Sign() < 0)...)
 	} else {
-		sb.addError(fmt.Errorf("argument out of range: number"))
+		sb.addPathError(path, fmt.Errorf("argument out of range: number"))
 	}
 }
 
 // EmitPushInteger emits a push "Instruction" with the specified integer type.
 func (sb *ScriptBuilder) EmitPushInteger(num interface{}) {
-	switch num.(type) {
+	sb.pushInteger(num, "")
+}
+
+func (sb *ScriptBuilder) pushInteger(num interface{}, path string) {
+	switch v := num.(type) {
 	case int8:
-		sb.EmitPushBigInt(big.NewInt(int64(num.(int8))))
-		break
+		sb.pushBigInt(big.NewInt(int64(v)), path)
 	case uint8:
-		sb.EmitPushBigInt(big.NewInt(int64(num.(uint8))))
-		break
+		sb.pushBigInt(big.NewInt(int64(v)), path)
 	case int16:
-		sb.EmitPushBigInt(big.NewInt(int64(num.(int16))))
-		break
+		sb.pushBigInt(big.NewInt(int64(v)), path)
 	case uint16:
-		sb.EmitPushBigInt(big.NewInt(int64(num.(uint16))))
-		break
+		sb.pushBigInt(big.NewInt(int64(v)), path)
 	case int32:
-		sb.EmitPushBigInt(big.NewInt(int64(num.(int32))))
-		break
+		sb.pushBigInt(big.NewInt(int64(v)), path)
 	case uint32:
-		sb.EmitPushBigInt(big.NewInt(int64(num.(uint32))))
-		break
+		sb.pushBigInt(big.NewInt(int64(v)), path)
 	case int64:
-		sb.EmitPushBigInt(big.NewInt(num.(int64)))
-		break
+		sb.pushBigInt(big.NewInt(v), path)
 	case uint64:
-		sb.EmitPushBigInt(big.NewInt(int64(num.(uint64))))
-		break
+		sb.pushBigInt(new(big.Int).SetUint64(v), path)
 	case int:
-		sb.EmitPushBigInt(big.NewInt(int64(num.(int))))
-		break
+		sb.pushBigInt(big.NewInt(int64(v)), path)
 	case uint:
-		sb.EmitPushBigInt(big.NewInt(int64(num.(uint))))
-		break
+		sb.pushBigInt(new(big.Int).SetUint64(uint64(v)), path)
+	case big.Int:
+		sb.pushBigInt(&v, path)
+	case *big.Int:
+		sb.pushBigInt(v, path)
 	default:
-		sb.addError(fmt.Errorf("param is not of integer type"))
+		sb.addPathError(path, fmt.Errorf("param is not of integer type: %T", num))
 	}
 }
 
@@ -153,8 +180,12 @@ func (sb *ScriptBuilder) EmitPushBool(data bool) {
 
 // Emits a push "Instruction" with the specified data.
 func (sb *ScriptBuilder) EmitPushBytes(data []byte) {
+	sb.pushBytes(data, "")
+}
+
+func (sb *ScriptBuilder) pushBytes(data []byte, path string) {
 	if data == nil {
-		sb.addError(fmt.Errorf("data is empty"))
+		sb.addPathError(path, fmt.Errorf("data is empty"))
 		return
 	}
 	l := len(data)
@@ -193,30 +224,68 @@ func (sb *ScriptBuilder) EmitSysCall(api uint) {
 // below methods are from the extension helper in VM.Helper.cs
 
 func (sb *ScriptBuilder) CreateArray(list []interface{}) {
+	sb.createArray(list, "")
+}
+
+func (sb *ScriptBuilder) createArray(list []interface{}, path string) {
 	if len(list) == 0 {
 		sb.Emit(NEWARRAY0)
 		return
 	}
 	for i := len(list) - 1; i >= 0; i-- {
-		sb.EmitPushObject(list[i])
+		sb.pushObject(list[i], fmt.Sprintf("%s[%d]", path, i))
 	}
 	sb.EmitPushInteger(len(list))
 	sb.Emit(PACK)
 }
 
-// CreateMap does not guarantee the order of the key-value pairs
+// CreateMap emits the key-value pairs in the order of the scripts of the keys, so the same map always gives the
+// same script
 func (sb *ScriptBuilder) CreateMap(m map[interface{}]interface{}) {
-	sb.Emit(NEWMAP)
-	if m != nil {
-		for k, v := range m {
-			sb.Emit(DUP)
-			sb.EmitPushObject(k)
-			sb.EmitPushObject(v)
-			sb.Emit(SETITEM)
+	sb.createMap(m, "")
+}
+
+func (sb *ScriptBuilder) createMap(m map[interface{}]interface{}, path string) {
+	type entry struct {
+		key   []byte
+		value interface{}
+		path  string
+	}
+	entries := make([]entry, 0, len(m))
+	for k, v := range m {
+		p := fmt.Sprintf("%s[%v]", path, k)
+		kb := NewScriptBuilder()
+		kb.pushObject(k, p)
+		if len(kb.errs) > 0 {
+			sb.errs = append(sb.errs, kb.errs...)
+			continue
+		}
+		key := kb.buff.Bytes()
+		if ins, err := decodeInstruction(key, 0); err != nil || ins.Size != len(key) || !isPrimitivePush(ins.OpCode) {
+			sb.addPathError(p, fmt.Errorf("map key must be a boolean, an integer or a byte string: %T", k))
+			continue
 		}
+		entries = append(entries, entry{key: key, value: v, path: p})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	sb.Emit(NEWMAP)
+	for _, e := range entries {
+		sb.Emit(DUP)
+		sb.EmitRaw(e.key)
+		sb.pushObject(e.value, e.path)
+		sb.Emit(SETITEM)
 	}
 }
 
+// isPrimitivePush returns true if the instruction pushes a Boolean, an Integer or a ByteString
+func isPrimitivePush(op OpCode) bool {
+	return op <= PUSHINT256 || op == PUSHT || op == PUSHF || (op >= PUSHDATA1 && op <= PUSHDATA4) ||
+		(op >= PUSHM1 && op <= PUSH16)
+}
+
 func (sb *ScriptBuilder) EmitOpCodes(ops ...OpCode) {
 	if ops == nil {
 		return
@@ -231,8 +300,9 @@ func (sb *ScriptBuilder) EmitDynamicCall(scriptHash *helper.UInt160, operation s
 	sb.EmitDynamicCallObj(scriptHash, operation, All, args)
 }
 
+// EmitDynamicCallObj emits the call with the flags, the errors of the arguments have paths like "args[1][0]"
 func (sb *ScriptBuilder) EmitDynamicCallObj(scriptHash *helper.UInt160, operation string, flags CallFlags, args []interface{}) {
-	sb.CreateArray(args)
+	sb.createArray(args, "args")
 	sb.EmitPushObject(flags)
 	sb.EmitPushString(operation)
 	sb.EmitPushSerializable(scriptHash)
@@ -240,115 +310,283 @@ func (sb *ScriptBuilder) EmitDynamicCallObj(scriptHash *helper.UInt160, operatio
 }
 
 func (sb *ScriptBuilder) EmitPushSerializable(data io.ISerializable) {
+	sb.pushSerializable(data, "")
+}
+
+func (sb *ScriptBuilder) pushSerializable(data io.ISerializable, path string) {
+	if data == nil || reflect.ValueOf(data).Kind() == reflect.Ptr && reflect.ValueOf(data).IsNil() {
+		sb.addPathError(path, fmt.Errorf("data is nil"))
+		return
+	}
 	b, e := io.ToArray(data)
-	sb.addError(e)
-	sb.EmitPushBytes(b)
+	if e != nil {
+		sb.addPathError(path, e)
+		return
+	}
+	sb.pushBytes(b, path)
 }
 
+// EmitPushParameter emits the value of the parameter, the Value of each type can be:
+//
+//	Any: nil or any value of EmitPushObject
+//	Boolean: bool
+//	Integer: the integer types, big.Int and *big.Int
+//	ByteArray: []byte or string
+//	String: string
+//	Hash160: helper.UInt160 or *helper.UInt160
+//	Hash256: helper.UInt256 or *helper.UInt256
+//	PublicKey: crypto.ECPoint, *crypto.ECPoint or the 33 bytes of a compressed point
+//	Signature: 64 bytes
+//	Array: []ContractParameter, []*ContractParameter or []interface{}
+//	Map: map[interface{}]interface{} or []ContractParameterPair
 func (sb *ScriptBuilder) EmitPushParameter(param *ContractParameter) {
-	if param.Value == nil {
+	sb.pushParameter(param, "")
+}
+
+func (sb *ScriptBuilder) pushParameter(param *ContractParameter, path string) {
+	if param == nil || param.Value == nil {
 		sb.Emit(PUSHNULL)
 		return
 	}
+	mismatch := func() {
+		sb.addPathError(path, fmt.Errorf("invalid value of %s parameter: %T", param.Type, param.Value))
+	}
 	switch param.Type {
-	case Signature, ByteArray:
-		sb.EmitPushBytes(param.Value.([]byte))
-		break
+	case Any:
+		sb.pushObject(param.Value, path)
+	case Signature:
+		b, ok := param.Value.([]byte)
+		if !ok {
+			mismatch()
+		} else if len(b) != 64 {
+			sb.addPathError(path, fmt.Errorf("invalid signature length %d", len(b)))
+		} else {
+			sb.pushBytes(b, path)
+		}
+	case ByteArray:
+		switch v := param.Value.(type) {
+		case []byte:
+			sb.pushBytes(v, path)
+		case string:
+			sb.pushBytes([]byte(v), path)
+		default:
+			mismatch()
+		}
 	case Boolean:
-		sb.EmitPushBool(param.Value.(bool))
-		break
+		if v, ok := param.Value.(bool); ok {
+			sb.EmitPushBool(v)
+		} else {
+			mismatch()
+		}
 	case Integer:
-		sb.EmitPushObject(param.Value)
-		break
+		sb.pushInteger(param.Value, path)
 	case Hash160:
-		sb.EmitPushSerializable(param.Value.(*helper.UInt160))
-		break
+		switch v := param.Value.(type) {
+		case *helper.UInt160:
+			sb.pushSerializable(v, path)
+		case helper.UInt160:
+			sb.pushSerializable(&v, path)
+		default:
+			mismatch()
+		}
 	case Hash256:
-		sb.EmitPushSerializable(param.Value.(*helper.UInt256))
-		break
+		switch v := param.Value.(type) {
+		case *helper.UInt256:
+			sb.pushSerializable(v, path)
+		case helper.UInt256:
+			sb.pushSerializable(&v, path)
+		default:
+			mismatch()
+		}
 	case PublicKey:
-		sb.EmitPushBytes(param.Value.([]byte))
-		break
+		switch v := param.Value.(type) {
+		case *crypto.ECPoint:
+			sb.pushBytes(v.EncodePoint(true), path)
+		case crypto.ECPoint:
+			sb.pushBytes(v.EncodePoint(true), path)
+		case []byte:
+			if _, err := crypto.NewECPointFromBytes(v); err != nil || len(v) != 33 {
+				sb.addPathError(path, fmt.Errorf("invalid public key %s", helper.BytesToHex(v)))
+			} else {
+				sb.pushBytes(v, path)
+			}
+		default:
+			mismatch()
+		}
 	case String:
-		sb.EmitPushString(param.Value.(string))
-		break
+		if v, ok := param.Value.(string); ok {
+			sb.EmitPushString(v)
+		} else {
+			mismatch()
+		}
 	case Array:
-		a, ok := param.Value.([]*ContractParameter)
-		if ok {
-			for i := len(a) - 1; i >= 0; i-- {
-				sb.EmitPushParameter(a[i])
+		switch v := param.Value.(type) {
+		case []*ContractParameter:
+			for i := len(v) - 1; i >= 0; i-- {
+				sb.pushParameter(v[i], fmt.Sprintf("%s[%d]", path, i))
 			}
-			sb.EmitPushInteger(len(a))
+			sb.EmitPushInteger(len(v))
 			sb.Emit(PACK)
-			break
-		} else {
-			b := param.Value.([]ContractParameter)
-			for i := len(b) - 1; i >= 0; i-- {
-				sb.EmitPushParameter(&b[i])
+		case []ContractParameter:
+			for i := len(v) - 1; i >= 0; i-- {
+				sb.pushParameter(&v[i], fmt.Sprintf("%s[%d]", path, i))
 			}
-			sb.EmitPushInteger(len(b))
+			sb.EmitPushInteger(len(v))
 			sb.Emit(PACK)
-			break
+		case []interface{}:
+			sb.createArray(v, path)
+		default:
+			mismatch()
 		}
 	case Map:
-		pairs := param.Value.(map[interface{}]interface{})
-		sb.CreateMap(pairs)
-		break
+		switch v := param.Value.(type) {
+		case map[interface{}]interface{}:
+			sb.createMap(v, path)
+		case []ContractParameterPair:
+			sb.Emit(NEWMAP)
+			for i := range v {
+				p := fmt.Sprintf("%s[%d]", path, i)
+				switch v[i].Key.Type {
+				case Boolean, Integer, ByteArray, String, Hash160, Hash256, PublicKey, Signature:
+				default:
+					sb.addPathError(p, fmt.Errorf("map key must be a boolean, an integer or a byte string: %s", v[i].Key.Type))
+					continue
+				}
+				sb.Emit(DUP)
+				sb.pushParameter(&v[i].Key, p+".Key")
+				sb.pushParameter(&v[i].Value, p+".Value")
+				sb.Emit(SETITEM)
+			}
+		default:
+			mismatch()
+		}
 	default:
-		sb.addError(fmt.Errorf("invalid param type"))
-		break
+		sb.addPathError(path, fmt.Errorf("invalid param type %s", param.Type))
 	}
 }
 
+// EmitPushObject emits a value, besides the types of EmitPushParameter, slices, maps and structs are emitted as
+// arrays and maps with reflection, a struct is an array of its exported fields
 func (sb *ScriptBuilder) EmitPushObject(obj interface{}) {
-	switch obj.(type) {
+	sb.pushObject(obj, "")
+}
+
+func (sb *ScriptBuilder) pushObject(obj interface{}, path string) {
+	switch v := obj.(type) {
+	case nil, types.Nil:
+		sb.Emit(PUSHNULL)
 	case CallFlags:
-		sb.EmitPushBigInt(big.NewInt(int64(obj.(CallFlags))))
-		break
+		sb.pushBigInt(big.NewInt(int64(v)), path)
 	case bool:
-		sb.EmitPushBool(obj.(bool))
-		break
+		sb.EmitPushBool(v)
 	case []byte:
-		sb.EmitPushBytes(obj.([]byte))
-		break
+		sb.pushBytes(v, path)
 	case string:
-		sb.EmitPushString(obj.(string))
-		break
-	case big.Int:
-		bi := obj.(big.Int)
-		sb.EmitPushBigInt(&bi)
-		break
-	case *big.Int:
-		sb.EmitPushBigInt(obj.(*big.Int))
-		break
-	case io.ISerializable:
-		sb.EmitPushSerializable(obj.(io.ISerializable))
-		break
-	case int8, uint8, int16, uint16,
+		sb.EmitPushString(v)
+	case big.Int, *big.Int,
+		int8, uint8, int16, uint16,
 		int32, uint32, int64, uint64,
 		int, uint:
-		sb.EmitPushInteger(obj)
-		break
+		sb.pushInteger(obj, path)
+	case *crypto.ECPoint:
+		if v == nil {
+			sb.Emit(PUSHNULL)
+		} else {
+			sb.pushBytes(v.EncodePoint(true), path)
+		}
+	case crypto.ECPoint:
+		sb.pushBytes(v.EncodePoint(true), path)
+	case helper.UInt160:
+		sb.pushSerializable(&v, path)
+	case helper.UInt256:
+		sb.pushSerializable(&v, path)
+	case io.ISerializable:
+		if reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+			sb.Emit(PUSHNULL)
+		} else {
+			sb.pushSerializable(v, path)
+		}
 	case ContractParameter:
-		cp := obj.(ContractParameter)
-		sb.EmitPushParameter(&cp)
-		break
+		sb.pushParameter(&v, path)
 	case *ContractParameter:
-		sb.EmitPushParameter(obj.(*ContractParameter))
-		break
-	case types.Nil:
-		sb.Emit(PUSHNULL)
-		break
+		sb.pushParameter(v, path)
+	case []interface{}:
+		sb.createArray(v, path)
+	case map[interface{}]interface{}:
+		sb.createMap(v, path)
+	default:
+		sb.pushValue(reflect.ValueOf(obj), path)
+	}
+}
+
+// pushValue emits the values that are not known by pushObject with reflection
+func (sb *ScriptBuilder) pushValue(v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			sb.Emit(PUSHNULL)
+		} else {
+			sb.pushObject(v.Elem().Interface(), path)
+		}
+	case reflect.Bool:
+		sb.EmitPushBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sb.pushBigInt(big.NewInt(v.Int()), path)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		sb.pushBigInt(new(big.Int).SetUint64(v.Uint()), path)
+	case reflect.String:
+		sb.EmitPushString(v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			sb.Emit(PUSHNULL)
+			return
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			sb.pushBytes(b, path)
+			return
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = v.Index(i).Interface()
+		}
+		sb.createArray(list, path)
+	case reflect.Map:
+		if v.IsNil() {
+			sb.Emit(PUSHNULL)
+			return
+		}
+		m := make(map[interface{}]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().Interface()] = iter.Value().Interface()
+		}
+		sb.createMap(m, path)
+	case reflect.Struct:
+		t := v.Type()
+		var fields []interface{}
+		var names []string
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				fields = append(fields, v.Field(i).Interface())
+				names = append(names, t.Field(i).Name)
+			}
+		}
+		for i := len(fields) - 1; i >= 0; i-- {
+			sb.pushObject(fields[i], path+"."+names[i])
+		}
+		sb.EmitPushInteger(len(fields))
+		sb.Emit(PACK)
 	default:
-		sb.addError(fmt.Errorf("invalid argument type"))
-		break
+		sb.addPathError(path, fmt.Errorf("invalid argument type %T", v.Interface()))
 	}
 }
 
 func (sb *ScriptBuilder) EmitSysCallObj(method uint, args ...interface{}) {
 	if args != nil {
 		for i := len(args) - 1; i >= 0; i-- {
-			sb.EmitPushObject(args[i])
+			sb.pushObject(args[i], fmt.Sprintf("args[%d]", i))
 		}
 	}
 	sb.EmitSysCall(method)
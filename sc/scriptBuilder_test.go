@@ -363,3 +363,95 @@ func TestMakeScript2(t *testing.T) {
 	bb := crypto.Base64Encode(b)
 	fmt.Println(bb)
 }
+
+func TestScriptBuilder_Reset(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.EmitPushBytes(nil)
+	b1, err := sb.ToArray()
+	assert.NotNil(t, err)
+
+	sb.Reset()
+	sb.Emit(PUSH1)
+	b1, err = sb.ToArray()
+	assert.Nil(t, err)
+	sb.Reset()
+	sb.Emit(PUSH2)
+	b2, err := sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{byte(PUSH1)}, b1)
+	assert.Equal(t, []byte{byte(PUSH2)}, b2)
+}
+
+func TestScriptBuilder_CreateMap_Order(t *testing.T) {
+	m := map[interface{}]interface{}{"b": 2, "a": 1, "c": 3, 10: 4, true: 5}
+	sb := NewScriptBuilder()
+	sb.CreateMap(m)
+	expected, err := sb.ToArray()
+	assert.Nil(t, err)
+	for i := 0; i < 10; i++ {
+		sb.Reset()
+		sb.CreateMap(m)
+		b, err := sb.ToArray()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, b)
+	}
+	// the keys of PUSHDATA1 are before the key of PUSH1, and PUSH10
+	assert.Equal(t, "c84a0c016111d04a0c016212d04a0c016313d04a1115d04a1a14d0", helper.BytesToHex(expected))
+
+	sb.Reset()
+	sb.CreateMap(map[interface{}]interface{}{[2]int{1, 2}: 1})
+	_, err = sb.ToArray()
+	assert.NotNil(t, err)
+}
+
+func TestScriptBuilder_EmitPushParameter(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.EmitPushParameter(&ContractParameter{Type: PublicKey, Value: G})
+	sb.EmitPushParameter(&ContractParameter{Type: Signature, Value: make([]byte, 64)})
+	sb.EmitPushParameter(&ContractParameter{Type: Hash256, Value: helper.UInt256Zero})
+	sb.EmitPushParameter(&ContractParameter{Type: Integer, Value: big.NewInt(-1)})
+	sb.EmitPushParameter(&ContractParameter{Type: Any})
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	expected := append([]byte{byte(PUSHDATA1), 33}, G.EncodePoint(true)...)
+	expected = append(expected, byte(PUSHDATA1), 64)
+	expected = append(expected, make([]byte, 64)...)
+	expected = append(expected, byte(PUSHDATA1), 32)
+	expected = append(expected, make([]byte, 32)...)
+	expected = append(expected, byte(PUSHM1), byte(PUSHNULL))
+	assert.Equal(t, expected, b)
+}
+
+func TestScriptBuilder_ErrorPath(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.EmitDynamicCall(helper.UInt160Zero, "put", []interface{}{
+		1,
+		ContractParameter{Type: Array, Value: []ContractParameter{
+			{Type: String, Value: "a"},
+			{Type: Hash160, Value: "not a hash"},
+		}},
+		map[interface{}]interface{}{"key": ContractParameter{Type: Signature, Value: []byte{0x01}}},
+	})
+	_, err := sb.ToArray()
+	assert.NotNil(t, err)
+	assert.Equal(t, "args[2][key]: invalid signature length 1\nargs[1][1]: invalid value of Hash160 parameter: string", err.Error())
+}
+
+func TestScriptBuilder_EmitPushObject_Struct(t *testing.T) {
+	type point struct {
+		X, Y  int
+		label string
+		Tags  []string
+	}
+	sb := NewScriptBuilder()
+	sb.EmitPushObject(point{X: 1, Y: 2, label: "ignored", Tags: []string{"a"}})
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, "0c016111c0121113c0", helper.BytesToHex(b))
+
+	sb.Reset()
+	sb.EmitPushObject(struct{ C chan int }{})
+	_, err = sb.ToArray()
+	assert.NotNil(t, err)
+	assert.Equal(t, ".C: invalid argument type chan int", err.Error())
+}
@@ -0,0 +1,57 @@
+package sender
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// OutboxEntry is a pending send, every rebuilt version of the tx is kept until one of them lands or all expire
+type OutboxEntry struct {
+	Id              string   `json:"id"`       // hash of the first version
+	Versions        []string `json:"versions"` // base64 encoded signed txs, the last one is the current version
+	ValidUntilBlock uint32   `json:"validuntilblock"`
+	SentAt          uint32   `json:"sentat"` // block height when the current version is sent
+	Bumps           int      `json:"bumps"`
+	LastError       string   `json:"lasterror,omitempty"`
+}
+
+// Outbox persists the pending sends
+type Outbox interface {
+	Load() ([]*OutboxEntry, error)
+	Save(entries []*OutboxEntry) error
+}
+
+// FileOutbox keeps the pending sends in a json file
+type FileOutbox struct {
+	Path string
+}
+
+func NewFileOutbox(path string) *FileOutbox {
+	return &FileOutbox{Path: path}
+}
+
+func (o *FileOutbox) Load() ([]*OutboxEntry, error) {
+	b, err := os.ReadFile(o.Path)
+	if os.IsNotExist(err) {
+		return []*OutboxEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := []*OutboxEntry{}
+	err = json.Unmarshal(b, &entries)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (o *FileOutbox) Save(entries []*OutboxEntry) error {
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return helper.WriteFileAtomic(o.Path, b)
+}
@@ -0,0 +1,383 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tracker"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/joeqian10/neo3-gogogo/wallet"
+)
+
+const (
+	DefaultPollInterval = 5 * time.Second
+	DefaultBumpAfter    = 2
+	DefaultBumpPercent  = 20
+	DefaultMaxBumps     = 5
+)
+
+// FeeBumpPolicy controls how a pending tx is replaced with a higher network fee, it is disabled by default
+type FeeBumpPolicy struct {
+	Enabled       bool
+	AfterBlocks   uint32 // blocks to wait for the current version before replacing it
+	Percent       int64  // network fee increase of each replacement
+	MaxNetworkFee int64  // 0 for no limit
+	MaxBumps      int
+	// HighPriority adds the HighPriorityAttribute to the replacement when the sender is the committee address
+	HighPriority bool
+}
+
+// SendResult is the final state of a send, Hash is the version which landed
+type SendResult struct {
+	Id         string
+	Hash       string
+	State      tracker.TxState // Confirmed or Expired
+	BlockIndex uint32
+	VMState    string
+	Exception  string
+	// Conflicts are the other versions found in blocks, every replacement carries the ConflictsAttribute of
+	// the previous versions, so this is only set by a node which does not enforce the attribute
+	Conflicts []string
+}
+
+// Sender keeps signed txs in an outbox and rebroadcasts them until they confirm or reach ValidUntilBlock.
+// With Policy enabled, a tx pending for too long is rebuilt with a higher network fee and re-signed by Helper.
+// Before a replacement is sent, every previous version is checked not to be in a block, and the old versions
+// are never rebroadcast again. The replacement conflicts with the previous versions, so at most one version lands.
+type Sender struct {
+	Helper       *wallet.WalletHelper
+	Client       rpc.IRpcClient
+	Outbox       Outbox
+	Magic        uint32
+	Policy       FeeBumpPolicy
+	PollInterval time.Duration
+	OnResult     func(result *SendResult) // optional
+
+	entries   []*OutboxEntry
+	committee *helper.UInt160
+	lock      sync.Mutex
+}
+
+// NewSender loads the pending sends from the outbox
+func NewSender(wh *wallet.WalletHelper, outbox Outbox, magic uint32) (*Sender, error) {
+	if wh == nil || outbox == nil {
+		return nil, fmt.Errorf("WalletHelper and Outbox should not be nil")
+	}
+	entries, err := outbox.Load()
+	if err != nil {
+		return nil, err
+	}
+	return &Sender{
+		Helper:       wh,
+		Client:       wh.Client,
+		Outbox:       outbox,
+		Magic:        magic,
+		PollInterval: DefaultPollInterval,
+		entries:      entries,
+	}, nil
+}
+
+// Pending returns the ids of the pending sends
+func (s *Sender) Pending() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ids := make([]string, len(s.entries))
+	for i, e := range s.entries {
+		ids[i] = e.Id
+	}
+	return ids
+}
+
+// Send puts a signed tx into the outbox and broadcasts it. The tx is kept for rebroadcasting if the node is
+// unreachable, and removed if the node rejects it.
+func (s *Sender) Send(trx *tx.Transaction) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	height, err := s.Helper.GetBlockHeight()
+	if err != nil {
+		return "", err
+	}
+	hash := "0x" + trx.GetHash().String()
+	raw := crypto.Base64Encode(trx.ToByteArray())
+	entry := &OutboxEntry{
+		Id:              hash,
+		Versions:        []string{raw},
+		ValidUntilBlock: trx.GetValidUntilBlock(),
+		SentAt:          height,
+	}
+	s.entries = append(s.entries, entry)
+	if err = s.Outbox.Save(s.entries); err != nil {
+		s.entries = s.entries[:len(s.entries)-1]
+		return "", err
+	}
+	response := s.Client.SendRawTransaction(raw)
+	if response.NetError != nil {
+		entry.LastError = response.NetError.Error()
+		return hash, s.Outbox.Save(s.entries)
+	}
+	if response.HasError() {
+		s.remove(entry)
+		if err = s.Outbox.Save(s.entries); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf(response.GetErrorInfo())
+	}
+	return hash, nil
+}
+
+// Run polls until the context is done, errors of a single poll are retried in the next one
+func (s *Sender) Run(ctx context.Context) error {
+	for {
+		_, _ = s.Poll()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.pollInterval()):
+		}
+	}
+}
+
+// Poll checks every pending send once, it rebroadcasts, replaces or resolves them
+func (s *Sender) Poll() ([]*SendResult, error) {
+	s.lock.Lock()
+	results, err := s.poll()
+	s.lock.Unlock()
+	if s.OnResult != nil {
+		for _, r := range results {
+			s.OnResult(r)
+		}
+	}
+	return results, err
+}
+
+func (s *Sender) poll() ([]*SendResult, error) {
+	results := []*SendResult{}
+	if len(s.entries) == 0 {
+		return results, nil
+	}
+	height, err := s.Helper.GetBlockHeight()
+	if err != nil {
+		return results, err
+	}
+	poolResponse := s.Client.GetRawMemPool()
+	if poolResponse.HasError() {
+		return results, fmt.Errorf(poolResponse.GetErrorInfo())
+	}
+	pool := map[string]bool{}
+	for _, h := range poolResponse.Result {
+		pool[h] = true
+	}
+	for _, entry := range append([]*OutboxEntry{}, s.entries...) {
+		result, err := s.check(entry, height, pool)
+		if err != nil {
+			entry.LastError = err.Error()
+			continue
+		}
+		if result != nil {
+			s.remove(entry)
+			results = append(results, result)
+		}
+	}
+	return results, s.Outbox.Save(s.entries)
+}
+
+func (s *Sender) check(entry *OutboxEntry, height uint32, pool map[string]bool) (*SendResult, error) {
+	versions, err := decodeVersions(entry)
+	if err != nil {
+		return nil, err
+	}
+	result := &SendResult{Id: entry.Id}
+	for _, v := range versions {
+		hash := "0x" + v.GetHash().String()
+		response := s.Client.GetTransactionHeight(hash)
+		if response.HasError() {
+			continue
+		}
+		if result.Hash != "" {
+			result.Conflicts = append(result.Conflicts, hash)
+			continue
+		}
+		result.Hash = hash
+		result.State = tracker.Confirmed
+		result.BlockIndex = uint32(response.Result)
+		logResponse := s.Client.GetApplicationLog(hash)
+		if !logResponse.HasError() {
+			for _, execution := range logResponse.Result.Executions {
+				if execution.Trigger == "Application" {
+					result.VMState, result.Exception = execution.VMState, execution.Exception
+				}
+			}
+		}
+	}
+	if result.Hash != "" {
+		return result, nil
+	}
+	if height >= entry.ValidUntilBlock {
+		result.State = tracker.Expired
+		return result, nil
+	}
+
+	current := versions[len(versions)-1]
+	if s.shouldBump(entry, height) {
+		replacement, err := s.Replace(current)
+		if err != nil {
+			return nil, err
+		}
+		raw := crypto.Base64Encode(replacement.ToByteArray())
+		entry.Versions = append(entry.Versions, raw)
+		entry.SentAt = height
+		entry.Bumps++
+		entry.LastError = ""
+		response := s.Client.SendRawTransaction(raw)
+		if response.HasError() {
+			entry.LastError = response.GetErrorInfo()
+		}
+		return nil, nil
+	}
+	if !pool["0x"+current.GetHash().String()] {
+		response := s.Client.SendRawTransaction(entry.Versions[len(entry.Versions)-1])
+		if response.HasError() {
+			entry.LastError = response.GetErrorInfo()
+		}
+	}
+	return nil, nil
+}
+
+func (s *Sender) shouldBump(entry *OutboxEntry, height uint32) bool {
+	if !s.Policy.Enabled {
+		return false
+	}
+	maxBumps := s.Policy.MaxBumps
+	if maxBumps <= 0 {
+		maxBumps = DefaultMaxBumps
+	}
+	after := s.Policy.AfterBlocks
+	if after == 0 {
+		after = DefaultBumpAfter
+	}
+	return entry.Bumps < maxBumps && height >= entry.SentAt+after
+}
+
+// Replace rebuilds a tx with a higher network fee, a ConflictsAttribute of the old tx, and the HighPriorityAttribute
+// if the policy allows, then re-signs it
+func (s *Sender) Replace(old *tx.Transaction) (*tx.Transaction, error) {
+	trx := tx.NewTransaction()
+	trx.SetVersion(old.GetVersion())
+	trx.SetNonce(old.GetNonce())
+	trx.SetSystemFee(old.GetSystemFee())
+	trx.SetValidUntilBlock(old.GetValidUntilBlock())
+	trx.SetSigners(old.GetSigners())
+	trx.SetScript(old.GetScript())
+	attributes := append([]tx.ITransactionAttribute{}, old.GetAttributes()...)
+	attributes = append(attributes, tx.NewConflictsAttribute(old.GetHash()))
+	if s.Policy.HighPriority && !hasHighPriority(attributes) {
+		committee, err := s.GetCommitteeAddress()
+		if err != nil {
+			return nil, err
+		}
+		if trx.GetSender().Equals(committee) {
+			attributes = append(attributes, &tx.HighPriorityAttribute{})
+		}
+	}
+	trx.SetAttributes(attributes)
+	if err := trx.ValidateAttributes(); err != nil {
+		return nil, err
+	}
+
+	fee, err := s.Helper.CalculateNetworkFee(trx)
+	if err != nil {
+		return nil, err
+	}
+	percent := s.Policy.Percent
+	if percent <= 0 {
+		percent = DefaultBumpPercent
+	}
+	bumped := old.GetNetworkFee() * (100 + percent) / 100
+	if int64(fee) > bumped {
+		bumped = int64(fee)
+	}
+	if s.Policy.MaxNetworkFee > 0 && bumped > s.Policy.MaxNetworkFee {
+		return nil, fmt.Errorf("network fee %d exceeds the limit %d", bumped, s.Policy.MaxNetworkFee)
+	}
+	trx.SetNetworkFee(bumped)
+	return s.Helper.SignTransaction(trx, s.Magic)
+}
+
+// GetCommitteeAddress returns the script hash of the committee multi-signature address
+func (s *Sender) GetCommitteeAddress() (*helper.UInt160, error) {
+	if s.committee != nil {
+		return s.committee, nil
+	}
+	response := s.Client.GetCommittee()
+	if response.HasError() {
+		return nil, fmt.Errorf(response.GetErrorInfo())
+	}
+	points := make([]*crypto.ECPoint, len(response.Result))
+	for i, p := range response.Result {
+		point, err := crypto.NewECPointFromString(p)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = point
+	}
+	n := len(points)
+	script, err := sc.CreateMultiSigRedeemScript(n-(n-1)/2, points)
+	if err != nil {
+		return nil, err
+	}
+	s.committee = helper.UInt160FromBytes(crypto.Hash160(script))
+	return s.committee, nil
+}
+
+func hasHighPriority(attributes []tx.ITransactionAttribute) bool {
+	for _, a := range attributes {
+		if a.GetAttributeType() == tx.HighPriority {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeVersions(entry *OutboxEntry) ([]*tx.Transaction, error) {
+	if len(entry.Versions) == 0 {
+		return nil, fmt.Errorf("outbox entry %s has no tx", entry.Id)
+	}
+	result := make([]*tx.Transaction, len(entry.Versions))
+	for i, v := range entry.Versions {
+		b, err := crypto.Base64Decode(v)
+		if err != nil {
+			return nil, err
+		}
+		trx := &tx.Transaction{}
+		br := io.NewBinaryReaderFromBuf(b)
+		trx.Deserialize(br)
+		if br.Err != nil {
+			return nil, br.Err
+		}
+		result[i] = trx
+	}
+	return result, nil
+}
+
+func (s *Sender) remove(entry *OutboxEntry) {
+	for i, e := range s.entries {
+		if e == entry {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Sender) pollInterval() time.Duration {
+	if s.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+	return s.PollInterval
+}
@@ -0,0 +1,182 @@
+package sender
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tracker"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/joeqian10/neo3-gogogo/wallet"
+)
+
+var unknownTx = rpc.GetTransactionHeightResponse{
+	ErrorResponse: rpc.ErrorResponse{Error: rpc.RpcError{Code: -100, Message: "Unknown transaction"}},
+}
+
+func blockCount(count int) rpc.GetBlockCountResponse {
+	return rpc.GetBlockCountResponse{Result: count}
+}
+
+func signedTx(t *testing.T, wh *wallet.WalletHelper, account *helper.UInt160) *tx.Transaction {
+	trx := tx.NewTransaction()
+	trx.SetNonce(1)
+	trx.SetSystemFee(1000000)
+	trx.SetValidUntilBlock(200)
+	trx.SetSigners([]*tx.Signer{tx.NewSigner(account, tx.CalledByEntry)})
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	fee, err := wh.CalculateNetworkFee(trx)
+	assert.Nil(t, err)
+	trx.SetNetworkFee(int64(fee))
+	trx, err = wh.SignTransaction(trx, helper.Neo3Magic_MainNet)
+	assert.Nil(t, err)
+	return trx
+}
+
+func TestSender(t *testing.T) {
+	pair, _ := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[0].PrivateKey))
+	account, _ := helper.UInt160FromString(keys.KeyCases[0].ScriptHash)
+	clientMock := new(rpc.RpcClientMock)
+	wh, err := wallet.NewWalletHelperFromPrivateKey(clientMock, pair.PrivateKey)
+	assert.Nil(t, err)
+	trx := signedTx(t, wh, account)
+	hash := "0x" + trx.GetHash().String()
+	outbox := NewFileOutbox(filepath.Join(t.TempDir(), "outbox.json"))
+
+	// send at height 100
+	clientMock.On("GetBlockCount").Return(blockCount(101)).Once()
+	clientMock.On("SendRawTransaction", mock.Anything).Return(rpc.SendRawTransactionResponse{})
+	s, err := NewSender(wh, outbox, helper.Neo3Magic_MainNet)
+	assert.Nil(t, err)
+	h, err := s.Send(trx)
+	assert.Nil(t, err)
+	assert.Equal(t, hash, h)
+
+	// restart
+	s, err = NewSender(wh, outbox, helper.Neo3Magic_MainNet)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{hash}, s.Pending())
+	s.Policy = FeeBumpPolicy{Enabled: true, AfterBlocks: 2}
+	replacement, err := s.Replace(trx)
+	assert.Nil(t, err)
+	assert.Equal(t, trx.GetNetworkFee()*120/100, replacement.GetNetworkFee())
+	// the replacement conflicts with the old tx, so at most one of them is ever persisted
+	assert.Equal(t, tx.Conflicts, replacement.GetAttributes()[0].GetAttributeType())
+	assert.True(t, replacement.GetAttributes()[0].(*tx.ConflictsAttribute).Hash.Equals(trx.GetHash()))
+	assert.Equal(t, trx.GetNonce(), replacement.GetNonce())
+	assert.True(t, replacement.GetSender().Equals(trx.GetSender()))
+	replacementHash := "0x" + replacement.GetHash().String()
+
+	// height 101, not in the mempool, rebroadcast
+	clientMock.On("GetBlockCount").Return(blockCount(102)).Once()
+	clientMock.On("GetRawMemPool").Return(rpc.GetRawMemPoolResponse{Result: []string{}})
+	clientMock.On("GetTransactionHeight", hash).Return(unknownTx)
+	results, err := s.Poll()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(results))
+	clientMock.AssertNumberOfCalls(t, "SendRawTransaction", 2)
+
+	// height 102, replaced
+	clientMock.On("GetBlockCount").Return(blockCount(103)).Once()
+	results, err = s.Poll()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(results))
+	entries, err := outbox.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries[0].Versions))
+	assert.Equal(t, 1, entries[0].Bumps)
+	assert.Equal(t, uint32(102), entries[0].SentAt)
+	clientMock.AssertNumberOfCalls(t, "SendRawTransaction", 3)
+
+	// height 103, the replacement is confirmed
+	clientMock.On("GetBlockCount").Return(blockCount(104)).Once()
+	clientMock.On("GetTransactionHeight", replacementHash).Return(rpc.GetTransactionHeightResponse{Result: 103})
+	clientMock.On("GetApplicationLog", replacementHash).Return(rpc.GetApplicationLogResponse{
+		Result: models.RpcApplicationLog{Executions: []models.RpcExecution{{Trigger: "Application", VMState: "HALT"}}},
+	})
+	var notified *SendResult
+	s.OnResult = func(r *SendResult) { notified = r }
+	results, err = s.Poll()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, hash, results[0].Id)
+	assert.Equal(t, replacementHash, results[0].Hash)
+	assert.Equal(t, tracker.Confirmed, results[0].State)
+	assert.Equal(t, "HALT", results[0].VMState)
+	assert.Equal(t, 0, len(results[0].Conflicts))
+	assert.Equal(t, results[0], notified)
+	assert.Equal(t, 0, len(s.Pending()))
+	entries, _ = outbox.Load()
+	assert.Equal(t, 0, len(entries))
+}
+
+func TestSender_Expired(t *testing.T) {
+	pair, _ := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[0].PrivateKey))
+	account, _ := helper.UInt160FromString(keys.KeyCases[0].ScriptHash)
+	clientMock := new(rpc.RpcClientMock)
+	wh, _ := wallet.NewWalletHelperFromPrivateKey(clientMock, pair.PrivateKey)
+	trx := signedTx(t, wh, account)
+
+	clientMock.On("GetBlockCount").Return(blockCount(101)).Once()
+	clientMock.On("SendRawTransaction", mock.Anything).Return(rpc.SendRawTransactionResponse{
+		ErrorResponse: rpc.ErrorResponse{Error: rpc.RpcError{Code: -500, Message: "InsufficientFunds"}},
+	}).Once()
+	s, _ := NewSender(wh, NewFileOutbox(filepath.Join(t.TempDir(), "outbox.json")), helper.Neo3Magic_MainNet)
+	_, err := s.Send(trx)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(s.Pending()))
+
+	clientMock.On("GetBlockCount").Return(blockCount(101)).Once()
+	clientMock.On("SendRawTransaction", mock.Anything).Return(rpc.SendRawTransactionResponse{})
+	_, err = s.Send(trx)
+	assert.Nil(t, err)
+	clientMock.On("GetBlockCount").Return(blockCount(201))
+	clientMock.On("GetRawMemPool").Return(rpc.GetRawMemPoolResponse{Result: []string{}})
+	clientMock.On("GetTransactionHeight", mock.Anything).Return(unknownTx)
+	results, err := s.Poll()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, tracker.Expired, results[0].State)
+}
+
+func TestSender_HighPriority(t *testing.T) {
+	pair, _ := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[0].PrivateKey))
+	contract, _ := sc.CreateMultiSigContract(1, []*crypto.ECPoint{pair.PublicKey})
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetCommittee").Return(rpc.GetCommitteeResponse{Result: []string{keys.KeyCases[0].PublicKey}})
+	name := "committee"
+	w, _ := wallet.NewNEP6Wallet("", &helper.DefaultProtocolSettings, &name, wallet.DefaultScryptParameters)
+	_ = w.Unlock("")
+	_, err := w.CreateAccountWithPrivateKey(pair.PrivateKey)
+	assert.Nil(t, err)
+	_, err = w.CreateAccountWithContract(contract, pair)
+	assert.Nil(t, err)
+	wh := wallet.NewWalletHelperFromWallet(clientMock, w)
+	s, _ := NewSender(wh, NewFileOutbox(filepath.Join(t.TempDir(), "outbox.json")), helper.Neo3Magic_MainNet)
+	s.Policy = FeeBumpPolicy{Enabled: true, HighPriority: true}
+
+	committee, err := s.GetCommitteeAddress()
+	assert.Nil(t, err)
+	assert.True(t, committee.Equals(contract.GetScriptHash()))
+
+	trx := signedTx(t, wh, committee)
+	replacement, err := s.Replace(trx)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(replacement.GetAttributes()))
+	assert.True(t, replacement.GetAttributes()[0].(*tx.ConflictsAttribute).Hash.Equals(trx.GetHash()))
+	assert.Equal(t, tx.HighPriority, replacement.GetAttributes()[1].GetAttributeType())
+	assert.True(t, replacement.GetNetworkFee() > trx.GetNetworkFee())
+	assert.True(t, tx.VerifyMultiSignatureWitness(tx.GetSignData(replacement, helper.Neo3Magic_MainNet), replacement.GetWitnesses()[0]))
+
+	s.Policy.MaxNetworkFee = trx.GetNetworkFee()
+	_, err = s.Replace(trx)
+	assert.NotNil(t, err)
+}
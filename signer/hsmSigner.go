@@ -0,0 +1,58 @@
+package signer
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/keys"
+)
+
+// IDigestSigner signs a sha256 digest with a key it never exports. This is what a PKCS#11 token does with the
+// CKM_ECDSA mechanism, or a cloud KMS with a secp256r1 key. The signature can be r||s or ASN.1 DER.
+type IDigestSigner interface {
+	SignDigest(digest []byte) ([]byte, error)
+}
+
+// HsmSigner signs with a key in a hardware security module. Pkcs11Token is the PKCS#11 backend, see
+// NewPkcs11Signer, any other token or KMS can be used by implementing IDigestSigner.
+type HsmSigner struct {
+	PublicKey *crypto.ECPoint
+	Token     IDigestSigner
+	// Options decide whether s is normalized and whether a non-canonical encoding from the token is rejected
+	Options keys.SignatureOptions
+}
+
+func NewHsmSigner(publicKey *crypto.ECPoint, token IDigestSigner) *HsmSigner {
+	return &HsmSigner{
+		PublicKey: publicKey,
+		Token:     token,
+	}
+}
+
+func (s *HsmSigner) GetPublicKey() *crypto.ECPoint {
+	return s.PublicKey
+}
+
+// Sign hashes the message and lets the token sign the digest, the signature is checked before it is returned
+func (s *HsmSigner) Sign(message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+	signature, err := s.Token.SignDigest(digest[:])
+	if err != nil {
+		return nil, err
+	}
+	signature, err = NormalizeSignature(signature, s.Options)
+	if err != nil {
+		return nil, err
+	}
+	if !keys.VerifySignature(message, signature, s.PublicKey) {
+		return nil, fmt.Errorf("signature from the token does not match the public key %s", s.PublicKey.String())
+	}
+	return signature, nil
+}
+
+// NormalizeSignature converts an ASN.1 DER signature to the 64 bytes r||s used by neo, the options decide whether
+// s is normalized and whether a non-canonical encoding is rejected
+func NormalizeSignature(signature []byte, options keys.SignatureOptions) ([]byte, error) {
+	return keys.NormalizeSignature(signature, crypto.P256, options)
+}
@@ -0,0 +1,61 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/keys"
+)
+
+// tokenStub signs digests like a PKCS#11 token
+type tokenStub struct {
+	pair *keys.KeyPair
+	der  bool
+}
+
+func (t *tokenStub) SignDigest(digest []byte) ([]byte, error) {
+	if t.der {
+		return ecdsa.SignASN1(rand.Reader, t.pair.ToECDsa(), digest)
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, t.pair.ToECDsa(), digest)
+	if err != nil {
+		return nil, err
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signature, nil
+}
+
+func TestHsmSigner_Sign(t *testing.T) {
+	pair, _ := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[0].PrivateKey))
+	other, _ := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[1].PrivateKey))
+	message := []byte("hello")
+
+	for _, der := range []bool{false, true} {
+		s := NewHsmSigner(pair.PublicKey, &tokenStub{pair: pair, der: der})
+		signature, err := s.Sign(message)
+		assert.Nil(t, err)
+		assert.Equal(t, 64, len(signature))
+		assert.True(t, keys.VerifySignature(message, signature, pair.PublicKey))
+	}
+
+	// the token holds another key
+	s := NewHsmSigner(pair.PublicKey, &tokenStub{pair: other})
+	_, err := s.Sign(message)
+	assert.NotNil(t, err)
+}
+
+func TestNormalizeSignature(t *testing.T) {
+	_, err := NormalizeSignature([]byte{0x30, 0x01}, keys.SignatureOptions{})
+	assert.NotNil(t, err)
+	// SEQUENCE { INTEGER 1, INTEGER 2 }
+	signature, err := NormalizeSignature([]byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02}, keys.SignatureOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, byte(1), signature[31])
+	assert.Equal(t, byte(2), signature[63])
+}
@@ -0,0 +1,313 @@
+//go:build cgo
+
+package signer
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"fmt"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+)
+
+// secp256r1 (prime256v1) in CKA_EC_PARAMS, the DER encoded OID 1.2.840.10045.3.1.7
+var p256Params = []byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}
+
+// Pkcs11Config locates a secp256r1 key on a PKCS#11 token, the key is found by KeyLabel, KeyId or both
+type Pkcs11Config struct {
+	Module     string // path of the PKCS#11 library, e.g. /usr/lib/softhsm/libsofthsm2.so
+	TokenLabel string
+	Pin        string // user pin
+	KeyLabel   string
+	KeyId      []byte
+}
+
+// Pkcs11Token signs digests with CKM_ECDSA on a PKCS#11 token, the private key never leaves the token.
+// It implements IDigestSigner and keeps one logged in session, which is used by one signing at a time.
+type Pkcs11Token struct {
+	module    string
+	ctx       *pkcs11.Ctx
+	session   pkcs11.SessionHandle
+	key       pkcs11.ObjectHandle
+	publicKey *crypto.ECPoint
+	lock      sync.Mutex
+}
+
+// OpenPkcs11Token loads the module, logs in to the token and finds the key, call Close to release them
+func OpenPkcs11Token(config Pkcs11Config) (*Pkcs11Token, error) {
+	if config.KeyLabel == "" && len(config.KeyId) == 0 {
+		return nil, fmt.Errorf("key label or key id is required")
+	}
+	t, err := openPkcs11Session(config)
+	if err != nil {
+		return nil, err
+	}
+	template := keyTemplate(pkcs11.CKO_PRIVATE_KEY, config.KeyLabel, config.KeyId)
+	t.key, err = t.findObject(template)
+	if err == nil {
+		t.publicKey, err = t.readPublicKey(keyTemplate(pkcs11.CKO_PUBLIC_KEY, config.KeyLabel, config.KeyId))
+	}
+	if err != nil {
+		t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// GeneratePkcs11Key generates a secp256r1 key pair on the token which can sign but never be extracted,
+// and opens it
+func GeneratePkcs11Key(config Pkcs11Config) (*Pkcs11Token, error) {
+	if config.KeyLabel == "" && len(config.KeyId) == 0 {
+		return nil, fmt.Errorf("key label or key id is required")
+	}
+	t, err := openPkcs11Session(config)
+	if err != nil {
+		return nil, err
+	}
+	public := append(keyTemplate(pkcs11.CKO_PUBLIC_KEY, config.KeyLabel, config.KeyId),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, p256Params),
+	)
+	private := append(keyTemplate(pkcs11.CKO_PRIVATE_KEY, config.KeyLabel, config.KeyId),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+	)
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)}
+	publicHandle, privateHandle, err := t.ctx.GenerateKeyPair(t.session, mechanism, public, private)
+	if err == nil {
+		t.key = privateHandle
+		t.publicKey, err = t.readPublicKeyOf(publicHandle)
+	}
+	if err != nil {
+		t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+func openPkcs11Session(config Pkcs11Config) (*Pkcs11Token, error) {
+	ctx, err := acquirePkcs11Module(config.Module)
+	if err != nil {
+		return nil, err
+	}
+	t := &Pkcs11Token{module: config.Module, ctx: ctx}
+	slot, err := findSlot(ctx, config.TokenLabel)
+	if err == nil {
+		t.session, err = ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	}
+	if err != nil {
+		releasePkcs11Module(config.Module)
+		return nil, err
+	}
+	err = ctx.Login(t.session, pkcs11.CKU_USER, config.Pin)
+	if err != nil && !isPkcs11Error(err, pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// pkcs11Module is a loaded PKCS#11 library, C_Initialize and C_Finalize apply to the whole library
+// in the process, so it is shared by all tokens opened with the same path
+type pkcs11Module struct {
+	ctx  *pkcs11.Ctx
+	refs int
+	// false when the library was initialized by someone else, then it is not finalized here
+	initialized bool
+}
+
+var pkcs11Modules = struct {
+	sync.Mutex
+	m map[string]*pkcs11Module
+}{m: map[string]*pkcs11Module{}}
+
+// acquirePkcs11Module loads and initializes the library on first use, every call is paired with
+// releasePkcs11Module
+func acquirePkcs11Module(path string) (*pkcs11.Ctx, error) {
+	pkcs11Modules.Lock()
+	defer pkcs11Modules.Unlock()
+	if m, ok := pkcs11Modules.m[path]; ok {
+		m.refs++
+		return m.ctx, nil
+	}
+	ctx := pkcs11.New(path)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 module %s", path)
+	}
+	m := &pkcs11Module{ctx: ctx, refs: 1, initialized: true}
+	if err := ctx.Initialize(); err != nil {
+		if !isPkcs11Error(err, pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+			ctx.Destroy()
+			return nil, err
+		}
+		m.initialized = false
+	}
+	pkcs11Modules.m[path] = m
+	return ctx, nil
+}
+
+// releasePkcs11Module finalizes and unloads the library when the last token using it is closed
+func releasePkcs11Module(path string) {
+	pkcs11Modules.Lock()
+	defer pkcs11Modules.Unlock()
+	m, ok := pkcs11Modules.m[path]
+	if !ok {
+		return
+	}
+	m.refs--
+	if m.refs > 0 {
+		return
+	}
+	delete(pkcs11Modules.m, path)
+	if m.initialized {
+		_ = m.ctx.Finalize()
+	}
+	m.ctx.Destroy()
+}
+
+func findSlot(ctx *pkcs11.Ctx, label string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, err
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, err
+		}
+		if info.Label == label {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("token %s not found", label)
+}
+
+func keyTemplate(class uint, label string, id []byte) []*pkcs11.Attribute {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+	}
+	if label != "" {
+		template = append(template, pkcs11.NewAttribute(pkcs11.CKA_LABEL, label))
+	}
+	if len(id) != 0 {
+		template = append(template, pkcs11.NewAttribute(pkcs11.CKA_ID, id))
+	}
+	return template
+}
+
+// findObject returns the only object matching the template
+func (t *Pkcs11Token) findObject(template []*pkcs11.Attribute) (pkcs11.ObjectHandle, error) {
+	err := t.ctx.FindObjectsInit(t.session, template)
+	if err != nil {
+		return 0, err
+	}
+	objects, _, err := t.ctx.FindObjects(t.session, 2)
+	if err2 := t.ctx.FindObjectsFinal(t.session); err == nil {
+		err = err2
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(objects) != 1 {
+		return 0, fmt.Errorf("%d keys found on the token, expected exactly one", len(objects))
+	}
+	return objects[0], nil
+}
+
+func (t *Pkcs11Token) readPublicKey(template []*pkcs11.Attribute) (*crypto.ECPoint, error) {
+	handle, err := t.findObject(template)
+	if err != nil {
+		return nil, err
+	}
+	return t.readPublicKeyOf(handle)
+}
+
+func (t *Pkcs11Token) readPublicKeyOf(handle pkcs11.ObjectHandle) (*crypto.ECPoint, error) {
+	attributes, err := t.ctx.GetAttributeValue(t.session, handle, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var params, point []byte
+	for _, a := range attributes {
+		switch a.Type {
+		case pkcs11.CKA_EC_PARAMS:
+			params = a.Value
+		case pkcs11.CKA_EC_POINT:
+			point = a.Value
+		}
+	}
+	if !bytes.Equal(params, p256Params) {
+		return nil, fmt.Errorf("key on the token is not a secp256r1 key")
+	}
+	return DecodeEcPoint(point)
+}
+
+// DecodeEcPoint decodes CKA_EC_POINT, which is an uncompressed point wrapped in a DER OCTET STRING,
+// some tokens return the raw point
+func DecodeEcPoint(value []byte) (*crypto.ECPoint, error) {
+	var raw []byte
+	rest, err := asn1.Unmarshal(value, &raw)
+	if err != nil || len(rest) != 0 {
+		raw = value
+	}
+	return crypto.NewECPointFromBytes(raw)
+}
+
+// PublicKey returns the public key read from the token
+func (t *Pkcs11Token) PublicKey() *crypto.ECPoint {
+	return t.publicKey
+}
+
+// SignDigest signs a sha256 digest with CKM_ECDSA, the token returns r||s
+func (t *Pkcs11Token) SignDigest(digest []byte) ([]byte, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.ctx == nil {
+		return nil, fmt.Errorf("token is closed")
+	}
+	err := t.ctx.SignInit(t.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, t.key)
+	if err != nil {
+		return nil, err
+	}
+	return t.ctx.Sign(t.session, digest)
+}
+
+// Close closes the session and unloads the module when no other token uses it. The login state is
+// shared by all sessions on a token, so there is no C_Logout here, the token logs out by itself
+// when its last session is closed.
+func (t *Pkcs11Token) Close() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.ctx == nil {
+		return
+	}
+	_ = t.ctx.CloseSession(t.session)
+	releasePkcs11Module(t.module)
+	t.ctx = nil
+}
+
+// NewPkcs11Signer opens the key on the token and returns an HsmSigner using it,
+// close the token when the signer is no longer used
+func NewPkcs11Signer(config Pkcs11Config) (*HsmSigner, *Pkcs11Token, error) {
+	token, err := OpenPkcs11Token(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewHsmSigner(token.PublicKey(), token), token, nil
+}
+
+func isPkcs11Error(err error, code uint) bool {
+	e, ok := err.(pkcs11.Error)
+	return ok && uint(e) == code
+}
@@ -0,0 +1,81 @@
+//go:build cgo
+
+package signer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/keys"
+)
+
+func TestDecodeEcPoint(t *testing.T) {
+	pair, _ := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[0].PrivateKey))
+	raw := pair.PublicKey.EncodePoint(false)
+	// OCTET STRING of the uncompressed point
+	wrapped := append([]byte{0x04, byte(len(raw))}, raw...)
+	for _, value := range [][]byte{wrapped, raw} {
+		p, err := DecodeEcPoint(value)
+		assert.Nil(t, err)
+		assert.True(t, p.Equals(pair.PublicKey))
+	}
+	_, err := DecodeEcPoint([]byte{0x04, 0x01, 0x02})
+	assert.NotNil(t, err)
+}
+
+func TestOpenPkcs11Token(t *testing.T) {
+	_, err := OpenPkcs11Token(Pkcs11Config{Module: "/nonexistent/libpkcs11.so"})
+	assert.NotNil(t, err) // no key label or id
+	_, err = OpenPkcs11Token(Pkcs11Config{Module: "/nonexistent/libpkcs11.so", KeyLabel: "neo3"})
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(pkcs11Modules.m)) // nothing is kept for a module that failed to load
+}
+
+// TestPkcs11Signer runs against SoftHSM, e.g.
+//
+//	softhsm2-util --init-token --free --label neo3 --pin 1234 --so-pin 5678
+//	NEO3_PKCS11_MODULE=/usr/lib/softhsm/libsofthsm2.so NEO3_PKCS11_TOKEN=neo3 NEO3_PKCS11_PIN=1234 go test ./signer
+func TestPkcs11Signer(t *testing.T) {
+	module := os.Getenv("NEO3_PKCS11_MODULE")
+	if module == "" {
+		t.Skip("NEO3_PKCS11_MODULE is not set")
+	}
+	config := Pkcs11Config{
+		Module:     module,
+		TokenLabel: os.Getenv("NEO3_PKCS11_TOKEN"),
+		Pin:        os.Getenv("NEO3_PKCS11_PIN"),
+		KeyLabel:   "neo3-gogogo-test",
+	}
+	// a new id every run, so that the key is unique on the token
+	config.KeyId, _ = helper.GenerateRandomBytes(8)
+	generated, err := GeneratePkcs11Key(config)
+	assert.Nil(t, err)
+	publicKey := generated.PublicKey()
+	generated.Close()
+
+	// the module is finalized with the last token, so this is a fresh login
+	wrong := config
+	wrong.Pin = "wrong"
+	_, _, err = NewPkcs11Signer(wrong)
+	assert.NotNil(t, err)
+
+	s, token, err := NewPkcs11Signer(config)
+	assert.Nil(t, err)
+	defer token.Close()
+	assert.True(t, s.GetPublicKey().Equals(publicKey))
+	message := []byte("hello")
+	signature, err := s.Sign(message)
+	assert.Nil(t, err)
+	assert.True(t, keys.VerifySignature(message, signature, publicKey))
+
+	// closing another token of the same module leaves this one usable
+	other, err := OpenPkcs11Token(config)
+	assert.Nil(t, err)
+	other.Close()
+	signature, err = s.Sign(message)
+	assert.Nil(t, err)
+	assert.True(t, keys.VerifySignature(message, signature, publicKey))
+}
@@ -0,0 +1,147 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/keys"
+)
+
+const DefaultRemoteTimeout = 10 * time.Second
+
+// SignRequest is posted to a remote signer, Data is the message to sign in base64, i.e. the sign data of
+// a verifiable which starts with the network magic
+type SignRequest struct {
+	PublicKey string `json:"publickey"` // compressed public key in hex
+	Data      string `json:"data"`
+}
+
+// SignResponse is the answer of a remote signer, Signature is the 64 bytes r||s in base64
+type SignResponse struct {
+	Signature string `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// RemoteSigner asks a signing service over HTTP, the service holds the key and may apply its own policies.
+// Header is sent with every request, e.g. an Authorization header.
+type RemoteSigner struct {
+	Endpoint  string
+	PublicKey *crypto.ECPoint
+	Header    http.Header
+	Client    *http.Client
+	// Options decide whether s is normalized and whether a non-canonical encoding from the signer is rejected
+	Options keys.SignatureOptions
+}
+
+func NewRemoteSigner(endpoint string, publicKey *crypto.ECPoint) *RemoteSigner {
+	return &RemoteSigner{
+		Endpoint:  endpoint,
+		PublicKey: publicKey,
+		Header:    http.Header{},
+		Client:    &http.Client{Timeout: DefaultRemoteTimeout},
+	}
+}
+
+func (s *RemoteSigner) GetPublicKey() *crypto.ECPoint {
+	return s.PublicKey
+}
+
+// Sign posts the message to the service, the signature is checked before it is returned
+func (s *RemoteSigner) Sign(message []byte) ([]byte, error) {
+	body, err := json.Marshal(SignRequest{
+		PublicKey: s.PublicKey.String(),
+		Data:      crypto.Base64Encode(message),
+	})
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest(http.MethodPost, s.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range s.Header {
+		request.Header[k] = v
+	}
+	request.Header.Set("Content-Type", "application/json")
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	response := SignResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("remote signer returned %s: %v", resp.Status, err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("remote signer: %s", response.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote signer returned %s", resp.Status)
+	}
+	signature, err := crypto.Base64Decode(response.Signature)
+	if err != nil {
+		return nil, err
+	}
+	signature, err = NormalizeSignature(signature, s.Options)
+	if err != nil {
+		return nil, err
+	}
+	if !keys.VerifySignature(message, signature, s.PublicKey) {
+		return nil, fmt.Errorf("signature from the remote signer does not match the public key %s", s.PublicKey.String())
+	}
+	return signature, nil
+}
+
+// Handler serves the remote signer protocol with the signers it holds, it can be used to build a signing service
+type Handler struct {
+	signers map[string]keys.ISigner
+}
+
+func NewHandler(signers ...keys.ISigner) *Handler {
+	h := &Handler{signers: map[string]keys.ISigner{}}
+	for _, s := range signers {
+		h.signers[s.GetPublicKey().String()] = s
+	}
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	reply := func(code int, response SignResponse) {
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(response)
+	}
+	if r.Method != http.MethodPost {
+		reply(http.StatusMethodNotAllowed, SignResponse{Error: "method not allowed"})
+		return
+	}
+	request := SignRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		reply(http.StatusBadRequest, SignResponse{Error: err.Error()})
+		return
+	}
+	s, ok := h.signers[request.PublicKey]
+	if !ok {
+		reply(http.StatusNotFound, SignResponse{Error: "unknown public key " + request.PublicKey})
+		return
+	}
+	data, err := crypto.Base64Decode(request.Data)
+	if err != nil {
+		reply(http.StatusBadRequest, SignResponse{Error: err.Error()})
+		return
+	}
+	signature, err := s.Sign(data)
+	if err != nil {
+		reply(http.StatusInternalServerError, SignResponse{Error: err.Error()})
+		return
+	}
+	reply(http.StatusOK, SignResponse{Signature: crypto.Base64Encode(signature)})
+}
@@ -0,0 +1,53 @@
+package signer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/keys"
+)
+
+// wrongSigner signs with another key than the one it claims
+type wrongSigner struct {
+	publicKey *crypto.ECPoint
+	pair      *keys.KeyPair
+}
+
+func (s *wrongSigner) GetPublicKey() *crypto.ECPoint { return s.publicKey }
+
+func (s *wrongSigner) Sign(message []byte) ([]byte, error) { return s.pair.Sign(message) }
+
+func TestRemoteSigner_Sign(t *testing.T) {
+	pair, _ := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[0].PrivateKey))
+	other, _ := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[1].PrivateKey))
+	third, _ := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[2].PrivateKey))
+	handler := NewHandler(pair, &wrongSigner{publicKey: other.PublicKey, pair: third})
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	message := []byte("hello")
+
+	s := NewRemoteSigner(server.URL, pair.PublicKey)
+	s.Header.Set("Authorization", "Bearer token")
+	signature, err := s.Sign(message)
+	assert.Nil(t, err)
+	assert.True(t, keys.VerifySignature(message, signature, pair.PublicKey))
+	assert.Equal(t, "Bearer token", auth)
+
+	// the service returns a signature of another key
+	_, err = NewRemoteSigner(server.URL, other.PublicKey).Sign(message)
+	assert.NotNil(t, err)
+
+	// the service does not hold the key
+	_, err = NewRemoteSigner(server.URL, third.PublicKey).Sign(message)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown public key")
+}
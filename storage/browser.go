@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/mpt"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+)
+
+// Entry is a key-value pair in the storage of a contract, the key doesn't have the id of the contract
+type Entry struct {
+	Key   []byte
+	Value []byte
+	// Verified is true if the entry is verified with the proof of the state root
+	Verified bool
+}
+
+// Browser reads the storage of a contract with findstorage, page by page. The entries are verified with the proofs
+// of the StateService plugin if RootHash is set. findstorage reads the latest state of the node, so the root should
+// be the local state root of the current height, see UseLatestStateRoot.
+type Browser struct {
+	Client   rpc.IRpcClient
+	Contract *helper.UInt160
+	RootHash *helper.UInt256 // optional
+	decoders []*Decoder
+	id       *int // id of the contract, for the keys in the trie
+}
+
+func NewBrowser(client rpc.IRpcClient, contract *helper.UInt160) *Browser {
+	if client == nil || contract == nil {
+		return nil
+	}
+	return &Browser{Client: client, Contract: contract}
+}
+
+// Find returns the entries with the prefix from the index start, and the start of the next page if the result is
+// truncated by the node
+func (b *Browser) Find(prefix []byte, start int) (entries []*Entry, next int, truncated bool, err error) {
+	response := b.Client.FindStorage("0x"+b.Contract.String(), crypto.Base64Encode(prefix), start)
+	if response.HasError() {
+		return nil, 0, false, fmt.Errorf(response.GetErrorInfo())
+	}
+	entries = make([]*Entry, 0, len(response.Result.Results))
+	for _, r := range response.Result.Results {
+		key, err := crypto.Base64Decode(r.Key)
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("invalid key %s: %v", r.Key, err)
+		}
+		value, err := crypto.Base64Decode(r.Value)
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("invalid value of key %s: %v", r.Key, err)
+		}
+		if !bytes.HasPrefix(key, prefix) {
+			return nil, 0, false, fmt.Errorf("key %s doesn't have the prefix", r.Key)
+		}
+		entry := &Entry{Key: key, Value: value}
+		if b.RootHash != nil {
+			if err = b.Verify(entry); err != nil {
+				return nil, 0, false, err
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, response.Result.Next, response.Result.Truncated, nil
+}
+
+// ForEach calls fn with every entry with the prefix until fn returns false
+func (b *Browser) ForEach(prefix []byte, fn func(entry *Entry) bool) error {
+	start := 0
+	for {
+		entries, next, truncated, err := b.Find(prefix, start)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if !fn(entry) {
+				return nil
+			}
+		}
+		if !truncated {
+			return nil
+		}
+		if next <= start {
+			return fmt.Errorf("invalid next index %d after %d", next, start)
+		}
+		start = next
+	}
+}
+
+// FindAll returns all the entries with the prefix
+func (b *Browser) FindAll(prefix []byte) ([]*Entry, error) {
+	entries := []*Entry{}
+	err := b.ForEach(prefix, func(entry *Entry) bool {
+		entries = append(entries, entry)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// UseLatestStateRoot sets RootHash to the local state root of the node
+func (b *Browser) UseLatestStateRoot() error {
+	height := b.Client.GetStateHeight()
+	if height.HasError() {
+		return fmt.Errorf(height.GetErrorInfo())
+	}
+	root := b.Client.GetStateRoot(height.Result.LocalRootIndex)
+	if root.HasError() {
+		return fmt.Errorf(root.GetErrorInfo())
+	}
+	hash, err := helper.UInt256FromString(root.Result.RootHash)
+	if err != nil {
+		return fmt.Errorf("invalid root hash %s: %v", root.Result.RootHash, err)
+	}
+	b.RootHash = hash
+	return nil
+}
+
+// Verify gets the proof of the key from the node and checks the value against RootHash
+func (b *Browser) Verify(entry *Entry) error {
+	if b.RootHash == nil {
+		return fmt.Errorf("root hash is not set")
+	}
+	id, err := b.contractId()
+	if err != nil {
+		return err
+	}
+	response := b.Client.GetProof("0x"+b.RootHash.String(), "0x"+b.Contract.String(), crypto.Base64Encode(entry.Key))
+	if response.HasError() {
+		return fmt.Errorf(response.GetErrorInfo())
+	}
+	proofBytes, err := crypto.Base64Decode(response.Result)
+	if err != nil {
+		return fmt.Errorf("invalid proof of key %s: %v", helper.BytesToHex(entry.Key), err)
+	}
+	_, _, proof, err := mpt.ResolveProof(proofBytes)
+	if err != nil {
+		return fmt.Errorf("invalid proof of key %s: %v", helper.BytesToHex(entry.Key), err)
+	}
+	// the key of the trie is built from the contract and the entry, not taken from the proof
+	value, err := mpt.VerifyProof(b.RootHash, id, entry.Key, proof)
+	if err != nil {
+		return fmt.Errorf("failed to verify key %s: %v", helper.BytesToHex(entry.Key), err)
+	}
+	if !bytes.Equal(value, entry.Value) {
+		return fmt.Errorf("value of key %s doesn't match the proof", helper.BytesToHex(entry.Key))
+	}
+	entry.Verified = true
+	return nil
+}
+
+func (b *Browser) contractId() (int, error) {
+	if b.id != nil {
+		return *b.id, nil
+	}
+	response := b.Client.GetContractState("0x" + b.Contract.String())
+	if response.HasError() {
+		return 0, fmt.Errorf(response.GetErrorInfo())
+	}
+	id := response.Result.Id
+	b.id = &id
+	return id, nil
+}
+
+// dump is the json written by Dump
+type dump struct {
+	Contract string          `json:"contract"`
+	RootHash string          `json:"roothash,omitempty"`
+	Entries  []*DecodedEntry `json:"entries"`
+}
+
+// Dump writes the entries with the prefix as indented json, the keys and values are in hex and decoded by the
+// registered decoders
+func (b *Browser) Dump(w io.Writer, prefix []byte) error {
+	d := dump{Contract: "0x" + b.Contract.String(), Entries: []*DecodedEntry{}}
+	if b.RootHash != nil {
+		d.RootHash = "0x" + b.RootHash.String()
+	}
+	err := b.ForEach(prefix, func(entry *Entry) bool {
+		d.Entries = append(d.Entries, b.Decode(entry))
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
+
+var contract, _ = helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+
+func page(next int, truncated bool, entries ...[]byte) rpc.FindStorageResponse {
+	results := []models.RpcStorageEntry{}
+	for i := 0; i < len(entries); i += 2 {
+		results = append(results, models.RpcStorageEntry{
+			Key:   crypto.Base64Encode(entries[i]),
+			Value: crypto.Base64Encode(entries[i+1]),
+		})
+	}
+	return rpc.FindStorageResponse{Result: models.RpcFoundStorage{Truncated: truncated, Next: next, Results: results}}
+}
+
+func TestBrowser_FindAll(t *testing.T) {
+	client := new(rpc.RpcClientMock)
+	client.On("FindStorage", "0x"+contract.String(), "AQ==", 0).Return(page(2, true,
+		[]byte{1, 1}, []byte{10}, []byte{1, 2}, []byte{20}))
+	client.On("FindStorage", "0x"+contract.String(), "AQ==", 2).Return(page(3, false,
+		[]byte{1, 3}, []byte{30}))
+	b := NewBrowser(client, contract)
+
+	entries, err := b.FindAll([]byte{1})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(entries))
+	assert.Equal(t, []byte{1, 3}, entries[2].Key)
+	assert.Equal(t, []byte{30}, entries[2].Value)
+	assert.False(t, entries[2].Verified)
+
+	// stops when fn returns false
+	count := 0
+	err = b.ForEach([]byte{1}, func(entry *Entry) bool {
+		count++
+		return false
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestBrowser_Find_Error(t *testing.T) {
+	client := new(rpc.RpcClientMock)
+	client.On("FindStorage", mock.Anything, "AQ==", 0).Return(page(1, false, []byte{2}, []byte{1}))
+	client.On("FindStorage", mock.Anything, "Ag==", 0).Return(page(0, true))
+	failed := rpc.FindStorageResponse{}
+	failed.Error = rpc.RpcError{Code: -100, Message: "Unknown contract"}
+	client.On("FindStorage", mock.Anything, "Aw==", 0).Return(failed)
+	b := NewBrowser(client, contract)
+
+	_, _, _, err := b.Find([]byte{1}, 0)
+	assert.EqualError(t, err, "key Ag== doesn't have the prefix")
+	_, err = b.FindAll([]byte{2})
+	assert.EqualError(t, err, "invalid next index 0 after 0")
+	_, err = b.FindAll([]byte{3})
+	assert.EqualError(t, err, "Unknown contract")
+}
+
+// the proof is from mpt.TestVerifyProof, of the key 01020500 in the contract 12
+const proofHex = "080c0000000102050008720003a69d0798767e0166c14d03e31fc81e147b91287560e7fa19d1561f29759b957703a9495885fc335dd5daa222e49ce0d542c8d6b1094ca51e30b5b2ea17d9b6facc0404040404040404040404040403251d0de36de3bfd935fee7c7c3bee40f68777b87e08f4b3c9eb6164fac558e3a04fd120100040403de0449d75800e662cc5c07b804e72cb2aa7e6bb3c3c8812fa178587b9586089b040404040403ebd5e01d9738d1158597b04e452deb599305fef2f92c1f8e1a112cfb7f94d8830403a065ed1a35318147644d985278da6c38a6c0cfaac1856b388e4f8cb492afd91103de0449d75800e662cc5c07b804e72cb2aa7e6bb3c3c8812fa178587b9586089b03652b8a83ead517596149de57733acfb988d709f46a78fd2e1d7cdca7798e2d16034ee881ac96d9a53b8fc20f18cf4fe7e2ee998276272d385d2ca3db57776022ce03aa2dc96edb7ef06ecf9674559046b8dbd03e1b3c7a1f3a62eaefd9ea13b64b9603d90b274d165198c2045263551c524a390b70ccc13ca527a076db7b5cf714de7e042a01070000000000000003791d0651089230b366bc3bb286aaf6008e7298803ebdaf6a92e92204879717ed92000403a7de2e8df729aa720e5bda2dd4903a092aeb6eb147300a31f7b54b016544e31d03d340ff18882c9b4f6b5dee49b6be575938019edbad4404633e8f27f65d41c2a803f1f3e3558f3a751b5bb2511493e19f382af6b80d38ae306881e3db4bb1d0e4a604037fdf37ec7a2b533746412242f763d665c20593baaabb2f6a528a51fa4d11172b04040404040404040404042401010003538699ec1fb90dfc037ced3617f4d0c8d941a72c5a24e868a5ad96d8d29eb6b352000403904fb8e816446e1b3d1501783006a2f01fb13166117497ed714cc74a385221ad03a2497231297a2a46abf007954393ae6b3e7d9c1f09c3f869aefcaeb8425eb74504040404040404040404040404042701040005000003497d33633b08061b6cc8882bc5a797cb048d1ff916fd984f4bd09671f64fb88dc902c700201ca7d4dcecd3705602d6e285deafa2c8240f2883045d237822ae33d50451f59f208d735f64159b5f9c7a5670dbab887dbb895c012f39003e5fbc55b6fbff9df44414cc9f88a9e96be8e91131b06ea674fbba51c7c99e0500000000000000144f5f702b3f459f222d371052940bb9ce2d86d2ed06756e6c6f636b4a14e14fdd69cf7bf6afb9265ac806e09fea438df7b81425820465d41a57dca24529e88387ac2d787227780f42400000000000000000000000000000000000000000000000000000000000"
+
+const valueHex = "00201ca7d4dcecd3705602d6e285deafa2c8240f2883045d237822ae33d50451f59f208d735f64159b5f9c7a5670dbab887dbb895c012f39003e5fbc55b6fbff9df44414cc9f88a9e96be8e91131b06ea674fbba51c7c99e0500000000000000144f5f702b3f459f222d371052940bb9ce2d86d2ed06756e6c6f636b4a14e14fdd69cf7bf6afb9265ac806e09fea438df7b81425820465d41a57dca24529e88387ac2d787227780f42400000000000000000000000000000000000000000000000000000000000"
+
+func TestBrowser_Verify(t *testing.T) {
+	root, _ := helper.UInt256FromString("0x721697bf93a8f96ed125ba481585b2f7e604e962262062df92ce7c7448101bf1")
+	key, value := helper.HexToBytes("01020500"), helper.HexToBytes(valueHex)
+	client := new(rpc.RpcClientMock)
+	client.On("GetStateHeight").Return(rpc.GetStateHeightResponse{Result: models.RpcStateHeight{LocalRootIndex: 100}})
+	stateRoot := rpc.GetStateRootResponse{}
+	stateRoot.Result.RootHash = "0x" + root.String()
+	client.On("GetStateRoot", uint32(100)).Return(stateRoot)
+	client.On("GetContractState", "0x"+contract.String()).Return(rpc.GetContractStateResponse{
+		Result: models.RpcContractState{Id: 12},
+	})
+	client.On("GetProof", "0x"+root.String(), "0x"+contract.String(), crypto.Base64Encode(key)).Return(
+		rpc.GetProofResponse{Result: crypto.Base64Encode(helper.HexToBytes(proofHex))})
+	client.On("FindStorage", mock.Anything, "AQI=", 0).Return(page(1, false, key, value))
+	b := NewBrowser(client, contract)
+	assert.Nil(t, b.UseLatestStateRoot())
+	assert.Equal(t, root, b.RootHash)
+
+	entries, err := b.FindAll([]byte{1, 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.True(t, entries[0].Verified)
+	client.AssertNumberOfCalls(t, "GetContractState", 1)
+
+	// the value doesn't match
+	err = b.Verify(&Entry{Key: key, Value: []byte{1}})
+	assert.EqualError(t, err, "value of key 01020500 doesn't match the proof")
+
+	// the proof is for another contract
+	b = NewBrowser(client, contract)
+	b.RootHash = root
+	b.id = new(int)
+	err = b.Verify(&Entry{Key: key, Value: value})
+	assert.NotNil(t, err)
+}
+
+func TestBrowser_Dump(t *testing.T) {
+	account := helper.HexToBytes("d8ae73e06552e270340b63a8bcabf9277a1aac99")
+	client := new(rpc.RpcClientMock)
+	client.On("FindStorage", mock.Anything, "", 0).Return(page(3, false,
+		[]byte{0}, []byte{0x00, 0xe1, 0xf5, 0x05},
+		append([]byte{1}, account...), []byte{0x64},
+		[]byte{1, 2}, []byte{1}))
+	b := NewBrowser(client, contract)
+	b.RegisterDecoder(&Decoder{Name: "totalSupply", Prefix: []byte{0}, Value: DecodeInteger})
+	b.RegisterDecoder(&Decoder{Name: "balance", Prefix: []byte{1}, Key: DecodeUInt160, Value: DecodeInteger})
+
+	buff := new(bytes.Buffer)
+	assert.Nil(t, b.Dump(buff, nil))
+	var d struct {
+		Contract string
+		Entries  []map[string]interface{}
+	}
+	assert.Nil(t, json.Unmarshal(buff.Bytes(), &d))
+	assert.Equal(t, "0x"+contract.String(), d.Contract)
+	assert.Equal(t, 3, len(d.Entries))
+	assert.Equal(t, map[string]interface{}{
+		"key": "00", "value": "00e1f505", "name": "totalSupply", "decodedvalue": "100000000",
+	}, d.Entries[0])
+	assert.Equal(t, map[string]interface{}{
+		"key": "01" + helper.BytesToHex(account), "value": "64", "name": "balance",
+		"decodedkey": "0x99ac1a7a27f9abbca8630b3470e25265e073aed8", "decodedvalue": "100",
+	}, d.Entries[1])
+	assert.Equal(t, "key: expected 20 bytes, got 1", d.Entries[2]["error"])
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/vm"
+)
+
+// DecodeFunc decodes a key or a value to a value which can be marshaled to json
+type DecodeFunc func(b []byte) (interface{}, error)
+
+// Decoder decodes the entries with the prefix, Key gets the key without the prefix. Key or Value can be nil to keep
+// the bytes.
+type Decoder struct {
+	Name   string
+	Prefix []byte
+	Key    DecodeFunc
+	Value  DecodeFunc
+}
+
+// DecodedEntry is an entry with the name of its decoder and the decoded key and value
+type DecodedEntry struct {
+	Key          string      `json:"key"`   // hex
+	Value        string      `json:"value"` // hex
+	Verified     bool        `json:"verified,omitempty"`
+	Name         string      `json:"name,omitempty"`
+	DecodedKey   interface{} `json:"decodedkey,omitempty"`
+	DecodedValue interface{} `json:"decodedvalue,omitempty"`
+	// Error is the error of the decoder, the entry is kept in hex
+	Error string `json:"error,omitempty"`
+}
+
+// RegisterDecoder adds a decoder, an entry is decoded by the decoder with the longest matching prefix
+func (b *Browser) RegisterDecoder(d *Decoder) {
+	b.decoders = append(b.decoders, d)
+}
+
+func (b *Browser) decoderOf(key []byte) *Decoder {
+	var found *Decoder
+	for _, d := range b.decoders {
+		if bytes.HasPrefix(key, d.Prefix) && (found == nil || len(d.Prefix) > len(found.Prefix)) {
+			found = d
+		}
+	}
+	return found
+}
+
+// Decode decodes the entry with the registered decoders
+func (b *Browser) Decode(entry *Entry) *DecodedEntry {
+	result := &DecodedEntry{
+		Key:      helper.BytesToHex(entry.Key),
+		Value:    helper.BytesToHex(entry.Value),
+		Verified: entry.Verified,
+	}
+	d := b.decoderOf(entry.Key)
+	if d == nil {
+		return result
+	}
+	result.Name = d.Name
+	var err error
+	if d.Key != nil {
+		if result.DecodedKey, err = d.Key(entry.Key[len(d.Prefix):]); err != nil {
+			result.Error = fmt.Sprintf("key: %v", err)
+			return result
+		}
+	}
+	if d.Value != nil {
+		if result.DecodedValue, err = d.Value(entry.Value); err != nil {
+			result.Error = fmt.Sprintf("value: %v", err)
+		}
+	}
+	return result
+}
+
+// DecodeString decodes UTF-8 bytes
+func DecodeString(b []byte) (interface{}, error) {
+	if !utf8.Valid(b) {
+		return nil, fmt.Errorf("invalid UTF-8 string")
+	}
+	return string(b), nil
+}
+
+// DecodeInteger decodes an integer of NeoVM, little-endian in two's complement, it is a decimal string in json
+func DecodeInteger(b []byte) (interface{}, error) {
+	if len(b) > vm.MaxIntegerSize {
+		return nil, fmt.Errorf("integer of %d bytes is too large", len(b))
+	}
+	return helper.BigIntFromNeoBytes(b).String(), nil
+}
+
+// DecodeUInt32BigEndian decodes a big-endian uint32, it is used for the keys ordered by number, e.g. block indexes
+func DecodeUInt32BigEndian(b []byte) (interface{}, error) {
+	if len(b) != 4 {
+		return nil, fmt.Errorf("expected 4 bytes, got %d", len(b))
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+// DecodeUInt160 decodes a script hash, it is 0x hex in json
+func DecodeUInt160(b []byte) (interface{}, error) {
+	if len(b) != 20 {
+		return nil, fmt.Errorf("expected 20 bytes, got %d", len(b))
+	}
+	return helper.UInt160FromBytes(b), nil
+}
+
+// DecodeAddress returns a DecodeFunc which decodes a script hash to an address
+func DecodeAddress(version byte) DecodeFunc {
+	return func(b []byte) (interface{}, error) {
+		if len(b) != 20 {
+			return nil, fmt.Errorf("expected 20 bytes, got %d", len(b))
+		}
+		return crypto.ScriptHashToAddress(helper.UInt160FromBytes(b), version), nil
+	}
+}
+
+// DecodeStackItem decodes a value serialized by StdLib.serialize, it is in the format of the stack of invokefunction
+// in json
+func DecodeStackItem(b []byte) (interface{}, error) {
+	item, err := vm.DeserializeStackItem(b)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewInvokeStackFromStackItem(item)
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
+
+func TestBrowser_Decode(t *testing.T) {
+	b := &Browser{}
+	b.RegisterDecoder(&Decoder{Name: "token", Prefix: []byte{1}, Key: DecodeString})
+	b.RegisterDecoder(&Decoder{Name: "index", Prefix: []byte{1, 2}, Key: DecodeUInt32BigEndian})
+
+	// the longest prefix
+	d := b.Decode(&Entry{Key: []byte{1, 2, 0, 0, 1, 0}, Value: []byte{5}})
+	assert.Equal(t, "index", d.Name)
+	assert.Equal(t, uint32(256), d.DecodedKey)
+	assert.Nil(t, d.DecodedValue)
+
+	d = b.Decode(&Entry{Key: []byte{1, 'a'}, Value: []byte{5}})
+	assert.Equal(t, "token", d.Name)
+	assert.Equal(t, "a", d.DecodedKey)
+
+	d = b.Decode(&Entry{Key: []byte{3}, Value: []byte{5}})
+	assert.Equal(t, &DecodedEntry{Key: "03", Value: "05"}, d)
+}
+
+func TestDecodeInteger(t *testing.T) {
+	v, err := DecodeInteger([]byte{0xff})
+	assert.Nil(t, err)
+	assert.Equal(t, "-1", v)
+	v, err = DecodeInteger(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "0", v)
+	_, err = DecodeInteger(make([]byte, 33))
+	assert.NotNil(t, err)
+}
+
+func TestDecodeAddress(t *testing.T) {
+	v, err := DecodeAddress(helper.DefaultAddressVersion)(crypto.Hash160([]byte{0x01}))
+	assert.Nil(t, err)
+	assert.Equal(t, "NdtB8RXRmJ7Nhw1FPTm7E6HoDZGnDw37nf", v)
+	_, err = DecodeAddress(helper.DefaultAddressVersion)([]byte{1})
+	assert.NotNil(t, err)
+}
+
+func TestDecodeStackItem(t *testing.T) {
+	// struct of an integer and a byte string
+	v, err := DecodeStackItem(helper.HexToBytes("41022101052802abcd"))
+	assert.Nil(t, err)
+	stack := v.(models.InvokeStack)
+	assert.Equal(t, "Struct", stack.Type)
+	_, err = DecodeStackItem([]byte{0xff})
+	assert.NotNil(t, err)
+}
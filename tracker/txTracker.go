@@ -0,0 +1,304 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
+
+type TxState byte
+
+const (
+	Pending TxState = iota
+	Confirmed
+	Expired // not in a block and the chain has passed ValidUntilBlock
+	Dropped // not in a block and gone from the mempool, e.g. evicted or conflicting with another tx
+)
+
+func (s TxState) String() string {
+	switch s {
+	case Pending:
+		return "Pending"
+	case Confirmed:
+		return "Confirmed"
+	case Expired:
+		return "Expired"
+	case Dropped:
+		return "Dropped"
+	}
+	return "Unknown"
+}
+
+const (
+	DefaultPollInterval = 3 * time.Second
+	DefaultDropAfter    = 3
+)
+
+// TxResult is the final state of a tracked tx, BlockIndex, VMState and Exception are only set when it is confirmed,
+// VMState is empty if the node does not have the ApplicationLogs plugin
+type TxResult struct {
+	Hash       string
+	State      TxState
+	BlockIndex uint32
+	VMState    string
+	Exception  string
+}
+
+// Callback is called in the polling goroutine, so it should return quickly
+type Callback func(result *TxResult)
+
+type trackedTx struct {
+	hash            string
+	validUntilBlock uint32
+	checked         bool // whether GetTransactionHeight has been called for it
+	misses          int  // consecutive polls it is missing from the mempool
+	callback        Callback
+	ch              chan *TxResult
+}
+
+type resolvedTx struct {
+	tracked *trackedTx
+	result  *TxResult
+}
+
+// TxTracker resolves many transactions with a single polling loop. Each poll costs one GetBlockCount,
+// one GetBlock per new block and one GetRawMemPool, however many transactions are tracked. On top of that,
+// a newly tracked tx costs one GetTransactionHeight and a confirmed tx one GetApplicationLog.
+type TxTracker struct {
+	Client       rpc.IRpcClient
+	PollInterval time.Duration
+	// DropAfter is the number of consecutive polls a tx can be missing from both the mempool and the blocks
+	// before it is considered dropped, a tx just sent may not be in the mempool of the node yet
+	DropAfter int
+
+	tracked  map[string]*trackedTx
+	resolved []resolvedTx // results to dispatch at the end of a poll
+	height   uint32       // the last block scanned
+	started  bool
+	lock     sync.Mutex
+}
+
+func NewTxTracker(client rpc.IRpcClient) *TxTracker {
+	if client == nil {
+		return nil
+	}
+	return &TxTracker{
+		Client:       client,
+		PollInterval: DefaultPollInterval,
+		DropAfter:    DefaultDropAfter,
+		tracked:      map[string]*trackedTx{},
+	}
+}
+
+// Track starts tracking a tx, the result is sent to the returned channel and passed to the callback if it is not nil.
+// Tracking the same hash again replaces the previous one.
+func (t *TxTracker) Track(hash string, validUntilBlock uint32, callback Callback) <-chan *TxResult {
+	ch := make(chan *TxResult, 1)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	hash = normalizeHash(hash)
+	t.tracked[hash] = &trackedTx{
+		hash:            hash,
+		validUntilBlock: validUntilBlock,
+		callback:        callback,
+		ch:              ch,
+	}
+	return ch
+}
+
+// Untrack stops tracking a tx, its channel will never receive a result
+func (t *TxTracker) Untrack(hash string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.tracked, normalizeHash(hash))
+}
+
+// Count returns the number of pending transactions
+func (t *TxTracker) Count() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return len(t.tracked)
+}
+
+// Run polls until the context is done, errors of a single poll are retried in the next one
+func (t *TxTracker) Run(ctx context.Context) error {
+	for {
+		_ = t.Poll()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(t.pollInterval()):
+		}
+	}
+}
+
+// Wait tracks a tx and polls until it is resolved or the context is done, use it when nothing else polls the tracker.
+// Errors of a single poll are retried, the last one is returned if the context is done.
+func (t *TxTracker) Wait(ctx context.Context, hash string, validUntilBlock uint32) (*TxResult, error) {
+	ch := t.Track(hash, validUntilBlock, nil)
+	for {
+		err := t.Poll()
+		select {
+		case r := <-ch:
+			return r, nil
+		default:
+		}
+		select {
+		case r := <-ch:
+			return r, nil
+		case <-ctx.Done():
+			t.Untrack(hash)
+			if err != nil {
+				return nil, fmt.Errorf("%v, last error: %v", ctx.Err(), err)
+			}
+			return nil, ctx.Err()
+		case <-time.After(t.pollInterval()):
+		}
+	}
+}
+
+// Poll checks new blocks and the mempool once, and resolves the transactions whose state is known
+func (t *TxTracker) Poll() error {
+	t.lock.Lock()
+	err := t.poll()
+	resolved := t.resolved
+	t.resolved = nil
+	t.lock.Unlock()
+	// callbacks are called without the lock, so that they can track other txs
+	for _, r := range resolved {
+		if r.tracked.callback != nil {
+			r.tracked.callback(r.result)
+		}
+		r.tracked.ch <- r.result
+	}
+	return err
+}
+
+func (t *TxTracker) poll() error {
+	if len(t.tracked) == 0 {
+		// newly tracked txs are looked up with GetTransactionHeight, so the blocks in between need no scan
+		t.started = false
+		return nil
+	}
+	response := t.Client.GetBlockCount()
+	if response.HasError() {
+		return fmt.Errorf(response.GetErrorInfo())
+	}
+	if response.Result == 0 {
+		return nil
+	}
+	current := uint32(response.Result - 1)
+
+	// newly tracked txs may be in a block already
+	for _, tracked := range t.tracked {
+		if tracked.checked {
+			continue
+		}
+		heightResponse := t.Client.GetTransactionHeight(tracked.hash)
+		if !heightResponse.HasError() {
+			t.confirm(tracked, uint32(heightResponse.Result))
+			continue
+		}
+		tracked.checked = true
+	}
+	if !t.started {
+		t.height = current
+		t.started = true
+	}
+	for t.height < current && len(t.tracked) != 0 {
+		next := t.height + 1
+		blockResponse := t.Client.GetBlock(strconv.FormatUint(uint64(next), 10))
+		if blockResponse.HasError() {
+			return fmt.Errorf(blockResponse.GetErrorInfo())
+		}
+		for _, trx := range blockResponse.Result.Tx {
+			if tracked, ok := t.tracked[normalizeHash(trx.Hash)]; ok {
+				t.confirm(tracked, next)
+			}
+		}
+		t.height = next
+	}
+	if len(t.tracked) == 0 {
+		return nil
+	}
+
+	// the blocks up to current are scanned, so a tx not found is expired once current reaches ValidUntilBlock
+	for _, tracked := range t.tracked {
+		if current >= tracked.validUntilBlock {
+			t.resolve(tracked, &TxResult{Hash: tracked.hash, State: Expired})
+		}
+	}
+	if len(t.tracked) == 0 {
+		return nil
+	}
+	poolResponse := t.Client.GetRawMemPool()
+	if poolResponse.HasError() {
+		return fmt.Errorf(poolResponse.GetErrorInfo())
+	}
+	pool := make(map[string]bool, len(poolResponse.Result))
+	for _, h := range poolResponse.Result {
+		pool[normalizeHash(h)] = true
+	}
+	for _, tracked := range t.tracked {
+		if pool[tracked.hash] {
+			tracked.misses = 0
+			continue
+		}
+		tracked.misses++
+		if tracked.misses >= t.dropAfter() {
+			t.resolve(tracked, &TxResult{Hash: tracked.hash, State: Dropped})
+		}
+	}
+	return nil
+}
+
+func (t *TxTracker) confirm(tracked *trackedTx, index uint32) {
+	result := &TxResult{
+		Hash:       tracked.hash,
+		State:      Confirmed,
+		BlockIndex: index,
+	}
+	logResponse := t.Client.GetApplicationLog(tracked.hash)
+	if !logResponse.HasError() {
+		result.VMState, result.Exception = getVMState(logResponse.Result)
+	}
+	t.resolve(tracked, result)
+}
+
+func getVMState(log models.RpcApplicationLog) (string, string) {
+	for _, execution := range log.Executions {
+		if execution.Trigger == "Application" {
+			return execution.VMState, execution.Exception
+		}
+	}
+	return "", ""
+}
+
+func (t *TxTracker) resolve(tracked *trackedTx, result *TxResult) {
+	delete(t.tracked, tracked.hash)
+	t.resolved = append(t.resolved, resolvedTx{tracked: tracked, result: result})
+}
+
+func (t *TxTracker) pollInterval() time.Duration {
+	if t.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+	return t.PollInterval
+}
+
+func (t *TxTracker) dropAfter() int {
+	if t.DropAfter <= 0 {
+		return DefaultDropAfter
+	}
+	return t.DropAfter
+}
+
+func normalizeHash(hash string) string {
+	return "0x" + strings.TrimPrefix(strings.ToLower(hash), "0x")
+}
@@ -0,0 +1,139 @@
+package tracker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
+
+var unknownTx = rpc.GetTransactionHeightResponse{
+	ErrorResponse: rpc.ErrorResponse{Error: rpc.RpcError{Code: -100, Message: "Unknown transaction"}},
+}
+
+func blockWith(index int, hashes ...string) rpc.GetBlockResponse {
+	txs := make([]models.RpcTransaction, len(hashes))
+	for i, h := range hashes {
+		txs[i] = models.RpcTransaction{Hash: h}
+	}
+	return rpc.GetBlockResponse{Result: models.RpcBlock{RpcBlockHeader: models.RpcBlockHeader{Index: index}, Tx: txs}}
+}
+
+func haltLog(txId string) rpc.GetApplicationLogResponse {
+	return rpc.GetApplicationLogResponse{
+		Result: models.RpcApplicationLog{
+			TxId:       txId,
+			Executions: []models.RpcExecution{{Trigger: "Application", VMState: "HALT"}},
+		},
+	}
+}
+
+func TestTxTracker_Poll(t *testing.T) {
+	clientMock := new(rpc.RpcClientMock)
+	tracker := NewTxTracker(clientMock)
+	tracker.DropAfter = 2
+
+	results := map[string]*TxResult{}
+	callback := func(r *TxResult) { results[r.Hash] = r }
+	chConfirmed := tracker.Track("0xAA", 200, callback)
+	tracker.Track("0xbb", 101, callback)
+	tracker.Track("0xcc", 200, callback)
+	tracker.Track("0xdd", 200, callback)
+	tracker.Track("0xee", 200, callback)
+
+	// first poll at height 100, 0xee is confirmed already
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 101}).Once()
+	for _, h := range []string{"0xaa", "0xbb", "0xcc", "0xdd"} {
+		clientMock.On("GetTransactionHeight", h).Return(unknownTx)
+	}
+	clientMock.On("GetTransactionHeight", "0xee").Return(rpc.GetTransactionHeightResponse{Result: 99})
+	clientMock.On("GetApplicationLog", "0xee").Return(haltLog("0xee"))
+	clientMock.On("GetRawMemPool").Return(rpc.GetRawMemPoolResponse{Result: []string{"0xaa", "0xbb", "0xcc"}}).Once()
+	assert.Nil(t, tracker.Poll())
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, Confirmed, results["0xee"].State)
+	assert.Equal(t, uint32(99), results["0xee"].BlockIndex)
+	assert.Equal(t, 4, tracker.Count())
+
+	// second poll at height 102, 0xaa is in block 101, 0xbb expires, 0xdd is dropped
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 103}).Once()
+	clientMock.On("GetBlock", "101").Return(blockWith(101, "0xaa"))
+	clientMock.On("GetBlock", "102").Return(blockWith(102))
+	clientMock.On("GetApplicationLog", "0xaa").Return(haltLog("0xaa"))
+	clientMock.On("GetRawMemPool").Return(rpc.GetRawMemPoolResponse{Result: []string{"0xcc"}}).Once()
+	assert.Nil(t, tracker.Poll())
+	assert.Equal(t, Confirmed, results["0xaa"].State)
+	assert.Equal(t, uint32(101), results["0xaa"].BlockIndex)
+	assert.Equal(t, "HALT", results["0xaa"].VMState)
+	assert.Equal(t, Expired, results["0xbb"].State)
+	assert.Equal(t, Dropped, results["0xdd"].State)
+	assert.Equal(t, 1, tracker.Count())
+
+	r := <-chConfirmed
+	assert.Equal(t, "0xaa", r.Hash)
+	clientMock.AssertNumberOfCalls(t, "GetTransactionHeight", 5)
+}
+
+func TestTxTracker_PollAfterIdle(t *testing.T) {
+	clientMock := new(rpc.RpcClientMock)
+	tracker := NewTxTracker(clientMock)
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 101}).Once()
+	clientMock.On("GetTransactionHeight", "0x01").Return(rpc.GetTransactionHeightResponse{Result: 100})
+	clientMock.On("GetApplicationLog", "0x01").Return(haltLog("0x01"))
+	tracker.Track("0x01", 200, nil)
+	assert.Nil(t, tracker.Poll())
+	assert.Nil(t, tracker.Poll()) // nothing tracked
+	assert.Equal(t, 0, tracker.Count())
+
+	// the blocks produced while nothing was tracked are not scanned
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 201})
+	clientMock.On("GetTransactionHeight", "0x02").Return(unknownTx)
+	clientMock.On("GetRawMemPool").Return(rpc.GetRawMemPoolResponse{Result: []string{"0x02"}})
+	tracker.Track("0x02", 300, nil)
+	assert.Nil(t, tracker.Poll())
+	assert.Equal(t, 1, tracker.Count())
+	clientMock.AssertNotCalled(t, "GetBlock", mock.Anything)
+}
+
+func TestTxTracker_Wait(t *testing.T) {
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 101}).Once()
+	clientMock.On("GetTransactionHeight", "0x01").Return(unknownTx)
+	clientMock.On("GetRawMemPool").Return(rpc.GetRawMemPoolResponse{Result: []string{"0x01"}})
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 102})
+	clientMock.On("GetBlock", "101").Return(blockWith(101, "0x01"))
+	clientMock.On("GetApplicationLog", "0x01").Return(rpc.GetApplicationLogResponse{
+		Result: models.RpcApplicationLog{
+			Executions: []models.RpcExecution{{Trigger: "Application", VMState: "FAULT", Exception: "ASSERT is executed with false result."}},
+		},
+	})
+	tracker := NewTxTracker(clientMock)
+	tracker.PollInterval = time.Millisecond
+
+	r, err := tracker.Wait(context.Background(), "0x01", 200)
+	assert.Nil(t, err)
+	assert.Equal(t, Confirmed, r.State)
+	assert.Equal(t, "FAULT", r.VMState)
+	assert.Equal(t, "ASSERT is executed with false result.", r.Exception)
+	assert.Equal(t, 0, tracker.Count())
+}
+
+func TestTxTracker_WaitTimeout(t *testing.T) {
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 101})
+	clientMock.On("GetTransactionHeight", "0x01").Return(unknownTx)
+	clientMock.On("GetRawMemPool").Return(rpc.GetRawMemPoolResponse{Result: []string{"0x01"}})
+	tracker := NewTxTracker(clientMock)
+	tracker.PollInterval = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := tracker.Wait(ctx, "0x01", 200)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 0, tracker.Count())
+}
@@ -1,7 +1,10 @@
 package conditions
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
@@ -158,33 +161,7 @@ func (this *WitnessCondition) Deserialize(br *io.BinaryReader) {
 	if br.Err != nil {
 		return
 	}
-	if this.Type != wc.Type {
-		br.Err = fmt.Errorf("witness condition type does not match when deserializing")
-		return
-	}
-	switch this.Type {
-	case Boolean:
-		this.booleanCondition = wc.booleanCondition
-	case Not:
-		this.notCondition = wc.notCondition
-	case And:
-		this.andConditions = wc.andConditions
-	case Or:
-		this.orConditions = wc.orConditions
-	case ScriptHash:
-		this.scriptHashCondition = wc.scriptHashCondition
-	case Group:
-		this.groupCondition = wc.groupCondition
-	case CalledByEntryType:
-		return
-	case CalledByContract:
-		this.calledByContractCondition = wc.calledByContractCondition
-	case CalledByGroup:
-		this.calledByGroupCondition = wc.calledByGroupCondition
-	default:
-		br.Err = fmt.Errorf("not supported witness condition type")
-		return
-	}
+	*this = *wc
 }
 
 func DeserializeConditions(br *io.BinaryReader, maxNestDepth int) []*WitnessCondition {
@@ -207,27 +184,30 @@ func DeserializeConditionFrom(br *io.BinaryReader, maxNestDepth int) *WitnessCon
 	condition := &WitnessCondition{Type: WitnessConditionType(t)}
 	switch condition.Type {
 	case Boolean:
-		br.ReadLE(condition.booleanCondition)
-	case Not:
-		if maxNestDepth <= 0 {
-			br.Err = fmt.Errorf("max nest depth exceeded")
+		b := br.ReadOneByte()
+		if b > 1 {
+			br.Err = fmt.Errorf("format error: invalid boolean %d", b)
 		}
-		condition.notCondition = DeserializeConditionFrom(br, maxNestDepth-1)
-	case And:
+		expression := b == 1
+		condition.booleanCondition = &expression
+	case Not, And, Or:
 		if maxNestDepth <= 0 {
 			br.Err = fmt.Errorf("max nest depth exceeded")
+			return nil
 		}
-		condition.andConditions = DeserializeConditions(br, maxNestDepth-1)
-	case Or:
-		if maxNestDepth <= 0 {
-			br.Err = fmt.Errorf("max nest depth exceeded")
+		switch condition.Type {
+		case Not:
+			condition.notCondition = DeserializeConditionFrom(br, maxNestDepth-1)
+		case And:
+			condition.andConditions = DeserializeConditions(br, maxNestDepth-1)
+		default:
+			condition.orConditions = DeserializeConditions(br, maxNestDepth-1)
 		}
-		condition.andConditions = DeserializeConditions(br, maxNestDepth-1)
 	case ScriptHash:
 		condition.scriptHashCondition = new(helper.UInt160)
 		condition.scriptHashCondition.Deserialize(br)
 	case Group:
-		condition.groupCondition = new(crypto.ECPoint)
+		condition.groupCondition, _ = crypto.NewECPoint()
 		condition.groupCondition.Deserialize(br)
 	case CalledByEntryType:
 		return condition
@@ -235,7 +215,7 @@ func DeserializeConditionFrom(br *io.BinaryReader, maxNestDepth int) *WitnessCon
 		condition.calledByContractCondition = new(helper.UInt160)
 		condition.calledByContractCondition.Deserialize(br)
 	case CalledByGroup:
-		condition.calledByGroupCondition = new(crypto.ECPoint)
+		condition.calledByGroupCondition, _ = crypto.NewECPoint()
 		condition.calledByGroupCondition.Deserialize(br)
 	default:
 		br.Err = fmt.Errorf("not supported witness condition type")
@@ -278,6 +258,139 @@ func (this *WitnessCondition) Serialize(bw *io.BinaryWriter) {
 	}
 }
 
+// MarshalJSON implements the json marshaller interface, e.g. {"type":"Not","expression":{"type":"CalledByEntry"}}
+func (this *WitnessCondition) MarshalJSON() ([]byte, error) {
+	v := struct {
+		Type        string              `json:"type"`
+		Expression  interface{}         `json:"expression,omitempty"`
+		Expressions []*WitnessCondition `json:"expressions,omitempty"`
+		Hash        string              `json:"hash,omitempty"`
+		Group       string              `json:"group,omitempty"`
+	}{Type: this.Type.String()}
+	switch this.Type {
+	case Boolean:
+		if this.booleanCondition == nil {
+			return nil, fmt.Errorf("format error: Boolean expression is nil")
+		}
+		v.Expression = *this.booleanCondition
+	case Not:
+		if this.notCondition == nil {
+			return nil, fmt.Errorf("format error: Not expression is nil")
+		}
+		v.Expression = this.notCondition
+	case And:
+		v.Expressions = this.andConditions
+	case Or:
+		v.Expressions = this.orConditions
+	case ScriptHash, CalledByContract:
+		hash := this.GetCondition().(*helper.UInt160)
+		if hash == nil {
+			return nil, fmt.Errorf("format error: %s hash is nil", this.Type.String())
+		}
+		v.Hash = "0x" + hash.String()
+	case Group, CalledByGroup:
+		group := this.GetCondition().(*crypto.ECPoint)
+		if group == nil {
+			return nil, fmt.Errorf("format error: %s group is nil", this.Type.String())
+		}
+		v.Group = group.String()
+	case CalledByEntryType:
+	default:
+		return nil, fmt.Errorf("not supported witness condition type")
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json unmarshaller interface, the nesting is limited by MaxNestingDepth.
+func (this *WitnessCondition) UnmarshalJSON(data []byte) error {
+	wc, err := witnessConditionFromJson(data, MaxNestingDepth)
+	if err != nil {
+		return err
+	}
+	*this = *wc
+	return nil
+}
+
+func witnessConditionFromJson(data []byte, maxNestDepth int) (*WitnessCondition, error) {
+	v := struct {
+		Type        string            `json:"type"`
+		Expression  json.RawMessage   `json:"expression"`
+		Expressions []json.RawMessage `json:"expressions"`
+		Hash        string            `json:"hash"`
+		Group       string            `json:"group"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	t, err := NewWitnessConditionTypeFromString(v.Type)
+	if err != nil {
+		return nil, err
+	}
+	condition := &WitnessCondition{Type: t}
+	switch t {
+	case Boolean:
+		// the node writes a boolean, "true" and "false" are accepted as well
+		var expression bool
+		if err = json.Unmarshal(v.Expression, &expression); err != nil {
+			var s string
+			if json.Unmarshal(v.Expression, &s) != nil || (s != "true" && s != "false") {
+				return nil, fmt.Errorf("format error: invalid Boolean expression: %s", string(v.Expression))
+			}
+			expression = s == "true"
+		}
+		condition.booleanCondition = &expression
+	case Not:
+		if maxNestDepth <= 0 {
+			return nil, fmt.Errorf("max nest depth exceeded")
+		}
+		if condition.notCondition, err = witnessConditionFromJson(v.Expression, maxNestDepth-1); err != nil {
+			return nil, err
+		}
+	case And, Or:
+		if maxNestDepth <= 0 {
+			return nil, fmt.Errorf("max nest depth exceeded")
+		}
+		if len(v.Expressions) > MaxSubItems {
+			return nil, fmt.Errorf("format error: too many expressions: %d", len(v.Expressions))
+		}
+		expressions := make([]*WitnessCondition, len(v.Expressions))
+		for i := range v.Expressions {
+			if expressions[i], err = witnessConditionFromJson(v.Expressions[i], maxNestDepth-1); err != nil {
+				return nil, err
+			}
+		}
+		if t == And {
+			condition.andConditions = expressions
+		} else {
+			condition.orConditions = expressions
+		}
+	case ScriptHash, CalledByContract:
+		if len(strings.TrimPrefix(v.Hash, "0x")) != helper.UINT160SIZE*2 {
+			return nil, fmt.Errorf("format error: invalid %s hash: %s", t.String(), v.Hash)
+		}
+		hash, err := helper.UInt160FromString(v.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if t == ScriptHash {
+			condition.scriptHashCondition = hash
+		} else {
+			condition.calledByContractCondition = hash
+		}
+	case Group, CalledByGroup:
+		group, err := crypto.NewECPointFromString(v.Group)
+		if err != nil {
+			return nil, fmt.Errorf("format error: invalid %s group: %s", t.String(), v.Group)
+		}
+		if t == Group {
+			condition.groupCondition = group
+		} else {
+			condition.calledByGroupCondition = group
+		}
+	}
+	return condition, nil
+}
+
 func CreateWitnessCondition(t WitnessConditionType, v interface{}) (IWitnessCondition, error) {
 	switch t {
 	case Boolean:
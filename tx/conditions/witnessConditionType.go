@@ -1,5 +1,7 @@
 package conditions
 
+import "fmt"
+
 type WitnessConditionType byte
 
 const (
@@ -43,3 +45,13 @@ func (w WitnessConditionType) String() string {
 func (w WitnessConditionType) GetSize() int {
 	return 1
 }
+
+// NewWitnessConditionTypeFromString parses the name of a type, e.g. "CalledByEntry"
+func NewWitnessConditionTypeFromString(s string) (WitnessConditionType, error) {
+	for _, t := range []WitnessConditionType{Boolean, Not, And, Or, ScriptHash, Group, CalledByEntryType, CalledByContract, CalledByGroup} {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("not supported witness condition type: %s", s)
+}
@@ -0,0 +1,88 @@
+package conditions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+)
+
+const testGroup = "02a7bc55fe8684e0119768d104ba30795bdcc86619e864add26156723ed185cd62"
+
+func newTestConditions(t *testing.T) []*WitnessCondition {
+	f := false
+	hash, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	group, err := crypto.NewECPointFromString(testGroup)
+	assert.Nil(t, err)
+	calledByEntry := NewWitnessCondition(CalledByEntryType, nil)
+	return []*WitnessCondition{
+		NewWitnessCondition(Boolean, &f),
+		NewWitnessCondition(Not, calledByEntry),
+		NewWitnessCondition(And, []*WitnessCondition{calledByEntry, NewWitnessCondition(Not, NewWitnessCondition(Boolean, &f))}),
+		NewWitnessCondition(Or, []*WitnessCondition{calledByEntry, NewWitnessCondition(ScriptHash, hash)}),
+		NewWitnessCondition(ScriptHash, hash),
+		NewWitnessCondition(Group, group),
+		calledByEntry,
+		NewWitnessCondition(CalledByContract, hash),
+		NewWitnessCondition(CalledByGroup, group),
+	}
+}
+
+func TestWitnessCondition_Serialize(t *testing.T) {
+	for _, c := range newTestConditions(t) {
+		bbw := io.NewBufBinaryWriter()
+		c.Serialize(bbw.BinaryWriter)
+		assert.Nil(t, bbw.Err)
+		b := bbw.Bytes()
+		assert.Equal(t, c.GetSize(), len(b))
+
+		d := &WitnessCondition{}
+		br := io.NewBinaryReaderFromBuf(b)
+		d.Deserialize(br)
+		assert.Nil(t, br.Err, c.Type.String())
+		assert.Equal(t, c, d)
+	}
+}
+
+func TestWitnessCondition_Deserialize_MaxNestDepth(t *testing.T) {
+	// Not(Not(Not(CalledByEntry)))
+	br := io.NewBinaryReaderFromBuf([]byte{0x01, 0x01, 0x01, 0x20})
+	c := &WitnessCondition{}
+	c.Deserialize(br)
+	assert.EqualError(t, br.Err, "max nest depth exceeded")
+}
+
+func TestWitnessCondition_MarshalJSON(t *testing.T) {
+	for _, c := range newTestConditions(t) {
+		data, err := json.Marshal(c)
+		assert.Nil(t, err)
+		d := &WitnessCondition{}
+		assert.Nil(t, json.Unmarshal(data, d), string(data))
+		assert.Equal(t, c, d)
+	}
+
+	data, _ := json.Marshal(newTestConditions(t)[2])
+	assert.Equal(t, `{"type":"And","expressions":[{"type":"CalledByEntry"},{"type":"Not","expression":{"type":"Boolean","expression":false}}]}`, string(data))
+	data, _ = json.Marshal(newTestConditions(t)[7])
+	assert.Equal(t, `{"type":"CalledByContract","hash":"0xd2a4cff31913016155e38e474a2c06d08be276cf"}`, string(data))
+	data, _ = json.Marshal(newTestConditions(t)[8])
+	assert.Equal(t, `{"type":"CalledByGroup","group":"`+testGroup+`"}`, string(data))
+}
+
+func TestWitnessCondition_UnmarshalJSON(t *testing.T) {
+	c := &WitnessCondition{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"type":"Boolean","expression":"true"}`), c))
+	assert.True(t, *c.GetCondition().(*bool))
+
+	assert.EqualError(t, json.Unmarshal([]byte(`{"type":"Not","expression":{"type":"Not","expression":{"type":"Not","expression":{"type":"CalledByEntry"}}}}`), c),
+		"max nest depth exceeded")
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"Boolean","expression":"yes"}`), c))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"Unknown"}`), c))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"ScriptHash","hash":"0x01"}`), c))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"Group","group":"02"}`), c))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"Not"}`), c))
+}
@@ -0,0 +1,97 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+)
+
+// ConflictsAttribute declares that the transaction conflicts with the transaction of Hash,
+// at most one of them can be persisted, and the mempool keeps the one paying more network fee
+type ConflictsAttribute struct {
+	Hash *helper.UInt256
+}
+
+func NewConflictsAttribute(hash *helper.UInt256) *ConflictsAttribute {
+	return &ConflictsAttribute{Hash: hash}
+}
+
+func (c *ConflictsAttribute) GetAttributeType() TransactionAttributeType {
+	return Conflicts
+}
+
+func (c *ConflictsAttribute) AllowMultiple() bool {
+	return true
+}
+
+func (c *ConflictsAttribute) GetAttributeSize() int {
+	return 1 + // base size
+		helper.UINT256SIZE // Hash
+}
+
+func (c *ConflictsAttribute) Deserialize(br *io.BinaryReader) {
+	if br.ReadOneByte() != byte(Conflicts) {
+		br.Err = fmt.Errorf("format error: not Conflicts")
+	}
+	c.DeserializeWithoutType(br)
+}
+
+func (c *ConflictsAttribute) Serialize(bw *io.BinaryWriter) {
+	bw.WriteLE(byte(Conflicts))
+	c.SerializeWithoutType(bw)
+}
+
+func (c *ConflictsAttribute) DeserializeWithoutType(br *io.BinaryReader) {
+	c.Hash = helper.NewUInt256()
+	c.Hash.Deserialize(br)
+}
+
+func (c *ConflictsAttribute) SerializeWithoutType(bw *io.BinaryWriter) {
+	if c.Hash == nil {
+		if bw.Err == nil {
+			bw.Err = fmt.Errorf("format error: Conflicts hash is nil")
+		}
+		return
+	}
+	c.Hash.Serialize(bw)
+}
+
+// MarshalJSON implements the json marshaller interface, e.g. {"type":"Conflicts","hash":"0x..."}
+func (c *ConflictsAttribute) MarshalJSON() ([]byte, error) {
+	if c.Hash == nil {
+		return nil, fmt.Errorf("format error: Conflicts hash is nil")
+	}
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		Hash string `json:"hash"`
+	}{
+		Type: Conflicts.String(),
+		Hash: "0x" + c.Hash.String(),
+	})
+}
+
+// UnmarshalJSON implements the json unmarshaller interface.
+func (c *ConflictsAttribute) UnmarshalJSON(data []byte) error {
+	v := struct {
+		Type string `json:"type"`
+		Hash string `json:"hash"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Type != Conflicts.String() {
+		return fmt.Errorf("format error: not Conflicts: %s", v.Type)
+	}
+	if len(strings.TrimPrefix(v.Hash, "0x")) != helper.UINT256SIZE*2 {
+		return fmt.Errorf("format error: invalid Conflicts hash: %s", v.Hash)
+	}
+	hash, err := helper.UInt256FromString(v.Hash)
+	if err != nil {
+		return err
+	}
+	c.Hash = hash
+	return nil
+}
@@ -0,0 +1,51 @@
+package tx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/stretchr/testify/assert"
+)
+
+const conflictsHash = "0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20"
+
+func TestConflictsAttribute_AllowMultiple(t *testing.T) {
+	assert.Equal(t, true, NewConflictsAttribute(helper.UInt256Zero).AllowMultiple())
+}
+
+func TestConflictsAttribute_Serialize(t *testing.T) {
+	hash, _ := helper.UInt256FromString(conflictsHash)
+	c := NewConflictsAttribute(hash)
+	assert.Equal(t, 33, c.GetAttributeSize())
+	bbw := io.NewBufBinaryWriter()
+	c.Serialize(bbw.BinaryWriter)
+	assert.Nil(t, bbw.Err)
+	b := bbw.Bytes()
+	assert.Equal(t, 33, len(b))
+	assert.Equal(t, byte(Conflicts), b[0])
+
+	d := ConflictsAttribute{}
+	br := io.NewBinaryReaderFromBuf(b)
+	d.Deserialize(br)
+	assert.Nil(t, br.Err)
+	assert.True(t, hash.Equals(d.Hash))
+
+	bbw = io.NewBufBinaryWriter()
+	(&ConflictsAttribute{}).Serialize(bbw.BinaryWriter)
+	assert.NotNil(t, bbw.Err)
+}
+
+func TestConflictsAttribute_MarshalJSON(t *testing.T) {
+	hash, _ := helper.UInt256FromString(conflictsHash)
+	b, err := json.Marshal(NewConflictsAttribute(hash))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"Conflicts","hash":"`+conflictsHash+`"}`, string(b))
+
+	c := ConflictsAttribute{}
+	assert.Nil(t, json.Unmarshal(b, &c))
+	assert.True(t, hash.Equals(c.Hash))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"Conflicts","hash":"0x01"}`), &c))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"HighPriority","hash":"`+conflictsHash+`"}`), &c))
+}
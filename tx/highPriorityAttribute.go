@@ -1,7 +1,9 @@
 package tx
 
 import (
+	"encoding/json"
 	"fmt"
+
 	"github.com/joeqian10/neo3-gogogo/io"
 )
 
@@ -40,3 +42,22 @@ func (h *HighPriorityAttribute) DeserializeWithoutType(br *io.BinaryReader) {
 func (h *HighPriorityAttribute) SerializeWithoutType(bw *io.BinaryWriter) {
 
 }
+
+// MarshalJSON implements the json marshaller interface, e.g. {"type":"HighPriority"}
+func (h *HighPriorityAttribute) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"type": HighPriority.String()})
+}
+
+// UnmarshalJSON implements the json unmarshaller interface.
+func (h *HighPriorityAttribute) UnmarshalJSON(data []byte) error {
+	v := struct {
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Type != HighPriority.String() {
+		return fmt.Errorf("format error: not HighPriority: %s", v.Type)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package tx
 
 import (
+	"encoding/json"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
 	"github.com/stretchr/testify/assert"
@@ -34,3 +35,16 @@ func TestHighPriorityAttribute_Serialize(t *testing.T) {
 	highPriority.Serialize(bbw.BinaryWriter)
 	assert.Equal(t, "01", helper.BytesToHex(bbw.Bytes()))
 }
+
+func TestHighPriorityAttribute_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(highPriority)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"HighPriority"}`, string(b))
+
+	a, err := NewTransactionAttributeFromJSON(b)
+	assert.Nil(t, err)
+	assert.Equal(t, HighPriority, a.GetAttributeType())
+
+	_, err = NewTransactionAttributeFromJSON([]byte(`{"type":"Unknown"}`))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,86 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/io"
+)
+
+// NotValidBeforeAttribute makes the transaction invalid before the given block height
+type NotValidBeforeAttribute struct {
+	Height uint32
+}
+
+func NewNotValidBeforeAttribute(height uint32) *NotValidBeforeAttribute {
+	return &NotValidBeforeAttribute{Height: height}
+}
+
+func (n *NotValidBeforeAttribute) GetAttributeType() TransactionAttributeType {
+	return NotValidBefore
+}
+
+func (n *NotValidBeforeAttribute) AllowMultiple() bool {
+	return false
+}
+
+func (n *NotValidBeforeAttribute) GetAttributeSize() int {
+	return 1 + // base size
+		4 // Height
+}
+
+func (n *NotValidBeforeAttribute) Deserialize(br *io.BinaryReader) {
+	if br.ReadOneByte() != byte(NotValidBefore) {
+		br.Err = fmt.Errorf("format error: not NotValidBefore")
+	}
+	n.DeserializeWithoutType(br)
+}
+
+func (n *NotValidBeforeAttribute) Serialize(bw *io.BinaryWriter) {
+	bw.WriteLE(byte(NotValidBefore))
+	n.SerializeWithoutType(bw)
+}
+
+func (n *NotValidBeforeAttribute) DeserializeWithoutType(br *io.BinaryReader) {
+	br.ReadLE(&n.Height)
+}
+
+func (n *NotValidBeforeAttribute) SerializeWithoutType(bw *io.BinaryWriter) {
+	bw.WriteLE(n.Height)
+}
+
+// Verify returns true if the transaction can be included in the block after currentHeight,
+// the same as NotValidBefore.Verify in neo which compares with Ledger.CurrentIndex
+func (n *NotValidBeforeAttribute) Verify(currentHeight uint32) bool {
+	return currentHeight >= n.Height
+}
+
+// MarshalJSON implements the json marshaller interface, e.g. {"type":"NotValidBefore","height":100}
+func (n *NotValidBeforeAttribute) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string `json:"type"`
+		Height uint32 `json:"height"`
+	}{
+		Type:   NotValidBefore.String(),
+		Height: n.Height,
+	})
+}
+
+// UnmarshalJSON implements the json unmarshaller interface.
+func (n *NotValidBeforeAttribute) UnmarshalJSON(data []byte) error {
+	v := struct {
+		Type   string  `json:"type"`
+		Height *uint32 `json:"height"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Type != NotValidBefore.String() {
+		return fmt.Errorf("format error: not NotValidBefore: %s", v.Type)
+	}
+	if v.Height == nil {
+		return fmt.Errorf("format error: height is missing")
+	}
+	n.Height = *v.Height
+	return nil
+}
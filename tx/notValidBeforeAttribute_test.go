@@ -0,0 +1,54 @@
+package tx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotValidBeforeAttribute_AllowMultiple(t *testing.T) {
+	assert.Equal(t, false, NewNotValidBeforeAttribute(0).AllowMultiple())
+}
+
+func TestNotValidBeforeAttribute_Deserialize(t *testing.T) {
+	br := io.NewBinaryReaderFromBuf(helper.HexToBytes("2064000000"))
+	n := NotValidBeforeAttribute{}
+	n.Deserialize(br)
+	assert.Nil(t, br.Err)
+	assert.Equal(t, uint32(100), n.Height)
+
+	br = io.NewBinaryReaderFromBuf(helper.HexToBytes("0164000000"))
+	n.Deserialize(br)
+	assert.NotNil(t, br.Err)
+}
+
+func TestNotValidBeforeAttribute_GetAttributeSize(t *testing.T) {
+	assert.Equal(t, 5, NewNotValidBeforeAttribute(100).GetAttributeSize())
+}
+
+func TestNotValidBeforeAttribute_Serialize(t *testing.T) {
+	bbw := io.NewBufBinaryWriter()
+	NewNotValidBeforeAttribute(100).Serialize(bbw.BinaryWriter)
+	assert.Equal(t, "2064000000", helper.BytesToHex(bbw.Bytes()))
+}
+
+func TestNotValidBeforeAttribute_Verify(t *testing.T) {
+	n := NewNotValidBeforeAttribute(100)
+	assert.False(t, n.Verify(99))
+	assert.True(t, n.Verify(100))
+}
+
+func TestNotValidBeforeAttribute_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(NewNotValidBeforeAttribute(100))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"NotValidBefore","height":100}`, string(b))
+
+	n := NotValidBeforeAttribute{}
+	assert.Nil(t, json.Unmarshal(b, &n))
+	assert.Equal(t, uint32(100), n.Height)
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"Conflicts","height":100}`), &n))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"NotValidBefore"}`), &n))
+}
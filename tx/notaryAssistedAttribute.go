@@ -0,0 +1,87 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+)
+
+const NotaryContractId = "0xc1e14f19c3e60d0b9244d06dd7ba9b113135ec3b"
+
+var NotaryContract, _ = helper.UInt160FromString(NotaryContractId)
+
+// NotaryAssistedAttribute marks a transaction whose witnesses are completed by the Notary service,
+// NKeys is the number of keys collected by the service, it is 0 for a fallback transaction.
+// The transaction must have the Notary contract as a signer, and pays (NKeys+1) times the attribute fee.
+type NotaryAssistedAttribute struct {
+	NKeys byte
+}
+
+func NewNotaryAssistedAttribute(nKeys byte) *NotaryAssistedAttribute {
+	return &NotaryAssistedAttribute{NKeys: nKeys}
+}
+
+func (n *NotaryAssistedAttribute) GetAttributeType() TransactionAttributeType {
+	return NotaryAssisted
+}
+
+func (n *NotaryAssistedAttribute) AllowMultiple() bool {
+	return false
+}
+
+func (n *NotaryAssistedAttribute) GetAttributeSize() int {
+	return 1 + // base size
+		1 // NKeys
+}
+
+func (n *NotaryAssistedAttribute) Deserialize(br *io.BinaryReader) {
+	if br.ReadOneByte() != byte(NotaryAssisted) {
+		br.Err = fmt.Errorf("format error: not NotaryAssisted")
+	}
+	n.DeserializeWithoutType(br)
+}
+
+func (n *NotaryAssistedAttribute) Serialize(bw *io.BinaryWriter) {
+	bw.WriteLE(byte(NotaryAssisted))
+	n.SerializeWithoutType(bw)
+}
+
+func (n *NotaryAssistedAttribute) DeserializeWithoutType(br *io.BinaryReader) {
+	n.NKeys = br.ReadOneByte()
+}
+
+func (n *NotaryAssistedAttribute) SerializeWithoutType(bw *io.BinaryWriter) {
+	bw.WriteLE(n.NKeys)
+}
+
+// MarshalJSON implements the json marshaller interface, e.g. {"type":"NotaryAssisted","nkeys":3}
+func (n *NotaryAssistedAttribute) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string `json:"type"`
+		NKeys byte   `json:"nkeys"`
+	}{
+		Type:  NotaryAssisted.String(),
+		NKeys: n.NKeys,
+	})
+}
+
+// UnmarshalJSON implements the json unmarshaller interface.
+func (n *NotaryAssistedAttribute) UnmarshalJSON(data []byte) error {
+	v := struct {
+		Type  string `json:"type"`
+		NKeys *byte  `json:"nkeys"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Type != NotaryAssisted.String() {
+		return fmt.Errorf("format error: not NotaryAssisted: %s", v.Type)
+	}
+	if v.NKeys == nil {
+		return fmt.Errorf("format error: nkeys is missing")
+	}
+	n.NKeys = *v.NKeys
+	return nil
+}
@@ -0,0 +1,50 @@
+package tx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotaryAssistedAttribute_AllowMultiple(t *testing.T) {
+	assert.Equal(t, false, NewNotaryAssistedAttribute(0).AllowMultiple())
+}
+
+func TestNotaryAssistedAttribute_Serialize(t *testing.T) {
+	n := NewNotaryAssistedAttribute(3)
+	assert.Equal(t, 2, n.GetAttributeSize())
+	bbw := io.NewBufBinaryWriter()
+	n.Serialize(bbw.BinaryWriter)
+	b := bbw.Bytes()
+	assert.Equal(t, "2203", helper.BytesToHex(b))
+
+	br := io.NewBinaryReaderFromBuf(b)
+	a := DeserializeFrom(br)
+	assert.Nil(t, br.Err)
+	assert.Equal(t, byte(3), a.(*NotaryAssistedAttribute).NKeys)
+}
+
+func TestNotaryAssistedAttribute_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(NewNotaryAssistedAttribute(3))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"NotaryAssisted","nkeys":3}`, string(b))
+
+	n := NotaryAssistedAttribute{}
+	assert.Nil(t, json.Unmarshal(b, &n))
+	assert.Equal(t, byte(3), n.NKeys)
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"NotaryAssisted"}`), &n))
+}
+
+func TestNotaryAssistedAttribute_Validate(t *testing.T) {
+	x := Transaction{}
+	x.SetValidUntilBlock(100)
+	x.SetSigners([]*Signer{{Account: helper.UInt160Zero, Scopes: CalledByEntry}})
+	x.SetAttributes([]ITransactionAttribute{NewNotaryAssistedAttribute(1)})
+	assert.NotNil(t, x.ValidateAttributes())
+
+	x.SetSigners([]*Signer{{Account: helper.UInt160Zero, Scopes: CalledByEntry}, {Account: NotaryContract, Scopes: None}})
+	assert.Nil(t, x.ValidateAttributes())
+}
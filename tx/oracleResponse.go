@@ -1,7 +1,10 @@
 package tx
 
 import (
+	"encoding/json"
 	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
 	"github.com/joeqian10/neo3-gogogo/sc"
@@ -80,3 +83,51 @@ func (o *OracleResponseAttribute) SerializeWithoutType(bw *io.BinaryWriter) {
 	bw.WriteLE(byte(o.Code))
 	bw.WriteVarBytes(o.Result)
 }
+
+// MarshalJSON implements the json marshaller interface,
+// e.g. {"type":"OracleResponse","id":1,"code":"Success","result":"AQI="}
+func (o *OracleResponseAttribute) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string `json:"type"`
+		Id     uint64 `json:"id"`
+		Code   string `json:"code"`
+		Result string `json:"result"`
+	}{
+		Type:   OracleResponse.String(),
+		Id:     o.Id,
+		Code:   o.Code.String(),
+		Result: crypto.Base64Encode(o.Result),
+	})
+}
+
+// UnmarshalJSON implements the json unmarshaller interface.
+func (o *OracleResponseAttribute) UnmarshalJSON(data []byte) error {
+	v := struct {
+		Type   string  `json:"type"`
+		Id     *uint64 `json:"id"`
+		Code   string  `json:"code"`
+		Result string  `json:"result"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Type != OracleResponse.String() {
+		return fmt.Errorf("format error: not OracleResponse: %s", v.Type)
+	}
+	if v.Id == nil {
+		return fmt.Errorf("format error: id is missing")
+	}
+	code, err := NewOracleResponseCodeFromString(v.Code)
+	if err != nil {
+		return err
+	}
+	result, err := crypto.Base64Decode(v.Result)
+	if err != nil || len(result) > o.GetMaxResultSize() {
+		return fmt.Errorf("format error: invalid result: %s", v.Result)
+	}
+	if code != Success && len(result) > 0 {
+		return fmt.Errorf("format error: wrong result")
+	}
+	o.Id, o.Code, o.Result = *v.Id, code, result
+	return nil
+}
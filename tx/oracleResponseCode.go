@@ -1,5 +1,7 @@
 package tx
 
+import "fmt"
+
 type OracleResponseCode byte
 
 const (
@@ -12,6 +14,7 @@ const (
 	Forbidden = 0x18
 	ResponseTooLarge = 0x1a
 	InsufficientFunds = 0x1c
+	ContentTypeNotSupported = 0x1f
 
 	Error = 0xff
 )
@@ -21,9 +24,47 @@ func (code OracleResponseCode) IsDefined() bool {
 	switch b {
 	case 0x00, 0x10, 0x12, 0x14,
 		0x16, 0x18, 0x1a, 0x1c,
-		0xff:
+		0x1f, 0xff:
 		return true
 	default:
 		return false
 	}
 }
+
+func (code OracleResponseCode) String() string {
+	b := byte(code)
+	switch b {
+	case 0x00:
+		return "Success"
+	case 0x10:
+		return "ProtocolNotSupported"
+	case 0x12:
+		return "ConsensusUnreachable"
+	case 0x14:
+		return "NotFound"
+	case 0x16:
+		return "Timeout"
+	case 0x18:
+		return "Forbidden"
+	case 0x1a:
+		return "ResponseTooLarge"
+	case 0x1c:
+		return "InsufficientFunds"
+	case 0x1f:
+		return "ContentTypeNotSupported"
+	case 0xff:
+		return "Error"
+	default:
+		return "Not Defined"
+	}
+}
+
+// NewOracleResponseCodeFromString parses the name of a code, e.g. "Success"
+func NewOracleResponseCodeFromString(s string) (OracleResponseCode, error) {
+	for _, b := range []byte{0x00, 0x10, 0x12, 0x14, 0x16, 0x18, 0x1a, 0x1c, 0x1f, 0xff} {
+		if OracleResponseCode(b).String() == s {
+			return OracleResponseCode(b), nil
+		}
+	}
+	return Error, fmt.Errorf("invalid oracle response code: %s", s)
+}
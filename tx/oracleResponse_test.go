@@ -1,6 +1,7 @@
 package tx
 
 import (
+	"encoding/json"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
 	"github.com/stretchr/testify/assert"
@@ -51,3 +52,21 @@ func TestOracleResponseAttribute_Serialize(t *testing.T) {
 	oracleRes.Serialize(bbw.BinaryWriter)
 	assert.Equal(t, "110100000000000000000401020304", helper.BytesToHex(bbw.Bytes()))
 }
+
+func TestOracleResponseAttribute_MarshalJSON(t *testing.T) {
+	o := &OracleResponseAttribute{Id: 1, Code: Success, Result: []byte{1, 2}}
+	b, err := json.Marshal(o)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"OracleResponse","id":1,"code":"Success","result":"AQI="}`, string(b))
+
+	a, err := NewTransactionAttributeFromJSON(b)
+	assert.Nil(t, err)
+	assert.Equal(t, o.Result, a.(*OracleResponseAttribute).Result)
+	assert.Equal(t, uint64(1), a.(*OracleResponseAttribute).Id)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"OracleResponse","id":1,"code":"NotFound","result":"AQI="}`), o))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"OracleResponse","id":1,"code":"Unknown","result":""}`), o))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"type":"OracleResponse","code":"Success","result":""}`), o))
+	assert.Nil(t, json.Unmarshal([]byte(`{"type":"OracleResponse","id":2,"code":"ContentTypeNotSupported","result":""}`), o))
+	assert.Equal(t, OracleResponseCode(ContentTypeNotSupported), o.Code)
+}
@@ -1,7 +1,10 @@
 package tx
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
@@ -39,7 +42,10 @@ func (c *Signer) GetSize() int {
 		size += crypto.PublicKeySlice(c.AllowedGroups).GetVarSize()
 	}
 	if c.Scopes&WitnessRules != 0 {
-
+		size += helper.GetVarSize(len(c.Rules))
+		for _, r := range c.Rules {
+			size += r.GetSize()
+		}
 	}
 	return size
 }
@@ -120,6 +126,123 @@ func (c *Signer) Serialize(bw *io.BinaryWriter) {
 	}
 }
 
+// MarshalJSON implements the json marshaller interface, the allowed contracts, allowed groups and rules are
+// written only with their scopes like the node, e.g. {"account":"0x...","scopes":"CalledByEntry"}
+func (c *Signer) MarshalJSON() ([]byte, error) {
+	if c.Account == nil {
+		return nil, fmt.Errorf("format error: signer account is nil")
+	}
+	v := struct {
+		Account          string          `json:"account"`
+		Scopes           string          `json:"scopes"`
+		AllowedContracts *[]string       `json:"allowedcontracts,omitempty"`
+		AllowedGroups    *[]string       `json:"allowedgroups,omitempty"`
+		Rules            *[]*WitnessRule `json:"rules,omitempty"`
+	}{
+		Account: "0x" + c.Account.String(),
+		Scopes:  c.Scopes.String(),
+	}
+	if c.Scopes&CustomContracts != 0 {
+		contracts := make([]string, len(c.AllowedContracts))
+		for i, contract := range c.AllowedContracts {
+			contracts[i] = "0x" + contract.String()
+		}
+		v.AllowedContracts = &contracts
+	}
+	if c.Scopes&CustomGroups != 0 {
+		groups := make([]string, len(c.AllowedGroups))
+		for i, group := range c.AllowedGroups {
+			groups[i] = group.String()
+		}
+		v.AllowedGroups = &groups
+	}
+	if c.Scopes&WitnessRules != 0 {
+		rules := c.Rules
+		if rules == nil {
+			rules = []*WitnessRule{}
+		}
+		v.Rules = &rules
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json unmarshaller interface, it validates the signer like Deserialize.
+func (c *Signer) UnmarshalJSON(data []byte) error {
+	v := struct {
+		Account          string         `json:"account"`
+		Scopes           string         `json:"scopes"`
+		AllowedContracts []string       `json:"allowedcontracts"`
+		AllowedGroups    []string       `json:"allowedgroups"`
+		Rules            []*WitnessRule `json:"rules"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	account, err := parseUInt160(v.Account)
+	if err != nil {
+		return err
+	}
+	scopes, err := NewWitnessScopeFromString(v.Scopes)
+	if err != nil {
+		return err
+	}
+	if scopes&Global != 0 && scopes != Global {
+		return fmt.Errorf("invalid witness scopes: %s", scopes.String())
+	}
+	signer := Signer{
+		Account:          account,
+		Scopes:           scopes,
+		AllowedContracts: []*helper.UInt160{},
+		AllowedGroups:    []*crypto.ECPoint{},
+		Rules:            []*WitnessRule{},
+	}
+	if scopes&CustomContracts != 0 {
+		if len(v.AllowedContracts) > MaxSubitems {
+			return fmt.Errorf("format error: too many allowed contracts: %d", len(v.AllowedContracts))
+		}
+		for _, s := range v.AllowedContracts {
+			contract, err := parseUInt160(s)
+			if err != nil {
+				return err
+			}
+			signer.AllowedContracts = append(signer.AllowedContracts, contract)
+		}
+	}
+	if scopes&CustomGroups != 0 {
+		if len(v.AllowedGroups) > MaxSubitems {
+			return fmt.Errorf("format error: too many allowed groups: %d", len(v.AllowedGroups))
+		}
+		for _, s := range v.AllowedGroups {
+			group, err := crypto.NewECPointFromString(s)
+			if err != nil {
+				return fmt.Errorf("format error: invalid allowed group: %s", s)
+			}
+			signer.AllowedGroups = append(signer.AllowedGroups, group)
+		}
+	}
+	if scopes&WitnessRules != 0 {
+		if len(v.Rules) > MaxSubitems {
+			return fmt.Errorf("format error: too many rules: %d", len(v.Rules))
+		}
+		for _, rule := range v.Rules {
+			if rule == nil {
+				return fmt.Errorf("format error: rule is null")
+			}
+			signer.Rules = append(signer.Rules, rule)
+		}
+	}
+	*c = signer
+	return nil
+}
+
+// parseUInt160 parses a script hash in big-endian hex, with or without 0x
+func parseUInt160(s string) (*helper.UInt160, error) {
+	if len(strings.TrimPrefix(s, "0x")) != helper.UINT160SIZE*2 {
+		return nil, fmt.Errorf("format error: invalid script hash: %s", s)
+	}
+	return helper.UInt160FromString(s)
+}
+
 type SignerSlice []*Signer
 
 func (cs SignerSlice) GetVarSize() int {
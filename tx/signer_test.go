@@ -1,6 +1,7 @@
 package tx
 
 import (
+	"encoding/json"
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
@@ -210,3 +211,56 @@ func TestSignerSlice_GetVarSize(t *testing.T) {
 	size := SignerSlice(css).GetVarSize()
 	assert.Equal(t, 1+20+1+1+20, size)
 }
+
+func TestWitnessScope_String(t *testing.T) {
+	assert.Equal(t, "CalledByEntry", CalledByEntry.String())
+	assert.Equal(t, "CalledByEntry, CustomGroups, WitnessRules", (CalledByEntry | CustomGroups | WitnessRules).String())
+	scopes, err := NewWitnessScopeFromString("CalledByEntry, CustomGroups,WitnessRules")
+	assert.Nil(t, err)
+	assert.Equal(t, CalledByEntry|CustomGroups|WitnessRules, scopes)
+	_, err = NewWitnessScopeFromString("CalledByEntry, Entry")
+	assert.NotNil(t, err)
+}
+
+func newTestSigner() *Signer {
+	b := true
+	group, _ := crypto.NewECPointFromString("02a7bc55fe8684e0119768d104ba30795bdcc86619e864add26156723ed185cd62")
+	return &Signer{
+		Account:          helper.UInt160FromBytes(crypto.Hash160([]byte{0x01})),
+		Scopes:           CalledByEntry | CustomContracts | CustomGroups | WitnessRules,
+		AllowedContracts: []*helper.UInt160{GasToken},
+		AllowedGroups:    []*crypto.ECPoint{group},
+		Rules: []*WitnessRule{
+			{Action: Allow, Condition: conditions.NewWitnessCondition(conditions.CalledByContract, NeoToken)},
+			{Action: Deny, Condition: conditions.NewWitnessCondition(conditions.Not, conditions.NewWitnessCondition(conditions.Boolean, &b))},
+		},
+	}
+}
+
+func TestSigner_MarshalJSON(t *testing.T) {
+	s := newTestSigner()
+	b, err := json.Marshal(s)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"account":"0x820944cfdc70976602d71b0091445eedbc661bc5",`+
+		`"scopes":"CalledByEntry, CustomContracts, CustomGroups, WitnessRules",`+
+		`"allowedcontracts":["0xd2a4cff31913016155e38e474a2c06d08be276cf"],`+
+		`"allowedgroups":["02a7bc55fe8684e0119768d104ba30795bdcc86619e864add26156723ed185cd62"],`+
+		`"rules":[{"action":"Allow","condition":{"type":"CalledByContract","hash":"0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5"}},`+
+		`{"action":"Deny","condition":{"type":"Not","expression":{"type":"Boolean","expression":true}}}]}`, string(b))
+
+	s2 := &Signer{}
+	assert.Nil(t, json.Unmarshal(b, s2))
+	bbw := io.NewBufBinaryWriter()
+	s.Serialize(bbw.BinaryWriter)
+	expected := bbw.Bytes()
+	s2.Serialize(bbw.BinaryWriter)
+	assert.Equal(t, expected, bbw.Bytes())
+	assert.Equal(t, len(expected), s2.GetSize())
+
+	b, _ = json.Marshal(NewSigner(GasToken, CalledByEntry))
+	assert.Equal(t, `{"account":"0xd2a4cff31913016155e38e474a2c06d08be276cf","scopes":"CalledByEntry"}`, string(b))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"account":"0x01","scopes":"CalledByEntry"}`), s2))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"account":"0xd2a4cff31913016155e38e474a2c06d08be276cf","scopes":"Global, CalledByEntry"}`), s2))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"account":"0xd2a4cff31913016155e38e474a2c06d08be276cf","scopes":"WitnessRules","rules":[{"action":"Allow"}]}`), s2))
+}
@@ -2,7 +2,12 @@ package tx
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
 )
@@ -10,7 +15,7 @@ import (
 const (
 	TransactionVersion          uint8  = 0
 	MaxTransactionSize                 = 102400
-	MaxValidUntilBlockIncrement uint32 = 5760 // 24 hours
+	MaxValidUntilBlockIncrement uint32 = 5760 // 24 hours, the default of helper.ProtocolSettings
 	MaxTransactionAttributes           = 16   // Maximum number of attributes that can be contained within a transaction
 	MaxSigners                         = 16   // Maximum number of cosigners that can be contained within a transaction
 )
@@ -19,6 +24,7 @@ const NeoTokenId = "0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5"
 const GasTokenId = "0xd2a4cff31913016155e38e474a2c06d08be276cf"
 
 const GasFactor = 100000000
+// ExecFeeFactor and FeePerByte are the defaults of the Policy contract, WalletHelper takes them from helper.ProtocolSettings
 const ExecFeeFactor = 30
 const FeePerByte = 1000
 const ECDsaVerifyPrice = 1 << 15
@@ -253,16 +259,17 @@ func (tx *Transaction) DeserializeUnsigned(br *io.BinaryReader) {
 func deserializeAttributes(br *io.BinaryReader, maxCount int) []ITransactionAttribute {
 	count := int(br.ReadVarUIntWithMaxLimit(uint64(maxCount)))
 	result := make([]ITransactionAttribute, count)
-	m := make(map[TransactionAttributeType]ITransactionAttribute)
+	m := make(map[TransactionAttributeType]bool)
 	for i := 0; i < count; i++ {
 		attribute := DeserializeFrom(br)
 		if attribute == nil {
 			return nil
 		}
-		if !attribute.AllowMultiple() && m[attribute.GetAttributeType()] == attribute {
+		if !attribute.AllowMultiple() && m[attribute.GetAttributeType()] {
 			br.Err = fmt.Errorf("format error: duplicate attribute")
 			return nil
 		}
+		m[attribute.GetAttributeType()] = true
 		result[i] = attribute
 	}
 	return result
@@ -342,3 +349,174 @@ func (tx *Transaction) GetScriptHashesForVerifying() []*helper.UInt160 {
 	}
 	return result
 }
+
+// ValidateAttributes checks the attributes like the node does when decoding the transaction,
+// and rejects the attributes which make the transaction never valid
+func (tx *Transaction) ValidateAttributes() error {
+	if len(tx.attributes) > MaxTransactionAttributes-len(tx.signers) {
+		return fmt.Errorf("too many attributes: %d", len(tx.attributes))
+	}
+	m := make(map[TransactionAttributeType]bool)
+	for _, attribute := range tx.attributes {
+		if attribute == nil || !attribute.GetAttributeType().IsDefined() {
+			return fmt.Errorf("invalid attribute")
+		}
+		if !attribute.AllowMultiple() && m[attribute.GetAttributeType()] {
+			return fmt.Errorf("duplicate attribute: %s", attribute.GetAttributeType().String())
+		}
+		m[attribute.GetAttributeType()] = true
+		switch a := attribute.(type) {
+		case *NotValidBeforeAttribute:
+			// valid in the block after height h only if a.Height <= h < validUntilBlock
+			if a.Height >= tx.validUntilBlock {
+				return fmt.Errorf("NotValidBefore %d should be lower than ValidUntilBlock %d", a.Height, tx.validUntilBlock)
+			}
+		case *ConflictsAttribute:
+			if a.Hash == nil {
+				return fmt.Errorf("Conflicts hash is nil")
+			}
+		case *NotaryAssistedAttribute:
+			notary := false
+			for _, signer := range tx.signers {
+				notary = notary || signer.Account.Equals(NotaryContract)
+			}
+			if !notary {
+				return fmt.Errorf("NotaryAssisted requires the Notary contract as a signer")
+			}
+		}
+	}
+	return nil
+}
+
+// transactionJson is the json of a transaction in the verbose getrawtransaction of the node, without the fields
+// of the block
+type transactionJson struct {
+	Hash            string                  `json:"hash"`
+	Size            int                     `json:"size"`
+	Version         uint8                   `json:"version"`
+	Nonce           uint32                  `json:"nonce"`
+	Sender          string                  `json:"sender"`
+	SysFee          string                  `json:"sysfee"`
+	NetFee          string                  `json:"netfee"`
+	ValidUntilBlock uint32                  `json:"validuntilblock"`
+	Signers         []*Signer               `json:"signers"`
+	Attributes      []ITransactionAttribute `json:"attributes"`
+	Script          string                  `json:"script"`
+	Witnesses       []*Witness              `json:"witnesses"`
+}
+
+// MarshalJSON implements the json marshaller interface, the sender is the address of the first signer
+// with helper.DefaultAddressVersion
+func (tx *Transaction) MarshalJSON() ([]byte, error) {
+	if len(tx.signers) == 0 {
+		return nil, fmt.Errorf("format error: signer count is zero")
+	}
+	v := transactionJson{
+		Hash:            "0x" + tx.GetHash().String(),
+		Size:            tx.GetSize(),
+		Version:         tx.version,
+		Nonce:           tx.nonce,
+		Sender:          crypto.ScriptHashToAddress(tx.GetSender(), helper.DefaultAddressVersion),
+		SysFee:          strconv.FormatInt(tx.sysfee, 10),
+		NetFee:          strconv.FormatInt(tx.netfee, 10),
+		ValidUntilBlock: tx.validUntilBlock,
+		Signers:         tx.signers,
+		Attributes:      tx.attributes,
+		Script:          crypto.Base64Encode(tx.script),
+		Witnesses:       tx.witnesses,
+	}
+	if v.Attributes == nil {
+		v.Attributes = []ITransactionAttribute{}
+	}
+	if v.Witnesses == nil {
+		v.Witnesses = []*Witness{}
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json unmarshaller interface, it validates the transaction like Deserialize.
+// The hash is checked if it is not empty, the size and the sender are derived from the other fields.
+func (tx *Transaction) UnmarshalJSON(data []byte) error {
+	v := struct {
+		transactionJson
+		Attributes []json.RawMessage `json:"attributes"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Version > TransactionVersion {
+		return fmt.Errorf("format error: version > 0")
+	}
+	sysfee, err := strconv.ParseInt(v.SysFee, 10, 64)
+	if err != nil || sysfee < 0 {
+		return fmt.Errorf("format error: invalid sysfee: %s", v.SysFee)
+	}
+	netfee, err := strconv.ParseInt(v.NetFee, 10, 64)
+	if err != nil || netfee < 0 {
+		return fmt.Errorf("format error: invalid netfee: %s", v.NetFee)
+	}
+	if sysfee+netfee < sysfee {
+		return fmt.Errorf("format error: overflow")
+	}
+	if len(v.Signers) == 0 {
+		return fmt.Errorf("format error: signer count is zero")
+	}
+	if len(v.Signers) > MaxTransactionAttributes {
+		return fmt.Errorf("format error: too many signers: %d", len(v.Signers))
+	}
+	accounts := make(map[helper.UInt160]bool)
+	for _, signer := range v.Signers {
+		if signer == nil {
+			return fmt.Errorf("format error: signer is null")
+		}
+		if accounts[*signer.Account] {
+			return fmt.Errorf("format error: duplicate signer")
+		}
+		accounts[*signer.Account] = true
+	}
+	if len(v.Attributes) > MaxTransactionAttributes-len(v.Signers) {
+		return fmt.Errorf("format error: too many attributes: %d", len(v.Attributes))
+	}
+	attributes := make([]ITransactionAttribute, len(v.Attributes))
+	types := make(map[TransactionAttributeType]bool)
+	for i := range v.Attributes {
+		if attributes[i], err = NewTransactionAttributeFromJSON(v.Attributes[i]); err != nil {
+			return err
+		}
+		if !attributes[i].AllowMultiple() && types[attributes[i].GetAttributeType()] {
+			return fmt.Errorf("format error: duplicate attribute")
+		}
+		types[attributes[i].GetAttributeType()] = true
+	}
+	script, err := crypto.Base64Decode(v.Script)
+	if err != nil || len(script) > 65535 {
+		return fmt.Errorf("format error: invalid script: %s", v.Script)
+	}
+	if len(script) == 0 {
+		return fmt.Errorf("format error: script is empty")
+	}
+	for _, witness := range v.Witnesses {
+		if witness == nil {
+			return fmt.Errorf("format error: witness is null")
+		}
+	}
+	if v.Witnesses == nil {
+		v.Witnesses = []*Witness{}
+	}
+	t := Transaction{
+		version:         v.Version,
+		nonce:           v.Nonce,
+		sysfee:          sysfee,
+		netfee:          netfee,
+		validUntilBlock: v.ValidUntilBlock,
+		signers:         v.Signers,
+		attributes:      attributes,
+		script:          script,
+		witnesses:       v.Witnesses,
+	}
+	if v.Hash != "" && strings.ToLower(strings.TrimPrefix(v.Hash, "0x")) != t.GetHash().String() {
+		return fmt.Errorf("wrong transaction hash, expected: %s, got: 0x%s", v.Hash, t.GetHash().String())
+	}
+	*tx = t
+	return nil
+}
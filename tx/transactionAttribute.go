@@ -1,7 +1,9 @@
 package tx
 
 import (
+	"encoding/json"
 	"fmt"
+
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
 )
@@ -25,6 +27,12 @@ func CreateTransactionAttribute(attributeType TransactionAttributeType) ITransac
 	case 0x11:
 		a, _ := NewOracleResponseAttribute()
 		return a
+	case 0x20:
+		return &NotValidBeforeAttribute{}
+	case 0x21:
+		return &ConflictsAttribute{}
+	case 0x22:
+		return &NotaryAssistedAttribute{}
 	default:
 		return nil
 	}
@@ -41,6 +49,30 @@ func DeserializeFrom(br *io.BinaryReader) ITransactionAttribute {
 	return a
 }
 
+// NewTransactionAttributeFromJSON decodes an attribute in the json format of the node by its type,
+// e.g. {"type":"NotValidBefore","height":100}
+func NewTransactionAttributeFromJSON(data []byte) (ITransactionAttribute, error) {
+	v := struct {
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	t, err := NewTransactionAttributeTypeFromString(v.Type)
+	if err != nil {
+		return nil, err
+	}
+	a := CreateTransactionAttribute(t)
+	u, ok := a.(json.Unmarshaler)
+	if !ok {
+		return nil, fmt.Errorf("format error: attribute %s can't be decoded from json", v.Type)
+	}
+	if err = u.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
 type TransactionAttributeSlice []ITransactionAttribute
 
 func (ts TransactionAttributeSlice) GetVarSize() int {
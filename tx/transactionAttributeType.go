@@ -1,11 +1,16 @@
 package tx
 
+import "fmt"
+
 // Transaction attribute type
 type TransactionAttributeType byte
 
 const (
 	HighPriority   TransactionAttributeType = 0x01 //
 	OracleResponse TransactionAttributeType = 0x11
+	NotValidBefore TransactionAttributeType = 0x20
+	Conflicts      TransactionAttributeType = 0x21
+	NotaryAssisted TransactionAttributeType = 0x22
 )
 
 func (u TransactionAttributeType) String() string {
@@ -15,6 +20,12 @@ func (u TransactionAttributeType) String() string {
 		return "HighPriority"
 	case 0x11:
 		return "OracleResponse"
+	case 0x20:
+		return "NotValidBefore"
+	case 0x21:
+		return "Conflicts"
+	case 0x22:
+		return "NotaryAssisted"
 	default:
 		return "Not Defined"
 	}
@@ -26,3 +37,13 @@ func (u TransactionAttributeType) IsDefined() bool {
 	}
 	return false
 }
+
+// NewTransactionAttributeTypeFromString parses the name of a type, e.g. "Conflicts"
+func NewTransactionAttributeTypeFromString(s string) (TransactionAttributeType, error) {
+	for _, t := range []TransactionAttributeType{HighPriority, OracleResponse, NotValidBefore, Conflicts, NotaryAssisted} {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("format error: invalid attribute type: %s", s)
+}
@@ -1,10 +1,12 @@
 package tx
 
 import (
+	"encoding/json"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
 	"github.com/joeqian10/neo3-gogogo/sc"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -220,3 +222,119 @@ func TestTransaction_SerializeWitnesses(t *testing.T) {
 
 	assert.Equal(t, expected, helper.BytesToHex(b))
 }
+
+func TestTransaction_DeserializeAttributes(t *testing.T) {
+	hash, _ := helper.UInt256FromString(conflictsHash)
+	for _, c := range []struct {
+		attributes []ITransactionAttribute
+		ok         bool
+	}{
+		{[]ITransactionAttribute{NewConflictsAttribute(hash), NewConflictsAttribute(helper.UInt256Zero)}, true},
+		{[]ITransactionAttribute{NewNotValidBeforeAttribute(1), NewNotValidBeforeAttribute(2)}, false},
+	} {
+		bbw := io.NewBufBinaryWriter()
+		bbw.WriteVarUInt(uint64(len(c.attributes)))
+		for _, a := range c.attributes {
+			a.Serialize(bbw.BinaryWriter)
+		}
+		br := io.NewBinaryReaderFromBuf(bbw.Bytes())
+		deserializeAttributes(br, MaxTransactionAttributes)
+		assert.Equal(t, c.ok, br.Err == nil)
+	}
+}
+
+func TestTransaction_ValidateAttributes(t *testing.T) {
+	hash, _ := helper.UInt256FromString(conflictsHash)
+	x := Transaction{}
+	x.SetValidUntilBlock(100)
+	x.SetSigners([]*Signer{{Account: helper.UInt160Zero, Scopes: CalledByEntry}})
+	x.SetAttributes([]ITransactionAttribute{NewNotValidBeforeAttribute(99), NewConflictsAttribute(hash), NewConflictsAttribute(helper.UInt256Zero)})
+	assert.Nil(t, x.ValidateAttributes())
+
+	x.SetAttributes([]ITransactionAttribute{NewNotValidBeforeAttribute(100)})
+	assert.NotNil(t, x.ValidateAttributes())
+
+	x.SetAttributes([]ITransactionAttribute{&HighPriorityAttribute{}, &HighPriorityAttribute{}})
+	assert.NotNil(t, x.ValidateAttributes())
+
+	x.SetAttributes([]ITransactionAttribute{&ConflictsAttribute{}})
+	assert.NotNil(t, x.ValidateAttributes())
+}
+
+func newTestTransaction() *Transaction {
+	hash, _ := helper.UInt256FromString(conflictsHash)
+	x := NewTransaction()
+	x.SetNonce(1072419131)
+	x.SetSystemFee(9977780)
+	x.SetNetworkFee(1230610)
+	x.SetValidUntilBlock(2105487)
+	x.SetSigners([]*Signer{newTestSigner(), NewSigner(NotaryContract, None)})
+	x.SetAttributes([]ITransactionAttribute{
+		&HighPriorityAttribute{},
+		&OracleResponseAttribute{Id: 7, Code: Success, Result: []byte("result")},
+		NewNotValidBeforeAttribute(2105000),
+		NewConflictsAttribute(hash),
+		NewNotaryAssistedAttribute(2),
+	})
+	x.SetScript([]byte{byte(sc.PUSH1), byte(sc.RET)})
+	x.SetWitnesses([]*Witness{
+		{InvocationScript: []byte{0x0c, 0x01, 0x02}, VerificationScript: []byte{0x11}},
+		{InvocationScript: []byte{}, VerificationScript: []byte{}},
+	})
+	return x
+}
+
+func TestTransaction_MarshalJSON(t *testing.T) {
+	x := newTestTransaction()
+	b, err := json.Marshal(x)
+	assert.Nil(t, err)
+	v := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(b, &v))
+	assert.Equal(t, "0x"+x.GetHash().String(), v["hash"])
+	assert.Equal(t, float64(x.GetSize()), v["size"])
+	assert.Equal(t, "NdtB8RXRmJ7Nhw1FPTm7E6HoDZGnDw37nf", v["sender"])
+	assert.Equal(t, "9977780", v["sysfee"])
+	assert.Equal(t, "1230610", v["netfee"])
+	assert.Equal(t, float64(2105487), v["validuntilblock"])
+	assert.Equal(t, "EUA=", v["script"])
+	assert.Equal(t, 5, len(v["attributes"].([]interface{})))
+	assert.Equal(t, map[string]interface{}{"account": "0xc1e14f19c3e60d0b9244d06dd7ba9b113135ec3b", "scopes": "None"},
+		v["signers"].([]interface{})[1])
+
+	x2 := &Transaction{}
+	assert.Nil(t, json.Unmarshal(b, x2))
+	assert.Equal(t, x.ToByteArray(), x2.ToByteArray())
+	assert.Equal(t, x.GetHash(), x2.GetHash())
+	b2, _ := json.Marshal(x2)
+	assert.Equal(t, string(b), string(b2))
+
+	// the hash is checked, and it can be omitted
+	wrong := strings.Replace(string(b), `"nonce":1072419131`, `"nonce":1`, 1)
+	assert.NotNil(t, json.Unmarshal([]byte(wrong), x2))
+	delete(v, "hash")
+	b, _ = json.Marshal(v)
+	assert.Nil(t, json.Unmarshal(b, x2))
+	assert.Equal(t, x.GetHash(), x2.GetHash())
+}
+
+func TestTransaction_UnmarshalJSON(t *testing.T) {
+	s := `{"version":0,"nonce":1,"sysfee":"0","netfee":"0","validuntilblock":1,` +
+		`"signers":[{"account":"0xd2a4cff31913016155e38e474a2c06d08be276cf","scopes":"CalledByEntry"}],` +
+		`"attributes":[],"script":"EUA=","witnesses":[]}`
+	x := &Transaction{}
+	assert.Nil(t, json.Unmarshal([]byte(s), x))
+	assert.Equal(t, GasToken, x.GetSender())
+
+	for _, c := range []struct{ old, new string }{
+		{`"version":0`, `"version":1`},
+		{`"sysfee":"0"`, `"sysfee":"-1"`},
+		{`"netfee":"0"`, `"netfee":"1.5"`},
+		{`"script":"EUA="`, `"script":""`},
+		{`"attributes":[]`, `"attributes":[{"type":"HighPriority"},{"type":"HighPriority"}]`},
+		{`"attributes":[]`, `"attributes":[{"type":"Usage"}]`},
+		{`"scopes":"CalledByEntry"}]`, `"scopes":"CalledByEntry"},{"account":"0xd2a4cff31913016155e38e474a2c06d08be276cf","scopes":"None"}]`},
+		{`"signers":[{"account":"0xd2a4cff31913016155e38e474a2c06d08be276cf","scopes":"CalledByEntry"}]`, `"signers":[]`},
+	} {
+		assert.NotNil(t, json.Unmarshal([]byte(strings.Replace(s, c.old, c.new, 1)), x), c.new)
+	}
+}
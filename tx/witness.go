@@ -1,7 +1,6 @@
 package tx
 
 import (
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -53,16 +52,38 @@ func (w *Witness) Serialize(bw *io.BinaryWriter) {
 	bw.WriteVarBytes(w.VerificationScript)
 }
 
-// MarshalJSON implements the json marshaller interface.
+// MarshalJSON implements the json marshaller interface, the scripts are in base64 like the node,
+// e.g. {"invocation":"DEA...","verification":"DCE..."}
 func (w *Witness) MarshalJSON() ([]byte, error) {
 	data := map[string]string{
-		"invocation":   hex.EncodeToString(w.InvocationScript),
-		"verification": hex.EncodeToString(w.VerificationScript),
+		"invocation":   crypto.Base64Encode(w.InvocationScript),
+		"verification": crypto.Base64Encode(w.VerificationScript),
 	}
 
 	return json.Marshal(data)
 }
 
+// UnmarshalJSON implements the json unmarshaller interface.
+func (w *Witness) UnmarshalJSON(data []byte) error {
+	v := struct {
+		Invocation   string `json:"invocation"`
+		Verification string `json:"verification"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	invocation, err := crypto.Base64Decode(v.Invocation)
+	if err != nil || len(invocation) > MaxInvocationScript {
+		return fmt.Errorf("format error: invalid invocation script: %s", v.Invocation)
+	}
+	verification, err := crypto.Base64Decode(v.Verification)
+	if err != nil || len(verification) > MaxVerificationScript {
+		return fmt.Errorf("format error: invalid verification script: %s", v.Verification)
+	}
+	w.InvocationScript, w.VerificationScript, w._scriptHash = invocation, verification, nil
+	return nil
+}
+
 // CreateWitness with invocationScript and verificationScript
 func CreateWitness(invocationScript []byte, verificationScript []byte) (*Witness, error) {
 	if len(verificationScript) == 0 {
@@ -1,6 +1,9 @@
 package tx
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/joeqian10/neo3-gogogo/io"
 	"github.com/joeqian10/neo3-gogogo/tx/conditions"
 )
@@ -20,6 +23,10 @@ func (this *WitnessRule) Deserialize(br *io.BinaryReader) {
 		return
 	}
 	this.Action = WitnessRuleAction(a)
+	if this.Action != Deny && this.Action != Allow {
+		br.Err = fmt.Errorf("format error: invalid witness rule action %d", a)
+		return
+	}
 	this.Condition = new(conditions.WitnessCondition)
 	this.Condition.Deserialize(br)
 }
@@ -28,3 +35,37 @@ func (this *WitnessRule) Serialize(bw *io.BinaryWriter) {
 	bw.WriteLE(this.Action)
 	this.Condition.Serialize(bw)
 }
+
+// MarshalJSON implements the json marshaller interface, e.g. {"action":"Allow","condition":{"type":"CalledByEntry"}}
+func (this *WitnessRule) MarshalJSON() ([]byte, error) {
+	if this.Condition == nil {
+		return nil, fmt.Errorf("format error: witness rule condition is nil")
+	}
+	return json.Marshal(struct {
+		Action    string                       `json:"action"`
+		Condition *conditions.WitnessCondition `json:"condition"`
+	}{
+		Action:    this.Action.String(),
+		Condition: this.Condition,
+	})
+}
+
+// UnmarshalJSON implements the json unmarshaller interface.
+func (this *WitnessRule) UnmarshalJSON(data []byte) error {
+	v := struct {
+		Action    string                       `json:"action"`
+		Condition *conditions.WitnessCondition `json:"condition"`
+	}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	action, err := NewWitnessRuleActionFromString(v.Action)
+	if err != nil {
+		return err
+	}
+	if v.Condition == nil {
+		return fmt.Errorf("format error: witness rule condition is missing")
+	}
+	this.Action, this.Condition = action, v.Condition
+	return nil
+}
@@ -1,5 +1,7 @@
 package tx
 
+import "fmt"
+
 type WitnessRuleAction byte
 
 const (
@@ -22,3 +24,15 @@ func (this WitnessRuleAction) String() string {
 func (this WitnessRuleAction) GetSize() int {
 	return 1
 }
+
+// NewWitnessRuleActionFromString parses "Deny" or "Allow"
+func NewWitnessRuleActionFromString(s string) (WitnessRuleAction, error) {
+	switch s {
+	case Deny.String():
+		return Deny, nil
+	case Allow.String():
+		return Allow, nil
+	default:
+		return Deny, fmt.Errorf("invalid witness rule action: %s", s)
+	}
+}
@@ -1,5 +1,11 @@
 package tx
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type WitnessScope byte
 
 const (
@@ -51,6 +57,36 @@ func (w WitnessScope) String() string {
 	case 0x80:
 		return "Global"
 	default:
-		return string(b)
+		// combined flags are separated by commas like the node, e.g. "CalledByEntry, CustomContracts"
+		names := []string{}
+		for _, flag := range []WitnessScope{CalledByEntry, CustomContracts, CustomGroups, WitnessRules, Global} {
+			if w&flag != 0 {
+				names = append(names, flag.String())
+				w &^= flag
+			}
+		}
+		if w != 0 {
+			return strconv.Itoa(int(b))
+		}
+		return strings.Join(names, ", ")
+	}
+}
+
+// NewWitnessScopeFromString parses the scopes separated by commas, e.g. "CalledByEntry, CustomContracts"
+func NewWitnessScopeFromString(s string) (WitnessScope, error) {
+	scopes := None
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, flag := range []WitnessScope{None, CalledByEntry, CustomContracts, CustomGroups, WitnessRules, Global} {
+			if flag.String() == name {
+				scopes, found = scopes|flag, true
+				break
+			}
+		}
+		if !found {
+			return None, fmt.Errorf("invalid witness scope: %s", name)
+		}
 	}
+	return scopes, nil
 }
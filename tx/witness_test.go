@@ -1,6 +1,7 @@
 package tx
 
 import (
+	"encoding/json"
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
@@ -60,6 +61,17 @@ func TestCreateSignatureWitness(t *testing.T) {
 	assert.Equal(t, 40, len(witness.VerificationScript))
 }
 
+func TestCreateSignatureWitness_Deterministic(t *testing.T) {
+	msg := []byte("sample")
+	pair, _ := keys.NewKeyPairFromWIF(keys.KeyCases[0].Wif)
+	pair.Options = keys.SignatureOptions{Deterministic: true, LowS: true}
+	w1, err := CreateSignatureWitness(msg, pair)
+	assert.Nil(t, err)
+	w2, _ := CreateSignatureWitness(msg, pair)
+	assert.Equal(t, w1.InvocationScript, w2.InvocationScript)
+	assert.True(t, keys.VerifySignatureStrict(msg, w1.InvocationScript[2:], pair.PublicKey))
+}
+
 func TestCreateMultiSignatureWitness(t *testing.T) {
 	msg := []byte("sample")
 	pairs := make([]*keys.KeyPair, caseLen)
@@ -114,3 +126,16 @@ func TestVerifyMultiSignatureWitness2(t *testing.T) {
 	b := VerifyMultiSignatureWitness(msg, witness)
 	assert.Equal(t, true, b)
 }
+
+func TestWitness_MarshalJSON(t *testing.T) {
+	w := &Witness{InvocationScript: []byte{0x0c, 0x01, 0x02}, VerificationScript: []byte{0x11}}
+	b, err := json.Marshal(w)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"invocation":"DAEC","verification":"EQ=="}`, string(b))
+
+	w2 := &Witness{}
+	assert.Nil(t, json.Unmarshal(b, w2))
+	assert.Equal(t, w.InvocationScript, w2.InvocationScript)
+	assert.Equal(t, w.VerificationScript, w2.VerificationScript)
+	assert.NotNil(t, json.Unmarshal([]byte(`{"invocation":"!","verification":""}`), w2))
+}
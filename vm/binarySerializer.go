@@ -0,0 +1,175 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+)
+
+// SerializeStackItem encodes the item in the format of BinarySerializer, which is used by StdLib.serialize, e.g.
+// for the values in the storage of contracts. The item can't have more than MaxStackSize items or be larger than
+// MaxItemSize after serialization.
+func SerializeStackItem(item StackItem) ([]byte, error) {
+	buff := new(bytes.Buffer)
+	writer := io.NewBinaryWriterFromIO(buff)
+	maxItems := MaxStackSize
+	unserialized := []StackItem{item}
+	for len(unserialized) > 0 {
+		if maxItems--; maxItems < 0 {
+			return nil, fmt.Errorf("too many items to serialize")
+		}
+		item = unserialized[len(unserialized)-1]
+		unserialized = unserialized[:len(unserialized)-1]
+		writer.WriteLE(byte(item.Type))
+		switch item.Type {
+		case Any:
+		case Boolean, Integer, ByteString, Buffer:
+			b, err := item.GetBytes()
+			if err != nil {
+				return nil, err
+			}
+			if item.Type == Boolean {
+				writer.WriteLE(b)
+			} else {
+				writer.WriteVarBytes(b)
+			}
+		case Array, Struct:
+			items, err := item.GetItems()
+			if err != nil {
+				return nil, err
+			}
+			writer.WriteVarUInt(uint64(len(items)))
+			for i := len(items) - 1; i >= 0; i-- {
+				unserialized = append(unserialized, items[i])
+			}
+		case Map:
+			pairs, err := item.GetPairs()
+			if err != nil {
+				return nil, err
+			}
+			writer.WriteVarUInt(uint64(len(pairs)))
+			for i := len(pairs) - 1; i >= 0; i-- {
+				unserialized = append(unserialized, pairs[i].Value, pairs[i].Key)
+			}
+		default:
+			return nil, fmt.Errorf("%s item can't be serialized", item.Type)
+		}
+		if writer.Err != nil {
+			return nil, writer.Err
+		}
+		if buff.Len() > MaxItemSize {
+			return nil, fmt.Errorf("serialized item is larger than %d bytes", MaxItemSize)
+		}
+	}
+	return buff.Bytes(), nil
+}
+
+// DeserializeStackItem decodes the format of BinarySerializer with the limits of StdLib.deserialize
+func DeserializeStackItem(data []byte) (StackItem, error) {
+	maxSize := MaxItemSize
+	if len(data) < maxSize {
+		maxSize = len(data)
+	}
+	d := &deserializer{reader: io.NewBinaryReaderFromBuf(data), maxSize: maxSize}
+	item, err := d.read()
+	if err != nil {
+		return StackItem{}, err
+	}
+	if d.reader.Err != nil {
+		return StackItem{}, d.reader.Err
+	}
+	return item, nil
+}
+
+type deserializer struct {
+	reader  *io.BinaryReader
+	maxSize int
+	// count is the number of the deserialized items, it can't be more than MaxStackSize
+	count int
+}
+
+func (d *deserializer) read() (StackItem, error) {
+	if d.count++; d.count > MaxStackSize {
+		return StackItem{}, fmt.Errorf("too many items to deserialize")
+	}
+	t := StackItemType(d.reader.ReadOneByte())
+	if d.reader.Err != nil {
+		return StackItem{}, d.reader.Err
+	}
+	switch t {
+	case Any:
+		return NewNullStackItem(), nil
+	case Boolean:
+		switch b := d.reader.ReadOneByte(); b {
+		case 0, 1:
+			return NewBooleanStackItem(b == 1), d.reader.Err
+		default:
+			return StackItem{}, fmt.Errorf("invalid boolean 0x%02x", b)
+		}
+	case Integer:
+		b := d.reader.ReadVarBytesWithMaxLimit(MaxIntegerSize)
+		return NewIntegerStackItem(helper.BigIntFromNeoBytes(b)), d.reader.Err
+	case ByteString, Buffer:
+		b := d.reader.ReadVarBytesWithMaxLimit(d.maxSize)
+		return StackItem{Type: t, Value: b}, d.reader.Err
+	case Array, Struct:
+		count := d.reader.ReadVarUIntWithMaxLimit(MaxStackSize)
+		if d.reader.Err != nil {
+			return StackItem{}, d.reader.Err
+		}
+		items := make([]StackItem, 0, count)
+		for i := uint64(0); i < count; i++ {
+			item, err := d.read()
+			if err != nil {
+				return StackItem{}, err
+			}
+			items = append(items, item)
+		}
+		return StackItem{Type: t, Value: items}, nil
+	case Map:
+		count := d.reader.ReadVarUIntWithMaxLimit(MaxStackSize)
+		if d.reader.Err != nil {
+			return StackItem{}, d.reader.Err
+		}
+		pairs := make([]StackItemPair, 0, count)
+		for i := uint64(0); i < count; i++ {
+			key, err := d.read()
+			if err != nil {
+				return StackItem{}, err
+			}
+			value, err := d.read()
+			if err != nil {
+				return StackItem{}, err
+			}
+			if pairs, err = setPair(pairs, key, value); err != nil {
+				return StackItem{}, err
+			}
+		}
+		return NewMapStackItem(pairs), nil
+	default:
+		return StackItem{}, fmt.Errorf("invalid stack item type 0x%02x", byte(t))
+	}
+}
+
+// setPair sets the value of the key like the Map of NeoVM, the value of an existing key is replaced in place
+func setPair(pairs []StackItemPair, key, value StackItem) ([]StackItemPair, error) {
+	if !key.isPrimitive() {
+		return nil, fmt.Errorf("%s item can't be a key of a map", key.Type)
+	}
+	b, err := key.GetBytes()
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > MaxKeySize {
+		return nil, fmt.Errorf("key of %d bytes is too large", len(b))
+	}
+	for i := range pairs {
+		if pairs[i].Key.equalKey(key) {
+			pairs[i].Value = value
+			return pairs, nil
+		}
+	}
+	return append(pairs, StackItemPair{Key: key, Value: value}), nil
+}
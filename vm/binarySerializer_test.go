@@ -0,0 +1,87 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerializeStackItem(t *testing.T) {
+	item := NewArrayStackItem([]StackItem{
+		NewIntegerStackItem(big.NewInt(100)),
+		NewIntegerStackItem(big.NewInt(0)),
+		NewByteStringStackItem([]byte("a")),
+		NewMapStackItem([]StackItemPair{
+			{Key: NewByteStringStackItem([]byte("a")), Value: NewBooleanStackItem(true)},
+		}),
+		NewNullStackItem(),
+		NewStructStackItem([]StackItem{NewIntegerStackItem(big.NewInt(-1))}),
+		NewBufferStackItem([]byte{0x01}),
+	})
+	b, err := SerializeStackItem(item)
+	assert.Nil(t, err)
+	assert.Equal(t, "4007"+"210164"+"2100"+"280161"+"4801"+"280161"+"2001"+"00"+"4101"+"2101ff"+"300101", helper.BytesToHex(b))
+
+	decoded, err := DeserializeStackItem(b)
+	assert.Nil(t, err)
+	items, err := decoded.GetItems()
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(items))
+	i, err := items[0].GetInteger()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), i.Int64())
+	s, err := items[2].GetString()
+	assert.Nil(t, err)
+	assert.Equal(t, "a", s)
+	pairs, err := items[3].GetPairs()
+	assert.Nil(t, err)
+	assert.Equal(t, true, pairs[0].Value.Value)
+	assert.True(t, items[4].IsNull())
+	assert.Equal(t, Struct, items[5].Type)
+	assert.Equal(t, Buffer, items[6].Type)
+
+	b2, err := SerializeStackItem(decoded)
+	assert.Nil(t, err)
+	assert.Equal(t, b, b2)
+}
+
+func TestSerializeStackItem_Limits(t *testing.T) {
+	items := make([]StackItem, MaxStackSize)
+	for i := range items {
+		items[i] = NewNullStackItem()
+	}
+	_, err := SerializeStackItem(NewArrayStackItem(items))
+	assert.NotNil(t, err)
+
+	_, err = SerializeStackItem(NewByteStringStackItem(make([]byte, MaxItemSize)))
+	assert.NotNil(t, err)
+
+	_, err = SerializeStackItem(StackItem{Type: InteropInterface})
+	assert.NotNil(t, err)
+}
+
+func TestDeserializeStackItem_Invalid(t *testing.T) {
+	cases := []string{
+		"",                                     // empty
+		"2002",                                 // invalid boolean
+		"2121" + "00",                          // integer longer than 32 bytes
+		"280561",                               // truncated byte string
+		"4802" + "4000" + "00" + "2100" + "00", // array as a key
+		"ff",                                   // unknown type
+		"40fd0110",                             // too many items
+	}
+	for _, c := range cases {
+		_, err := DeserializeStackItem(helper.HexToBytes(c))
+		assert.NotNil(t, err, c)
+	}
+
+	// the value of a duplicate key is replaced
+	item, err := DeserializeStackItem(helper.HexToBytes("4802" + "210101" + "2100" + "210101" + "210101"))
+	assert.Nil(t, err)
+	pairs, _ := item.GetPairs()
+	assert.Equal(t, 1, len(pairs))
+	i, _ := pairs[0].Value.GetInteger()
+	assert.Equal(t, int64(1), i.Int64())
+}
@@ -0,0 +1,232 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"unicode/utf16"
+)
+
+const (
+	// MaxJsonDepth is the max nesting of arrays and objects in StdLib.jsonDeserialize
+	MaxJsonDepth = 10
+	// maxSafeInteger is the max integer of json, 2^53 - 1
+	maxSafeInteger = 1<<53 - 1
+)
+
+// SerializeStackItemToJson encodes the item like StdLib.jsonSerialize. Integer is a number in the safe range of
+// json, ByteString and Buffer are UTF-8 strings, Map is an object with ByteString keys and null is null. Pointer,
+// InteropInterface and Struct can't be serialized.
+func SerializeStackItemToJson(item StackItem) ([]byte, error) {
+	buff := new(bytes.Buffer)
+	if err := writeJson(buff, item); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
+
+func writeJson(buff *bytes.Buffer, item StackItem) error {
+	switch item.Type {
+	case Any:
+		buff.WriteString("null")
+	case Boolean:
+		b, err := item.GetBoolean()
+		if err != nil {
+			return err
+		}
+		buff.WriteString(strconv.FormatBool(b))
+	case Integer:
+		i, err := item.GetInteger()
+		if err != nil {
+			return err
+		}
+		if !i.IsInt64() || i.Int64() > maxSafeInteger || i.Int64() < -maxSafeInteger {
+			return fmt.Errorf("integer %s is out of the range of json", i.String())
+		}
+		buff.WriteString(i.String())
+	case ByteString, Buffer:
+		s, err := item.GetString()
+		if err != nil {
+			return err
+		}
+		writeJsonString(buff, s)
+	case Array, Struct:
+		items, err := item.GetItems()
+		if err != nil {
+			return err
+		}
+		buff.WriteByte('[')
+		for i := range items {
+			if i > 0 {
+				buff.WriteByte(',')
+			}
+			if err = writeJson(buff, items[i]); err != nil {
+				return err
+			}
+		}
+		buff.WriteByte(']')
+	case Map:
+		pairs, err := item.GetPairs()
+		if err != nil {
+			return err
+		}
+		buff.WriteByte('{')
+		for i := range pairs {
+			if pairs[i].Key.Type != ByteString {
+				return fmt.Errorf("%s item can't be a key of json", pairs[i].Key.Type)
+			}
+			key, err := pairs[i].Key.GetString()
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				buff.WriteByte(',')
+			}
+			writeJsonString(buff, key)
+			buff.WriteByte(':')
+			if err = writeJson(buff, pairs[i].Value); err != nil {
+				return err
+			}
+		}
+		buff.WriteByte('}')
+	default:
+		return fmt.Errorf("%s item can't be serialized to json", item.Type)
+	}
+	if buff.Len() > MaxItemSize {
+		return fmt.Errorf("serialized item is larger than %d bytes", MaxItemSize)
+	}
+	return nil
+}
+
+// writeJsonString escapes the string like the default encoder of System.Text.Json, the characters other than
+// printable ASCII and the html characters are escaped as \uXXXX
+func writeJsonString(buff *bytes.Buffer, s string) {
+	buff.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			buff.WriteString(`\\`)
+		case '\b':
+			buff.WriteString(`\b`)
+		case '\f':
+			buff.WriteString(`\f`)
+		case '\n':
+			buff.WriteString(`\n`)
+		case '\r':
+			buff.WriteString(`\r`)
+		case '\t':
+			buff.WriteString(`\t`)
+		case '"', '&', '\'', '+', '<', '>', '`':
+			fmt.Fprintf(buff, `\u%04X`, r)
+		default:
+			if r >= 0x20 && r < 0x7f {
+				buff.WriteRune(r)
+			} else if r > 0xffff {
+				r1, r2 := utf16.EncodeRune(r)
+				fmt.Fprintf(buff, `\u%04X\u%04X`, r1, r2)
+			} else {
+				fmt.Fprintf(buff, `\u%04X`, r)
+			}
+		}
+	}
+	buff.WriteByte('"')
+}
+
+// DeserializeStackItemFromJson decodes json like StdLib.jsonDeserialize, a number must be an integer, a string is a
+// ByteString and an object is a Map
+func DeserializeStackItemFromJson(data []byte) (StackItem, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	d := &jsonDeserializer{decoder: decoder}
+	item, err := d.read(0)
+	if err != nil {
+		return StackItem{}, err
+	}
+	if _, err = decoder.Token(); err != io.EOF {
+		return StackItem{}, fmt.Errorf("invalid json: data after the top-level value")
+	}
+	return item, nil
+}
+
+type jsonDeserializer struct {
+	decoder *json.Decoder
+	// count is the number of the deserialized items, it can't be more than MaxStackSize
+	count int
+}
+
+func (d *jsonDeserializer) read(depth int) (StackItem, error) {
+	token, err := d.decoder.Token()
+	if err != nil {
+		return StackItem{}, err
+	}
+	return d.readToken(token, depth)
+}
+
+func (d *jsonDeserializer) readToken(token json.Token, depth int) (StackItem, error) {
+	if d.count++; d.count > MaxStackSize {
+		return StackItem{}, fmt.Errorf("too many items to deserialize")
+	}
+	switch v := token.(type) {
+	case nil:
+		return NewNullStackItem(), nil
+	case bool:
+		return NewBooleanStackItem(v), nil
+	case string:
+		return NewByteStringStackItem([]byte(v)), nil
+	case json.Number:
+		f, ok := new(big.Float).SetString(v.String())
+		if !ok {
+			return StackItem{}, fmt.Errorf("invalid number %s", v)
+		}
+		// the number is a double in C#
+		f64, _ := f.Float64()
+		i, accuracy := big.NewFloat(f64).Int(nil)
+		if accuracy != big.Exact {
+			return StackItem{}, fmt.Errorf("decimal value %s is not allowed", v)
+		}
+		return NewIntegerStackItem(i), nil
+	case json.Delim:
+		if depth++; depth > MaxJsonDepth {
+			return StackItem{}, fmt.Errorf("json is nested more than %d levels", MaxJsonDepth)
+		}
+		if v == '[' {
+			items := []StackItem{}
+			for d.decoder.More() {
+				item, err := d.read(depth)
+				if err != nil {
+					return StackItem{}, err
+				}
+				items = append(items, item)
+			}
+			_, err := d.decoder.Token()
+			return NewArrayStackItem(items), err
+		}
+		pairs := []StackItemPair{}
+		for d.decoder.More() {
+			token, err := d.decoder.Token()
+			if err != nil {
+				return StackItem{}, err
+			}
+			key := NewByteStringStackItem([]byte(token.(string)))
+			for i := range pairs {
+				if pairs[i].Key.equalKey(key) {
+					return StackItem{}, fmt.Errorf("duplicate key %s", token)
+				}
+			}
+			value, err := d.read(depth)
+			if err != nil {
+				return StackItem{}, err
+			}
+			if pairs, err = setPair(pairs, key, value); err != nil {
+				return StackItem{}, err
+			}
+		}
+		_, err := d.decoder.Token()
+		return NewMapStackItem(pairs), err
+	default:
+		return StackItem{}, fmt.Errorf("invalid json token %v", token)
+	}
+}
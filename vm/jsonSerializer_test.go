@@ -0,0 +1,52 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerializeStackItemToJson(t *testing.T) {
+	item := NewMapStackItem([]StackItemPair{
+		{Key: NewByteStringStackItem([]byte("b")), Value: NewArrayStackItem([]StackItem{
+			NewIntegerStackItem(big.NewInt(-5)),
+			NewBooleanStackItem(false),
+			NewNullStackItem(),
+		})},
+		{Key: NewByteStringStackItem([]byte("a")), Value: NewByteStringStackItem([]byte("<\"é\n"))},
+	})
+	b, err := SerializeStackItemToJson(item)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"b":[-5,false,null],"a":"\u003C\u0022\u00E9\n"}`, string(b))
+
+	decoded, err := DeserializeStackItemFromJson(b)
+	assert.Nil(t, err)
+	b2, err := SerializeStackItemToJson(decoded)
+	assert.Nil(t, err)
+	assert.Equal(t, b, b2)
+
+	_, err = SerializeStackItemToJson(NewIntegerStackItem(big.NewInt(1 << 53)))
+	assert.NotNil(t, err)
+	_, err = SerializeStackItemToJson(NewMapStackItem([]StackItemPair{{Key: NewIntegerStackItem(big.NewInt(1)), Value: NewNullStackItem()}}))
+	assert.NotNil(t, err)
+	_, err = SerializeStackItemToJson(NewByteStringStackItem([]byte{0xff}))
+	assert.NotNil(t, err)
+}
+
+func TestDeserializeStackItemFromJson(t *testing.T) {
+	item, err := DeserializeStackItemFromJson([]byte(`{"n":1e2,"s":"x"}`))
+	assert.Nil(t, err)
+	pairs, err := item.GetPairs()
+	assert.Nil(t, err)
+	i, err := pairs[0].Value.GetInteger()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), i.Int64())
+
+	for _, c := range []string{`1.5`, `{"a":1,"a":2}`, `[[[[[[[[[[[]]]]]]]]]]]`, `1 2`, `[`} {
+		_, err = DeserializeStackItemFromJson([]byte(c))
+		assert.NotNil(t, err, c)
+	}
+	_, err = DeserializeStackItemFromJson([]byte(`[[[[[[[[[[]]]]]]]]]]`))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,191 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+const (
+	// MaxItemSize is the max size of an item and of a serialized item, ExecutionEngineLimits.MaxItemSize
+	MaxItemSize = 65535 * 2
+	// MaxStackSize is the max number of the items in a serialized item, ExecutionEngineLimits.MaxStackSize
+	MaxStackSize = 2 * 1024
+	// MaxIntegerSize is the max size of an Integer in bytes
+	MaxIntegerSize = 32
+	// MaxKeySize is the max size of a key of a Map in bytes
+	MaxKeySize = 64
+)
+
+// StackItem is a value of NeoVM. The Value is nil for Any (the null item), bool for Boolean, *big.Int for Integer,
+// []byte for ByteString and Buffer, []StackItem for Array and Struct and []StackItemPair for Map.
+type StackItem struct {
+	Type  StackItemType
+	Value interface{}
+}
+
+// StackItemPair is a key-value pair of a Map, the pairs of a Map are ordered
+type StackItemPair struct {
+	Key   StackItem
+	Value StackItem
+}
+
+// NewNullStackItem returns the null item, its type is Any
+func NewNullStackItem() StackItem {
+	return StackItem{Type: Any}
+}
+
+func NewBooleanStackItem(b bool) StackItem {
+	return StackItem{Type: Boolean, Value: b}
+}
+
+func NewIntegerStackItem(i *big.Int) StackItem {
+	return StackItem{Type: Integer, Value: i}
+}
+
+func NewByteStringStackItem(b []byte) StackItem {
+	return StackItem{Type: ByteString, Value: b}
+}
+
+func NewBufferStackItem(b []byte) StackItem {
+	return StackItem{Type: Buffer, Value: b}
+}
+
+func NewArrayStackItem(items []StackItem) StackItem {
+	return StackItem{Type: Array, Value: items}
+}
+
+func NewStructStackItem(items []StackItem) StackItem {
+	return StackItem{Type: Struct, Value: items}
+}
+
+func NewMapStackItem(pairs []StackItemPair) StackItem {
+	return StackItem{Type: Map, Value: pairs}
+}
+
+// IsNull returns true for the null item
+func (s StackItem) IsNull() bool {
+	return s.Type == Any
+}
+
+// GetBytes returns the bytes of a primitive item, an Integer is little-endian in two's complement
+func (s StackItem) GetBytes() ([]byte, error) {
+	switch s.Type {
+	case Boolean:
+		if v, ok := s.Value.(bool); ok {
+			if v {
+				return []byte{1}, nil
+			}
+			return []byte{0}, nil
+		}
+	case Integer:
+		if v, ok := s.Value.(*big.Int); ok {
+			return helper.BigIntToNeoBytes(v), nil
+		}
+	case ByteString, Buffer:
+		if v, ok := s.Value.([]byte); ok {
+			return v, nil
+		}
+	default:
+		return nil, fmt.Errorf("%s item has no bytes", s.Type)
+	}
+	return nil, fmt.Errorf("invalid value %T of %s item", s.Value, s.Type)
+}
+
+// GetInteger converts a Boolean, an Integer or a ByteString to an integer
+func (s StackItem) GetInteger() (*big.Int, error) {
+	switch s.Type {
+	case Integer:
+		if v, ok := s.Value.(*big.Int); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("invalid value %T of %s item", s.Value, s.Type)
+	case Boolean, ByteString:
+		b, err := s.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > MaxIntegerSize {
+			return nil, fmt.Errorf("integer of %d bytes is too large", len(b))
+		}
+		return helper.BigIntFromNeoBytes(b), nil
+	default:
+		return nil, fmt.Errorf("%s item can't be converted to an integer", s.Type)
+	}
+}
+
+// GetBoolean converts the item to a boolean the same way as NeoVM
+func (s StackItem) GetBoolean() (bool, error) {
+	switch s.Type {
+	case Any:
+		return false, nil
+	case Boolean:
+		if v, ok := s.Value.(bool); ok {
+			return v, nil
+		}
+		return false, fmt.Errorf("invalid value %T of %s item", s.Value, s.Type)
+	case Integer, ByteString:
+		b, err := s.GetBytes()
+		if err != nil {
+			return false, err
+		}
+		if len(b) > MaxIntegerSize {
+			return false, fmt.Errorf("boolean of %d bytes is too large", len(b))
+		}
+		return !bytes.Equal(b, make([]byte, len(b))), nil
+	default:
+		return true, nil
+	}
+}
+
+// GetString returns the bytes of a primitive item as a UTF-8 string
+func (s StackItem) GetString() (string, error) {
+	b, err := s.GetBytes()
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", fmt.Errorf("invalid UTF-8 string")
+	}
+	return string(b), nil
+}
+
+// GetItems returns the items of an Array or a Struct
+func (s StackItem) GetItems() ([]StackItem, error) {
+	if s.Type != Array && s.Type != Struct {
+		return nil, fmt.Errorf("%s item has no items", s.Type)
+	}
+	if v, ok := s.Value.([]StackItem); ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("invalid value %T of %s item", s.Value, s.Type)
+}
+
+// GetPairs returns the key-value pairs of a Map
+func (s StackItem) GetPairs() ([]StackItemPair, error) {
+	if s.Type != Map {
+		return nil, fmt.Errorf("%s item has no key-value pairs", s.Type)
+	}
+	if v, ok := s.Value.([]StackItemPair); ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("invalid value %T of %s item", s.Value, s.Type)
+}
+
+// isPrimitive returns true if the item can be a key of a Map
+func (s StackItem) isPrimitive() bool {
+	return s.Type == Boolean || s.Type == Integer || s.Type == ByteString
+}
+
+// equalKey compares two keys of a Map, keys of different types are different
+func (s StackItem) equalKey(other StackItem) bool {
+	if s.Type != other.Type {
+		return false
+	}
+	a, err1 := s.GetBytes()
+	b, err2 := other.GetBytes()
+	return err1 == nil && err2 == nil && bytes.Equal(a, b)
+}
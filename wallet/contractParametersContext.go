@@ -6,10 +6,16 @@ import (
 
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
 	"github.com/joeqian10/neo3-gogogo/sc"
 	"github.com/joeqian10/neo3-gogogo/tx"
 )
 
+// TransactionContextType is the type of a context of a transaction in the json of neo-cli
+const TransactionContextType = "Neo.Network.P2P.Payloads.Transaction"
+
 type ContextItem struct {
 	Script     []byte
 	Parameters []*sc.ContractParameter
@@ -50,6 +56,8 @@ func (s signatureHelperSlice) Swap(i, j int) {
 type ContractParametersContext struct {
 	Verifiable   tx.IVerifiable // transaction ?
 	ContextItems map[helper.UInt160]*ContextItem
+	// SignatureOptions decide whether AddSignature normalizes s and rejects a non-canonical encoding
+	SignatureOptions keys.SignatureOptions
 
 	scriptHashes []*helper.UInt160
 }
@@ -107,7 +115,17 @@ func (c *ContractParametersContext) AddItemWithParams(contract *sc.Contract, par
 	return true
 }
 
+// AddSignature adds the signature of pubKey, a DER signature is converted to r||s and SignatureOptions
+// decide whether s is normalized and whether a non-canonical encoding is rejected
 func (c *ContractParametersContext) AddSignature(contract *sc.Contract, pubKey *crypto.ECPoint, signature []byte) (bool, error) {
+	curve := pubKey.Curve
+	if curve == nil {
+		curve = crypto.P256
+	}
+	signature, err := keys.NormalizeSignature(signature, curve, c.SignatureOptions)
+	if err != nil {
+		return false, err
+	}
 	bs := sc.ByteSlice(contract.Script)
 	if b, _, points := bs.IsMultiSigContractWithPoints(); b {
 		if !pubKey.ExistsIn(points) {
@@ -153,7 +171,7 @@ func (c *ContractParametersContext) AddSignature(contract *sc.Contract, pubKey *
 			sort.Sort(sort.Reverse(signatureHelperSlice(signatureHelpers)))
 
 			for i := 0; i < len(signatureHelpers); i++ {
-				if !c.AddItemWithIndex(contract, i, signatureHelpers[i]) {
+				if !c.AddItemWithIndex(contract, i, signatureHelpers[i].Signature) {
 					return false, fmt.Errorf("invalid operation when adding item")
 				}
 			}
@@ -257,3 +275,143 @@ func (c *ContractParametersContext) GetWitnesses() ([]*tx.Witness, error) {
 	}
 	return witnesses, nil
 }
+
+// ToRpcContractParameterContext converts the context of a transaction to the json format of neo-cli, so it can be
+// signed by other wallets offline
+func (c *ContractParametersContext) ToRpcContractParameterContext(network uint32) (models.RpcContractParameterContext, error) {
+	trx, ok := c.Verifiable.(*tx.Transaction)
+	if !ok {
+		return models.RpcContractParameterContext{}, fmt.Errorf("only the context of a transaction is supported")
+	}
+	buf := io.NewBufBinaryWriter()
+	trx.SerializeUnsigned(buf.BinaryWriter)
+	if buf.Err != nil {
+		return models.RpcContractParameterContext{}, buf.Err
+	}
+	result := models.RpcContractParameterContext{
+		Type:    TransactionContextType,
+		Hash:    "0x" + trx.GetHash().String(),
+		Data:    crypto.Base64Encode(buf.Bytes()),
+		Items:   make(map[string]models.RpcContextItem, len(c.ContextItems)),
+		Network: network,
+	}
+	for scriptHash, item := range c.ContextItems {
+		rpcItem := models.RpcContextItem{
+			Script:     crypto.Base64Encode(item.Script),
+			Parameters: make([]models.RpcContractParameter, len(item.Parameters)),
+			Signatures: make(map[string]string, len(item.Signatures)),
+		}
+		for i, param := range item.Parameters {
+			p, err := models.NewRpcContractParameterFromContractParameter(param)
+			if err != nil {
+				return models.RpcContractParameterContext{}, err
+			}
+			rpcItem.Parameters[i] = p
+		}
+		for pubKey, signature := range item.Signatures {
+			rpcItem.Signatures[pubKey] = crypto.Base64Encode(signature)
+		}
+		result.Items["0x"+scriptHash.String()] = rpcItem
+	}
+	return result, nil
+}
+
+// NewContractParametersContextFromRpc reads a context of a transaction in the json format of neo-cli
+func NewContractParametersContextFromRpc(context models.RpcContractParameterContext) (*ContractParametersContext, error) {
+	if context.Type != TransactionContextType {
+		return nil, fmt.Errorf("unsupported context type %s", context.Type)
+	}
+	data, err := crypto.Base64Decode(context.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data: %v", err)
+	}
+	trx := tx.NewTransaction()
+	br := io.NewBinaryReaderFromBuf(data)
+	trx.DeserializeUnsigned(br)
+	if br.Err != nil {
+		return nil, fmt.Errorf("invalid data: %v", br.Err)
+	}
+	if context.Hash != "" && context.Hash != "0x"+trx.GetHash().String() {
+		return nil, fmt.Errorf("hash %s doesn't match the data", context.Hash)
+	}
+	c := NewContractParametersContract(trx)
+	c.GetScriptHashes()
+	for k, rpcItem := range context.Items {
+		scriptHash, err := helper.UInt160FromString(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid script hash %s: %v", k, err)
+		}
+		script, err := crypto.Base64Decode(rpcItem.Script)
+		if err != nil {
+			return nil, fmt.Errorf("invalid script of %s: %v", k, err)
+		}
+		if len(script) > 0 && !helper.UInt160FromBytes(crypto.Hash160(script)).Equals(scriptHash) {
+			return nil, fmt.Errorf("script of %s doesn't match the script hash", k)
+		}
+		if !scriptHash.ExistsIn(c.scriptHashes) {
+			return nil, fmt.Errorf("%s is not a signer of the transaction", k)
+		}
+		item := &ContextItem{
+			Script:     script,
+			Parameters: make([]*sc.ContractParameter, len(rpcItem.Parameters)),
+			Signatures: make(map[string][]byte, len(rpcItem.Signatures)),
+		}
+		for i := range rpcItem.Parameters {
+			if item.Parameters[i], err = rpcItem.Parameters[i].ToContractParameter(); err != nil {
+				return nil, fmt.Errorf("invalid parameter %d of %s: %v", i, k, err)
+			}
+		}
+		for pubKey, signature := range rpcItem.Signatures {
+			if _, err = crypto.NewECPointFromString(pubKey); err != nil {
+				return nil, fmt.Errorf("invalid public key %s of %s: %v", pubKey, k, err)
+			}
+			if item.Signatures[pubKey], err = crypto.Base64Decode(signature); err != nil {
+				return nil, fmt.Errorf("invalid signature of %s: %v", pubKey, err)
+			}
+		}
+		c.ContextItems[*scriptHash] = item
+	}
+	return c, nil
+}
+
+// Merge adds the signatures and parameters in other, a context of the same transaction signed by other wallets
+func (c *ContractParametersContext) Merge(other *ContractParametersContext) error {
+	if !c.Verifiable.GetHash().Equals(other.Verifiable.GetHash()) {
+		return fmt.Errorf("contexts are not of the same transaction")
+	}
+	c.GetScriptHashes()
+	for scriptHash, item := range other.ContextItems {
+		types := make([]sc.ContractParameterType, len(item.Parameters))
+		for i, param := range item.Parameters {
+			types[i] = param.Type
+		}
+		var contract *sc.Contract
+		if len(item.Script) > 0 {
+			contract = sc.CreateContract(types, item.Script)
+		} else {
+			sh := scriptHash
+			contract = sc.CreateContractWithScriptHash(&sh, types)
+		}
+		for pubKey, signature := range item.Signatures {
+			point, err := crypto.NewECPointFromString(pubKey)
+			if err != nil {
+				return err
+			}
+			if _, err = c.AddSignature(contract, point, signature); err != nil {
+				return err
+			}
+		}
+		for i, param := range item.Parameters {
+			if param.Value == nil {
+				continue
+			}
+			if p := c.GetParameter(&scriptHash, i); p != nil && p.Value != nil {
+				continue
+			}
+			if !c.AddItemWithIndex(contract, i, param.Value) {
+				return fmt.Errorf("%s is not a signer of the transaction", scriptHash.String())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package wallet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+func newMultiSigContext(t *testing.T) ([]*keys.KeyPair, *sc.Contract, *ContractParametersContext) {
+	pairs := make([]*keys.KeyPair, 3)
+	points := make([]*crypto.ECPoint, 3)
+	for i := range pairs {
+		k := make([]byte, 32)
+		k[31] = byte(i + 1)
+		pair, err := keys.NewKeyPair(k)
+		require.Nil(t, err)
+		pairs[i], points[i] = pair, pair.PublicKey
+	}
+	multi, err := sc.CreateMultiSigContract(2, points)
+	require.Nil(t, err)
+	trx := tx.NewTransaction()
+	trx.SetNonce(1)
+	trx.SetValidUntilBlock(100)
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetSigners([]*tx.Signer{tx.NewSigner(multi.GetScriptHash(), tx.CalledByEntry)})
+	ctx := NewContractParametersContract(trx)
+	ctx.GetScriptHashes()
+	return pairs, multi, ctx
+}
+
+// roundTrip converts the context to the json of neo-cli and back
+func roundTrip(t *testing.T, ctx *ContractParametersContext) *ContractParametersContext {
+	r, err := ctx.ToRpcContractParameterContext(helper.Neo3Magic_TestNet)
+	require.Nil(t, err)
+	data, err := json.Marshal(r)
+	require.Nil(t, err)
+	var decoded models.RpcContractParameterContext
+	require.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, helper.Neo3Magic_TestNet, decoded.Network)
+	result, err := NewContractParametersContextFromRpc(decoded)
+	require.Nil(t, err)
+	return result
+}
+
+func TestContractParametersContext_ToRpcContractParameterContext(t *testing.T) {
+	pairs, multi, ctx := newMultiSigContext(t)
+	signature, err := Sign(ctx.Verifiable, pairs[0], helper.Neo3Magic_TestNet)
+	assert.Nil(t, err)
+	ok, err := ctx.AddSignature(multi, pairs[0].PublicKey, signature)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	r, err := ctx.ToRpcContractParameterContext(helper.Neo3Magic_TestNet)
+	assert.Nil(t, err)
+	assert.Equal(t, TransactionContextType, r.Type)
+	assert.Equal(t, "0x"+ctx.Verifiable.GetHash().String(), r.Hash)
+	item := r.Items["0x"+multi.GetScriptHash().String()]
+	assert.Equal(t, crypto.Base64Encode(multi.Script), item.Script)
+	assert.Equal(t, 2, len(item.Parameters))
+	assert.Equal(t, "Signature", item.Parameters[0].Type)
+	assert.Nil(t, item.Parameters[0].Value)
+	assert.Equal(t, crypto.Base64Encode(signature), item.Signatures[pairs[0].PublicKey.String()])
+
+	decoded := roundTrip(t, ctx)
+	assert.Equal(t, ctx.Verifiable.GetHash(), decoded.Verifiable.GetHash())
+	assert.Equal(t, signature, decoded.GetSignatures(multi.GetScriptHash())[pairs[0].PublicKey.String()])
+	assert.False(t, decoded.GetCompleted())
+}
+
+func TestNewContractParametersContextFromRpc(t *testing.T) {
+	_, _, ctx := newMultiSigContext(t)
+	r, err := ctx.ToRpcContractParameterContext(helper.Neo3Magic_TestNet)
+	assert.Nil(t, err)
+
+	invalid := r
+	invalid.Type = "Neo.Network.P2P.Payloads.Block"
+	_, err = NewContractParametersContextFromRpc(invalid)
+	assert.NotNil(t, err)
+
+	invalid = r
+	invalid.Hash = "0x" + helper.UInt256Zero.String()
+	_, err = NewContractParametersContextFromRpc(invalid)
+	assert.NotNil(t, err)
+
+	invalid = r
+	invalid.Items = map[string]models.RpcContextItem{"0x" + helper.UInt160Zero.String(): {}}
+	_, err = NewContractParametersContextFromRpc(invalid)
+	assert.NotNil(t, err)
+}
+
+func TestContractParametersContext_Merge(t *testing.T) {
+	pairs, multi, ctx := newMultiSigContext(t)
+	// two cosigners sign their copies of the context offline
+	ctx1, ctx2 := roundTrip(t, ctx), roundTrip(t, ctx)
+	for i, c := range []*ContractParametersContext{ctx1, ctx2} {
+		signature, err := Sign(c.Verifiable, pairs[i+1], helper.Neo3Magic_TestNet)
+		assert.Nil(t, err)
+		ok, err := c.AddSignature(multi, pairs[i+1].PublicKey, signature)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+	}
+
+	merged := roundTrip(t, ctx1)
+	assert.Nil(t, merged.Merge(roundTrip(t, ctx2)))
+	assert.True(t, merged.GetCompleted())
+	witnesses, err := merged.GetWitnesses()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(witnesses))
+	assert.True(t, tx.VerifyMultiSignatureWitness(tx.GetSignData(merged.Verifiable, helper.Neo3Magic_TestNet), witnesses[0]))
+
+	// merging again changes nothing
+	assert.Nil(t, merged.Merge(ctx2))
+	assert.True(t, merged.GetCompleted())
+
+	// a context of another transaction
+	_, _, other := newMultiSigContext(t)
+	other.Verifiable.(*tx.Transaction).SetNonce(2)
+	assert.NotNil(t, merged.Merge(other))
+}
@@ -66,6 +66,7 @@ func getSigners(sender *helper.UInt160, cosigners []*tx.Signer) []*tx.Signer {
 	return append([]*tx.Signer{signer}, cosigners...)
 }
 
-func Sign(verifiable tx.IVerifiable, pair *keys.KeyPair, magic uint32) ([]byte, error) {
-	return pair.Sign(tx.GetSignData(verifiable, magic))
+// Sign signs the verifiable with a KeyPair or any other ISigner
+func Sign(verifiable tx.IVerifiable, signer keys.ISigner, magic uint32) ([]byte, error) {
+	return signer.Sign(tx.GetSignData(verifiable, magic))
 }
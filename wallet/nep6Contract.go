@@ -41,6 +41,24 @@ func NewNEP6Contract(script []byte, paramList []sc.ContractParameterType, paramN
 	}, nil
 }
 
+// load checks the contract decoded from a wallet file and fills the parameter lists
+func (c *NEP6Contract) load() error {
+	script, err := crypto.Base64Decode(c.Script)
+	if err != nil || len(script) == 0 {
+		return fmt.Errorf("invalid contract script %s", c.Script)
+	}
+	c.parameterList = make([]sc.ContractParameterType, len(c.Parameters))
+	c.parameterNames = make([]string, len(c.Parameters))
+	for i, p := range c.Parameters {
+		c.parameterList[i], err = sc.NewContractParameterTypeFromString(p.Type)
+		if err != nil {
+			return fmt.Errorf("invalid type %s of contract parameter %s", p.Type, p.Name)
+		}
+		c.parameterNames[i] = p.Name
+	}
+	return nil
+}
+
 func (c *NEP6Contract) GetScript() []byte {
 	b, _ := crypto.Base64Decode(c.Script)
 	return b
@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -31,10 +32,28 @@ type NEP6Wallet struct {
 	accounts map[helper.UInt160]NEP6Account
 }
 
-// NewNEP6Wallet creates a NEO wallet.
+// NewNEP6Wallet opens the wallet file at path, or creates a new wallet with name and scrypt if path is empty
+// or the file does not exist, the default protocol settings are used if settings is nil.
+// The addresses of the accounts loaded from path should have the address version of the settings.
 func NewNEP6Wallet(path string, settings *helper.ProtocolSettings, name *string, scrypt *ScryptParameters) (*NEP6Wallet, error) {
-	file, err := os.OpenFile(path, os.O_RDONLY, os.ModeExclusive)
-	if err != nil {
+	if settings == nil {
+		settings = &helper.DefaultProtocolSettings
+	}
+	var file *os.File
+	var err error
+	if path != "" {
+		file, err = os.Open(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if file == nil {
+		if scrypt == nil {
+			scrypt = DefaultScryptParameters
+		}
+		if err = scrypt.Validate(); err != nil {
+			return nil, err
+		}
 		return &NEP6Wallet{
 			protocolSettings: settings,
 			path:             path,
@@ -43,28 +62,78 @@ func NewNEP6Wallet(path string, settings *helper.ProtocolSettings, name *string,
 			accounts:         make(map[helper.UInt160]NEP6Account, 0),
 			Scrypt:           scrypt,
 			Extra:            nil,
-		}, err
+		}, nil
 	}
+	defer file.Close()
 	w := &NEP6Wallet{
 		protocolSettings: settings,
 		path:             path,
-		Scrypt:           &ScryptParameters{},
 		accounts:         make(map[helper.UInt160]NEP6Account, 0),
 	}
-	if err := json.NewDecoder(file).Decode(w); err != nil {
-		return nil, err
+	if err = json.NewDecoder(file).Decode(w); err != nil {
+		return nil, fmt.Errorf("invalid wallet file %s: %s", path, err.Error())
 	}
-	if w.Accounts != nil {
-		w.accounts = make(map[helper.UInt160]NEP6Account, len(w.Accounts))
-		for i, _ := range w.Accounts {
-			w.Accounts[i].wallet = w
-			w.Accounts[i].protocolSettings = settings
-			w.accounts[*w.Accounts[i].GetScriptHash()] = w.Accounts[i]
-		}
+	if err = w.load(); err != nil {
+		return nil, fmt.Errorf("invalid wallet file %s: %s", path, err.Error())
 	}
 	return w, nil
 }
 
+// load validates the decoded wallet and indexes the accounts
+func (w *NEP6Wallet) load() error {
+	if w.Scrypt == nil {
+		return fmt.Errorf("scrypt parameters are missing")
+	}
+	if err := w.Scrypt.Validate(); err != nil {
+		return err
+	}
+	w.accounts = make(map[helper.UInt160]NEP6Account, len(w.Accounts))
+	for i := range w.Accounts {
+		a := &w.Accounts[i]
+		scriptHash, err := crypto.AddressToScriptHash(a.Address, w.protocolSettings.AddressVersion)
+		if err != nil {
+			return fmt.Errorf("account %d: invalid address %s: %s", i, a.Address, err.Error())
+		}
+		if a.Nep2Key != nil {
+			if err = validateNEP2(*a.Nep2Key); err != nil {
+				return fmt.Errorf("account %d (%s): %s", i, a.Address, err.Error())
+			}
+		}
+		if a.Contract != nil {
+			if err = a.Contract.load(); err != nil {
+				return fmt.Errorf("account %d (%s): %s", i, a.Address, err.Error())
+			}
+			if !a.Contract.GetScriptHash().Equals(scriptHash) {
+				return fmt.Errorf("account %d (%s): contract script hash %s does not match the address", i, a.Address, a.Contract.GetScriptHash().String())
+			}
+		}
+		if _, ok := w.accounts[*scriptHash]; ok {
+			return fmt.Errorf("account %d (%s): duplicate account", i, a.Address)
+		}
+		a.scriptHash = scriptHash
+		a.wallet = w
+		a.protocolSettings = w.protocolSettings
+		w.accounts[*scriptHash] = *a
+	}
+	return nil
+}
+
+// validateNEP2 checks the format of a NEP-2 key without decrypting it
+func validateNEP2(nep2 string) error {
+	data, err := crypto.Base58CheckDecode(nep2)
+	if err != nil {
+		return fmt.Errorf("invalid NEP-2 key: %s", err.Error())
+	}
+	if len(data) != 39 || data[0] != 0x01 || data[1] != 0x42 || data[2] != 0xe0 {
+		return fmt.Errorf("invalid NEP-2 key format")
+	}
+	return nil
+}
+
+func (w *NEP6Wallet) GetProtocolSettings() *helper.ProtocolSettings {
+	return w.protocolSettings
+}
+
 func (w *NEP6Wallet) GetName() string {
 	if w.Name == nil {
 		return ""
@@ -176,6 +245,9 @@ func (w *NEP6Wallet) CreateAccountWithScriptHash(scriptHash *helper.UInt160) (IA
 }
 
 func (w *NEP6Wallet) DecryptKey(nep2Key string) (*keys.KeyPair, error) {
+	if w.password == nil {
+		return nil, fmt.Errorf("unlock wallet first")
+	}
 	priKey, err := GetPrivateKeyFromNEP2(nep2Key, *w.password, w.protocolSettings.AddressVersion, w.Scrypt.N, w.Scrypt.R, w.Scrypt.P)
 	if err != nil {
 		return nil, err
@@ -230,6 +302,9 @@ func (w *NEP6Wallet) ImportFromWIF(wif string) (IAccount, error) {
 	if err != nil {
 		return nil, err
 	}
+	if w.password == nil {
+		return nil, fmt.Errorf("unlock wallet first")
+	}
 	acc, err := NewNEP6AccountFromKeyPair(w, contract.GetScriptHash(), pair, *w.password)
 	if err != nil {
 		return nil, err
@@ -239,8 +314,13 @@ func (w *NEP6Wallet) ImportFromWIF(wif string) (IAccount, error) {
 	return acc, nil
 }
 
-// Import account from Nep2Key
+// ImportFromNEP2 imports a NEP-2 key encrypted with the scrypt parameters N, R and P. The key is kept as it is
+// if the parameters are the ones of the wallet and the passphrase is the password of the wallet, otherwise it is
+// encrypted again with the password and the scrypt parameters of the unlocked wallet.
 func (w *NEP6Wallet) ImportFromNEP2(nep2, passphrase string, N, R, P int) (IAccount, error) {
+	if err := NewScryptParameters(N, R, P).Validate(); err != nil {
+		return nil, err
+	}
 	pair, err := keys.NewKeyPairFromNEP2(nep2, passphrase, w.protocolSettings.AddressVersion, N, R, P)
 	if err != nil {
 		return nil, err
@@ -254,10 +334,15 @@ func (w *NEP6Wallet) ImportFromNEP2(nep2, passphrase string, N, R, P int) (IAcco
 		return nil, err
 	}
 	var acc *NEP6Account
-	if w.Scrypt.N == 16384 && w.Scrypt.R == 8 && w.Scrypt.P == 8 {
+	sameScrypt := w.Scrypt.N == N && w.Scrypt.R == R && w.Scrypt.P == P
+	if sameScrypt && (w.password == nil || *w.password == passphrase) && w.VerifyPassword(passphrase) {
 		acc = NewNEP6Account(w, contract.GetScriptHash(), &nep2)
+		acc.key = pair
 	} else {
-		acc, err = NewNEP6AccountFromKeyPair(w, contract.GetScriptHash(), pair, passphrase)
+		if w.password == nil {
+			return nil, fmt.Errorf("unlock wallet first")
+		}
+		acc, err = NewNEP6AccountFromKeyPair(w, contract.GetScriptHash(), pair, *w.password)
 		if err != nil {
 			return nil, err
 		}
@@ -267,14 +352,120 @@ func (w *NEP6Wallet) ImportFromNEP2(nep2, passphrase string, N, R, P int) (IAcco
 	return acc, nil
 }
 
+// ChangePassword encrypts every NEP-2 key again with the new password, the wallet is not changed if any key
+// fails to decrypt. The wallet is unlocked with the new password, call Save to persist it.
+func (w *NEP6Wallet) ChangePassword(oldPassword, newPassword string) error {
+	return w.reencrypt(oldPassword, newPassword, w.Scrypt)
+}
+
+// MigrateScrypt encrypts every NEP-2 key again with new scrypt parameters, e.g. to move a wallet created with
+// weak parameters to the default ones. Call Save to persist it.
+func (w *NEP6Wallet) MigrateScrypt(password string, scrypt *ScryptParameters) error {
+	return w.reencrypt(password, password, scrypt)
+}
+
+func (w *NEP6Wallet) reencrypt(oldPassword, newPassword string, scrypt *ScryptParameters) error {
+	if scrypt == nil {
+		return fmt.Errorf("scrypt parameters are nil")
+	}
+	if err := scrypt.Validate(); err != nil {
+		return err
+	}
+	nep2Keys := make(map[helper.UInt160]string, len(w.accounts))
+	pairs := make(map[helper.UInt160]*keys.KeyPair, len(w.accounts))
+	for hash, acc := range w.accounts {
+		if acc.Nep2Key == nil {
+			continue
+		}
+		pair, err := keys.NewKeyPairFromNEP2(*acc.Nep2Key, oldPassword, w.protocolSettings.AddressVersion, w.Scrypt.N, w.Scrypt.R, w.Scrypt.P)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt the key of %s: %s", acc.Address, err.Error())
+		}
+		nep2, err := pair.ExportWithPassword(newPassword, w.protocolSettings.AddressVersion, scrypt.N, scrypt.R, scrypt.P)
+		if err != nil {
+			return err
+		}
+		nep2Keys[hash] = nep2
+		pairs[hash] = pair
+	}
+	for hash, nep2 := range nep2Keys {
+		acc := w.accounts[hash]
+		key := nep2
+		acc.Nep2Key = &key
+		acc.key = pairs[hash]
+		w.accounts[hash] = acc
+	}
+	w.Scrypt = scrypt
+	w.password = &newPassword
+	return nil
+}
+
+// ImportFromMnemonic derives the first count accounts of a BIP-39 mnemonic with SLIP-10 on the path
+// m/44'/888'/0'/0/index. Only English mnemonics are accepted, since only the English wordlist ships.
+// The accounts already in the wallet are returned as they are.
+func (w *NEP6Wallet) ImportFromMnemonic(mnemonic, passphrase string, count int) ([]IAccount, error) {
+	if w.password == nil {
+		return nil, fmt.Errorf("unlock wallet first")
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("count should be positive")
+	}
+	seed, err := keys.MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	master, err := keys.NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]IAccount, count)
+	for i := 0; i < count; i++ {
+		node, err := master.Derive(keys.NeoDerivationPath(0, 0, uint32(i)))
+		if err != nil {
+			return nil, err
+		}
+		pair, err := node.GetKeyPair()
+		if err != nil {
+			return nil, err
+		}
+		contract, err := sc.CreateSignatureContract(pair.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		if acc := w.GetAccountByScriptHash(contract.GetScriptHash()); acc != nil {
+			accounts[i] = acc
+			continue
+		}
+		script, err := sc.CreateSignatureRedeemScript(pair.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		nep6contract, err := NewNEP6Contract(script, []sc.ContractParameterType{sc.Signature}, []string{"signature"}, false)
+		if err != nil {
+			return nil, err
+		}
+		acc, err := NewNEP6AccountFromKeyPair(w, nep6contract.GetScriptHash(), pair, *w.password)
+		if err != nil {
+			return nil, err
+		}
+		acc.Contract = nep6contract
+		w.addAccount(acc, true)
+		accounts[i] = acc
+	}
+	return accounts, nil
+}
+
 func (w *NEP6Wallet) Lock() {
 	w.password = nil
 }
 
+// Save writes the wallet to path, or to the path it is opened from if path is empty, the file is replaced atomically
 func (w *NEP6Wallet) Save(path string) error {
-	file, err := os.Create(path)
-	if err != nil {
-		return err
+	if path == "" {
+		path = w.path
+	}
+	if path == "" {
+		return fmt.Errorf("wallet path is empty")
 	}
 	if w.accounts != nil {
 		w.Accounts = make([]NEP6Account, 0)
@@ -282,11 +473,11 @@ func (w *NEP6Wallet) Save(path string) error {
 			w.Accounts = append(w.Accounts, v)
 		}
 	}
-	err = json.NewEncoder(file).Encode(w)
-	if err != nil {
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(w); err != nil {
 		return err
 	}
-	return file.Close()
+	return helper.WriteFileAtomic(path, buf.Bytes())
 }
 
 func (w *NEP6Wallet) Unlock(password string) error {
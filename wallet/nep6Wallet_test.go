@@ -2,8 +2,12 @@ package wallet
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,10 +55,61 @@ func TestNewNEP6Wallet1(t *testing.T) {
 func TestNewNEP6Wallet(t *testing.T) {
 	s := "test"
 	wallet, err := NewNEP6Wallet("", &helper.DefaultProtocolSettings, &s, DefaultScryptParameters)
-	assert.NotNil(t, err)
+	assert.Nil(t, err)
 	assert.Equal(t, "test", wallet.GetName())
 	assert.Equal(t, "3.0", wallet.Version)
 	assert.Equal(t, DefaultScryptParameters.P, wallet.Scrypt.P)
+
+	// a new wallet is created if the file does not exist
+	path := filepath.Join(t.TempDir(), "new.json")
+	wallet, err = NewNEP6Wallet(path, nil, &s, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, path, wallet.GetPath())
+	assert.Equal(t, DefaultScryptParameters, wallet.Scrypt)
+
+	_, err = NewNEP6Wallet("", nil, &s, NewScryptParameters(3, 1, 1))
+	assert.NotNil(t, err)
+	_, err = NewNEP6Wallet(t.TempDir(), nil, &s, nil)
+	assert.NotNil(t, err)
+}
+
+// test the validation of wallet files
+func TestNewNEP6Wallet2(t *testing.T) {
+	const account = `{"address":"NRNHsLqbKYBQeVQ1TRyZrLbudi6HACjUaT","label":null,"isdefault":false,"lock":false,"key":%s,"contract":%s,"extra":null}`
+	const key = `"6PYMaiJHhqrCfEDJxJCa6u5MJgS24S564RFQfx6C48FegTR5UvqHjfF3sN"`
+	const contract = `{"script":"DCEC6W884XWN8uDUF64bnkk64et86LWWDjdHd+AZQ+2vyC0LQZVEDXg=","parameters":[{"name":"signature","type":"Signature"}],"deployed":false}`
+	wallet := func(scrypt string, accounts ...string) string {
+		return fmt.Sprintf(`{"name":null,"version":"3.0","scrypt":%s,"accounts":[%s],"extra":null}`, scrypt, strings.Join(accounts, ","))
+	}
+	const scrypt = `{"n":16384,"r":8,"p":8}`
+	cases := []struct {
+		content string
+		err     string
+	}{
+		{wallet(scrypt, fmt.Sprintf(account, key, contract)), ""},
+		{wallet(scrypt, fmt.Sprintf(account, "null", "null")), ""},
+		{`{"name":`, "unexpected EOF"},
+		{`{"name":null,"version":"3.0","accounts":[]}`, "scrypt parameters are missing"},
+		{wallet(`{"n":1000,"r":8,"p":8}`), "should be a power of 2"},
+		{wallet(scrypt, strings.Replace(fmt.Sprintf(account, key, contract), "NRNH", "ARNH", 1)), "account 0: invalid address"},
+		{wallet(scrypt, fmt.Sprintf(account, `"6PYMaiJHhqrCfEDJxJCa6u5MJgS24S564RFQfx6C48FegTR5UvqHjfF3sM"`, contract)), "invalid NEP-2 key"},
+		{wallet(scrypt, fmt.Sprintf(account, key, `{"script":"!!","parameters":[],"deployed":false}`)), "invalid contract script"},
+		{wallet(scrypt, fmt.Sprintf(account, key, `{"script":"EQ==","parameters":[],"deployed":false}`)), "does not match the address"},
+		{wallet(scrypt, fmt.Sprintf(account, key, strings.Replace(contract, `"Signature"`, `"Unknown"`, 1))), "invalid type Unknown"},
+		{wallet(scrypt, fmt.Sprintf(account, key, contract), fmt.Sprintf(account, key, contract)), "account 1 (NRNHsLqbKYBQeVQ1TRyZrLbudi6HACjUaT): duplicate account"},
+	}
+	for _, c := range cases {
+		path := filepath.Join(t.TempDir(), "wallet.json")
+		assert.Nil(t, os.WriteFile(path, []byte(c.content), 0600))
+		w, err := NewNEP6Wallet(path, nil, nil, nil)
+		if c.err == "" {
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(w.GetAccounts()))
+		} else {
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), c.err)
+		}
+	}
 }
 
 func TestGetPrivateKeyFromNEP2(t *testing.T) {
@@ -249,6 +304,45 @@ func TestNEP6Wallet_ImportFromNEP2(t *testing.T) {
 	_, err := testWallet.ImportFromNEP2(nep2, password, 2, 1, 1)
 	assert.Nil(t, err)
 	assert.Equal(t, true, testWallet.Contains(testScriptHash))
+	resetTestWallet()
+
+	// a key encrypted with other scrypt parameters is encrypted again with the ones of the wallet
+	nep2Key, _ := pair.ExportWithPassword(password, helper.DefaultAddressVersion, 4, 1, 1)
+	_, err = testWallet.ImportFromNEP2(nep2Key, password, 3, 1, 1)
+	assert.NotNil(t, err)
+	_, err = testWallet.ImportFromNEP2(nep2Key, password, 2, 1, 1)
+	assert.NotNil(t, err)
+	_ = testWallet.Unlock(password)
+	_, err = testWallet.ImportFromNEP2(nep2Key, password, 4, 1, 1)
+	assert.Nil(t, err)
+	acc := testWallet.accounts[*testScriptHash]
+	assert.NotEqual(t, nep2Key, *acc.Nep2Key)
+	_, err = keys.NewKeyPairFromNEP2(*acc.Nep2Key, password, helper.DefaultAddressVersion, 2, 1, 1)
+	assert.Nil(t, err)
+
+	resetTestWallet()
+}
+
+func TestNEP6Wallet_ImportFromMnemonic(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	_, err := testWallet.ImportFromMnemonic(mnemonic, "", 2)
+	assert.NotNil(t, err) // locked
+	err = testWallet.Unlock(password)
+	assert.Nil(t, err)
+	_, err = testWallet.ImportFromMnemonic("abandon abandon abandon", "", 2)
+	assert.NotNil(t, err)
+	accounts, err := testWallet.ImportFromMnemonic(mnemonic, "", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(accounts))
+	assert.Equal(t, 2, len(testWallet.GetAccounts()))
+	// m/44'/888'/0'/0/0 and m/44'/888'/0'/0/1
+	assert.Equal(t, "NYqCjmV8g8PFCYpyD3K4kSCkQxZff1UNMV", accounts[0].GetAddress())
+	assert.Equal(t, "Nij7Ef7uf8E5Vj9UfN9YiUkBUpRFTn4aP2", accounts[1].GetAddress())
+	// importing again does not add accounts
+	accounts, err = testWallet.ImportFromMnemonic(mnemonic, "", 3)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(accounts))
+	assert.Equal(t, 3, len(testWallet.GetAccounts()))
 
 	resetTestWallet()
 }
@@ -279,6 +373,66 @@ func TestNEP6Wallet_Save(t *testing.T) {
 
 	assert.Nil(t, err)
 	assert.Equal(t, w.Name, testWrite.Name)
+
+	// save to the path it is opened from, no temporary file is left
+	dir := t.TempDir()
+	path = filepath.Join(dir, "wallet.json")
+	w, err = NewNEP6Wallet(path, nil, nil, NewScryptParameters(2, 1, 1))
+	assert.Nil(t, err)
+	_ = w.Unlock(password)
+	_, err = w.CreateAccountWithPrivateKey(privateKey)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Save(""))
+	assert.Nil(t, w.Save(""))
+	files, _ := os.ReadDir(dir)
+	assert.Equal(t, 1, len(files))
+	saved, err := NewNEP6Wallet(path, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.True(t, saved.Contains(testScriptHash))
+
+	assert.NotNil(t, (&NEP6Wallet{}).Save(""))
+}
+
+func TestNEP6Wallet_ChangePassword(t *testing.T) {
+	_ = testWallet.Unlock(password)
+	_, err := testWallet.CreateAccountWithPrivateKey(privateKey)
+	assert.Nil(t, err)
+	_, err = testWallet.CreateAccount()
+	assert.Nil(t, err)
+	_, err = testWallet.CreateAccountWithScriptHash(hash)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, testWallet.ChangePassword("wrong", "new"))
+	assert.True(t, testWallet.VerifyPassword(password))
+	assert.Nil(t, testWallet.ChangePassword(password, "new"))
+	for _, acc := range testWallet.accounts {
+		if acc.Nep2Key != nil {
+			_, err = keys.NewKeyPairFromNEP2(*acc.Nep2Key, "new", helper.DefaultAddressVersion, 2, 1, 1)
+			assert.Nil(t, err)
+		}
+	}
+	testWallet.Lock()
+	assert.False(t, testWallet.VerifyPassword(password))
+	assert.Nil(t, testWallet.Unlock("new"))
+
+	assert.NotNil(t, testWallet.MigrateScrypt("new", NewScryptParameters(0, 1, 1)))
+	assert.Nil(t, testWallet.MigrateScrypt("new", NewScryptParameters(4, 1, 1)))
+	assert.Equal(t, 4, testWallet.Scrypt.N)
+	acc := testWallet.accounts[*testScriptHash]
+	_, err = keys.NewKeyPairFromNEP2(*acc.Nep2Key, "new", helper.DefaultAddressVersion, 4, 1, 1)
+	assert.Nil(t, err)
+
+	// a key with another passphrase is encrypted with the password of the wallet
+	_ = testWallet.MigrateScrypt("new", NewScryptParameters(2, 1, 1))
+	testWallet.DeleteAccount(testScriptHash)
+	_, err = testWallet.ImportFromNEP2(nep2, password, 2, 1, 1)
+	assert.Nil(t, err)
+	acc = testWallet.accounts[*testScriptHash]
+	assert.NotEqual(t, nep2, *acc.Nep2Key)
+	_, err = keys.NewKeyPairFromNEP2(*acc.Nep2Key, "new", helper.DefaultAddressVersion, 2, 1, 1)
+	assert.Nil(t, err)
+
+	resetTestWallet()
 }
 
 func TestNEP6Wallet_Unlock(t *testing.T) {
@@ -1,5 +1,7 @@
 package wallet
 
+import "fmt"
+
 // ScryptParameters is a json-serializable container for scrypt KDF parameters.
 type ScryptParameters struct {
 	N int `json:"n"`
@@ -16,3 +18,14 @@ func NewScryptParameters(n int, r int, p int) *ScryptParameters {
 		P: p,
 	}
 }
+
+// Validate checks the parameters are accepted by scrypt
+func (p *ScryptParameters) Validate() error {
+	if p.N <= 1 || p.N&(p.N-1) != 0 {
+		return fmt.Errorf("scrypt parameter N %d should be a power of 2 larger than 1", p.N)
+	}
+	if p.R <= 0 || p.P <= 0 || uint64(p.R)*uint64(p.P) >= 1<<30 {
+		return fmt.Errorf("scrypt parameters r %d and p %d are invalid", p.R, p.P)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package wallet
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// WalletFileLock is an advisory lock on a wallet file, the lock file path + ".lock" holds the pid and the host of
+// the owner. Take it before opening the wallet and release it after saving, so that no other process changes
+// the file in between.
+type WalletFileLock struct {
+	path    string
+	content string
+}
+
+// LockWalletFile takes the lock of the wallet file at path. A lock left by a process which no longer runs on this
+// host is stale and taken over, a lock of another host is never taken over since its process can't be checked.
+func LockWalletFile(path string) (*WalletFileLock, error) {
+	if path == "" {
+		return nil, fmt.Errorf("wallet path is empty")
+	}
+	host, _ := os.Hostname()
+	l := &WalletFileLock{
+		path:    path + ".lock",
+		content: strconv.Itoa(os.Getpid()) + "\n" + host,
+	}
+	for retry := 0; ; retry++ {
+		file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_, err = file.WriteString(l.content)
+			if err2 := file.Close(); err == nil {
+				err = err2
+			}
+			if err != nil {
+				_ = os.Remove(l.path)
+				return nil, err
+			}
+			return l, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		b, err := os.ReadFile(l.path)
+		if os.IsNotExist(err) {
+			continue // released meanwhile
+		}
+		if err != nil {
+			return nil, err
+		}
+		pid, owner := parseLockContent(string(b))
+		if pid == 0 {
+			// being written, or not written by LockWalletFile
+			return nil, fmt.Errorf("wallet %s is locked, remove %s if no process holds it", path, l.path)
+		}
+		if retry > 0 || owner != host || processExists(pid) {
+			return nil, fmt.Errorf("wallet %s is locked by process %d on %s, remove %s if it is not running",
+				path, pid, owner, l.path)
+		}
+		// stale, unless another process took it over since it was read
+		if current, err := os.ReadFile(l.path); err == nil && string(current) == string(b) {
+			_ = os.Remove(l.path)
+		}
+	}
+}
+
+// Unlock releases the lock, unless it has been taken over by another process meanwhile
+func (l *WalletFileLock) Unlock() error {
+	b, err := os.ReadFile(l.path)
+	if err != nil {
+		return err
+	}
+	if string(b) != l.content {
+		return fmt.Errorf("lock %s is held by another process", l.path)
+	}
+	return os.Remove(l.path)
+}
+
+func parseLockContent(s string) (int, string) {
+	parts := strings.SplitN(s, "\n", 2)
+	pid, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || pid <= 0 {
+		return 0, ""
+	}
+	if len(parts) == 1 {
+		return pid, ""
+	}
+	return pid, strings.TrimSpace(parts[1])
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package wallet
+
+import "os"
+
+// processExists fails on windows if there is no such process, other platforms always find one,
+// so their locks are never taken as stale
+func processExists(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
@@ -0,0 +1,46 @@
+package wallet
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockWalletFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet.json")
+	lock, err := LockWalletFile(path)
+	assert.Nil(t, err)
+	_, err = LockWalletFile(path)
+	assert.NotNil(t, err)
+	assert.Nil(t, lock.Unlock())
+	_, err = os.Stat(path + ".lock")
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = LockWalletFile("")
+	assert.NotNil(t, err)
+}
+
+func TestLockWalletFile_Stale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet.json")
+	host, _ := os.Hostname()
+	lockWith := func(content string) error {
+		assert.Nil(t, os.WriteFile(path+".lock", []byte(content), 0600))
+		lock, err := LockWalletFile(path)
+		if err == nil {
+			assert.Nil(t, lock.Unlock())
+		}
+		return err
+	}
+	// the owner is not running
+	assert.Nil(t, lockWith(strconv.Itoa(math.MaxInt32)+"\n"+host))
+	// the owner is running
+	assert.NotNil(t, lockWith(strconv.Itoa(os.Getpid())+"\n"+host))
+	// the owner is on another host
+	assert.NotNil(t, lockWith(strconv.Itoa(math.MaxInt32)+"\nanother-host"))
+	// unknown owner
+	assert.NotNil(t, lockWith(""))
+}
@@ -0,0 +1,11 @@
+//go:build unix
+
+package wallet
+
+import "syscall"
+
+// processExists sends the null signal, which checks the pid without affecting the process
+func processExists(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
@@ -1,12 +1,14 @@
 package wallet
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"math"
 	"math/big"
 	"sort"
 	"strconv"
+	"sync"
 
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
@@ -14,6 +16,7 @@ import (
 	"github.com/joeqian10/neo3-gogogo/rpc"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
 	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tracker"
 	"github.com/joeqian10/neo3-gogogo/tx"
 )
 
@@ -21,14 +24,22 @@ type WalletHelper struct {
 	Client rpc.IRpcClient
 	wallet *NEP6Wallet
 	Magic  uint32
+
+	signers map[helper.UInt160]keys.ISigner // external signers by the script hash of the signature contract
+
+	txTracker   *tracker.TxTracker // shared by SendAndWait calls, created on first use
+	trackerOnce sync.Once
 }
 
 var dummy = "dummy"
 
 func NewWalletHelperFromPrivateKey(rpc rpc.IRpcClient, priKey []byte) (*WalletHelper, error) {
-	dummyWallet, _ := NewNEP6Wallet("", &helper.DefaultProtocolSettings, &dummy, DefaultScryptParameters)
+	dummyWallet, err := NewNEP6Wallet("", &helper.DefaultProtocolSettings, &dummy, DefaultScryptParameters)
+	if err != nil {
+		return nil, err
+	}
 	_ = dummyWallet.Unlock("")
-	_, err := dummyWallet.CreateAccountWithPrivateKey(priKey)
+	_, err = dummyWallet.CreateAccountWithPrivateKey(priKey)
 	if err != nil {
 		return nil, err
 	}
@@ -39,11 +50,14 @@ func NewWalletHelperFromPrivateKey(rpc rpc.IRpcClient, priKey []byte) (*WalletHe
 }
 
 func NewWalletHelperFromContract(rpc rpc.IRpcClient, contract *sc.Contract, pair *keys.KeyPair) (*WalletHelper, error) {
-	dummyWallet, _ := NewNEP6Wallet("", &helper.DefaultProtocolSettings, &dummy, DefaultScryptParameters)
+	dummyWallet, err := NewNEP6Wallet("", &helper.DefaultProtocolSettings, &dummy, DefaultScryptParameters)
+	if err != nil {
+		return nil, err
+	}
 	if pair != nil {
 		_ = dummyWallet.Unlock("")
 	}
-	_, err := dummyWallet.CreateAccountWithContract(contract, pair)
+	_, err = dummyWallet.CreateAccountWithContract(contract, pair)
 	if err != nil {
 		return nil, err
 	}
@@ -55,9 +69,12 @@ func NewWalletHelperFromContract(rpc rpc.IRpcClient, contract *sc.Contract, pair
 
 // Create a WalletHelper using your own private key, password is "" by default
 func NewWalletHelperFromWIF(rpc rpc.IRpcClient, wif string) (*WalletHelper, error) {
-	dummyWallet, _ := NewNEP6Wallet("", &helper.DefaultProtocolSettings, &dummy, DefaultScryptParameters)
+	dummyWallet, err := NewNEP6Wallet("", &helper.DefaultProtocolSettings, &dummy, DefaultScryptParameters)
+	if err != nil {
+		return nil, err
+	}
 	_ = dummyWallet.Unlock("")
-	_, err := dummyWallet.ImportFromWIF(wif)
+	_, err = dummyWallet.ImportFromWIF(wif)
 	if err != nil {
 		return nil, err
 	}
@@ -68,8 +85,11 @@ func NewWalletHelperFromWIF(rpc rpc.IRpcClient, wif string) (*WalletHelper, erro
 }
 
 func NewWalletHelperFromNEP2(rpc rpc.IRpcClient, nep2 string, passphrase string, N, R, P int) (*WalletHelper, error) {
-	dummyWallet, _ := NewNEP6Wallet("", &helper.DefaultProtocolSettings, &dummy, NewScryptParameters(N, R, P))
-	_, err := dummyWallet.ImportFromNEP2(nep2, passphrase, N, R, P)
+	dummyWallet, err := NewNEP6Wallet("", &helper.DefaultProtocolSettings, &dummy, NewScryptParameters(N, R, P))
+	if err != nil {
+		return nil, err
+	}
+	_, err = dummyWallet.ImportFromNEP2(nep2, passphrase, N, R, P)
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +110,67 @@ func NewWalletHelperFromWallet(rpc rpc.IRpcClient, wlt *NEP6Wallet) *WalletHelpe
 	}
 }
 
+// NewWalletHelperFromSigner creates a WalletHelper whose only account is signed by the signer,
+// the private key never needs to be in memory
+func NewWalletHelperFromSigner(rpc rpc.IRpcClient, signer keys.ISigner) (*WalletHelper, error) {
+	dummyWallet, err := NewNEP6Wallet("", &helper.DefaultProtocolSettings, &dummy, DefaultScryptParameters)
+	if err != nil {
+		return nil, err
+	}
+	w := &WalletHelper{
+		Client: rpc,
+		wallet: dummyWallet,
+	}
+	_, err = w.AddSigner(signer)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// AddSigner registers an external signer, e.g. an HSM or a remote signing service. A watch-only account of the
+// signature contract is added to the wallet if it does not exist. The signer is used instead of the key of the
+// account, and also signs for the multi-signature accounts in the wallet which contain its public key.
+func (w *WalletHelper) AddSigner(signer keys.ISigner) (IAccount, error) {
+	if w.wallet == nil {
+		return nil, fmt.Errorf("wallet is nil")
+	}
+	if signer == nil || signer.GetPublicKey() == nil {
+		return nil, fmt.Errorf("signer has no public key")
+	}
+	contract, err := sc.CreateSignatureContract(signer.GetPublicKey())
+	if err != nil {
+		return nil, err
+	}
+	account := w.wallet.GetAccountByScriptHash(contract.GetScriptHash())
+	if account == nil || account.GetContract() == nil {
+		account, err = w.wallet.CreateAccountWithContract(contract, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if w.signers == nil {
+		w.signers = map[helper.UInt160]keys.ISigner{}
+	}
+	w.signers[*contract.GetScriptHash()] = signer
+	return account, nil
+}
+
+// getSigner returns the external signer of the signature contract, or the key of the account
+func (w *WalletHelper) getSigner(scriptHash *helper.UInt160, account IAccount) (keys.ISigner, error) {
+	if signer, ok := w.signers[*scriptHash]; ok {
+		return signer, nil
+	}
+	if account == nil || !account.HasKey() {
+		return nil, nil
+	}
+	pair, err := account.GetKey()
+	if err != nil || pair == nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
 func (w *WalletHelper) CalculateNetworkFee(trx *tx.Transaction) (uint64, error) {
 	if trx == nil {
 		return 0, fmt.Errorf("no transaction to calculate")
@@ -103,7 +184,8 @@ func (w *WalletHelper) CalculateNetworkFee(trx *tx.Transaction) (uint64, error)
 		sc.ByteSlice(trx.GetScript()).GetVarSize() +
 		helper.GetVarSize(len(hashes))
 
-	exec_fee_factor := int64(30)
+	settings := w.GetProtocolSettings()
+	exec_fee_factor := settings.GetExecFeeFactor()
 	nf := uint64(0)
 	index := -1
 	for _, hash := range hashes {
@@ -204,10 +286,23 @@ func (w *WalletHelper) CalculateNetworkFee(trx *tx.Transaction) (uint64, error)
 			// support more cotnract types in the future
 		}
 	}
-	nf += uint64(size * tx.FeePerByte)
+	nf += uint64(int64(size) * settings.GetFeePerByte())
 	return nf, nil
 }
 
+// GetProtocolSettings returns the settings of the wallet, which limit the transactions made by the helper
+func (w *WalletHelper) GetProtocolSettings() *helper.ProtocolSettings {
+	if w.wallet == nil || w.wallet.protocolSettings == nil {
+		return &helper.DefaultProtocolSettings
+	}
+	return w.wallet.protocolSettings
+}
+
+// Contains returns true if the wallet has an account of the script hash
+func (w *WalletHelper) Contains(scriptHash *helper.UInt160) bool {
+	return w.wallet != nil && w.wallet.Contains(scriptHash)
+}
+
 // ClaimGas for NEP6Account
 func (w *WalletHelper) ClaimGas(magic uint32) (string, error) {
 	if w.wallet == nil {
@@ -391,12 +486,16 @@ func (w *WalletHelper) MakeTransaction(script []byte, cosigners []*tx.Signer, at
 		if err != nil {
 			return nil, err
 		}
-		trx.SetValidUntilBlock(blockHeight + tx.MaxValidUntilBlockIncrement)
+		trx.SetValidUntilBlock(blockHeight + w.GetProtocolSettings().GetMaxValidUntilBlockIncrement())
 		// signers
 		signers := getSigners(ab.Account, cosigners)
 		trx.SetSigners(signers)
 		// attributes
 		trx.SetAttributes(attributes)
+		err = trx.ValidateAttributes()
+		if err != nil {
+			return nil, err
+		}
 		// sysfee
 		gasConsumed, err := w.GetGasConsumed(script, models.CreateRpcSigners(signers))
 		if err != nil {
@@ -418,6 +517,67 @@ func (w *WalletHelper) MakeTransaction(script []byte, cosigners []*tx.Signer, at
 	return nil, fmt.Errorf("insufficient GAS")
 }
 
+// MakeScheduledTransaction makes a transaction which can only be persisted after the block of height notValidBefore,
+// it must be sent before ValidUntilBlock, so notValidBefore is limited to MaxValidUntilBlockIncrement blocks ahead
+func (w *WalletHelper) MakeScheduledTransaction(script []byte, cosigners []*tx.Signer, attributes []tx.ITransactionAttribute, balanceGas []*AccountAndBalance, notValidBefore uint32) (*tx.Transaction, error) {
+	attrs := append([]tx.ITransactionAttribute{}, attributes...)
+	attrs = append(attrs, tx.NewNotValidBeforeAttribute(notValidBefore))
+	return w.MakeTransaction(script, cosigners, attrs, balanceGas)
+}
+
+// MakeCancelTransaction makes a transaction which conflicts with the pending transaction of hash and pays more
+// network fee, so that the node drops the pending one and it can never be persisted.
+// The sender of the pending transaction must be in the wallet, since only it can cancel the transaction.
+func (w *WalletHelper) MakeCancelTransaction(hash *helper.UInt256, magic uint32) (*tx.Transaction, error) {
+	response := w.Client.GetRawTransaction("0x" + hash.String())
+	if response.HasError() {
+		return nil, fmt.Errorf(response.GetErrorInfo())
+	}
+	pending := response.Result
+	if pending.BlockHash != "" {
+		return nil, fmt.Errorf("transaction %s is already persisted in block %s", hash.String(), pending.BlockHash)
+	}
+	sender, err := crypto.AddressToScriptHash(pending.Sender, w.GetProtocolSettings().AddressVersion)
+	if err != nil {
+		return nil, err
+	}
+	pendingFee, err := strconv.ParseInt(pending.NetFee, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := w.GetBalanceFromAccount(tx.GasToken, sender)
+	if err != nil {
+		return nil, err
+	}
+	balanceGas := []*AccountAndBalance{{Account: sender, Value: balance}}
+	cosigners := []*tx.Signer{tx.NewSigner(sender, tx.None)}
+	attributes := []tx.ITransactionAttribute{tx.NewConflictsAttribute(hash)}
+	trx, err := w.MakeTransaction([]byte{byte(sc.RET)}, cosigners, attributes, balanceGas)
+	if err != nil {
+		return nil, err
+	}
+	if trx.GetNetworkFee() <= pendingFee {
+		trx.SetNetworkFee(pendingFee + 1)
+		if balance.Int64() < trx.GetSystemFee()+trx.GetNetworkFee() {
+			return nil, fmt.Errorf("insufficient GAS")
+		}
+	}
+	return w.SignTransaction(trx, magic)
+}
+
+// CancelTransaction sends a transaction made by MakeCancelTransaction
+func (w *WalletHelper) CancelTransaction(hash *helper.UInt256, magic uint32) (string, error) {
+	trx, err := w.MakeCancelTransaction(hash, magic)
+	if err != nil {
+		return "", err
+	}
+	response := w.Client.SendRawTransaction(crypto.Base64Encode(trx.ToByteArray()))
+	if response.HasError() {
+		return "", fmt.Errorf(response.GetErrorInfo())
+	}
+	return response.Result.Hash, nil
+}
+
 func (w *WalletHelper) Sign(ctx *ContractParametersContext, magic uint32) (bool, error) {
 	fSuccess := false
 	for _, scriptHash := range ctx.GetScriptHashes() {
@@ -433,28 +593,32 @@ func (w *WalletHelper) Sign(ctx *ContractParametersContext, magic uint32) (bool,
 			}
 			if msc != nil && b {
 				for _, point := range points {
-					account = w.wallet.GetAccountByPublicKey(&point)
-					if account == nil || account.HasKey() != true {
-						continue
+					contract, err := sc.CreateSignatureContract(&point)
+					if err != nil {
+						return false, err
 					}
-					pair, err := account.GetKey()
+					signer, err := w.getSigner(contract.GetScriptHash(), w.wallet.GetAccountByScriptHash(contract.GetScriptHash()))
 					if err != nil {
 						return false, err
 					}
-					signature, err := Sign(ctx.Verifiable, pair, magic)
+					if signer == nil {
+						continue
+					}
+					signature, err := Sign(ctx.Verifiable, signer, magic)
 					if err != nil {
 						return false, err
 					}
-					ctr, err := account.GetContract().ToContract()
+					// the signature belongs to the multi-signature contract, not the account of the key
+					ctr, err := msc.ToContract()
 					if err != nil {
 						return false, err
 					}
-					addSigSuccess, err := ctx.AddSignature(ctr, pair.PublicKey, signature)
+					addSigSuccess, err := ctx.AddSignature(ctr, signer.GetPublicKey(), signature)
 					if err != nil {
 						return false, err
 					}
 					fSuccess = fSuccess || addSigSuccess
-					if fSuccess {
+					if addSigSuccess {
 						m--
 					}
 					if ctx.GetCompleted() || m <= 0 {
@@ -462,13 +626,14 @@ func (w *WalletHelper) Sign(ctx *ContractParametersContext, magic uint32) (bool,
 					}
 				}
 				continue
-			} else if account.HasKey() {
-				// Try to sign with regular accounts
-				pair, err := account.GetKey()
-				if err != nil {
-					return false, err
-				}
-				signature, err := Sign(ctx.Verifiable, pair, magic)
+			}
+			// Try to sign with regular accounts
+			signer, err := w.getSigner(scriptHash, account)
+			if err != nil {
+				return false, err
+			}
+			if signer != nil {
+				signature, err := Sign(ctx.Verifiable, signer, magic)
 				if err != nil {
 					return false, err
 				}
@@ -476,7 +641,7 @@ func (w *WalletHelper) Sign(ctx *ContractParametersContext, magic uint32) (bool,
 				if err != nil {
 					return false, err
 				}
-				addSigSuccess, err := ctx.AddSignature(ctr, pair.PublicKey, signature)
+				addSigSuccess, err := ctx.AddSignature(ctr, signer.GetPublicKey(), signature)
 				if err != nil {
 					return false, err
 				}
@@ -527,19 +692,63 @@ func (w *WalletHelper) SignTransaction(trx *tx.Transaction, magic uint32) (*tx.T
 
 // Transfer is used to transfer neo or gas or other nep17 asset, from NEP6Account
 func (w *WalletHelper) Transfer(assetHash *helper.UInt160, toAddress string, amount *big.Int, magic uint32) (string, error) {
-	to, err := crypto.AddressToScriptHash(toAddress, w.wallet.protocolSettings.AddressVersion)
+	trx, err := w.makeTransferTransaction(assetHash, toAddress, amount, magic)
 	if err != nil {
 		return "", err
 	}
 
+	//fmt.Println(crypto.Base64Encode(trx.ToByteArray()))
+	//fmt.Println(helper.BytesToHex(trx.ToByteArray()))
+	//fmt.Println(trx.GetHash().String())
+
+	// use RPC to send the tx
+	response := w.Client.SendRawTransaction(crypto.Base64Encode(trx.ToByteArray()))
+	if response.HasError() {
+		return "", fmt.Errorf(response.GetErrorInfo())
+	}
+	return response.Result.Hash, nil
+}
+
+// TransferAndWait sends a transfer like Transfer, then waits until it is confirmed, expired or dropped
+func (w *WalletHelper) TransferAndWait(ctx context.Context, assetHash *helper.UInt160, toAddress string, amount *big.Int, magic uint32) (*tracker.TxResult, error) {
+	trx, err := w.makeTransferTransaction(assetHash, toAddress, amount, magic)
+	if err != nil {
+		return nil, err
+	}
+	return w.SendAndWait(ctx, trx)
+}
+
+// SendAndWait sends a signed tx and waits until it is confirmed, expired or dropped
+func (w *WalletHelper) SendAndWait(ctx context.Context, trx *tx.Transaction) (*tracker.TxResult, error) {
+	response := w.Client.SendRawTransaction(crypto.Base64Encode(trx.ToByteArray()))
+	if response.HasError() {
+		return nil, fmt.Errorf(response.GetErrorInfo())
+	}
+	return w.sharedTracker().Wait(ctx, response.Result.Hash, trx.GetValidUntilBlock())
+}
+
+// sharedTracker returns the tracker of the wallet helper, so that concurrent waits share one polling loop
+func (w *WalletHelper) sharedTracker() *tracker.TxTracker {
+	w.trackerOnce.Do(func() {
+		w.txTracker = tracker.NewTxTracker(w.Client)
+	})
+	return w.txTracker
+}
+
+func (w *WalletHelper) makeTransferTransaction(assetHash *helper.UInt160, toAddress string, amount *big.Int, magic uint32) (*tx.Transaction, error) {
+	to, err := crypto.AddressToScriptHash(toAddress, w.GetProtocolSettings().AddressVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	balances, err := w.GetAccountAndBalance(assetHash)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	sort.Sort(AccountAndBalanceSlice(balances))
 	balancesUsed := FindPayingAccounts(balances, amount)
 	if balancesUsed == nil {
-		return "", fmt.Errorf("insufficient funds of asset: %s", assetHash.String())
+		return nil, fmt.Errorf("insufficient funds of asset: %s", assetHash.String())
 	}
 	// add cosigner
 	cosigners := make([]*tx.Signer, 0)
@@ -559,7 +768,7 @@ func (w *WalletHelper) Transfer(assetHash *helper.UInt160, toAddress string, amo
 	}
 	script, err := sb.ToArray()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	balancesGas := make([]*AccountAndBalance, 0)
 	if assetHash.Equals(tx.GasToken) {
@@ -567,27 +776,17 @@ func (w *WalletHelper) Transfer(assetHash *helper.UInt160, toAddress string, amo
 	} else {
 		balancesGas, err = w.GetAccountAndBalance(tx.GasToken)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 	trx, err := w.MakeTransaction(script, cosigners, []tx.ITransactionAttribute{}, balancesGas)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	// sign the tx
 	trx, err = w.SignTransaction(trx, magic)
 	if err != nil {
-		return "", err
-	}
-
-	//fmt.Println(crypto.Base64Encode(trx.ToByteArray()))
-	//fmt.Println(helper.BytesToHex(trx.ToByteArray()))
-	//fmt.Println(trx.GetHash().String())
-
-	// use RPC to send the tx
-	response := w.Client.SendRawTransaction(crypto.Base64Encode(trx.ToByteArray()))
-	if response.HasError() {
-		return "", fmt.Errorf(response.GetErrorInfo())
+		return nil, err
 	}
-	return response.Result.Hash, nil
+	return trx, nil
 }
@@ -1,8 +1,10 @@
 package wallet
 
 import (
+	"context"
 	"fmt"
 	"math/big"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,9 +12,12 @@ import (
 
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/keys"
 	"github.com/joeqian10/neo3-gogogo/rpc"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
 	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tracker"
 	"github.com/joeqian10/neo3-gogogo/tx"
 )
 
@@ -43,6 +48,10 @@ func TestNewWalletHelperFromNEP2(t *testing.T) {
 	assert.NotNil(t, wh.Client)
 	assert.NotNil(t, wh.wallet)
 	assert.Equal(t, 1, len(wh.wallet.GetAccounts()))
+
+	wh, err = NewWalletHelperFromNEP2(client, nep2, password, 3, 1, 1)
+	assert.NotNil(t, err)
+	assert.Nil(t, wh)
 }
 
 func TestNewWalletHelperFromWIF(t *testing.T) {
@@ -663,12 +672,103 @@ func TestWalletHelper_MakeTransaction(t *testing.T) {
 	trx, e := wh.MakeTransaction(script, nil, nil, ab)
 	assert.Nil(t, e)
 	assert.Equal(t, int64(1141520), trx.GetNetworkFee())
+	assert.Equal(t, uint32(6666665+5760), trx.GetValidUntilBlock())
+
+	// ValidUntilBlock follows the settings of the wallet
+	settings := helper.TestNetProtocolSettings.Copy()
+	settings.MaxValidUntilBlockIncrement = 100
+	testWallet.protocolSettings = settings
+	trx, e = wh.MakeTransaction(script, nil, nil, ab)
+	assert.Nil(t, e)
+	assert.Equal(t, uint32(6666665+100), trx.GetValidUntilBlock())
+
+	resetTestWallet()
+}
+
+func TestWalletHelper_MakeCancelTransaction(t *testing.T) {
+	var clientMock = new(rpc.RpcClientMock)
+	pending := "0x992f941c9751aabc8bab0200503e07e38f38f0884cb8b11f6c6c72d8d2fb2948"
+	clientMock.On("GetRawTransaction", pending).Return(rpc.GetRawTransactionResponse{
+		Result: models.RpcTransaction{
+			Hash:   pending,
+			Sender: crypto.ScriptHashToAddress(testScriptHash, helper.DefaultAddressVersion),
+			NetFee: "5000000",
+		},
+	}).Once()
+	clientMock.On("InvokeFunction", tx.GasToken.String(), "balanceOf", mock.Anything, mock.Anything, false).Return(rpc.InvokeResultResponse{
+		Result: models.InvokeResult{State: "HALT", Stack: []models.InvokeStack{{Type: "Integer", Value: "1000000000"}}},
+	})
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 1000})
+	clientMock.On("InvokeScript", mock.Anything, mock.Anything, false).Return(rpc.InvokeResultResponse{
+		Result: models.InvokeResult{State: "HALT", GasConsumed: "0"},
+	})
+	_ = testWallet.Unlock("")
+	_, err := testWallet.CreateAccountWithPrivateKey(privateKey)
+	assert.Nil(t, err)
+	wh := NewWalletHelperFromWallet(clientMock, testWallet)
+
+	hash, _ := helper.UInt256FromString(pending)
+	trx, err := wh.MakeCancelTransaction(hash, helper.Neo3Magic_MainNet)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{byte(sc.RET)}, trx.GetScript())
+	assert.Equal(t, int64(5000001), trx.GetNetworkFee())
+	assert.Equal(t, tx.None, trx.GetSigners()[0].Scopes)
+	assert.Equal(t, 1, len(trx.GetAttributes()))
+	assert.True(t, hash.Equals(trx.GetAttributes()[0].(*tx.ConflictsAttribute).Hash))
+	assert.Equal(t, 1, len(trx.GetWitnesses()))
+
+	// already persisted
+	clientMock.On("GetRawTransaction", pending).Return(rpc.GetRawTransactionResponse{
+		Result: models.RpcTransaction{Hash: pending, BlockHash: "0x01"},
+	})
+	_, err = wh.MakeCancelTransaction(hash, helper.Neo3Magic_MainNet)
+	assert.NotNil(t, err)
 
 	resetTestWallet()
 }
 
 func TestWalletHelper_Sign(t *testing.T) {
-	// tested in SignTransaction
+	// a wallet holding two keys of a 2-of-3 multi-signature account completes the context alone
+	pairs := make([]*keys.KeyPair, 3)
+	points := make([]*crypto.ECPoint, 3)
+	for i := range pairs {
+		k := make([]byte, 32)
+		k[31] = byte(i + 1)
+		pair, err := keys.NewKeyPair(k)
+		assert.Nil(t, err)
+		pairs[i], points[i] = pair, pair.PublicKey
+	}
+	multi, err := sc.CreateMultiSigContract(2, points)
+	assert.Nil(t, err)
+	trx := tx.NewTransaction()
+	trx.SetNonce(1)
+	trx.SetValidUntilBlock(100)
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetSigners([]*tx.Signer{tx.NewSigner(multi.GetScriptHash(), tx.CalledByEntry)})
+	ctx := NewContractParametersContract(trx)
+	ctx.GetScriptHashes()
+
+	// a wallet without a file
+	w, err := NewNEP6Wallet("", &helper.DefaultProtocolSettings, &dummy, NewScryptParameters(2, 1, 1))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Unlock(""))
+	for _, pair := range pairs[:2] {
+		_, err = w.CreateAccountWithPrivateKey(pair.PrivateKey)
+		assert.Nil(t, err)
+	}
+	_, err = w.CreateAccountWithContract(multi, nil)
+	assert.Nil(t, err)
+	wh := NewWalletHelperFromWallet(new(rpc.RpcClientMock), w)
+
+	ok, err := wh.Sign(ctx, helper.Neo3Magic_TestNet)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.True(t, ctx.GetCompleted())
+	witnesses, err := ctx.GetWitnesses()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(witnesses))
+	assert.Equal(t, multi.Script, witnesses[0].VerificationScript)
+	assert.True(t, tx.VerifyMultiSignatureWitness(tx.GetSignData(trx, helper.Neo3Magic_TestNet), witnesses[0]))
 }
 
 func TestWalletHelper_SignTransaction(t *testing.T) {
@@ -839,6 +939,39 @@ func TestWalletHelper_Transfer(t *testing.T) {
 	resetTestWallet()
 }
 
+func TestWalletHelper_SendAndWait(t *testing.T) {
+	var clientMock = new(rpc.RpcClientMock)
+	clientMock.On("SendRawTransaction", mock.Anything).Return(rpc.SendRawTransactionResponse{
+		Result: struct {
+			Hash string `json:"hash"`
+		}{Hash: "0x992f941c9751aabc8bab0200503e07e38f38f0884cb8b11f6c6c72d8d2fb2948"},
+	})
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 1235})
+	clientMock.On("GetTransactionHeight", "0x992f941c9751aabc8bab0200503e07e38f38f0884cb8b11f6c6c72d8d2fb2948").
+		Return(rpc.GetTransactionHeightResponse{Result: 1234})
+	clientMock.On("GetApplicationLog", "0x992f941c9751aabc8bab0200503e07e38f38f0884cb8b11f6c6c72d8d2fb2948").
+		Return(rpc.GetApplicationLogResponse{
+			Result: models.RpcApplicationLog{
+				Executions: []models.RpcExecution{{Trigger: "Application", VMState: "HALT"}},
+			},
+		})
+	wh := NewWalletHelperFromWallet(clientMock, testWallet)
+
+	trx := tx.NewTransaction()
+	trx.SetValidUntilBlock(2000)
+	r, err := wh.SendAndWait(context.Background(), trx)
+	assert.Nil(t, err)
+	assert.Equal(t, tracker.Confirmed, r.State)
+	assert.Equal(t, uint32(1234), r.BlockIndex)
+	assert.Equal(t, "HALT", r.VMState)
+
+	// the next call uses the same tracker
+	tracker := wh.sharedTracker()
+	_, err = wh.SendAndWait(context.Background(), trx)
+	assert.Nil(t, err)
+	assert.Same(t, tracker, wh.sharedTracker())
+}
+
 func TestFindPayingAccounts(t *testing.T) {
 	orderedBalances := []*AccountAndBalance{
 		{helper.UInt160FromBytes([]byte{0x01}), big.NewInt(1)},
@@ -881,3 +1014,143 @@ func TestMakeScript2(t *testing.T) {
 	s := crypto.Base64Encode(script)
 	fmt.Println(s)
 }
+
+// countingSigner keeps the key out of the wallet and counts the signatures
+type countingSigner struct {
+	pair  *keys.KeyPair
+	count int
+}
+
+func (s *countingSigner) GetPublicKey() *crypto.ECPoint { return s.pair.PublicKey }
+
+func (s *countingSigner) Sign(message []byte) ([]byte, error) {
+	s.count++
+	return s.pair.Sign(message)
+}
+
+func TestWalletHelper_AddSigner(t *testing.T) {
+	signers := make([]*countingSigner, 2)
+	points := make([]*crypto.ECPoint, 2)
+	for i := range signers {
+		pair, _ := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[i].PrivateKey))
+		signers[i] = &countingSigner{pair: pair}
+		points[i] = pair.PublicKey
+	}
+	wh, err := NewWalletHelperFromSigner(new(rpc.RpcClientMock), signers[0])
+	assert.Nil(t, err)
+	account := wh.wallet.GetAccounts()[0]
+	assert.False(t, account.HasKey())
+
+	trx := tx.NewTransaction()
+	trx.SetSigners([]*tx.Signer{tx.NewSigner(account.GetScriptHash(), tx.CalledByEntry)})
+	trx, err = wh.SignTransaction(trx, helper.Neo3Magic_MainNet)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, signers[0].count)
+	assert.True(t, tx.VerifySignatureWitness(tx.GetSignData(trx, helper.Neo3Magic_MainNet), trx.GetWitnesses()[0]))
+
+	// the signers also sign for the multi-signature account
+	multi, err := sc.CreateMultiSigContract(2, points)
+	assert.Nil(t, err)
+	_, err = wh.wallet.CreateAccountWithContract(multi, nil)
+	assert.Nil(t, err)
+	trx = tx.NewTransaction()
+	trx.SetSigners([]*tx.Signer{tx.NewSigner(multi.GetScriptHash(), tx.CalledByEntry)})
+	_, err = wh.SignTransaction(trx, helper.Neo3Magic_MainNet)
+	assert.NotNil(t, err) // one of the two signatures
+	_, err = wh.AddSigner(signers[1])
+	assert.Nil(t, err)
+	trx, err = wh.SignTransaction(trx, helper.Neo3Magic_MainNet)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(trx.GetWitnesses()))
+	assert.Equal(t, multi.Script, trx.GetWitnesses()[0].VerificationScript)
+	assert.Equal(t, 3, signers[0].count)
+	assert.Equal(t, 1, signers[1].count)
+}
+
+// relayedTransaction decodes the transaction of the i-th sendrawtransaction received by the replay client
+func relayedTransaction(t *testing.T, replay *rpc.ReplayHttpClient, i int) *tx.Transaction {
+	requests := replay.Requests("sendrawtransaction")
+	if !assert.True(t, len(requests) > i) {
+		t.FailNow()
+	}
+	raw, ok := requests[i].Params[0].(string)
+	assert.True(t, ok)
+	b, err := crypto.Base64Decode(raw)
+	assert.Nil(t, err)
+	trx := &tx.Transaction{}
+	br := io.NewBinaryReaderFromBuf(b)
+	trx.Deserialize(br)
+	assert.Nil(t, br.Err)
+	return trx
+}
+
+// assertRelayedTransaction checks the script, the signer, the fees and the witness of a transaction sent by the
+// account, the system fee is the gasconsumed of the invokescript fixture
+func assertRelayedTransaction(t *testing.T, trx *tx.Transaction, script []byte, account *helper.UInt160, validUntilBlock uint32) {
+	assert.Equal(t, script, trx.GetScript())
+	assert.True(t, trx.GetSender().Equals(account))
+	assert.Equal(t, 1, len(trx.GetSigners()))
+	assert.Equal(t, tx.CalledByEntry, trx.GetSigners()[0].Scopes)
+	assert.Equal(t, int64(997775), trx.GetSystemFee())
+	assert.True(t, trx.GetNetworkFee() > 0)
+	assert.Equal(t, validUntilBlock, trx.GetValidUntilBlock())
+	assert.Equal(t, 1, len(trx.GetWitnesses()))
+	assert.True(t, tx.VerifySignatureWitness(tx.GetSignData(trx, helper.Neo3Magic_TestNet), trx.GetWitnesses()[0]))
+	assert.False(t, tx.VerifySignatureWitness(tx.GetSignData(trx, helper.Neo3Magic_MainNet), trx.GetWitnesses()[0]))
+}
+
+// TestWalletHelper_Replay uses fixtures recorded against a local stub node in the response format of neo-cli, not
+// against MainNet or TestNet. The relayed transactions are decoded from the sendrawtransaction requests and checked.
+func TestWalletHelper_Replay(t *testing.T) {
+	replay, err := rpc.NewReplayHttpClient(filepath.Join("testdata", "replay"))
+	assert.Nil(t, err)
+	// the nonce of a transaction is random
+	replay.IgnoreParams["sendrawtransaction"] = true
+	wh, err := NewWalletHelperFromPrivateKey(rpc.NewClientWithHttpClient("http://localhost:20332", replay), privateKey)
+	assert.Nil(t, err)
+
+	height, err := wh.GetBlockHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2500122), height)
+	unclaimed, err := wh.GetUnClaimedGas()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4937500), unclaimed)
+	b, err := wh.GetBalanceFromWallet(tx.NeoToken, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(100), b)
+
+	account := wh.wallet.GetAccounts()[0].GetScriptHash()
+	validUntilBlock := height + wh.GetProtocolSettings().GetMaxValidUntilBlockIncrement()
+	hash, err := wh.Transfer(tx.GasToken, "NdtB8RXRmJ7Nhw1FPTm7E6HoDZGnDw37nf", big.NewInt(100000000), helper.Neo3Magic_TestNet)
+	assert.Nil(t, err)
+	assert.Equal(t, 66, len(hash))
+	to, err := crypto.AddressToScriptHash("NdtB8RXRmJ7Nhw1FPTm7E6HoDZGnDw37nf", helper.DefaultAddressVersion)
+	assert.Nil(t, err)
+	sb := sc.NewScriptBuilder()
+	sb.EmitDynamicCall(tx.GasToken, "transfer", []interface{}{
+		sc.ContractParameter{Type: sc.Hash160, Value: account},
+		sc.ContractParameter{Type: sc.Hash160, Value: to},
+		sc.ContractParameter{Type: sc.Integer, Value: big.NewInt(100000000)},
+		sc.ContractParameter{Type: sc.String, Value: ""},
+	})
+	sb.Emit(sc.ASSERT)
+	script, err := sb.ToArray()
+	assert.Nil(t, err)
+	assertRelayedTransaction(t, relayedTransaction(t, replay, 0), script, account, validUntilBlock)
+
+	hash, err = wh.ClaimGas(helper.Neo3Magic_TestNet)
+	assert.Nil(t, err)
+	assert.Equal(t, 66, len(hash))
+	// claiming transfers all the neo to the account itself
+	sb = sc.NewScriptBuilder()
+	sb.EmitDynamicCall(tx.NeoToken, "transfer", []interface{}{
+		sc.ContractParameter{Type: sc.Hash160, Value: account},
+		sc.ContractParameter{Type: sc.Hash160, Value: account},
+		sc.ContractParameter{Type: sc.Integer, Value: big.NewInt(100)},
+		sc.ContractParameter{Type: sc.String, Value: ""},
+	})
+	sb.Emit(sc.ASSERT)
+	script, err = sb.ToArray()
+	assert.Nil(t, err)
+	assertRelayedTransaction(t, relayedTransaction(t, replay, 1), script, account, validUntilBlock)
+}